@@ -5,7 +5,8 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/errortypes"
@@ -229,8 +230,8 @@ func (a *TtxAdapter) MakeBids(internalRequest *openrtb2.BidRequest, externalRequ
 
 func validateVideoParams(video *openrtb2.Video, prod string) (*openrtb2.Video, error) {
 	videoCopy := *video
-	if videoCopy.W == 0 ||
-		videoCopy.H == 0 ||
+	if videoCopy.W == nil || *videoCopy.W == 0 ||
+		videoCopy.H == nil || *videoCopy.H == 0 ||
 		videoCopy.Protocols == nil ||
 		videoCopy.MIMEs == nil ||
 		videoCopy.PlaybackMethod == nil {
@@ -248,7 +249,7 @@ func validateVideoParams(video *openrtb2.Video, prod string) (*openrtb2.Video, e
 		videoCopy.Placement = 1
 
 		if videoCopy.StartDelay == nil {
-			videoCopy.StartDelay = openrtb2.StartDelay.Ptr(0)
+			videoCopy.StartDelay = adcom1.StartDelay.Ptr(0)
 		}
 	}
 
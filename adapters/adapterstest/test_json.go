@@ -8,7 +8,7 @@ import (
 	"testing"
 
 	"github.com/mitchellh/copystructure"
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/stretchr/testify/assert"
 	"github.com/yudai/gojsondiff"
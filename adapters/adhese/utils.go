@@ -1,6 +1,6 @@
 package adhese
 
-import "github.com/mxmCherry/openrtb/v15/openrtb2"
+import "github.com/prebid/openrtb/v20/openrtb2"
 
 type AdheseOriginData struct {
 	Priority                  string `json:"priority"`
@@ -13,7 +13,8 @@ import (
 	"strings"
 
 	"github.com/buger/jsonparser"
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/pbs"
 
@@ -164,9 +165,9 @@ func (a *AppNexusAdapter) Call(ctx context.Context, req *pbs.PBSRequest, bidder
 		}
 		if anReq.Imp[i].Banner != nil && params.Position != "" {
 			if params.Position == "above" {
-				anReq.Imp[i].Banner.Pos = openrtb2.AdPositionAboveTheFold.Ptr()
+				anReq.Imp[i].Banner.Pos = adcom1.PositionAboveFold.Ptr()
 			} else if params.Position == "below" {
-				anReq.Imp[i].Banner.Pos = openrtb2.AdPositionBelowTheFold.Ptr()
+				anReq.Imp[i].Banner.Pos = adcom1.PositionBelowFold.Ptr()
 			}
 		}
 
@@ -512,9 +513,9 @@ func preprocess(imp *openrtb2.Imp, defaultDisplayManagerVer string) (string, boo
 	if imp.Banner != nil {
 		bannerCopy := *imp.Banner
 		if appnexusExt.Position == "above" {
-			bannerCopy.Pos = openrtb2.AdPositionAboveTheFold.Ptr()
+			bannerCopy.Pos = adcom1.PositionAboveFold.Ptr()
 		} else if appnexusExt.Position == "below" {
-			bannerCopy.Pos = openrtb2.AdPositionBelowTheFold.Ptr()
+			bannerCopy.Pos = adcom1.PositionBelowFold.Ptr()
 		}
 
 		// Fixes #307
@@ -11,7 +11,8 @@ import (
 	"testing"
 	"time"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/prebid/prebid-server/cache/dummycache"
@@ -413,11 +414,11 @@ func DummyAppNexusServer(w http.ResponseWriter, r *http.Request) {
 				http.Error(w, fmt.Sprintf("Empty imp.banner.format array"), http.StatusInternalServerError)
 				return
 			}
-			if andata.tags[i].position == "above" && *imp.Banner.Pos != openrtb2.AdPosition(1) {
+			if andata.tags[i].position == "above" && *imp.Banner.Pos != adcom1.PlacementPosition(1) {
 				http.Error(w, fmt.Sprintf("Mismatch in position - expected 1 for atf"), http.StatusInternalServerError)
 				return
 			}
-			if andata.tags[i].position == "below" && *imp.Banner.Pos != openrtb2.AdPosition(3) {
+			if andata.tags[i].position == "below" && *imp.Banner.Pos != adcom1.PlacementPosition(3) {
 				http.Error(w, fmt.Sprintf("Mismatch in position - expected 3 for btf"), http.StatusInternalServerError)
 				return
 			}
@@ -449,7 +450,7 @@ func DummyAppNexusServer(w http.ResponseWriter, r *http.Request) {
 		}
 
 		if imp.Video != nil {
-			resBid.Attr = []openrtb2.CreativeAttribute{openrtb2.CreativeAttribute(6)}
+			resBid.Attr = []adcom1.CreativeAttribute{adcom1.CreativeAttribute(6)}
 		}
 		resp.SeatBid[0].Bid = append(resp.SeatBid[0].Bid, resBid)
 	}
@@ -8,7 +8,8 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/errortypes"
@@ -331,7 +332,7 @@ func getBannerRequest(request *openrtb2.BidRequest) (beachfrontBannerRequest, []
 
 	var t = fallBackDeviceType(request)
 
-	if t == openrtb2.DeviceTypeMobileTablet {
+	if t == adcom1.DeviceMobile {
 		bfr.Page = request.App.Bundle
 		if request.App.Domain == "" {
 			bfr.Domain = getDomain(request.App.Domain)
@@ -340,7 +341,7 @@ func getBannerRequest(request *openrtb2.BidRequest) (beachfrontBannerRequest, []
 		}
 
 		bfr.IsMobile = 1
-	} else if t == openrtb2.DeviceTypePersonalComputer {
+	} else if t == adcom1.DevicePC {
 		bfr.Page = request.Site.Page
 		if request.Site.Domain == "" {
 			bfr.Domain = getDomain(request.Site.Page)
@@ -384,12 +385,12 @@ func getBannerRequest(request *openrtb2.BidRequest) (beachfrontBannerRequest, []
 	return bfr, errs
 }
 
-func fallBackDeviceType(request *openrtb2.BidRequest) openrtb2.DeviceType {
+func fallBackDeviceType(request *openrtb2.BidRequest) adcom1.DeviceType {
 	if request.Site != nil {
-		return openrtb2.DeviceTypePersonalComputer
+		return adcom1.DevicePC
 	}
 
-	return openrtb2.DeviceTypeMobileTablet
+	return adcom1.DeviceMobile
 }
 
 func getVideoRequests(request *openrtb2.BidRequest) ([]beachfrontVideoRequest, []error) {
@@ -468,9 +469,9 @@ func getVideoRequests(request *openrtb2.BidRequest) ([]beachfrontVideoRequest, [
 		imp.Secure = &secure
 		setBidFloor(&beachfrontExt, &imp)
 
-		if imp.Video.H == 0 && imp.Video.W == 0 {
-			imp.Video.W = defaultVideoWidth
-			imp.Video.H = defaultVideoHeight
+		if (imp.Video.H == nil || *imp.Video.H == 0) && (imp.Video.W == nil || *imp.Video.W == 0) {
+			imp.Video.W = openrtb2.Int64Ptr(defaultVideoWidth)
+			imp.Video.H = openrtb2.Int64Ptr(defaultVideoHeight)
 		}
 
 		if len(bfReqs[i].Request.Cur) == 0 {
@@ -634,8 +635,12 @@ func postprocessVideo(bids []openrtb2.Bid, xtrnal openrtb2.BidRequest, uri strin
 
 			bids[i].CrID = crid
 			bids[i].ImpID = xtrnal.Imp[i].ID
-			bids[i].H = xtrnal.Imp[i].Video.H
-			bids[i].W = xtrnal.Imp[i].Video.W
+			if xtrnal.Imp[i].Video.H != nil {
+				bids[i].H = *xtrnal.Imp[i].Video.H
+			}
+			if xtrnal.Imp[i].Video.W != nil {
+				bids[i].W = *xtrnal.Imp[i].Video.W
+			}
 			bids[i].ID = fmt.Sprintf("%sNurlVideo", xtrnal.Imp[i].ID)
 		}
 
@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/metrics"
 	"github.com/prebid/prebid-server/openrtb_ext"
@@ -96,6 +96,8 @@ type TypedBid struct {
 	BidType      openrtb_ext.BidType
 	BidVideo     *openrtb_ext.ExtBidPrebidVideo
 	DealPriority int
+	// Seat is the seatbid.seat of the bid in the bidder response, the buyer seat the deals can be restricted to
+	Seat string
 }
 
 // RequestData and ResponseData exist so that prebid-server core code can implement its "debug" functionality
@@ -116,6 +118,12 @@ type RequestData struct {
 	Body    []byte
 	Headers http.Header
 
+	// FallbackUri is retried once when Uri fails with a connection error or a 5xx and the
+	// request still has time left. See RegionResolver.
+	FallbackUri string
+	// FallbackRegion is the region of FallbackUri, which TapjoyData.Region is set to when it is called
+	FallbackRegion string
+
 	// Tapjoy Opentelemetry
 	TapjoyData TapjoyData
 }
@@ -124,6 +132,12 @@ type TapjoyData struct {
 	Bidder        string        `json:"bidder"`
 	PlacementType PlacementType `json:"placement"`
 	Region        string        `json:"region"`
+	// RegionFallback is set when the request was retried against the fallback region
+	RegionFallback bool `json:"region_fallback"`
+	// Hedged is set when the request was also sent to the fallback region after the hedge delay of the bidder,
+	// HedgeWinner is then the endpoint whose response was kept: primary, secondary or none
+	Hedged      bool   `json:"hedged"`
+	HedgeWinner string `json:"hedge_winner,omitempty"`
 
 	SKAN  SKAN
 	MRAID MRAID
@@ -159,6 +173,40 @@ func (r *RequestData) SetBasicAuth(username string, password string) {
 type ExtraRequestInfo struct {
 	PbsEntryPoint              metrics.RequestType
 	GlobalPrivacyControlHeader string
+
+	// EnforceFloors is set when bids under imp.bidfloor are rejected by the exchange (request.ext.prebid.floors)
+	EnforceFloors bool
+
+	// Placements holds the placement of every imp of the auction by imp id, see Placement
+	Placements map[string]Placement
+
+	// BlockedCreatives are the creative ids the account blocks, the exchange rejects the bids using them
+	BlockedCreatives []string
+}
+
+// Placement returns the placement the exchange classified the imp as. The imp is classified on the spot
+// when the exchange did not, e.g. in the adapter tests.
+func (r *ExtraRequestInfo) Placement(imp *openrtb2.Imp) Placement {
+	if r != nil {
+		if placement, ok := r.Placements[imp.ID]; ok {
+			return placement
+		}
+	}
+	return ClassifyPlacement(imp)
+}
+
+// PlacementType returns the placement type shared by the imps of a request sent with several imps, it is
+// empty when their placements differ.
+func (r *ExtraRequestInfo) PlacementType(imps []openrtb2.Imp) PlacementType {
+	var placementType PlacementType
+	for i := range imps {
+		impType := r.Placement(&imps[i]).Type
+		if i > 0 && impType != placementType {
+			return ""
+		}
+		placementType = impType
+	}
+	return placementType
 }
 
 type Builder func(openrtb_ext.BidderName, config.Adapter) (Bidder, error)
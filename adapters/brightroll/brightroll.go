@@ -6,7 +6,8 @@ import (
 	"net/http"
 	"strconv"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/errortypes"
@@ -199,10 +200,10 @@ func (a *BrightrollAdapter) MakeBids(internalRequest *openrtb2.BidRequest, exter
 	return bidResponse, nil
 }
 
-func getBlockedCreativetypes(attr []int8) []openrtb2.CreativeAttribute {
-	var creativeAttr []openrtb2.CreativeAttribute
+func getBlockedCreativetypes(attr []int8) []adcom1.CreativeAttribute {
+	var creativeAttr []adcom1.CreativeAttribute
 	for i := 0; i < len(attr); i++ {
-		creativeAttr = append(creativeAttr, openrtb2.CreativeAttribute(attr[i]))
+		creativeAttr = append(creativeAttr, adcom1.CreativeAttribute(attr[i]))
 	}
 	return creativeAttr
 }
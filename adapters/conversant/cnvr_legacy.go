@@ -8,7 +8,8 @@ import (
 	"io/ioutil"
 	"net/http"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/pbs"
@@ -88,7 +89,7 @@ func (a *ConversantLegacyAdapter) Call(ctx context.Context, req *pbs.PBSRequest,
 		}
 
 		if params.Mobile != nil && !(cnvrReq.Site == nil) {
-			cnvrReq.Site.Mobile = *params.Mobile
+			cnvrReq.Site.Mobile = params.Mobile
 		}
 
 		// Fill in additional impression info
@@ -98,9 +99,9 @@ func (a *ConversantLegacyAdapter) Call(ctx context.Context, req *pbs.PBSRequest,
 		imp.BidFloor = params.BidFloor
 		imp.TagID = params.TagID
 
-		var position *openrtb2.AdPosition
+		var position *adcom1.PlacementPosition
 		if params.Position != nil {
-			position = openrtb2.AdPosition(*params.Position).Ptr()
+			position = adcom1.PlacementPosition(*params.Position).Ptr()
 		}
 
 		if imp.Banner != nil {
@@ -109,9 +110,9 @@ func (a *ConversantLegacyAdapter) Call(ctx context.Context, req *pbs.PBSRequest,
 			imp.Video.Pos = position
 
 			if len(params.API) > 0 {
-				imp.Video.API = make([]openrtb2.APIFramework, 0, len(params.API))
+				imp.Video.API = make([]adcom1.APIFramework, 0, len(params.API))
 				for _, api := range params.API {
-					imp.Video.API = append(imp.Video.API, openrtb2.APIFramework(api))
+					imp.Video.API = append(imp.Video.API, adcom1.APIFramework(api))
 				}
 			}
 
@@ -120,9 +121,9 @@ func (a *ConversantLegacyAdapter) Call(ctx context.Context, req *pbs.PBSRequest,
 			// but are overridden if the custom params object also contains them.
 
 			if len(params.Protocols) > 0 {
-				imp.Video.Protocols = make([]openrtb2.Protocol, 0, len(params.Protocols))
+				imp.Video.Protocols = make([]adcom1.MediaCreativeSubtype, 0, len(params.Protocols))
 				for _, protocol := range params.Protocols {
-					imp.Video.Protocols = append(imp.Video.Protocols, openrtb2.Protocol(protocol))
+					imp.Video.Protocols = append(imp.Video.Protocols, adcom1.MediaCreativeSubtype(protocol))
 				}
 			}
 
@@ -260,8 +261,12 @@ func (a *ConversantLegacyAdapter) Call(ctx context.Context, req *pbs.PBSRequest,
 			if imp.Video != nil {
 				pbsBid.CreativeMediaType = "video"
 				pbsBid.NURL = bid.AdM // Assign to NURL so it'll be interpreted as a vastUrl
-				pbsBid.Width = imp.Video.W
-				pbsBid.Height = imp.Video.H
+				if imp.Video.W != nil {
+					pbsBid.Width = *imp.Video.W
+				}
+				if imp.Video.H != nil {
+					pbsBid.Height = *imp.Video.H
+				}
 			} else {
 				pbsBid.CreativeMediaType = "banner"
 				pbsBid.NURL = bid.NURL
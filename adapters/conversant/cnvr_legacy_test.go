@@ -11,7 +11,8 @@ import (
 	"testing"
 	"time"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/cache/dummycache"
 	"github.com/prebid/prebid-server/config"
@@ -203,7 +204,7 @@ func TestConversantRequestDefault(t *testing.T) {
 
 	assertEqual(t, imp.DisplayManager, ExpectedDisplayManager, "Request display manager value")
 	assertEqual(t, lastReq.Site.ID, ExpectedSiteID, "Request site id")
-	assertEqual(t, int(lastReq.Site.Mobile), 0, "Request site mobile flag")
+	assertTrue(t, lastReq.Site.Mobile == nil, "Request site mobile flag")
 	assertEqual(t, lastReq.User.BuyerUID, ExpectedBuyerUID, "Request buyeruid")
 	assertTrue(t, imp.Video == nil, "Request video should be nil")
 	assertEqual(t, int(*imp.Secure), 0, "Request secure")
@@ -250,8 +251,8 @@ func TestConversantInappVideoRequest(t *testing.T) {
 	_, err = an.Call(ctx, pbReq, pbReq.Bidders[0])
 
 	imp := &lastReq.Imp[0]
-	assertEqual(t, int(imp.Video.W), 300, "Request width")
-	assertEqual(t, int(imp.Video.H), 250, "Request height")
+	assertEqual(t, int(*imp.Video.W), 300, "Request width")
+	assertEqual(t, int(*imp.Video.H), 250, "Request height")
 	assertEqual(t, lastReq.App.ID, "12345", "App Id")
 }
 
@@ -328,7 +329,7 @@ func TestConversantRequest(t *testing.T) {
 
 	assertEqual(t, imp.DisplayManager, ExpectedDisplayManager, "Request display manager value")
 	assertEqual(t, lastReq.Site.ID, ExpectedSiteID, "Request site id")
-	assertEqual(t, int(lastReq.Site.Mobile), 1, "Request site mobile flag")
+	assertEqual(t, int(*lastReq.Site.Mobile), 1, "Request site mobile flag")
 	assertEqual(t, lastReq.User.BuyerUID, ExpectedBuyerUID, "Request buyeruid")
 	assertTrue(t, imp.Video == nil, "Request video should be nil")
 	assertEqual(t, int(*imp.Secure), 1, "Request secure")
@@ -427,15 +428,15 @@ func TestConversantBasicVideoRequest(t *testing.T) {
 
 	assertEqual(t, imp.DisplayManager, ExpectedDisplayManager, "Request display manager value")
 	assertEqual(t, lastReq.Site.ID, ExpectedSiteID, "Request site id")
-	assertEqual(t, int(lastReq.Site.Mobile), 0, "Request site mobile flag")
+	assertTrue(t, lastReq.Site.Mobile == nil, "Request site mobile flag")
 	assertEqual(t, lastReq.User.BuyerUID, ExpectedBuyerUID, "Request buyeruid")
 	assertTrue(t, imp.Banner == nil, "Request banner should be nil")
 	assertEqual(t, int(*imp.Secure), 0, "Request secure")
 	assertEqual(t, imp.BidFloor, 1.01, "Request bid floor")
 	assertEqual(t, imp.TagID, "bottom left", "Request tag id")
 	assertEqual(t, int(*imp.Video.Pos), 3, "Request pos")
-	assertEqual(t, int(imp.Video.W), 300, "Request width")
-	assertEqual(t, int(imp.Video.H), 250, "Request height")
+	assertEqual(t, int(*imp.Video.W), 300, "Request width")
+	assertEqual(t, int(*imp.Video.H), 250, "Request height")
 
 	assertEqual(t, len(imp.Video.MIMEs), 1, "Request video MIMEs entries")
 	assertEqual(t, imp.Video.MIMEs[0], "video/mp4", "Requst video MIMEs type")
@@ -484,25 +485,25 @@ func TestConversantVideoRequestWithParams(t *testing.T) {
 
 	assertEqual(t, imp.DisplayManager, ExpectedDisplayManager, "Request display manager value")
 	assertEqual(t, lastReq.Site.ID, ExpectedSiteID, "Request site id")
-	assertEqual(t, int(lastReq.Site.Mobile), 0, "Request site mobile flag")
+	assertTrue(t, lastReq.Site.Mobile == nil, "Request site mobile flag")
 	assertEqual(t, lastReq.User.BuyerUID, ExpectedBuyerUID, "Request buyeruid")
 	assertTrue(t, imp.Banner == nil, "Request banner should be nil")
 	assertEqual(t, int(*imp.Secure), 0, "Request secure")
 	assertEqual(t, imp.BidFloor, 1.01, "Request bid floor")
 	assertEqual(t, imp.TagID, "bottom left", "Request tag id")
 	assertEqual(t, int(*imp.Video.Pos), 3, "Request pos")
-	assertEqual(t, int(imp.Video.W), 300, "Request width")
-	assertEqual(t, int(imp.Video.H), 250, "Request height")
+	assertEqual(t, int(*imp.Video.W), 300, "Request width")
+	assertEqual(t, int(*imp.Video.H), 250, "Request height")
 
 	assertEqual(t, len(imp.Video.MIMEs), 1, "Request video MIMEs entries")
 	assertEqual(t, imp.Video.MIMEs[0], "video/x-ms-wmv", "Requst video MIMEs type")
 	assertEqual(t, len(imp.Video.Protocols), 2, "Request video protocols")
-	assertEqual(t, imp.Video.Protocols[0], openrtb2.Protocol(1), "Request video protocols 1")
-	assertEqual(t, imp.Video.Protocols[1], openrtb2.Protocol(2), "Request video protocols 2")
+	assertEqual(t, imp.Video.Protocols[0], adcom1.MediaCreativeSubtype(1), "Request video protocols 1")
+	assertEqual(t, imp.Video.Protocols[1], adcom1.MediaCreativeSubtype(2), "Request video protocols 2")
 	assertEqual(t, imp.Video.MaxDuration, int64(90), "Request video 0 max duration")
 	assertEqual(t, len(imp.Video.API), 2, "Request video api should be nil")
-	assertEqual(t, imp.Video.API[0], openrtb2.APIFramework(1), "Request video api 1")
-	assertEqual(t, imp.Video.API[1], openrtb2.APIFramework(2), "Request video api 2")
+	assertEqual(t, imp.Video.API[0], adcom1.APIFramework(1), "Request video api 1")
+	assertEqual(t, imp.Video.API[1], adcom1.APIFramework(2), "Request video api 2")
 }
 
 // Test video request with parameters in the video object
@@ -546,19 +547,19 @@ func TestConversantVideoRequestWithParams2(t *testing.T) {
 
 	assertEqual(t, imp.DisplayManager, ExpectedDisplayManager, "Request display manager value")
 	assertEqual(t, lastReq.Site.ID, ExpectedSiteID, "Request site id")
-	assertEqual(t, int(lastReq.Site.Mobile), 0, "Request site mobile flag")
+	assertTrue(t, lastReq.Site.Mobile == nil, "Request site mobile flag")
 	assertEqual(t, lastReq.User.BuyerUID, ExpectedBuyerUID, "Request buyeruid")
 	assertTrue(t, imp.Banner == nil, "Request banner should be nil")
 	assertEqual(t, int(*imp.Secure), 0, "Request secure")
 	assertEqual(t, imp.BidFloor, 0.0, "Request bid floor")
-	assertEqual(t, int(imp.Video.W), 300, "Request width")
-	assertEqual(t, int(imp.Video.H), 250, "Request height")
+	assertEqual(t, int(*imp.Video.W), 300, "Request width")
+	assertEqual(t, int(*imp.Video.H), 250, "Request height")
 
 	assertEqual(t, len(imp.Video.MIMEs), 1, "Request video MIMEs entries")
 	assertEqual(t, imp.Video.MIMEs[0], "video/x-ms-wmv", "Requst video MIMEs type")
 	assertEqual(t, len(imp.Video.Protocols), 2, "Request video protocols")
-	assertEqual(t, imp.Video.Protocols[0], openrtb2.Protocol(1), "Request video protocols 1")
-	assertEqual(t, imp.Video.Protocols[1], openrtb2.Protocol(2), "Request video protocols 2")
+	assertEqual(t, imp.Video.Protocols[0], adcom1.MediaCreativeSubtype(1), "Request video protocols 1")
+	assertEqual(t, imp.Video.Protocols[1], adcom1.MediaCreativeSubtype(2), "Request video protocols 2")
 	assertEqual(t, imp.Video.MaxDuration, int64(90), "Request video 0 max duration")
 }
 
@@ -608,8 +609,8 @@ func TestConversantVideoResponse(t *testing.T) {
 			assertEqual(t, bid.Adm, "", "Bad ad markup in response")
 			assertEqual(t, bid.NURL, ExpectedAdM, "Bad notification url in response")
 			assertEqual(t, bid.Creative_id, ExpectedCrID, "Bad creative id in response")
-			assertEqual(t, bid.Width, imps[i].Video.W, "Bad width in response")
-			assertEqual(t, bid.Height, imps[i].Video.H, "Bad height in response")
+			assertEqual(t, bid.Width, *imps[i].Video.W, "Bad width in response")
+			assertEqual(t, bid.Height, *imps[i].Video.H, "Bad height in response")
 		}
 	}
 }
@@ -771,8 +772,8 @@ func CreateServer(prices ...float64) (*httptest.Server, *openrtb2.BidRequest) {
 					bid.W = *imp.Banner.W
 					bid.H = *imp.Banner.H
 				} else if imp.Video != nil {
-					bid.W = imp.Video.W
-					bid.H = imp.Video.H
+					bid.W = *imp.Video.W
+					bid.H = *imp.Video.H
 				}
 			} else {
 				bid = openrtb2.Bid{
@@ -7,26 +7,14 @@ import (
 	"github.com/prebid/prebid-server/config"
 	"net/http"
 
-	openrtb "github.com/mxmCherry/openrtb/v15/openrtb2"
+	openrtb "github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/cache/skanidlist"
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/openrtb_ext"
 	"github.com/prebid/prebid-server/pbs"
 )
 
-// Region ...
-type Region string
-
-const (
-	USEast Region = "us_east"
-	USWest Region = "us_west"
-)
-
-// SKAN IDs must be lower case
-var crossinstallSKADNetIDs = map[string]bool{
-	"prcb7njmu6.skadnetwork": true,
-}
-
 type crossinstallImpExt struct {
 	Reward int                `json:"reward"`
 	SKADN  *openrtb_ext.SKADN `json:"skadn,omitempty"`
@@ -39,9 +27,8 @@ type crossinstallBannerExt struct {
 
 // CrossInstallAdapter ...
 type adapter struct {
-	http             *adapters.HTTPAdapter
-	endpoint         string
-	SupportedRegions map[Region]string
+	http    *adapters.HTTPAdapter
+	regions *adapters.RegionResolver
 }
 
 func (adapter *adapter) Name() string {
@@ -58,11 +45,7 @@ func (adapter *adapter) Call(_ context.Context, _ *pbs.PBSRequest, _ *pbs.PBSBid
 
 func Builder(_ openrtb_ext.BidderName, config config.Adapter) (adapters.Bidder, error) {
 	bidder := &adapter{
-		endpoint: config.Endpoint,
-		SupportedRegions: map[Region]string{
-			USEast: config.XAPI.EndpointUSEast,
-			USWest: config.XAPI.EndpointUSWest,
-		},
+		regions: adapters.NewRegionResolverFromConfig(config),
 	}
 	return bidder, nil
 }
@@ -73,17 +56,16 @@ func NewCrossInstallLegacyAdapter(config *adapters.HTTPAdapterConfig, uri, useas
 
 func NewCrossInstallBidder(client *http.Client, uri, useast, uswest string) *adapter {
 	return &adapter{
-		http:     &adapters.HTTPAdapter{Client: client},
-		endpoint: uri,
-		SupportedRegions: map[Region]string{
-			USEast: useast,
-			USWest: uswest,
-		},
+		http: &adapters.HTTPAdapter{Client: client},
+		regions: adapters.NewRegionResolver(uri, map[string]config.AdapterRegion{
+			config.RegionUSEast: {Endpoint: useast},
+			config.RegionUSWest: {Endpoint: uswest},
+		}, ""),
 	}
 }
 
 // MakeRequests ...
-func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
 	numRequests := len(request.Imp)
 
 	requestData := make([]*adapters.RequestData, 0, numRequests)
@@ -105,6 +87,7 @@ func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.Ex
 
 		// clone current imp
 		thisImp := requestImpCopy[i]
+		placement := reqInfo.Placement(&thisImp)
 
 		// extract bidder extension
 		var bidderExt adapters.ExtImpBidder
@@ -124,10 +107,7 @@ func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.Ex
 			continue
 		}
 
-		placementType := adapters.Interstitial
-		if crossinstallExt.Reward == 1 {
-			placementType = adapters.Rewarded
-		}
+		placementType := placement.FullscreenType()
 
 		if thisImp.Banner != nil {
 			if crossinstallExt.MRAIDSupported {
@@ -150,13 +130,14 @@ func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.Ex
 		}
 
 		impExt := crossinstallImpExt{
-			Reward: crossinstallExt.Reward,
+			Reward: placement.Reward(),
 		}
 
 		// Add SKADN if supported and present
 		if crossinstallExt.SKADNSupported {
-			skadn := adapters.FilterPrebidSKADNExt(bidderExt.Prebid, crossinstallSKADNetIDs)
-			if len(skadn.SKADNetIDs) > 0 {
+			skanIDList := skanidlist.Get(openrtb_ext.BidderCrossInstall)
+			skadn := adapters.FilterPrebidSKADNExt(bidderExt.Prebid, skanIDList)
+			if adapters.SKADNRequested(skadn, skanIDList) {
 				skanSent = true
 				impExt.SKADN = &skadn
 			}
@@ -178,25 +159,21 @@ func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.Ex
 			continue
 		}
 
-		// assign the default uri
-		uri := adapter.endpoint
-
-		// assign a region based uri if it exists
-		if endpoint, ok := adapter.SupportedRegions[Region(crossinstallExt.Region)]; ok {
-			uri = endpoint
-		}
+		region := adapter.regions.Resolve(crossinstallExt.Region, request)
 
 		// build request data object
 		reqData := &adapters.RequestData{
-			Method:  "POST",
-			Uri:     uri,
-			Body:    reqJSON,
-			Headers: headers,
+			Method:         "POST",
+			Uri:            region.Uri,
+			Body:           reqJSON,
+			Headers:        headers,
+			FallbackUri:    region.FallbackUri,
+			FallbackRegion: region.FallbackRegion,
 
 			TapjoyData: adapters.TapjoyData{
 				Bidder:        adapter.Name(),
-				PlacementType: placementType,
-				Region:        crossinstallExt.Region,
+				PlacementType: placement.Type,
+				Region:        region.Region,
 				SKAN: adapters.SKAN{
 					Supported: crossinstallExt.SKADNSupported,
 					Sent:      skanSent,
@@ -262,6 +239,7 @@ func (adapter *adapter) MakeBids(_ *openrtb.BidRequest, externalRequest *adapter
 				bidResponse.Bids = append(bidResponse.Bids, &adapters.TypedBid{
 					Bid:     &b,
 					BidType: bidType,
+					Seat:    sb.Seat,
 				})
 			}
 		}
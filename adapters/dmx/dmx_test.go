@@ -5,7 +5,7 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/openrtb_ext"
@@ -381,8 +381,8 @@ func TestMakeBidVideo(t *testing.T) {
 		ID:  "imp1",
 		Ext: json.RawMessage("{\"bidder\":{\"dmxid\": \"1007\", \"memberid\": \"123456\", \"seller_id\":\"1008\"}}"),
 		Video: &openrtb2.Video{
-			W:     width,
-			H:     height,
+			W:     &width,
+			H:     &height,
 			MIMEs: []string{"video/mp4"},
 		}}
 
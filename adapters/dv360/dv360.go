@@ -8,7 +8,7 @@ import (
 
 	"github.com/prebid/prebid-server/config"
 
-	openrtb "github.com/mxmCherry/openrtb/v15/openrtb2"
+	openrtb "github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/openrtb_ext"
@@ -78,7 +78,7 @@ func NewDV360Bidder(client *http.Client, uri string) *adapter {
 	}
 }
 
-func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
 	// number of requests
 	numRequests := len(request.Imp)
 
@@ -110,6 +110,7 @@ func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.Ex
 	for i := 0; i < numRequests; i++ {
 		// clone current imp
 		impCopy := requestImpCopy[i]
+		placement := reqInfo.Placement(&impCopy)
 
 		// extract bidder extension
 		var bidderExt adapters.ExtImpBidder
@@ -168,11 +169,6 @@ func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.Ex
 
 		request.Device = &requestDeviceCopy
 
-		rewarded := 0
-		if dv360Ext.Reward == 1 {
-			rewarded = 1
-		}
-
 		// if there is a banner object
 		if impCopy.Banner != nil {
 			// check if mraid is supported for this dsp
@@ -189,7 +185,7 @@ func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.Ex
 
 			// instantiate dv360 video extension
 			videoExt := dv360VideoExt{
-				Rewarded: rewarded,
+				Rewarded: placement.Reward(),
 			}
 
 			// convert dv360 video extension to json
@@ -245,8 +241,9 @@ func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.Ex
 			Headers: headers,
 
 			TapjoyData: adapters.TapjoyData{
-				Bidder: adapter.Name(),
-				Region: dv360Ext.Region,
+				Bidder:        adapter.Name(),
+				PlacementType: placement.Type,
+				Region:        dv360Ext.Region,
 				SKAN: adapters.SKAN{
 					Supported: dv360Ext.SKADNSupported,
 					Sent:      false,
@@ -311,6 +308,7 @@ func (adapter *adapter) MakeBids(_ *openrtb.BidRequest, externalRequest *adapter
 				bidResponse.Bids = append(bidResponse.Bids, &adapters.TypedBid{
 					Bid:     &b,
 					BidType: bidType,
+					Seat:    sb.Seat,
 				})
 			}
 		}
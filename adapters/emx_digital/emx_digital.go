@@ -9,7 +9,8 @@ import (
 	"strings"
 	"time"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/errortypes"
@@ -146,7 +147,7 @@ func buildImpVideo(imp *openrtb2.Imp) error {
 		}
 	}
 
-	if imp.Video.H == 0 && imp.Video.W == 0 {
+	if (imp.Video.H == nil || *imp.Video.H == 0) && (imp.Video.W == nil || *imp.Video.W == 0) {
 		return &errortypes.BadInput{
 			Message: fmt.Sprintf("Video: Need at least one size to build request"),
 		}
@@ -162,11 +163,11 @@ func buildImpVideo(imp *openrtb2.Imp) error {
 }
 
 // not supporting VAST protocol 7 (VAST 4.0);
-func cleanProtocol(protocols []openrtb2.Protocol) []openrtb2.Protocol {
-	newitems := make([]openrtb2.Protocol, 0, len(protocols))
+func cleanProtocol(protocols []adcom1.MediaCreativeSubtype) []adcom1.MediaCreativeSubtype {
+	newitems := make([]adcom1.MediaCreativeSubtype, 0, len(protocols))
 
 	for _, i := range protocols {
-		if i != openrtb2.ProtocolVAST40 {
+		if i != adcom1.CreativeVAST40 {
 			newitems = append(newitems, i)
 		}
 	}
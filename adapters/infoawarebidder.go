@@ -3,7 +3,8 @@ package adapters
 import (
 	"fmt"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/buger/jsonparser"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/openrtb_ext"
@@ -18,7 +19,9 @@ import (
 //   2. If a given MediaType is not supported for the platform, then it will be set
 //      to nil before the request is forwarded to the delegate.
 //   3. Any Imps which have no MediaTypes left will be removed.
-//   4. If there are no valid Imps left, the delegate won't be called at all.
+//   4. If the bidder is an MRAID seat, see config.AdapterSeat, then the videos and the banners
+//      of the Imps not flagged imp.ext.bidder.mraid_supported will be set to nil.
+//   5. If there are no valid Imps left, the delegate won't be called at all.
 type InfoAwareBidder struct {
 	Bidder
 	info parsedBidderInfo
@@ -53,7 +56,7 @@ func (i *InfoAwareBidder) MakeRequests(request *openrtb2.BidRequest, reqInfo *Ex
 	//
 	// To avoid allocating new arrays and copying in the normal case, we'll make one pass to
 	// see if any imps need to be removed, and another to do the removing if necessary.
-	numToFilter, errs := pruneImps(request.Imp, allowedMediaTypes)
+	numToFilter, errs := pruneImps(request.Imp, allowedMediaTypes, i.info.mraid)
 
 	// If all imps in bid request come with unsupported media types, exit
 	if numToFilter == len(request.Imp) {
@@ -72,10 +75,16 @@ func (i *InfoAwareBidder) MakeRequests(request *openrtb2.BidRequest, reqInfo *Ex
 
 // pruneImps trims invalid media types from each imp, and returns true if any of the
 // Imps have _no_ valid Media Types left.
-func pruneImps(imps []openrtb2.Imp, allowedTypes parsedSupports) (int, []error) {
+func pruneImps(imps []openrtb2.Imp, allowedTypes parsedSupports, mraid bool) (int, []error) {
 	numToFilter := 0
 	var errs []error
 	for i := 0; i < len(imps); i++ {
+		if mraid {
+			imps[i].Video = nil
+			if mraidSupported, _ := jsonparser.GetBoolean(imps[i].Ext, "bidder", "mraid_supported"); !mraidSupported {
+				imps[i].Banner = nil
+			}
+		}
 		if !allowedTypes.banner && imps[i].Banner != nil {
 			imps[i].Banner = nil
 			errs = append(errs, &errortypes.BadInput{Message: fmt.Sprintf("request.imp[%d] uses banner, but this bidder doesn't support it", i)})
@@ -134,8 +143,9 @@ func filterImps(imps []openrtb2.Imp, numToFilter int) ([]openrtb2.Imp, []error)
 
 // Structs to handle parsed bidder info, so we aren't reparsing every request
 type parsedBidderInfo struct {
-	app  parsedSupports
-	site parsedSupports
+	app   parsedSupports
+	site  parsedSupports
+	mraid bool
 }
 
 type parsedSupports struct {
@@ -156,5 +166,6 @@ func parseBidderInfo(info config.BidderInfo) parsedBidderInfo {
 		parsedInfo.site.enabled = true
 		parsedInfo.site.banner, parsedInfo.site.video, parsedInfo.site.audio, parsedInfo.site.native = parseAllowedTypes(info.Capabilities.Site.MediaTypes)
 	}
+	parsedInfo.mraid = info.Seat != nil && info.Seat.MRAID
 	return parsedInfo
 }
@@ -1,10 +1,11 @@
 package adapters_test
 
 import (
+	"encoding/json"
 	"errors"
 	"testing"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/errortypes"
@@ -177,12 +178,48 @@ func TestImpFiltering(t *testing.T) {
 	}
 }
 
+func TestMRAIDSeatImpFiltering(t *testing.T) {
+	bidder := &mockBidder{}
+	info := config.BidderInfo{
+		Capabilities: &config.CapabilitiesInfo{
+			App: &config.PlatformInfo{
+				MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeBanner, openrtb_ext.BidTypeVideo},
+			},
+		},
+		Seat: &config.AdapterSeat{Of: "liftoff", MRAID: true},
+	}
+
+	constrained := adapters.BuildInfoAwareBidder(bidder, info)
+
+	request := &openrtb2.BidRequest{
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Video: &openrtb2.Video{}, Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{"mraid_supported":true}}`)},
+			{ID: "imp-2", Video: &openrtb2.Video{}, Banner: &openrtb2.Banner{}, Ext: json.RawMessage(`{"bidder":{}}`)},
+			{ID: "imp-3", Video: &openrtb2.Video{}, Ext: json.RawMessage(`{"bidder":{"mraid_supported":true}}`)},
+		},
+		App: &openrtb2.App{},
+	}
+	requests, errs := constrained.MakeRequests(request, &adapters.ExtraRequestInfo{})
+
+	assert.Len(t, requests, 1)
+	assert.Equal(t, []error{
+		&errortypes.BadInput{Message: "request.imp[1] has no supported MediaTypes. It will be ignored"},
+		&errortypes.BadInput{Message: "request.imp[2] has no supported MediaTypes. It will be ignored"},
+	}, errs)
+	if assert.Len(t, bidder.gotRequest.Imp, 1) {
+		assert.Equal(t, "imp-1", bidder.gotRequest.Imp[0].ID)
+		assert.NotNil(t, bidder.gotRequest.Imp[0].Banner)
+		assert.Nil(t, bidder.gotRequest.Imp[0].Video, "the MRAID seat bids on the banners only")
+	}
+}
+
 type mockBidder struct {
 	gotRequest *openrtb2.BidRequest
 }
 
 func (m *mockBidder) MakeRequests(request *openrtb2.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
 	var adapterRequests []*adapters.RequestData
+	m.gotRequest = request
 
 	for i := 0; i < len(request.Imp); i++ {
 		adapterRequests = append(adapterRequests, &adapters.RequestData{})
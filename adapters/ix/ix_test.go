@@ -11,7 +11,8 @@ import (
 	"testing"
 	"time"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/adapters/adapterstest"
 	"github.com/prebid/prebid-server/config"
@@ -730,11 +731,11 @@ func TestIxMakeBidsWithCategoryDuration(t *testing.T) {
 		Imp: []openrtb2.Imp{{
 			ID: "1_1",
 			Video: &openrtb2.Video{
-				W:           640,
-				H:           360,
+				W:           openrtb2.Int64Ptr(640),
+				H:           openrtb2.Int64Ptr(360),
 				MIMEs:       []string{"video/mp4"},
 				MaxDuration: 60,
-				Protocols:   []openrtb2.Protocol{2, 3, 5, 6},
+				Protocols:   []adcom1.MediaCreativeSubtype{2, 3, 5, 6},
 			},
 			Ext: json.RawMessage(
 				`{
@@ -8,22 +8,14 @@ import (
 	"github.com/prebid/prebid-server/config"
 	"net/http"
 
-	openrtb "github.com/mxmCherry/openrtb/v15/openrtb2"
+	openrtb "github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/cache/skanidlist"
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/openrtb_ext"
 	"github.com/prebid/prebid-server/pbs"
 )
 
-// Region ...
-type Region string
-
-const (
-	USEast Region = "us_east"
-	EU     Region = "eu"
-	APAC   Region = "apac"
-)
-
 // Orientation ...
 type Orientation string
 
@@ -32,15 +24,9 @@ const (
 	Vertical   Orientation = "v"
 )
 
-// SKAN IDs must be lower case
-var liftoffSKADNetIDs = map[string]bool{
-	"7ug5zh24hu.skadnetwork": true,
-}
-
 type adapter struct {
-	http             *adapters.HTTPAdapter
-	endpoint         string
-	SupportedRegions map[Region]string
+	http    *adapters.HTTPAdapter
+	regions *adapters.RegionResolver
 }
 
 func (a *adapter) Name() string {
@@ -83,12 +69,7 @@ func (a *adapter) Call(ctx context.Context, req *pbs.PBSRequest, bidder *pbs.PBS
 
 func Builder(_ openrtb_ext.BidderName, config config.Adapter) (adapters.Bidder, error) {
 	bidder := &adapter{
-		endpoint: config.Endpoint,
-		SupportedRegions: map[Region]string{
-			USEast: config.XAPI.EndpointUSEast,
-			EU:     config.XAPI.EndpointEU,
-			APAC:   config.XAPI.EndpointAPAC,
-		},
+		regions: adapters.NewRegionResolverFromConfig(config),
 	}
 	return bidder, nil
 }
@@ -99,13 +80,12 @@ func NewLiftoffLegacyAdapter(config *adapters.HTTPAdapterConfig, uri string, use
 
 func NewLiftoffBidder(client *http.Client, uri string, useast string, eu string, apac string) *adapter {
 	return &adapter{
-		http:     &adapters.HTTPAdapter{Client: client},
-		endpoint: uri,
-		SupportedRegions: map[Region]string{
-			USEast: useast,
-			EU:     eu,
-			APAC:   apac,
-		},
+		http: &adapters.HTTPAdapter{Client: client},
+		regions: adapters.NewRegionResolver(uri, map[string]config.AdapterRegion{
+			config.RegionUSEast: {Endpoint: useast},
+			config.RegionEU:     {Endpoint: eu},
+			config.RegionAPAC:   {Endpoint: apac},
+		}, ""),
 	}
 }
 
@@ -139,6 +119,7 @@ func (a *adapter) MakeRequests(request *openrtb.BidRequest, reqInfo *adapters.Ex
 		skanSent := false
 
 		thisImp := requestImpCopy[i]
+		placement := reqInfo.Placement(&thisImp)
 
 		var bidderExt adapters.ExtImpBidder
 		if err = json.Unmarshal(thisImp.Ext, &bidderExt); err != nil {
@@ -156,17 +137,11 @@ func (a *adapter) MakeRequests(request *openrtb.BidRequest, reqInfo *adapters.Ex
 			continue
 		}
 
-		// default is interstitial
-		placementType := adapters.Interstitial
-		rewarded := 0
-		if liftoffExt.Video.Skip == 0 {
-			placementType = adapters.Rewarded
-			rewarded = 1
-		}
+		placementType := placement.FullscreenType()
 
 		if thisImp.Video != nil {
 			orientation := Horizontal
-			if liftoffExt.Video.Width < liftoffExt.Video.Height {
+			if placement.Orientation == adapters.Portrait {
 				orientation = Vertical
 			}
 
@@ -206,12 +181,13 @@ func (a *adapter) MakeRequests(request *openrtb.BidRequest, reqInfo *adapters.Ex
 		}
 
 		impExt := liftoffImpExt{
-			Rewarded: rewarded,
+			Rewarded: placement.Reward(),
 		}
 		// Add SKADN if supported and present
 		if liftoffExt.SKADNSupported {
-			skadn := adapters.FilterPrebidSKADNExt(bidderExt.Prebid, liftoffSKADNetIDs)
-			if len(skadn.SKADNetIDs) > 0 {
+			skanIDList := skanidlist.Get(openrtb_ext.BidderLiftoff)
+			skadn := adapters.FilterPrebidSKADNExt(bidderExt.Prebid, skanIDList)
+			if adapters.SKADNRequested(skadn, skanIDList) {
 				skanSent = true
 				impExt.SKADN = &skadn
 			}
@@ -233,22 +209,20 @@ func (a *adapter) MakeRequests(request *openrtb.BidRequest, reqInfo *adapters.Ex
 			continue
 		}
 
-		uri := a.endpoint
-
-		if endpoint, ok := a.SupportedRegions[Region(liftoffExt.Region)]; ok {
-			uri = endpoint
-		}
+		region := a.regions.Resolve(liftoffExt.Region, request)
 
 		reqData := &adapters.RequestData{
-			Method:  "POST",
-			Uri:     uri,
-			Body:    reqJSON,
-			Headers: headers,
+			Method:         "POST",
+			Uri:            region.Uri,
+			Body:           reqJSON,
+			Headers:        headers,
+			FallbackUri:    region.FallbackUri,
+			FallbackRegion: region.FallbackRegion,
 
 			TapjoyData: adapters.TapjoyData{
 				Bidder:        a.Name(),
-				PlacementType: placementType,
-				Region:        liftoffExt.Region,
+				PlacementType: placement.Type,
+				Region:        region.Region,
 				SKAN: adapters.SKAN{
 					Supported: liftoffExt.SKADNSupported,
 					Sent:      skanSent,
@@ -318,6 +292,7 @@ func (a *adapter) MakeBids(internalRequest *openrtb.BidRequest, externalRequest
 				bidResponse.Bids = append(bidResponse.Bids, &adapters.TypedBid{
 					Bid:     &b,
 					BidType: bidType,
+					Seat:    sb.Seat,
 				})
 			}
 		}
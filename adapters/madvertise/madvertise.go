@@ -6,7 +6,8 @@ import (
 	"net/http"
 	"text/template"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/errortypes"
@@ -149,13 +150,13 @@ func (a *adapter) MakeBids(request *openrtb2.BidRequest, requestData *adapters.R
 	return bidResponse, nil
 }
 
-func getMediaTypeForBid(attr []openrtb2.CreativeAttribute) openrtb_ext.BidType {
+func getMediaTypeForBid(attr []adcom1.CreativeAttribute) openrtb_ext.BidType {
 	for i := 0; i < len(attr); i++ {
-		if attr[i] == openrtb2.CreativeAttribute(16) {
+		if attr[i] == adcom1.CreativeAttribute(16) {
 			return openrtb_ext.BidTypeVideo
-		} else if attr[i] == openrtb2.CreativeAttribute(6) {
+		} else if attr[i] == adcom1.CreativeAttribute(6) {
 			return openrtb_ext.BidTypeVideo
-		} else if attr[i] == openrtb2.CreativeAttribute(7) {
+		} else if attr[i] == adcom1.CreativeAttribute(7) {
 			return openrtb_ext.BidTypeVideo
 		}
 	}
@@ -7,27 +7,14 @@ import (
 	"github.com/prebid/prebid-server/config"
 	"net/http"
 
-	openrtb "github.com/mxmCherry/openrtb/v15/openrtb2"
+	openrtb "github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/cache/skanidlist"
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/openrtb_ext"
 	"github.com/prebid/prebid-server/pbs"
 )
 
-// Region ...
-type Region string
-
-const (
-	USEast Region = "us_east"
-	EU     Region = "eu"
-	APAC   Region = "apac"
-)
-
-// SKAN IDs must be lower case
-var molocoSKADNetIDs = map[string]bool{
-	"9t245vhmpl.skadnetwork": true,
-}
-
 type molocoVideoExt struct {
 	PlacementType adapters.PlacementType `json:"placementtype"`
 }
@@ -42,9 +29,8 @@ type molocoImpExt struct {
 }
 
 type adapter struct {
-	http             *adapters.HTTPAdapter
-	endpoint         string
-	SupportedRegions map[Region]string
+	http    *adapters.HTTPAdapter
+	regions *adapters.RegionResolver
 }
 
 func (adapter *adapter) Name() string {
@@ -61,12 +47,7 @@ func (adapter *adapter) Call(_ context.Context, _ *pbs.PBSRequest, _ *pbs.PBSBid
 
 func Builder(_ openrtb_ext.BidderName, config config.Adapter) (adapters.Bidder, error) {
 	bidder := &adapter{
-		endpoint: config.Endpoint,
-		SupportedRegions: map[Region]string{
-			USEast: config.XAPI.EndpointUSEast,
-			EU:     config.XAPI.EndpointEU,
-			APAC:   config.XAPI.EndpointAPAC,
-		},
+		regions: adapters.NewRegionResolverFromConfig(config),
 	}
 	return bidder, nil
 }
@@ -79,18 +60,17 @@ func NewMolocoLegacyAdapter(config *adapters.HTTPAdapterConfig, uri, useast, eu,
 // NewMolocoBidder ...
 func NewMolocoBidder(client *http.Client, uri, useast, eu, apac string) *adapter {
 	return &adapter{
-		http:     &adapters.HTTPAdapter{Client: client},
-		endpoint: uri,
-		SupportedRegions: map[Region]string{
-			USEast: useast,
-			EU:     eu,
-			APAC:   apac,
-		},
+		http: &adapters.HTTPAdapter{Client: client},
+		regions: adapters.NewRegionResolver(uri, map[string]config.AdapterRegion{
+			config.RegionUSEast: {Endpoint: useast},
+			config.RegionEU:     {Endpoint: eu},
+			config.RegionAPAC:   {Endpoint: apac},
+		}, ""),
 	}
 }
 
 // MakeRequests ...
-func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
 	numRequests := len(request.Imp)
 
 	requestData := make([]*adapters.RequestData, 0, numRequests)
@@ -112,6 +92,7 @@ func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.Ex
 
 		// clone current imp
 		thisImp := requestImpCopy[i]
+		placement := reqInfo.Placement(&thisImp)
 
 		// extract bidder extension
 		var bidderExt adapters.ExtImpBidder
@@ -132,10 +113,7 @@ func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.Ex
 		}
 
 		// placement type is either Rewarded or Interstitial, default is Interstitial
-		placementType := adapters.Interstitial
-		if molocoExt.PlacementType == string(adapters.Rewarded) {
-			placementType = adapters.Rewarded
-		}
+		placementType := placement.FullscreenType()
 
 		if thisImp.Video != nil {
 			// instantiate moloco video extension struct
@@ -182,8 +160,9 @@ func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.Ex
 
 		// Add SKADN if supported and present
 		if molocoExt.SKADNSupported {
-			skadn := adapters.FilterPrebidSKADNExt(bidderExt.Prebid, molocoSKADNetIDs)
-			if len(skadn.SKADNetIDs) > 0 {
+			skanIDList := skanidlist.Get(openrtb_ext.BidderMoloco)
+			skadn := adapters.FilterPrebidSKADNExt(bidderExt.Prebid, skanIDList)
+			if adapters.SKADNRequested(skadn, skanIDList) {
 				impExt.SKADN = &skadn
 				skanSent = true
 			}
@@ -207,25 +186,21 @@ func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.Ex
 			continue
 		}
 
-		// assign the default uri
-		uri := adapter.endpoint
-
-		// assign a region based uri if it exists
-		if endpoint, ok := adapter.SupportedRegions[Region(molocoExt.Region)]; ok {
-			uri = endpoint
-		}
+		region := adapter.regions.Resolve(molocoExt.Region, request)
 
 		// build request data object
 		reqData := &adapters.RequestData{
-			Method:  "POST",
-			Uri:     uri,
-			Body:    reqJSON,
-			Headers: headers,
+			Method:         "POST",
+			Uri:            region.Uri,
+			Body:           reqJSON,
+			Headers:        headers,
+			FallbackUri:    region.FallbackUri,
+			FallbackRegion: region.FallbackRegion,
 
 			TapjoyData: adapters.TapjoyData{
 				Bidder:        adapter.Name(),
-				PlacementType: placementType,
-				Region:        molocoExt.Region,
+				PlacementType: placement.Type,
+				Region:        region.Region,
 				SKAN: adapters.SKAN{
 					Supported: molocoExt.SKADNSupported,
 					Sent:      skanSent,
@@ -300,6 +275,7 @@ func (adapter *adapter) MakeBids(_ *openrtb.BidRequest, externalRequest *adapter
 				bidResponse.Bids = append(bidResponse.Bids, &adapters.TypedBid{
 					Bid:     &b,
 					BidType: bidType,
+					Seat:    sb.Seat,
 				})
 			}
 		}
@@ -7,27 +7,14 @@ import (
 	"github.com/prebid/prebid-server/config"
 	"net/http"
 
-	openrtb "github.com/mxmCherry/openrtb/v15/openrtb2"
+	openrtb "github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/cache/skanidlist"
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/openrtb_ext"
 	"github.com/prebid/prebid-server/pbs"
 )
 
-// Region ...
-type Region string
-
-const (
-	USEast Region = "us_east"
-	EU     Region = "eu"
-	APAC   Region = "apac"
-)
-
-// SKAN IDs must be lower case
-var molocoCloudSKADNetIDs = map[string]bool{
-	"9t245vhmpl.skadnetwork": true,
-}
-
 type molocoCloudVideoExt struct {
 	PlacementType adapters.PlacementType `json:"placementtype"`
 }
@@ -42,9 +29,8 @@ type molocoCloudImpExt struct {
 }
 
 type adapter struct {
-	http             *adapters.HTTPAdapter
-	endpoint         string
-	SupportedRegions map[Region]string
+	http    *adapters.HTTPAdapter
+	regions *adapters.RegionResolver
 }
 
 func (adapter *adapter) Name() string {
@@ -61,12 +47,7 @@ func (adapter *adapter) Call(_ context.Context, _ *pbs.PBSRequest, _ *pbs.PBSBid
 
 func Builder(_ openrtb_ext.BidderName, config config.Adapter) (adapters.Bidder, error) {
 	bidder := &adapter{
-		endpoint: config.Endpoint,
-		SupportedRegions: map[Region]string{
-			USEast: config.XAPI.EndpointUSEast,
-			EU:     config.XAPI.EndpointEU,
-			APAC:   config.XAPI.EndpointAPAC,
-		},
+		regions: adapters.NewRegionResolverFromConfig(config),
 	}
 	return bidder, nil
 }
@@ -79,18 +60,17 @@ func NewMolocoCloudLegacyAdapter(config *adapters.HTTPAdapterConfig, endpoint, u
 // NewMolocoCloudBidder ...
 func NewMolocoCloudBidder(client *http.Client, endpoint, useast, eu, apac string) *adapter {
 	return &adapter{
-		http:     &adapters.HTTPAdapter{Client: client},
-		endpoint: endpoint,
-		SupportedRegions: map[Region]string{
-			USEast: useast,
-			EU:     eu,
-			APAC:   apac,
-		},
+		http: &adapters.HTTPAdapter{Client: client},
+		regions: adapters.NewRegionResolver(endpoint, map[string]config.AdapterRegion{
+			config.RegionUSEast: {Endpoint: useast},
+			config.RegionEU:     {Endpoint: eu},
+			config.RegionAPAC:   {Endpoint: apac},
+		}, ""),
 	}
 }
 
 // MakeRequests ...
-func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
 	numRequests := len(request.Imp)
 
 	requestData := make([]*adapters.RequestData, 0, numRequests)
@@ -110,6 +90,7 @@ func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.Ex
 	for i := 0; i < numRequests; i++ {
 		// clone current imp
 		thisImp := requestImpCopy[i]
+		placement := reqInfo.Placement(&thisImp)
 
 		// extract bidder extension
 		var bidderExt adapters.ExtImpBidder
@@ -130,10 +111,7 @@ func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.Ex
 		}
 
 		// placement type is either Rewarded or Interstitial, default is Interstitial
-		placementType := adapters.Interstitial
-		if molocoCloudExt.PlacementType == string(adapters.Rewarded) {
-			placementType = adapters.Rewarded
-		}
+		placementType := placement.FullscreenType()
 
 		if thisImp.Video != nil {
 			// instantiate moloco cloud video extension struct
@@ -180,8 +158,9 @@ func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.Ex
 
 		// Add SKADN if supported and present=
 		if molocoCloudExt.SKADNSupported {
-			skadn := adapters.FilterPrebidSKADNExt(bidderExt.Prebid, molocoCloudSKADNetIDs)
-			if len(skadn.SKADNetIDs) > 0 {
+			skanIDList := skanidlist.Get(openrtb_ext.BidderMolocoCloud)
+			skadn := adapters.FilterPrebidSKADNExt(bidderExt.Prebid, skanIDList)
+			if adapters.SKADNRequested(skadn, skanIDList) {
 				impExt.SKADN = &skadn
 			}
 		}
@@ -204,25 +183,21 @@ func (adapter *adapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.Ex
 			continue
 		}
 
-		// assign the default uri
-		uri := adapter.endpoint
-
-		// assign a region based uri if it exists
-		if endpoint, ok := adapter.SupportedRegions[Region(molocoCloudExt.Region)]; ok {
-			uri = endpoint
-		}
+		region := adapter.regions.Resolve(molocoCloudExt.Region, request)
 
 		// build request data object
 		reqData := &adapters.RequestData{
-			Method:  "POST",
-			Uri:     uri,
-			Body:    reqJSON,
-			Headers: headers,
+			Method:         "POST",
+			Uri:            region.Uri,
+			Body:           reqJSON,
+			Headers:        headers,
+			FallbackUri:    region.FallbackUri,
+			FallbackRegion: region.FallbackRegion,
 
 			TapjoyData: adapters.TapjoyData{
 				Bidder:        adapter.Name(),
-				PlacementType: placementType,
-				Region:        molocoCloudExt.Region,
+				PlacementType: placement.Type,
+				Region:        region.Region,
 				SKAN: adapters.SKAN{
 					Supported: molocoCloudExt.SKADNSupported,
 					Sent:      false,
@@ -297,6 +272,7 @@ func (adapter *adapter) MakeBids(_ *openrtb.BidRequest, externalRequest *adapter
 				bidResponse.Bids = append(bidResponse.Bids, &adapters.TypedBid{
 					Bid:     &b,
 					BidType: bidType,
+					Seat:    sb.Seat,
 				})
 			}
 		}
@@ -4,7 +4,8 @@ import (
 	"encoding/json"
 	"strings"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/openrtb_ext"
 	"github.com/prebid/prebid-server/pbs"
@@ -16,6 +17,8 @@ type PlacementType string
 const (
 	Interstitial PlacementType = "interstitial"
 	Rewarded     PlacementType = "rewarded"
+	Banner       PlacementType = "banner"
+	Native       PlacementType = "native"
 )
 
 func min(x, y int) int {
@@ -62,21 +65,21 @@ func makeVideo(unit pbs.PBSAdUnit) *openrtb2.Video {
 	}
 	mimes := make([]string, len(unit.Video.Mimes))
 	copy(mimes, unit.Video.Mimes)
-	pbm := make([]openrtb2.PlaybackMethod, 1)
+	pbm := make([]adcom1.PlaybackMethod, 1)
 	//this will become int8 soon, so we only care about the first index in the array
-	pbm[0] = openrtb2.PlaybackMethod(unit.Video.PlaybackMethod)
+	pbm[0] = adcom1.PlaybackMethod(unit.Video.PlaybackMethod)
 
-	protocols := make([]openrtb2.Protocol, 0, len(unit.Video.Protocols))
+	protocols := make([]adcom1.MediaCreativeSubtype, 0, len(unit.Video.Protocols))
 	for _, protocol := range unit.Video.Protocols {
-		protocols = append(protocols, openrtb2.Protocol(protocol))
+		protocols = append(protocols, adcom1.MediaCreativeSubtype(protocol))
 	}
 	return &openrtb2.Video{
 		MIMEs:          mimes,
 		MinDuration:    unit.Video.Minduration,
 		MaxDuration:    unit.Video.Maxduration,
-		W:              unit.Sizes[0].W,
-		H:              unit.Sizes[0].H,
-		StartDelay:     openrtb2.StartDelay(unit.Video.Startdelay).Ptr(),
+		W:              openrtb2.Int64Ptr(unit.Sizes[0].W),
+		H:              openrtb2.Int64Ptr(unit.Sizes[0].H),
+		StartDelay:     adcom1.StartDelay(unit.Video.Startdelay).Ptr(),
 		PlaybackMethod: pbm,
 		Protocols:      protocols,
 	}
@@ -165,7 +168,7 @@ func MakeOpenRTBGeneric(req *pbs.PBSRequest, bidder *pbs.PBSBidder, bidderFamily
 			Ext:      userExt,
 		},
 		Source: &openrtb2.Source{
-			FD:  1, // upstream, aka header
+			FD:  openrtb2.Int8Ptr(1), // upstream, aka header
 			TID: req.Tid,
 		},
 		AT:   1,
@@ -190,12 +193,38 @@ func FilterPrebidSKADNExt(prebidExt *openrtb_ext.ExtImpPrebid, filterMap map[str
 		return openrtb_ext.SKADN{}
 	}
 
-	return openrtb_ext.SKADN{
-		Version:    prebidExt.SKADN.Version,
-		Versions:   prebidExt.SKADN.Versions,
-		SourceApp:  prebidExt.SKADN.SourceApp,
-		SKADNetIDs: filterArrayWithMap(prebidExt.SKADN.SKADNetIDs, filterMap),
+	skadn := openrtb_ext.SKADN{
+		Version:     prebidExt.SKADN.Version,
+		Versions:    prebidExt.SKADN.Versions,
+		SourceApp:   prebidExt.SKADN.SourceApp,
+		SKADNetIDs:  filterArrayWithMap(prebidExt.SKADN.SKADNetIDs, filterMap),
+		ProductPage: prebidExt.SKADN.ProductPage,
+		SKOverlay:   prebidExt.SKADN.SKOverlay,
 	}
+
+	// the IAB list version and exclusions are passed as is, additions are filtered as the network ids
+	if list := prebidExt.SKADN.SKADNetList; list != nil {
+		skadn.SKADNetList = &openrtb_ext.SKADNetList{
+			Max:  list.Max,
+			Excl: list.Excl,
+			Addl: filterArrayWithMap(list.Addl, filterMap),
+		}
+	}
+
+	return skadn
+}
+
+// SKADNRequested returns true when the SKADN extension filtered by FilterPrebidSKADNExt still requests one of
+// the networks of filterMap, in skadnetids, in skadnetlist.addl, or through the IAB SKAdNetwork ID list version
+// of skadnetlist.max, which the networks of the bidder are loaded from
+func SKADNRequested(skadn openrtb_ext.SKADN, filterMap map[string]bool) bool {
+	if len(skadn.SKADNetIDs) > 0 {
+		return true
+	}
+	if list := skadn.SKADNetList; list != nil {
+		return len(list.Addl) > 0 || (list.Max > 0 && len(filterMap) > 0)
+	}
+	return false
 }
 
 // filterArrayWithMap -- Added by Tapjoy to handle SKADN DSP filtering
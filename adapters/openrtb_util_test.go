@@ -6,7 +6,9 @@ import (
 
 	"encoding/json"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/openrtb_ext"
 	"github.com/prebid/prebid-server/pbs"
 	"github.com/prebid/prebid-server/usersync"
 	"github.com/stretchr/testify/assert"
@@ -97,8 +99,8 @@ func TestOpenRTBVideo(t *testing.T) {
 	assert.Equal(t, resp.Imp[0].ID, "unitCode")
 	assert.EqualValues(t, resp.Imp[0].Video.MaxDuration, 30)
 	assert.EqualValues(t, resp.Imp[0].Video.MinDuration, 15)
-	assert.EqualValues(t, *resp.Imp[0].Video.StartDelay, openrtb2.StartDelay(5))
-	assert.EqualValues(t, resp.Imp[0].Video.PlaybackMethod, []openrtb2.PlaybackMethod{openrtb2.PlaybackMethod(1)})
+	assert.EqualValues(t, *resp.Imp[0].Video.StartDelay, adcom1.StartDelay(5))
+	assert.EqualValues(t, resp.Imp[0].Video.PlaybackMethod, []adcom1.PlaybackMethod{adcom1.PlaybackMethod(1)})
 	assert.EqualValues(t, resp.Imp[0].Video.MIMEs, []string{"video/mp4"})
 }
 
@@ -202,7 +204,7 @@ func TestOpenRTBMultiMediaImp(t *testing.T) {
 	assert.Equal(t, len(resp.Imp), 1)
 	assert.Equal(t, resp.Imp[0].ID, "unitCode")
 	assert.EqualValues(t, *resp.Imp[0].Banner.W, 10)
-	assert.EqualValues(t, resp.Imp[0].Video.W, 10)
+	assert.EqualValues(t, *resp.Imp[0].Video.W, 10)
 	assert.EqualValues(t, resp.Imp[0].Video.MaxDuration, 30)
 	assert.EqualValues(t, resp.Imp[0].Video.MinDuration, 15)
 }
@@ -422,7 +424,7 @@ func TestMakeVideo(t *testing.T) {
 	video := makeVideo(adUnit)
 	assert.EqualValues(t, video.MinDuration, 15)
 	assert.EqualValues(t, video.MaxDuration, 30)
-	assert.EqualValues(t, *video.StartDelay, openrtb2.StartDelay(5))
+	assert.EqualValues(t, *video.StartDelay, adcom1.StartDelay(5))
 	assert.EqualValues(t, len(video.PlaybackMethod), 1)
 	assert.EqualValues(t, len(video.Protocols), 4)
 }
@@ -572,3 +574,82 @@ func TestFilterArrayWithMap(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterPrebidSKADNExt(t *testing.T) {
+	prebidExt := &openrtb_ext.ExtImpPrebid{
+		SKADN: openrtb_ext.SKADN{
+			Versions:    []string{"2.2", "4.0"},
+			SourceApp:   "880047117",
+			SKADNetIDs:  []string{"bidder.skadnetwork", "other.skadnetwork"},
+			ProductPage: 1,
+			SKOverlay:   1,
+			SKADNetList: &openrtb_ext.SKADNetList{
+				Max:  306,
+				Excl: []int{2, 8},
+				Addl: []string{"BIDDER2.skadnetwork", "other2.skadnetwork"},
+			},
+		},
+	}
+	filter := map[string]bool{"bidder.skadnetwork": true, "bidder2.skadnetwork": true}
+
+	assert.Equal(t, openrtb_ext.SKADN{
+		Versions:    []string{"2.2", "4.0"},
+		SourceApp:   "880047117",
+		SKADNetIDs:  []string{"bidder.skadnetwork"},
+		ProductPage: 1,
+		SKOverlay:   1,
+		SKADNetList: &openrtb_ext.SKADNetList{
+			Max:  306,
+			Excl: []int{2, 8},
+			Addl: []string{"BIDDER2.skadnetwork"},
+		},
+	}, FilterPrebidSKADNExt(prebidExt, filter))
+
+	assert.Equal(t, openrtb_ext.SKADN{}, FilterPrebidSKADNExt(nil, filter))
+}
+
+func TestSKADNRequested(t *testing.T) {
+	networks := map[string]bool{"bidder.skadnetwork": true}
+
+	testCases := []struct {
+		description string
+		skadn       openrtb_ext.SKADN
+		networks    map[string]bool
+		expected    bool
+	}{
+		{
+			description: "Nothing Requested",
+			skadn:       openrtb_ext.SKADN{Versions: []string{"4.0"}},
+			networks:    networks,
+			expected:    false,
+		},
+		{
+			description: "SKADNetIDs",
+			skadn:       openrtb_ext.SKADN{SKADNetIDs: []string{"bidder.skadnetwork"}},
+			networks:    networks,
+			expected:    true,
+		},
+		{
+			description: "SKADNetList Additions",
+			skadn:       openrtb_ext.SKADN{SKADNetList: &openrtb_ext.SKADNetList{Addl: []string{"bidder.skadnetwork"}}},
+			networks:    networks,
+			expected:    true,
+		},
+		{
+			description: "SKADNetList Max",
+			skadn:       openrtb_ext.SKADN{SKADNetList: &openrtb_ext.SKADNetList{Max: 306, Excl: []int{2}}},
+			networks:    networks,
+			expected:    true,
+		},
+		{
+			description: "SKADNetList Max Without Bidder Networks",
+			skadn:       openrtb_ext.SKADN{SKADNetList: &openrtb_ext.SKADNetList{Max: 306}},
+			networks:    map[string]bool{},
+			expected:    false,
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, SKADNRequested(test.skadn, test.networks), test.description)
+	}
+}
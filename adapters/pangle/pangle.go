@@ -5,18 +5,14 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/cache/skanidlist"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/openrtb_ext"
 )
 
-// SKAN IDs must be lower case
-var pangleExtSKADNetIDs = map[string]bool{
-	"22mmun2rn5.skadnetwork": true,
-}
-
 type adapter struct {
 	Endpoint string
 }
@@ -60,9 +56,9 @@ func Builder(bidderName openrtb_ext.BidderName, config config.Adapter) (adapters
 
 /* MakeRequests */
 
-func getAdType(imp openrtb2.Imp, parsedImpExt *wrappedExtImpBidder) int {
+func getAdType(imp openrtb2.Imp, parsedImpExt *wrappedExtImpBidder, placement adapters.Placement) int {
 	// attempt to get tj adtype and return if successful
-	if adType, err := getTjAdType(imp, parsedImpExt); err == nil {
+	if adType, err := getTjAdType(imp, placement); err == nil {
 		return adType
 	}
 
@@ -97,6 +93,7 @@ func (a *adapter) MakeRequests(request *openrtb2.BidRequest, requestInfo *adapte
 
 	requestCopy := *request
 	for _, imp := range request.Imp {
+		placement := requestInfo.Placement(&imp)
 		skanSent := false
 
 		var impExt wrappedExtImpBidder
@@ -117,16 +114,17 @@ func (a *adapter) MakeRequests(request *openrtb2.BidRequest, requestInfo *adapte
 		}
 
 		if bidderImpExt.SKADNSupported {
-			skadn := adapters.FilterPrebidSKADNExt(impExt.Prebid, pangleExtSKADNetIDs)
+			skanIDList := skanidlist.Get(openrtb_ext.BidderPangle)
+			skadn := adapters.FilterPrebidSKADNExt(impExt.Prebid, skanIDList)
 			// only add if present
-			if len(skadn.SKADNetIDs) > 0 {
+			if adapters.SKADNRequested(skadn, skanIDList) {
 				impExt.SKADN = &skadn
 				skanSent = true
 			}
 		}
 
 		// detect and fill adtype
-		adType := getAdType(imp, &impExt)
+		adType := getAdType(imp, &impExt, placement)
 		if adType == -1 {
 			errs = append(errs, &errortypes.BadInput{Message: "not a supported adtype"})
 			continue
@@ -175,12 +173,6 @@ func (a *adapter) MakeRequests(request *openrtb2.BidRequest, requestInfo *adapte
 			continue
 		}
 
-		// Tapjoy Record placement type
-		placementType := adapters.Interstitial
-		if bidderImpExt.Reward == 1 {
-			placementType = adapters.Rewarded
-		}
-
 		requestData := &adapters.RequestData{
 			Method: "POST",
 			Uri:    a.Endpoint,
@@ -192,7 +184,7 @@ func (a *adapter) MakeRequests(request *openrtb2.BidRequest, requestInfo *adapte
 
 			TapjoyData: adapters.TapjoyData{
 				Bidder:        "pangle",
-				PlacementType: placementType,
+				PlacementType: placement.Type,
 				Region:        "apac",
 				SKAN: adapters.SKAN{
 					Supported: bidderImpExt.SKADNSupported,
@@ -277,6 +269,7 @@ func (a *adapter) MakeBids(request *openrtb2.BidRequest, requestData *adapters.R
 			b := &adapters.TypedBid{
 				Bid:     &bid,
 				BidType: mediaType,
+				Seat:    seatBid.Seat,
 			}
 			bidResponse.Bids = append(bidResponse.Bids, b)
 		}
@@ -285,16 +278,10 @@ func (a *adapter) MakeBids(request *openrtb2.BidRequest, requestData *adapters.R
 	return bidResponse, errs
 }
 
-func getTjAdType(imp openrtb2.Imp, parsedImpExt *wrappedExtImpBidder) (int, error) {
-	// for setting token
-	var bidderImpExt openrtb_ext.ImpExtPangle
-	if err := json.Unmarshal(parsedImpExt.Bidder, &bidderImpExt); err != nil {
-		return -1, err
-	}
-
+func getTjAdType(imp openrtb2.Imp, placement adapters.Placement) (int, error) {
 	// video
 	if imp.Video != nil {
-		if bidderImpExt.Reward == 1 {
+		if placement.Type == adapters.Rewarded {
 			return 7, nil
 		} else {
 			return 8, nil
@@ -303,7 +290,7 @@ func getTjAdType(imp openrtb2.Imp, parsedImpExt *wrappedExtImpBidder) (int, erro
 
 	// banner
 	if imp.Banner != nil {
-		if bidderImpExt.Reward == 1 {
+		if placement.Type == adapters.Rewarded {
 			return 1, nil
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/prebid/prebid-server/adapters/adapterstest"
+	"github.com/prebid/prebid-server/cache/skanidlist"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/openrtb_ext"
 )
@@ -17,5 +18,11 @@ func TestJsonSamples(t *testing.T) {
 		t.Fatalf("Builder returned unexpected error %v", buildErr)
 	}
 
+	infos, err := config.LoadBidderInfoFromDisk("../../static/bidder-info", nil, []string{string(openrtb_ext.BidderPangle)})
+	if err != nil {
+		t.Fatalf("Failed to load bidder info %v", err)
+	}
+	skanidlist.Init(infos)
+
 	adapterstest.RunJSONBidderTest(t, "pangletest", bidder)
 }
@@ -0,0 +1,140 @@
+package adapters
+
+import (
+	"github.com/buger/jsonparser"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+// Orientation is the orientation of a placement, given by the size of the creative it expects
+type Orientation string
+
+const (
+	Portrait  Orientation = "portrait"
+	Landscape Orientation = "landscape"
+)
+
+// Placement is the canonical classification of an imp. The exchange classifies every imp once, so that
+// all the adapters report the same placement for the same impression.
+type Placement struct {
+	Type PlacementType `json:"type,omitempty"`
+	// Orientation is empty for square or unsized imps
+	Orientation Orientation `json:"orientation,omitempty"`
+}
+
+// ClassifyPlacement classifies an imp from imp.rwdd or imp.ext.prebid.is_rewarded_inventory, or the bidder params
+// without them, imp.instl and its media types. The exchange classifies the imps before their bidder params are split
+// out, so the params of every bidder of the imp are read, see RewardedByParams.
+// A video imp which is neither rewarded nor banner is full screen, so it is an interstitial.
+func ClassifyPlacement(imp *openrtb2.Imp) Placement {
+	placement := Placement{Orientation: impOrientation(imp)}
+
+	switch {
+	case isRewardedInventory(imp):
+		placement.Type = Rewarded
+	case imp.Instl == 1 || imp.Video != nil:
+		placement.Type = Interstitial
+	case imp.Banner != nil:
+		placement.Type = Banner
+	case imp.Native != nil:
+		placement.Type = Native
+	}
+	return placement
+}
+
+// isRewardedInventory reads imp.ext.prebid.is_rewarded_inventory and, when the imp does not set it, the rewarded
+// flag of its bidder params, see RewardedByParams
+func isRewardedInventory(imp *openrtb2.Imp) bool {
+	if rewarded, ok := RewardedInventoryFlag(imp); ok {
+		return rewarded
+	}
+	return RewardedByParams(imp)
+}
+
+// RewardedInventoryFlag returns the OpenRTB 2.6 imp.rwdd, or imp.ext.prebid.is_rewarded_inventory without it, ok is
+// false when the imp sets neither
+func RewardedInventoryFlag(imp *openrtb2.Imp) (rewarded bool, ok bool) {
+	if imp.Rwdd == 1 {
+		return true, true
+	}
+	flag, err := jsonparser.GetInt(imp.Ext, openrtb_ext.PrebidExtKey, "is_rewarded_inventory")
+	if err != nil {
+		return false, false
+	}
+	return flag == 1, true
+}
+
+// RewardedByParams reads the rewarded flag the SDK sets on the bidder params, for the traffic without
+// imp.ext.prebid.is_rewarded_inventory. The imp is rewarded when the params of one of its bidders, in imp.ext.<bidder>,
+// imp.ext.prebid.bidder.<bidder> or the imp.ext.bidder of an imp split out for a bidder, set reward: 1,
+// placementtype: "rewarded" or an unskippable video, video.skip: 0.
+func RewardedByParams(imp *openrtb2.Imp) bool {
+	rewarded := false
+	jsonparser.ObjectEach(imp.Ext, func(key []byte, value []byte, dataType jsonparser.ValueType, _ int) error {
+		if dataType != jsonparser.Object {
+			return nil
+		}
+		switch string(key) {
+		case openrtb_ext.FirstPartyDataContextExtKey, openrtb_ext.FirstPartyDataExtKey, openrtb_ext.SKAdNExtKey:
+		case openrtb_ext.PrebidExtKey:
+			jsonparser.ObjectEach(value, func(_ []byte, params []byte, dataType jsonparser.ValueType, _ int) error {
+				rewarded = rewarded || (dataType == jsonparser.Object && rewardedParams(params))
+				return nil
+			}, openrtb_ext.PrebidExtBidderKey)
+		default:
+			rewarded = rewarded || rewardedParams(value)
+		}
+		return nil
+	})
+	return rewarded
+}
+
+func rewardedParams(params []byte) bool {
+	if reward, err := jsonparser.GetInt(params, "reward"); err == nil && reward == 1 {
+		return true
+	}
+	if placementType, err := jsonparser.GetString(params, "placementtype"); err == nil && placementType == string(Rewarded) {
+		return true
+	}
+	skip, err := jsonparser.GetInt(params, "video", "skip")
+	return err == nil && skip == 0
+}
+
+// impOrientation prefers the video size, then the banner size and then its first format
+func impOrientation(imp *openrtb2.Imp) Orientation {
+	var w, h int64
+	switch {
+	case imp.Video != nil && imp.Video.W != nil && imp.Video.H != nil:
+		w, h = *imp.Video.W, *imp.Video.H
+	case imp.Banner != nil && imp.Banner.W != nil && imp.Banner.H != nil:
+		w, h = *imp.Banner.W, *imp.Banner.H
+	case imp.Banner != nil && len(imp.Banner.Format) > 0:
+		w, h = imp.Banner.Format[0].W, imp.Banner.Format[0].H
+	}
+
+	switch {
+	case w == 0 || h == 0 || w == h:
+		return ""
+	case w > h:
+		return Landscape
+	default:
+		return Portrait
+	}
+}
+
+// FullscreenType is the placement type for the DSPs which only tell the rewarded placements from the
+// interstitial ones
+func (p Placement) FullscreenType() PlacementType {
+	if p.Type == Rewarded {
+		return Rewarded
+	}
+	return Interstitial
+}
+
+// Reward is the rewarded flag the DSPs expect, 1 for a rewarded placement and 0 otherwise
+func (p Placement) Reward() int {
+	if p.Type == Rewarded {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,154 @@
+package adapters
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyPlacement(t *testing.T) {
+	testCases := []struct {
+		description string
+		imp         openrtb2.Imp
+		expected    Placement
+	}{
+		{
+			description: "rewarded video",
+			imp:         openrtb2.Imp{Video: &openrtb2.Video{W: openrtb2.Int64Ptr(320), H: openrtb2.Int64Ptr(480)}, Instl: 1, Ext: json.RawMessage(`{"prebid":{"is_rewarded_inventory":1}}`)},
+			expected:    Placement{Type: Rewarded, Orientation: Portrait},
+		},
+		{
+			description: "rewarded banner",
+			imp:         openrtb2.Imp{Banner: &openrtb2.Banner{W: openrtb2.Int64Ptr(480), H: openrtb2.Int64Ptr(320)}, Ext: json.RawMessage(`{"prebid":{"is_rewarded_inventory":1}}`)},
+			expected:    Placement{Type: Rewarded, Orientation: Landscape},
+		},
+		{
+			description: "rewarded video by imp.rwdd",
+			imp:         openrtb2.Imp{Video: &openrtb2.Video{}, Rwdd: 1},
+			expected:    Placement{Type: Rewarded},
+		},
+		{
+			description: "rewarded by the reward param",
+			imp:         openrtb2.Imp{Video: &openrtb2.Video{}, Ext: json.RawMessage(`{"bidder":{"reward":1}}`)},
+			expected:    Placement{Type: Rewarded},
+		},
+		{
+			description: "rewarded by the placementtype param",
+			imp:         openrtb2.Imp{Video: &openrtb2.Video{}, Ext: json.RawMessage(`{"bidder":{"placementtype":"rewarded"}}`)},
+			expected:    Placement{Type: Rewarded},
+		},
+		{
+			description: "rewarded by the params of one of the bidders of the request",
+			imp:         openrtb2.Imp{Video: &openrtb2.Video{}, Ext: json.RawMessage(`{"appnexus":{"placementId":1},"taurusx":{"reward":1}}`)},
+			expected:    Placement{Type: Rewarded},
+		},
+		{
+			description: "rewarded by the params of imp.ext.prebid.bidder",
+			imp:         openrtb2.Imp{Video: &openrtb2.Video{}, Ext: json.RawMessage(`{"prebid":{"bidder":{"moloco":{"placementtype":"rewarded"}}}}`)},
+			expected:    Placement{Type: Rewarded},
+		},
+		{
+			description: "rewarded by an unskippable video param",
+			imp:         openrtb2.Imp{Video: &openrtb2.Video{}, Ext: json.RawMessage(`{"liftoff":{"video":{"skip":0}}}`)},
+			expected:    Placement{Type: Rewarded},
+		},
+		{
+			description: "skippable video param",
+			imp:         openrtb2.Imp{Video: &openrtb2.Video{}, Ext: json.RawMessage(`{"liftoff":{"video":{"skip":1}}}`)},
+			expected:    Placement{Type: Interstitial},
+		},
+		{
+			description: "first party data is not a bidder",
+			imp:         openrtb2.Imp{Video: &openrtb2.Video{}, Ext: json.RawMessage(`{"context":{"reward":1},"data":{"placementtype":"rewarded"}}`)},
+			expected:    Placement{Type: Interstitial},
+		},
+		{
+			description: "is_rewarded_inventory preferred to the params",
+			imp:         openrtb2.Imp{Video: &openrtb2.Video{}, Ext: json.RawMessage(`{"prebid":{"is_rewarded_inventory":0},"bidder":{"reward":1}}`)},
+			expected:    Placement{Type: Interstitial},
+		},
+		{
+			description: "interstitial banner",
+			imp:         openrtb2.Imp{Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 320, H: 480}}}, Instl: 1},
+			expected:    Placement{Type: Interstitial, Orientation: Portrait},
+		},
+		{
+			description: "video is full screen",
+			imp:         openrtb2.Imp{Video: &openrtb2.Video{}, Ext: json.RawMessage(`{"prebid":{"is_rewarded_inventory":0}}`)},
+			expected:    Placement{Type: Interstitial},
+		},
+		{
+			description: "banner",
+			imp:         openrtb2.Imp{Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 320, H: 50}, {W: 300, H: 250}}}},
+			expected:    Placement{Type: Banner, Orientation: Landscape},
+		},
+		{
+			description: "square banner",
+			imp:         openrtb2.Imp{Banner: &openrtb2.Banner{W: openrtb2.Int64Ptr(300), H: openrtb2.Int64Ptr(300)}},
+			expected:    Placement{Type: Banner},
+		},
+		{
+			description: "native",
+			imp:         openrtb2.Imp{Native: &openrtb2.Native{Request: "{}"}},
+			expected:    Placement{Type: Native},
+		},
+		{
+			description: "audio",
+			imp:         openrtb2.Imp{Audio: &openrtb2.Audio{}},
+			expected:    Placement{},
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, ClassifyPlacement(&test.imp), test.description)
+	}
+}
+
+func TestExtraRequestInfoPlacement(t *testing.T) {
+	imp := &openrtb2.Imp{ID: "imp", Video: &openrtb2.Video{W: openrtb2.Int64Ptr(320), H: openrtb2.Int64Ptr(480)}}
+
+	reqInfo := &ExtraRequestInfo{Placements: map[string]Placement{"imp": {Type: Rewarded}}}
+	assert.Equal(t, Placement{Type: Rewarded}, reqInfo.Placement(imp), "classified by the exchange")
+
+	reqInfo = &ExtraRequestInfo{Placements: map[string]Placement{"imp": {Type: Interstitial}}}
+	paramImp := &openrtb2.Imp{ID: "imp", Video: &openrtb2.Video{}, Ext: json.RawMessage(`{"bidder":{"reward":1}}`)}
+	assert.Equal(t, Placement{Type: Interstitial}, reqInfo.Placement(paramImp), "the exchange classification is kept")
+
+	reqInfo = &ExtraRequestInfo{}
+	assert.Equal(t, Placement{Type: Interstitial, Orientation: Portrait}, reqInfo.Placement(imp), "classified on the spot")
+
+	reqInfo = nil
+	assert.Equal(t, Placement{Type: Interstitial, Orientation: Portrait}, reqInfo.Placement(imp), "nil safe")
+}
+
+func TestExtraRequestInfoPlacementType(t *testing.T) {
+	reqInfo := &ExtraRequestInfo{Placements: map[string]Placement{
+		"rewarded1": {Type: Rewarded},
+		"rewarded2": {Type: Rewarded},
+		"banner":    {Type: Banner},
+	}}
+
+	assert.Equal(t, Rewarded, reqInfo.PlacementType([]openrtb2.Imp{{ID: "rewarded1"}, {ID: "rewarded2"}}), "same placements")
+	assert.Equal(t, PlacementType(""), reqInfo.PlacementType([]openrtb2.Imp{{ID: "rewarded1"}, {ID: "banner"}}), "different placements")
+	assert.Equal(t, PlacementType(""), reqInfo.PlacementType(nil), "no imp")
+}
+
+func TestPlacementFullscreen(t *testing.T) {
+	tests := []struct {
+		placement      Placement
+		expectedType   PlacementType
+		expectedReward int
+	}{
+		{placement: Placement{Type: Rewarded}, expectedType: Rewarded, expectedReward: 1},
+		{placement: Placement{Type: Interstitial}, expectedType: Interstitial},
+		{placement: Placement{Type: Banner}, expectedType: Interstitial},
+		{placement: Placement{}, expectedType: Interstitial},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expectedType, test.placement.FullscreenType(), string(test.placement.Type))
+		assert.Equal(t, test.expectedReward, test.placement.Reward(), string(test.placement.Type))
+	}
+}
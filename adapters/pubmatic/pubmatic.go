@@ -11,7 +11,7 @@ import (
 	"strings"
 
 	"github.com/golang/glog"
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/cache/skanidlist"
 	"github.com/prebid/prebid-server/config"
@@ -405,14 +405,12 @@ func (a *PubmaticAdapter) MakeRequests(request *openrtb2.BidRequest, reqInfo *ad
 	}
 
 	// Tapjoy Record placement type
-	placementType := adapters.Interstitial
-	if impData.pubmatic.Reward == 1 {
-		placementType = adapters.Rewarded
-	}
+	placementType := reqInfo.PlacementType(request.Imp)
 
 	skanSent := false
 	// only add if present
-	if len(adapters.FilterPrebidSKADNExt(impData.bidder.Prebid, skanidlist.Get(openrtb_ext.BidderPubmatic)).SKADNetIDs) > 0 {
+	skanIDList := skanidlist.Get(openrtb_ext.BidderPubmatic)
+	if adapters.SKADNRequested(adapters.FilterPrebidSKADNExt(impData.bidder.Prebid, skanIDList), skanIDList) {
 		skanSent = true
 	}
 
@@ -668,6 +666,7 @@ func (a *PubmaticAdapter) MakeBids(internalRequest *openrtb2.BidRequest, externa
 				Bid:      &bid,
 				BidType:  bidType,
 				BidVideo: impVideo,
+				Seat:     sb.Seat,
 			})
 
 		}
@@ -729,7 +728,7 @@ func populateExtensionMap(imp map[string]interface{}, bidderExt ExtImpBidderPubm
 		skadn := adapters.FilterPrebidSKADNExt(bidderExt.Prebid, skanIDList)
 
 		// only add if present
-		if len(skadn.SKADNetIDs) > 0 {
+		if adapters.SKADNRequested(skadn, skanIDList) {
 			imp["skadn"] = &skadn
 		}
 	}
@@ -12,10 +12,11 @@ import (
 	"testing"
 	"time"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/adapters/adapterstest"
 	"github.com/prebid/prebid-server/cache/dummycache"
+	"github.com/prebid/prebid-server/cache/skanidlist"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/openrtb_ext"
 	"github.com/prebid/prebid-server/pbs"
@@ -30,6 +31,12 @@ func TestJsonSamples(t *testing.T) {
 		t.Fatalf("Builder returned unexpected error %v", buildErr)
 	}
 
+	infos, err := config.LoadBidderInfoFromDisk("../../static/bidder-info", nil, []string{string(openrtb_ext.BidderPubmatic)})
+	if err != nil {
+		t.Fatalf("Failed to load bidder info %v", err)
+	}
+	skanidlist.Init(infos)
+
 	adapterstest.RunJSONBidderTest(t, "pubmatictest", bidder)
 }
 
@@ -0,0 +1,111 @@
+package adapters
+
+import (
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/config"
+)
+
+// RegionResolver picks the regional endpoint a request should be sent to.
+//
+// Regions are tried in this order:
+//   1. the region passed in the imp params by the caller
+//   2. the region serving the device.geo.country of the request
+//   3. the region this server is deployed in
+// If none of them has an endpoint configured the default endpoint is used.
+type RegionResolver struct {
+	endpoint         string
+	regions          map[string]config.AdapterRegion
+	countries        map[string]string
+	deploymentRegion string
+}
+
+// ResolvedRegion is the outcome of RegionResolver.Resolve
+type ResolvedRegion struct {
+	// Region is the name of the region the request is sent to, or the requested region when
+	// the default endpoint is used.
+	Region string
+	Uri    string
+	// FallbackUri is retried when Uri fails with a connection error or a 5xx. Empty when the
+	// region has no fallback.
+	FallbackUri string
+	// FallbackRegion is the name of the region of FallbackUri
+	FallbackRegion string
+}
+
+// NewRegionResolver builds a resolver for the given default endpoint and regions. Regions
+// without an endpoint are ignored.
+func NewRegionResolver(endpoint string, regions map[string]config.AdapterRegion, deploymentRegion string) *RegionResolver {
+	resolver := &RegionResolver{
+		endpoint:         endpoint,
+		regions:          make(map[string]config.AdapterRegion, len(regions)),
+		countries:        make(map[string]string),
+		deploymentRegion: strings.ToLower(deploymentRegion),
+	}
+
+	for name, region := range regions {
+		if region.Endpoint == "" {
+			continue
+		}
+		name = strings.ToLower(name)
+		resolver.regions[name] = region
+		for _, country := range region.Countries {
+			resolver.countries[strings.ToUpper(country)] = name
+		}
+	}
+
+	return resolver
+}
+
+// NewRegionResolverFromConfig builds a resolver from the adapter config, see config.Adapter.RegionEndpoints
+func NewRegionResolverFromConfig(cfg config.Adapter) *RegionResolver {
+	return NewRegionResolver(cfg.Endpoint, cfg.RegionEndpoints(), cfg.DeploymentRegion)
+}
+
+// Resolve picks the region for the request. paramRegion is the region requested in the imp params, it may be empty.
+// A nil resolver has no endpoints at all.
+func (r *RegionResolver) Resolve(paramRegion string, request *openrtb2.BidRequest) ResolvedRegion {
+	if r == nil {
+		return ResolvedRegion{Region: paramRegion}
+	}
+
+	for _, name := range r.candidates(paramRegion, request) {
+		if region, ok := r.regions[name]; ok {
+			resolved := ResolvedRegion{
+				Region: name,
+				Uri:    region.Endpoint,
+			}
+			if fallback, ok := r.regions[strings.ToLower(region.Fallback)]; ok && region.Fallback != "" {
+				resolved.FallbackUri = fallback.Endpoint
+				resolved.FallbackRegion = strings.ToLower(region.Fallback)
+			}
+			return resolved
+		}
+	}
+
+	return ResolvedRegion{
+		Region: paramRegion,
+		Uri:    r.endpoint,
+	}
+}
+
+func (r *RegionResolver) candidates(paramRegion string, request *openrtb2.BidRequest) []string {
+	candidates := make([]string, 0, 3)
+
+	if paramRegion != "" {
+		candidates = append(candidates, strings.ToLower(paramRegion))
+	}
+
+	if request != nil && request.Device != nil && request.Device.Geo != nil && request.Device.Geo.Country != "" {
+		if name, ok := r.countries[strings.ToUpper(request.Device.Geo.Country)]; ok {
+			candidates = append(candidates, name)
+		}
+	}
+
+	if r.deploymentRegion != "" {
+		candidates = append(candidates, r.deploymentRegion)
+	}
+
+	return candidates
+}
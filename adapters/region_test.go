@@ -0,0 +1,70 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegionResolver(t *testing.T) {
+	resolver := NewRegionResolver("https://default.com", map[string]config.AdapterRegion{
+		"us_east": {Endpoint: "https://useast.com"},
+		"eu":      {Endpoint: "https://eu.com", Countries: []string{"DEU", "fra"}, Fallback: "us_east"},
+		"apac":    {Endpoint: "", Countries: []string{"JPN"}},
+	}, "us_east")
+
+	withCountry := func(country string) *openrtb2.BidRequest {
+		return &openrtb2.BidRequest{Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: country}}}
+	}
+
+	testCases := []struct {
+		description string
+		paramRegion string
+		request     *openrtb2.BidRequest
+		expected    ResolvedRegion
+	}{
+		{
+			description: "imp param region",
+			paramRegion: "eu",
+			request:     withCountry("USA"),
+			expected:    ResolvedRegion{Region: "eu", Uri: "https://eu.com", FallbackUri: "https://useast.com", FallbackRegion: "us_east"},
+		},
+		{
+			description: "imp param region is case insensitive",
+			paramRegion: "US_EAST",
+			request:     &openrtb2.BidRequest{},
+			expected:    ResolvedRegion{Region: "us_east", Uri: "https://useast.com"},
+		},
+		{
+			description: "unknown imp param region falls through to device country",
+			paramRegion: "sa",
+			request:     withCountry("FRA"),
+			expected:    ResolvedRegion{Region: "eu", Uri: "https://eu.com", FallbackUri: "https://useast.com", FallbackRegion: "us_east"},
+		},
+		{
+			description: "region without endpoint is ignored",
+			request:     withCountry("JPN"),
+			expected:    ResolvedRegion{Region: "us_east", Uri: "https://useast.com"},
+		},
+		{
+			description: "deployment region",
+			request:     &openrtb2.BidRequest{},
+			expected:    ResolvedRegion{Region: "us_east", Uri: "https://useast.com"},
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, resolver.Resolve(test.paramRegion, test.request), test.description)
+	}
+}
+
+func TestRegionResolverDefaultEndpoint(t *testing.T) {
+	resolver := NewRegionResolver("https://default.com", map[string]config.AdapterRegion{
+		"eu": {Endpoint: "https://eu.com"},
+	}, "")
+
+	assert.Equal(t, ResolvedRegion{Region: "apac", Uri: "https://default.com"}, resolver.Resolve("apac", &openrtb2.BidRequest{}))
+	assert.Equal(t, ResolvedRegion{Uri: "https://default.com"}, resolver.Resolve("", nil))
+}
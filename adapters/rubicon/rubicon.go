@@ -13,7 +13,8 @@ import (
 	"github.com/golang/glog"
 	"github.com/jinzhu/copier"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/cache/skanidlist"
 	"github.com/prebid/prebid-server/config"
@@ -23,28 +24,26 @@ import (
 	"golang.org/x/net/context/ctxhttp"
 )
 
-// Region ...
-type Region string
-
-const (
-	USEast Region = "us_east"
-	USWest Region = "us_west"
-	EU     Region = "eu"
-	APAC   Region = "apac"
-)
-
 const badvLimitSize = 50
 
 type RubiconAdapter struct {
-	http             *adapters.HTTPAdapter
-	URI              string
-	XAPIUsername     string
-	XAPIPassword     string
-	SupportedRegions map[Region]string
+	http         *adapters.HTTPAdapter
+	URI          string
+	XAPIUsername string
+	XAPIPassword string
+	regions      *adapters.RegionResolver
+	// bidderName is the bidder code of a seat of the adapter, rubicon if empty
+	bidderName string
+	// banners makes the adapter bid on the banners instead of dropping them, for the MRAID seats which are only
+	// left with the MRAID banners by adapters.BuildInfoAwareBidder
+	banners bool
 }
 
 // used for cookies and such
 func (a *RubiconAdapter) Name() string {
+	if a.bidderName != "" {
+		return a.bidderName
+	}
 	return "rubicon"
 }
 
@@ -66,11 +65,8 @@ type bidRequestExt struct {
 }
 
 type bidRequestExtPrebid struct {
-	Bidders bidRequestExtPrebidBidders `json:"bidders"`
-}
-
-type bidRequestExtPrebidBidders struct {
-	Rubicon prebidBiddersRubicon `json:"rubicon,omitempty"`
+	// Bidders is keyed by the bidder code of the seat
+	Bidders map[string]prebidBiddersRubicon `json:"bidders"`
 }
 
 type prebidBiddersRubicon struct {
@@ -678,7 +674,7 @@ func (a *RubiconAdapter) Call(ctx context.Context, req *pbs.PBSRequest, bidder *
 	return bids, nil
 }
 
-func resolveVideoSizeId(placement openrtb2.VideoPlacementType, instl int8, impId string) (sizeID int, err error) {
+func resolveVideoSizeId(placement adcom1.VideoPlacementSubtype, instl int8, impId string) (sizeID int, err error) {
 	if placement != 0 {
 		if placement == 1 {
 			return 201, nil
@@ -711,6 +707,18 @@ func appendTrackerToUrl(uri string, tracker string) (res string) {
 	return
 }
 
+// appendTrackerToRegions appends the tracker to the endpoint of every configured region
+func appendTrackerToRegions(regions map[string]config.AdapterRegion, tracker string) map[string]config.AdapterRegion {
+	tracked := make(map[string]config.AdapterRegion, len(regions))
+	for name, region := range regions {
+		if region.Endpoint != "" {
+			region.Endpoint = appendTrackerToUrl(region.Endpoint, tracker)
+		}
+		tracked[name] = region
+	}
+	return tracked
+}
+
 // Builder builds a new instance of the Rubicon adapter for the given bidder with the given config.
 func Builder(bidderName openrtb_ext.BidderName, config config.Adapter) (adapters.Bidder, error) {
 	uri := appendTrackerToUrl(config.Endpoint, config.XAPI.Tracker)
@@ -719,12 +727,9 @@ func Builder(bidderName openrtb_ext.BidderName, config config.Adapter) (adapters
 		URI:          uri,
 		XAPIUsername: config.XAPI.Username,
 		XAPIPassword: config.XAPI.Password,
-		SupportedRegions: map[Region]string{
-			USEast: appendTrackerToUrl(config.XAPI.EndpointUSEast, config.XAPI.Tracker),
-			USWest: appendTrackerToUrl(config.XAPI.EndpointUSWest, config.XAPI.Tracker),
-			EU:     appendTrackerToUrl(config.XAPI.EndpointEU, config.XAPI.Tracker),
-			APAC:   appendTrackerToUrl(config.XAPI.EndpointAPAC, config.XAPI.Tracker),
-		},
+		regions:      adapters.NewRegionResolver(uri, appendTrackerToRegions(config.RegionEndpoints(), config.XAPI.Tracker), config.DeploymentRegion),
+		bidderName:   string(bidderName),
+		banners:      config.Seat.MRAID,
 	}
 	return bidder, nil
 }
@@ -739,15 +744,23 @@ func NewRubiconLegacyAdapter(httpConfig *adapters.HTTPAdapterConfig, uri, xuser,
 		URI:          uri,
 		XAPIUsername: xuser,
 		XAPIPassword: xpass,
-		SupportedRegions: map[Region]string{
-			USEast: appendTrackerToUrl(useast, tracker),
-			USWest: appendTrackerToUrl(uswest, tracker),
-			EU:     appendTrackerToUrl(eu, tracker),
-			APAC:   appendTrackerToUrl(apac, tracker),
-		},
+		regions: adapters.NewRegionResolver(uri, appendTrackerToRegions(map[string]config.AdapterRegion{
+			config.RegionUSEast: {Endpoint: useast},
+			config.RegionUSWest: {Endpoint: uswest},
+			config.RegionEU:     {Endpoint: eu},
+			config.RegionAPAC:   {Endpoint: apac},
+		}, tracker), ""),
 	}
 }
 
+// NewRubiconLegacySeatAdapter builds the legacy adapter of a seat of the Rubicon adapter registered under another
+// bidder code.
+func NewRubiconLegacySeatAdapter(bidderName string, httpConfig *adapters.HTTPAdapterConfig, uri, xuser, xpass, tracker, useast, uswest, eu, apac string) *RubiconAdapter {
+	a := NewRubiconLegacyAdapter(httpConfig, uri, xuser, xpass, tracker, useast, uswest, eu, apac)
+	a.bidderName = bidderName
+	return a
+}
+
 func (a *RubiconAdapter) MakeRequests(request *openrtb2.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
 	numRequests := len(request.Imp)
 	errs := make([]error, 0, len(request.Imp))
@@ -765,9 +778,9 @@ func (a *RubiconAdapter) MakeRequests(request *openrtb2.BidRequest, reqInfo *ada
 	requestImpCopy := rubiconRequest.Imp
 	for i := 0; i < numRequests; i++ {
 		skanSent := false
-		placementType := adapters.Interstitial
 
 		thisImp := requestImpCopy[i]
+		placement := reqInfo.Placement(&thisImp)
 
 		var bidderExt adapters.ExtImpBidder
 		if err = json.Unmarshal(thisImp.Ext, &bidderExt); err != nil {
@@ -832,7 +845,7 @@ func (a *RubiconAdapter) MakeRequests(request *openrtb2.BidRequest, reqInfo *ada
 			skadn := adapters.FilterPrebidSKADNExt(bidderExt.Prebid, skanIDList)
 
 			// only add if present
-			if len(skadn.SKADNetIDs) > 0 {
+			if adapters.SKADNRequested(skadn, skanIDList) {
 				impExt.SKADN = &skadn
 				skanSent = true
 			}
@@ -920,7 +933,6 @@ func (a *RubiconAdapter) MakeRequests(request *openrtb2.BidRequest, reqInfo *ada
 			var videoType = ""
 			if bidderExt.Prebid != nil && bidderExt.Prebid.IsRewardedInventory == 1 {
 				videoType = "rewarded"
-				placementType = adapters.Rewarded
 			}
 
 			videoCopy := *thisImp.Video
@@ -945,10 +957,23 @@ func (a *RubiconAdapter) MakeRequests(request *openrtb2.BidRequest, reqInfo *ada
 
 			thisImp.Video = &videoCopy
 			thisImp.Banner = nil
-		}
-
-		// Rubicon Bidder is responsible only for Video MRAID requests
-		if thisImp.Banner != nil {
+		} else if a.banners && thisImp.Banner != nil {
+			// The MRAID seat is responsible only for Banner MRAID requests, the only ones it is left with
+			primarySizeID, altSizeIDs, err := parseRubiconSizes(thisImp.Banner.Format)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			bannerExt := rubiconBannerExt{RP: rubiconBannerExtRP{SizeID: primarySizeID, AltSizeIDs: altSizeIDs, MIME: "text/html"}}
+			bannerCopy := *thisImp.Banner
+			bannerCopy.Ext, err = json.Marshal(&bannerExt)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			thisImp.Banner = &bannerCopy
+		} else {
+			// Rubicon Bidder is responsible only for Video MRAID requests
 			thisImp.Banner = nil
 		}
 
@@ -992,21 +1017,20 @@ func (a *RubiconAdapter) MakeRequests(request *openrtb2.BidRequest, reqInfo *ada
 			continue
 		}
 
-		uri := a.URI
-		if endpoint, ok := a.SupportedRegions[Region(rubiconExt.Region)]; ok {
-			uri = endpoint
-		}
+		region := a.regions.Resolve(rubiconExt.Region, request)
 
 		reqData := &adapters.RequestData{
-			Method:  "POST",
-			Uri:     uri,
-			Body:    reqJSON,
-			Headers: headers,
+			Method:         "POST",
+			Uri:            region.Uri,
+			Body:           reqJSON,
+			Headers:        headers,
+			FallbackUri:    region.FallbackUri,
+			FallbackRegion: region.FallbackRegion,
 
 			TapjoyData: adapters.TapjoyData{
 				Bidder:        a.Name(),
-				PlacementType: placementType,
-				Region:        rubiconExt.Region,
+				PlacementType: placement.Type,
+				Region:        region.Region,
 				SKAN: adapters.SKAN{
 					Supported: rubiconExt.SKADNSupported,
 					Sent:      skanSent,
@@ -1216,7 +1240,7 @@ func (a *RubiconAdapter) MakeBids(internalRequest *openrtb2.BidRequest, external
 	}
 
 	impToCpmOverride := mapImpIdToCpmOverride(internalRequest.Imp)
-	cmpOverride := cmpOverrideFromBidRequest(internalRequest)
+	cmpOverride := cmpOverrideFromBidRequest(internalRequest, a.Name())
 
 	for _, sb := range bidResp.SeatBid {
 		for i := 0; i < len(sb.Bid); i++ {
@@ -1243,6 +1267,7 @@ func (a *RubiconAdapter) MakeBids(internalRequest *openrtb2.BidRequest, external
 				bidResponse.Bids = append(bidResponse.Bids, &adapters.TypedBid{
 					Bid:     &bid,
 					BidType: bidType,
+					Seat:    sb.Seat,
 				})
 			}
 		}
@@ -1270,13 +1295,13 @@ func injectAqID(bid *openrtb2.Bid, aqid string, bidType openrtb_ext.BidType) {
 	return
 }
 
-func cmpOverrideFromBidRequest(bidRequest *openrtb2.BidRequest) float64 {
+func cmpOverrideFromBidRequest(bidRequest *openrtb2.BidRequest, bidderName string) float64 {
 	var bidRequestExt bidRequestExt
 	if err := json.Unmarshal(bidRequest.Ext, &bidRequestExt); err != nil {
 		return 0
 	}
 
-	return bidRequestExt.Prebid.Bidders.Rubicon.Debug.CpmOverride
+	return bidRequestExt.Prebid.Bidders[bidderName].Debug.CpmOverride
 }
 
 func mapImpIdToCpmOverride(imps []openrtb2.Imp) map[string]float64 {
@@ -11,7 +11,8 @@ import (
 	"testing"
 	"time"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/errortypes"
 
 	"github.com/prebid/prebid-server/adapters/adapterstest"
@@ -528,7 +529,7 @@ func TestAppendTracker(t *testing.T) {
 
 func TestResolveVideoSizeId(t *testing.T) {
 	testScenarios := []struct {
-		placement   openrtb2.VideoPlacementType
+		placement   adcom1.VideoPlacementSubtype
 		instl       int8
 		impId       string
 		expected    int
@@ -1014,8 +1015,8 @@ func TestOpenRTBRequest(t *testing.T) {
 		}, {
 			ID: "test-imp-video-id",
 			Video: &openrtb2.Video{
-				W:           640,
-				H:           360,
+				W:           openrtb2.Int64Ptr(640),
+				H:           openrtb2.Int64Ptr(360),
 				MIMEs:       []string{"video/mp4"},
 				MinDuration: 15,
 				MaxDuration: 30,
@@ -1096,11 +1097,11 @@ func TestOpenRTBRequest(t *testing.T) {
 				t.Fatal("Error unmarshalling request from the outgoing request.")
 			}
 
-			assert.Equal(t, int64(640), rpRequest.Imp[0].Video.W,
-				"Video width does not match. Expected %d, Got %d", 640, rpRequest.Imp[0].Video.W)
+			assert.Equal(t, int64(640), *rpRequest.Imp[0].Video.W,
+				"Video width does not match. Expected %d, Got %d", 640, *rpRequest.Imp[0].Video.W)
 
-			assert.Equal(t, int64(360), rpRequest.Imp[0].Video.H,
-				"Video height does not match. Expected %d, Got %d", 360, rpRequest.Imp[0].Video.H)
+			assert.Equal(t, int64(360), *rpRequest.Imp[0].Video.H,
+				"Video height does not match. Expected %d, Got %d", 360, *rpRequest.Imp[0].Video.H)
 
 			assert.Equal(t, "video/mp4", rpRequest.Imp[0].Video.MIMEs[0], "Video MIMEs do not match. Expected %s, Got %s", "video/mp4", rpRequest.Imp[0].Video.MIMEs[0])
 
@@ -1143,8 +1144,8 @@ func TestOpenRTBRequestWithBannerImpEvenIfImpHasVideo(t *testing.T) {
 				},
 			},
 			Video: &openrtb2.Video{
-				W:     640,
-				H:     360,
+				W:     openrtb2.Int64Ptr(640),
+				H:     openrtb2.Int64Ptr(360),
 				MIMEs: []string{"video/mp4"},
 			},
 			Ext: json.RawMessage(`{"bidder": {
@@ -1186,13 +1187,13 @@ func TestOpenRTBRequestWithImpAndAdSlotIncluded(t *testing.T) {
 			ID:    "test-imp-id",
 			Instl: 1,
 			Video: &openrtb2.Video{
-				W:           640,
-				H:           360,
+				W:           openrtb2.Int64Ptr(640),
+				H:           openrtb2.Int64Ptr(360),
 				MIMEs:       []string{"video/mp4"},
-				Protocols:   []openrtb2.Protocol{openrtb2.ProtocolVAST10},
+				Protocols:   []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST10},
 				MaxDuration: 30,
 				Linearity:   1,
-				API:         []openrtb2.APIFramework{},
+				API:         []adcom1.APIFramework{},
 			},
 			Ext: json.RawMessage(`{
 				"bidder": {
@@ -1250,13 +1251,13 @@ func TestOpenRTBRequestWithBadvOverflowed(t *testing.T) {
 			ID:    "test-imp-id",
 			Instl: 1,
 			Video: &openrtb2.Video{
-				W:           640,
-				H:           360,
+				W:           openrtb2.Int64Ptr(640),
+				H:           openrtb2.Int64Ptr(360),
 				MIMEs:       []string{"video/mp4"},
-				Protocols:   []openrtb2.Protocol{openrtb2.ProtocolVAST10},
+				Protocols:   []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST10},
 				MaxDuration: 30,
 				Linearity:   1,
-				API:         []openrtb2.APIFramework{},
+				API:         []adcom1.APIFramework{},
 			},
 			Ext: json.RawMessage(`{
 				"bidder": {
@@ -1290,13 +1291,13 @@ func TestOpenRTBRequestWithSpecificExtUserEids(t *testing.T) {
 			ID:    "test-imp-id",
 			Instl: 1,
 			Video: &openrtb2.Video{
-				W:           640,
-				H:           360,
+				W:           openrtb2.Int64Ptr(640),
+				H:           openrtb2.Int64Ptr(360),
 				MIMEs:       []string{"video/mp4"},
-				Protocols:   []openrtb2.Protocol{openrtb2.ProtocolVAST10},
+				Protocols:   []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST10},
 				MaxDuration: 30,
 				Linearity:   1,
-				API:         []openrtb2.APIFramework{},
+				API:         []adcom1.APIFramework{},
 			},
 			Ext: json.RawMessage(`{"bidder": {
 				"zoneId": 8394,
@@ -1395,13 +1396,13 @@ func TestOpenRTBRequestWithVideoImpEvenIfImpHasBannerButAllRequiredVideoFields(t
 				},
 			},
 			Video: &openrtb2.Video{
-				W:           640,
-				H:           360,
+				W:           openrtb2.Int64Ptr(640),
+				H:           openrtb2.Int64Ptr(360),
 				MIMEs:       []string{"video/mp4"},
-				Protocols:   []openrtb2.Protocol{openrtb2.ProtocolVAST10},
+				Protocols:   []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST10},
 				MaxDuration: 30,
 				Linearity:   1,
-				API:         []openrtb2.APIFramework{},
+				API:         []adcom1.APIFramework{},
 			},
 			Ext: json.RawMessage(`{"bidder": {
 				"zoneId": 8394,
@@ -1441,13 +1442,13 @@ func TestOpenRTBRequestWithVideoImpAndEnabledRewardedInventoryFlag(t *testing.T)
 		Imp: []openrtb2.Imp{{
 			ID: "test-imp-id",
 			Video: &openrtb2.Video{
-				W:           640,
-				H:           360,
+				W:           openrtb2.Int64Ptr(640),
+				H:           openrtb2.Int64Ptr(360),
 				MIMEs:       []string{"video/mp4"},
-				Protocols:   []openrtb2.Protocol{openrtb2.ProtocolVAST10},
+				Protocols:   []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST10},
 				MaxDuration: 30,
 				Linearity:   1,
-				API:         []openrtb2.APIFramework{},
+				API:         []adcom1.APIFramework{},
 			},
 			Ext: json.RawMessage(`{
 			"prebid":{
@@ -1663,6 +1664,141 @@ func TestOpenRTBCopyBidIdFromResponseIfZero(t *testing.T) {
 	assert.Equal(t, "1234567890", theBid.ID, "Bad bid ID. Expected %s, got %s", "1234567890", theBid.ID)
 }
 
+func buildMRAIDSeat(t *testing.T) adapters.Bidder {
+	bidder, buildErr := Builder(openrtb_ext.BidderRubiconMRAID, config.Adapter{
+		Endpoint: "uri",
+		Seat:     config.AdapterSeat{Of: "rubicon", MRAID: true},
+	})
+	if buildErr != nil {
+		t.Fatalf("Builder returned unexpected error %v", buildErr)
+	}
+	// the MRAID imps are pruned for the seat by the info aware bidder
+	return adapters.BuildInfoAwareBidder(bidder, config.BidderInfo{
+		Capabilities: &config.CapabilitiesInfo{
+			App: &config.PlatformInfo{MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeBanner, openrtb_ext.BidTypeVideo}},
+		},
+		Seat: &config.AdapterSeat{Of: "rubicon", MRAID: true},
+	})
+}
+
+func TestMRAIDSeatRequestWithBannerImpEvenIfImpHasVideo(t *testing.T) {
+	SIZE_ID := getTestSizes()
+	bidder := buildMRAIDSeat(t)
+
+	request := &openrtb2.BidRequest{
+		ID:  "test-request-id",
+		App: &openrtb2.App{ID: "test-app-id"},
+		Imp: []openrtb2.Imp{{
+			ID: "test-imp-id",
+			Banner: &openrtb2.Banner{
+				Format: []openrtb2.Format{
+					SIZE_ID[15],
+					SIZE_ID[10],
+				},
+			},
+			Video: &openrtb2.Video{
+				W:     openrtb2.Int64Ptr(640),
+				H:     openrtb2.Int64Ptr(360),
+				MIMEs: []string{"video/mp4"},
+			},
+			Ext: json.RawMessage(`{"bidder": {
+				"zoneId": 8394,
+				"siteId": 283282,
+				"accountId": 7891,
+				"video": {"size_id": 1},
+				"mraid_supported": true
+			}}`),
+		}, {
+			ID: "test-imp-id-without-mraid",
+			Banner: &openrtb2.Banner{
+				Format: []openrtb2.Format{SIZE_ID[15]},
+			},
+			Ext: json.RawMessage(`{"bidder": {
+				"zoneId": 8394,
+				"siteId": 283282,
+				"accountId": 7891
+			}}`),
+		}},
+	}
+
+	reqs, errs := bidder.MakeRequests(request, &adapters.ExtraRequestInfo{})
+
+	assert.Equal(t, []error{&errortypes.BadInput{Message: "request.imp[1] has no supported MediaTypes. It will be ignored"}}, errs, "the imp without MRAID is dropped")
+
+	assert.Equal(t, 1, len(reqs), "Unexpected number of HTTP requests. Got %d. Expected %d", len(reqs), 1)
+
+	rubiconReq := &openrtb2.BidRequest{}
+	if err := json.Unmarshal(reqs[0].Body, rubiconReq); err != nil {
+		t.Fatalf("Unexpected error while decoding request: %s", err)
+	}
+
+	assert.Equal(t, 1, len(rubiconReq.Imp), "Unexpected number of request impressions. Got %d. Expected %d", len(rubiconReq.Imp), 1)
+
+	assert.Nil(t, rubiconReq.Imp[0].Video, "Unexpected video object in request impression")
+
+	if assert.NotNil(t, rubiconReq.Imp[0].Banner, "Banner object must be in request impression") {
+		var bannerExt rubiconBannerExt
+		assert.NoError(t, json.Unmarshal(rubiconReq.Imp[0].Banner.Ext, &bannerExt))
+		assert.Equal(t, rubiconBannerExt{RP: rubiconBannerExtRP{SizeID: 15, AltSizeIDs: []int{10}, MIME: "text/html"}}, bannerExt)
+	}
+}
+
+func TestMRAIDSeatResponseOverridePriceFromBidRequest(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID: "test-request-id",
+		Imp: []openrtb2.Imp{{
+			ID: "test-imp-id",
+			Banner: &openrtb2.Banner{
+				Format: []openrtb2.Format{{
+					W: 320,
+					H: 50,
+				}},
+			},
+			Ext: json.RawMessage(`{"bidder": {
+				"accountId": 2763,
+				"siteId": 68780,
+				"zoneId": 327642,
+				"mraid_supported": true
+			}}`),
+		}},
+		Ext: json.RawMessage(`{"prebid": {
+			"bidders": {
+				"rubicon": {
+					"debug": {
+						"cpmoverride": 5
+				}},
+				"rubiconmraid": {
+					"debug": {
+						"cpmoverride": 10
+			}}}}}`),
+	}
+
+	requestJson, _ := json.Marshal(request)
+	reqData := &adapters.RequestData{
+		Method:  "POST",
+		Uri:     "test-uri",
+		Body:    requestJson,
+		Headers: nil,
+	}
+
+	httpResp := &adapters.ResponseData{
+		StatusCode: http.StatusOK,
+		Body:       []byte(`{"id":"test-request-id","seatbid":[{"bid":[{"id":"1234567890","impid":"test-imp-id","price": 2,"crid":"4122982","adm":"some ad","h": 50,"w": 320,"ext":{"bidder":{"rp":{"targeting": {"key": "rpfl_2763", "values":["43_tier0100"]},"mime": "text/html","size_id": 43}}}}]}]}`),
+	}
+
+	bidResponse, errs := buildMRAIDSeat(t).MakeBids(request, reqData, httpResp)
+
+	assert.Empty(t, errs, "Expected 0 errors. Got %d", len(errs))
+
+	assert.Equal(t, float64(10), bidResponse.Bids[0].Bid.Price,
+		"Expected Price 10. Got: %s", bidResponse.Bids[0].Bid.Price)
+}
+
+func TestLegacySeatName(t *testing.T) {
+	an := NewRubiconLegacySeatAdapter("rubiconmraid", adapters.DefaultHTTPAdapterConfig, "uri", "xuser", "xpass", "pbs-test-tracker", "", "", "", "")
+	assert.Equal(t, "rubiconmraid", an.Name(), "Name '%s' != 'rubiconmraid'", an.Name())
+}
+
 func TestJsonSamples(t *testing.T) {
 	bidder, buildErr := Builder(openrtb_ext.BidderRubicon, config.Adapter{
 		Endpoint: "uri",
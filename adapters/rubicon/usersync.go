@@ -10,3 +10,11 @@ import (
 func NewRubiconSyncer(temp *template.Template) usersync.Usersyncer {
 	return adapters.NewSyncer("rubicon", temp, adapters.SyncTypeRedirect)
 }
+
+// NewRubiconSeatSyncer returns the syncer factory of a seat of the Rubicon adapter, which syncs under its own
+// bidder code.
+func NewRubiconSeatSyncer(bidderName string) func(temp *template.Template) usersync.Usersyncer {
+	return func(temp *template.Template) usersync.Usersyncer {
+		return adapters.NewSyncer(bidderName, temp, adapters.SyncTypeRedirect)
+	}
+}
@@ -28,3 +28,12 @@ func TestRubiconSyncer(t *testing.T) {
 	assert.Equal(t, false, syncInfo.SupportCORS)
 	assert.Equal(t, "rubicon", syncer.FamilyName())
 }
+
+func TestRubiconSeatSyncer(t *testing.T) {
+	syncURLTemplate := template.Must(
+		template.New("sync-template").Parse("https://pixel.rubiconproject.com/exchange/sync.php?p=prebid"),
+	)
+
+	syncer := NewRubiconSeatSyncer("rubiconmraid")(syncURLTemplate)
+	assert.Equal(t, "rubiconmraid", syncer.FamilyName())
+}
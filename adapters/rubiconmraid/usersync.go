@@ -1,12 +0,0 @@
-package rubiconmraid
-
-import (
-	"text/template"
-
-	"github.com/prebid/prebid-server/adapters"
-	"github.com/prebid/prebid-server/usersync"
-)
-
-func NewRubiconSyncer(temp *template.Template) usersync.Usersyncer {
-	return adapters.NewSyncer("rubiconmraid", temp, adapters.SyncTypeRedirect)
-}
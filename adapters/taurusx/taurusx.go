@@ -7,7 +7,7 @@ import (
 	"github.com/prebid/prebid-server/config"
 	"net/http"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/cache/skanidlist"
 	"github.com/prebid/prebid-server/errortypes"
@@ -15,20 +15,6 @@ import (
 	"github.com/prebid/prebid-server/pbs"
 )
 
-// Region ...
-type Region string
-
-const (
-	USEast Region = "us_east"
-	JP     Region = "jp"
-	SG     Region = "sg"
-)
-
-// SKAN IDs must be lower case
-var taurusxExtSKADNetIDs = map[string]bool{
-	"22mmun2rn5.skadnetwork": true,
-}
-
 type taurusxVideoExt struct {
 	Rewarded int `json:"rewarded"`
 }
@@ -43,9 +29,8 @@ type taurusxImpExt struct {
 }
 
 type adapter struct {
-	http             *adapters.HTTPAdapter
-	endpoint         string
-	SupportedRegions map[Region]string
+	http    *adapters.HTTPAdapter
+	regions *adapters.RegionResolver
 }
 
 func (adapter *adapter) Name() string {
@@ -62,12 +47,7 @@ func (adapter *adapter) Call(_ context.Context, _ *pbs.PBSRequest, _ *pbs.PBSBid
 
 func Builder(_ openrtb_ext.BidderName, config config.Adapter) (adapters.Bidder, error) {
 	bidder := &adapter{
-		endpoint: config.Endpoint,
-		SupportedRegions: map[Region]string{
-			USEast: config.XAPI.EndpointUSEast,
-			JP:     config.XAPI.EndpointJP,
-			SG:     config.XAPI.EndpointSG,
-		},
+		regions: adapters.NewRegionResolverFromConfig(config),
 	}
 	return bidder, nil
 }
@@ -78,18 +58,17 @@ func NewTaurusXLegacyAdapter(config *adapters.HTTPAdapterConfig, uri, useast, jp
 
 func NewTaurusXBidder(client *http.Client, uri, useast, jp, sg string) *adapter {
 	return &adapter{
-		http:     &adapters.HTTPAdapter{Client: client},
-		endpoint: uri,
-		SupportedRegions: map[Region]string{
-			USEast: useast,
-			JP:     jp,
-			SG:     sg,
-		},
+		http: &adapters.HTTPAdapter{Client: client},
+		regions: adapters.NewRegionResolver(uri, map[string]config.AdapterRegion{
+			config.RegionUSEast: {Endpoint: useast},
+			config.RegionJP:     {Endpoint: jp},
+			config.RegionSG:     {Endpoint: sg},
+		}, ""),
 	}
 }
 
 // MakeRequests ...
-func (adapter *adapter) MakeRequests(request *openrtb2.BidRequest, _ *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+func (adapter *adapter) MakeRequests(request *openrtb2.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
 	numRequests := len(request.Imp)
 
 	requestData := make([]*adapters.RequestData, 0, numRequests)
@@ -111,6 +90,7 @@ func (adapter *adapter) MakeRequests(request *openrtb2.BidRequest, _ *adapters.E
 
 		// clone current imp
 		thisImp := requestImpCopy[i]
+		placement := reqInfo.Placement(&thisImp)
 
 		// extract bidder extension
 		var bidderExt adapters.ExtImpBidder
@@ -131,7 +111,7 @@ func (adapter *adapter) MakeRequests(request *openrtb2.BidRequest, _ *adapters.E
 		}
 
 		impVideoExt := taurusxVideoExt{
-			Rewarded: taurusxExt.Reward,
+			Rewarded: placement.Reward(),
 		}
 
 		if thisImp.Video != nil {
@@ -150,7 +130,7 @@ func (adapter *adapter) MakeRequests(request *openrtb2.BidRequest, _ *adapters.E
 			if taurusxExt.MRAIDSupported {
 				bannerCopy := *thisImp.Banner
 				bannerExt := taurusxBannerExt{
-					Rewarded:                taurusxExt.Reward,
+					Rewarded:                placement.Reward(),
 					AllowsCustomCloseButton: false,
 				}
 				bannerCopy.Ext, err = json.Marshal(&bannerExt)
@@ -172,7 +152,7 @@ func (adapter *adapter) MakeRequests(request *openrtb2.BidRequest, _ *adapters.E
 			skadn := adapters.FilterPrebidSKADNExt(bidderExt.Prebid, skanIDList)
 
 			// only add if present
-			if len(skadn.SKADNetIDs) > 0 {
+			if adapters.SKADNRequested(skadn, skanIDList) {
 				impExt.SKADN = &skadn
 				skanSent = true
 			}
@@ -194,31 +174,21 @@ func (adapter *adapter) MakeRequests(request *openrtb2.BidRequest, _ *adapters.E
 			continue
 		}
 
-		// assign the default uri
-		uri := adapter.endpoint
-
-		// assign a region based uri if it exists
-		if endpoint, ok := adapter.SupportedRegions[Region(taurusxExt.Region)]; ok {
-			uri = endpoint
-		}
-
-		// Tapjoy Record placement type
-		placementType := adapters.Interstitial
-		if taurusxExt.Reward == 1 {
-			placementType = adapters.Rewarded
-		}
+		region := adapter.regions.Resolve(taurusxExt.Region, request)
 
 		// build request data object
 		reqData := &adapters.RequestData{
-			Method:  "POST",
-			Uri:     uri,
-			Body:    reqJSON,
-			Headers: headers,
+			Method:         "POST",
+			Uri:            region.Uri,
+			Body:           reqJSON,
+			Headers:        headers,
+			FallbackUri:    region.FallbackUri,
+			FallbackRegion: region.FallbackRegion,
 
 			TapjoyData: adapters.TapjoyData{
 				Bidder:        adapter.Name(),
-				PlacementType: placementType,
-				Region:        taurusxExt.Region,
+				PlacementType: placement.Type,
+				Region:        region.Region,
 				SKAN: adapters.SKAN{
 					Supported: taurusxExt.SKADNSupported,
 					Sent:      skanSent,
@@ -284,6 +254,7 @@ func (adapter *adapter) MakeBids(_ *openrtb2.BidRequest, externalRequest *adapte
 				bidResponse.Bids = append(bidResponse.Bids, &adapters.TypedBid{
 					Bid:     &b,
 					BidType: bidType,
+					Seat:    sb.Seat,
 				})
 			}
 		}
@@ -6,28 +6,16 @@ import (
 	"net/http"
 
 	"github.com/buger/jsonparser"
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/cache/skanidlist"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/openrtb_ext"
 )
 
-// Region ...
-type Region string
-
-const (
-	JP Region = "jp"
-)
-
-// SKAN IDs must be lower case
-var unicornExtSKADNetIDs = map[string]bool{
-	"578prtvx9j.skadnetwork": true,
-}
-
 type adapter struct {
-	endpoint         string
-	SupportedRegions map[Region]string
+	regions *adapters.RegionResolver
 }
 
 // unicornImpExt is imp ext for UNICORN
@@ -59,10 +47,7 @@ type unicornVideoExt struct {
 // Builder builds a new instance of the UNICORN adapter for the given bidder with the given config.
 func Builder(bidderName openrtb_ext.BidderName, config config.Adapter) (adapters.Bidder, error) {
 	bidder := &adapter{
-		endpoint: config.Endpoint,
-		SupportedRegions: map[Region]string{
-			JP: config.XAPI.EndpointJP,
-		},
+		regions: adapters.NewRegionResolverFromConfig(config),
 	}
 	return bidder, nil
 }
@@ -111,6 +96,7 @@ func (a *adapter) MakeRequests(request *openrtb2.BidRequest, requestInfo *adapte
 
 		// clone current imp
 		thisImp := requestImpCopy[i]
+		placement := requestInfo.Placement(&thisImp)
 
 		// extract bidder extension
 		var bidderExt adapters.ExtImpBidder
@@ -135,7 +121,7 @@ func (a *adapter) MakeRequests(request *openrtb2.BidRequest, requestInfo *adapte
 				bannerCopy := *thisImp.Banner
 
 				bannerExt := unicornBannerExt{
-					Rewarded:                unicornExt.Reward,
+					Rewarded:                placement.Reward(),
 					AllowsCustomCloseButton: false,
 				}
 				bannerCopy.Ext, err = json.Marshal(&bannerExt)
@@ -154,7 +140,7 @@ func (a *adapter) MakeRequests(request *openrtb2.BidRequest, requestInfo *adapte
 			videoCopy := *thisImp.Video
 
 			videoExt := unicornVideoExt{
-				Rewarded: unicornExt.Reward,
+				Rewarded: placement.Reward(),
 			}
 
 			videoCopy.Ext, err = json.Marshal(&videoExt)
@@ -169,9 +155,10 @@ func (a *adapter) MakeRequests(request *openrtb2.BidRequest, requestInfo *adapte
 		impExt := unicornImpExt{}
 
 		if unicornExt.SKADNSupported {
-			skadn := adapters.FilterPrebidSKADNExt(bidderExt.Prebid, unicornExtSKADNetIDs)
+			skanIDList := skanidlist.Get(openrtb_ext.BidderUnicorn)
+			skadn := adapters.FilterPrebidSKADNExt(bidderExt.Prebid, skanIDList)
 			// only add if present
-			if len(skadn.SKADNetIDs) > 0 {
+			if adapters.SKADNRequested(skadn, skanIDList) {
 				impExt.SKADN = &skadn
 				skanSent = true
 			}
@@ -214,31 +201,21 @@ func (a *adapter) MakeRequests(request *openrtb2.BidRequest, requestInfo *adapte
 			continue
 		}
 
-		// assign the default uri
-		uri := a.endpoint
-
-		// assign a region based uri if it exists
-		if endpoint, ok := a.SupportedRegions[Region(unicornExt.Region)]; ok {
-			uri = endpoint
-		}
-
-		// Tapjoy Record placement type
-		placementType := adapters.Interstitial
-		if unicornExt.Reward == 1 {
-			placementType = adapters.Rewarded
-		}
+		region := a.regions.Resolve(unicornExt.Region, request)
 
 		// build request data object
 		reqData := &adapters.RequestData{
-			Method:  "POST",
-			Uri:     uri,
-			Body:    reqJSON,
-			Headers: headers,
+			Method:         "POST",
+			Uri:            region.Uri,
+			Body:           reqJSON,
+			Headers:        headers,
+			FallbackUri:    region.FallbackUri,
+			FallbackRegion: region.FallbackRegion,
 
 			TapjoyData: adapters.TapjoyData{
 				Bidder:        "unicorn",
-				PlacementType: placementType,
-				Region:        unicornExt.Region,
+				PlacementType: placement.Type,
+				Region:        region.Region,
 				SKAN: adapters.SKAN{
 					Supported: unicornExt.SKADNSupported,
 					Sent:      skanSent,
@@ -394,6 +371,7 @@ func (a *adapter) MakeBids(request *openrtb2.BidRequest, requestData *adapters.R
 			b := &adapters.TypedBid{
 				Bid:     &bid,
 				BidType: bidType,
+				Seat:    seatBid.Seat,
 			}
 			bidResponse.Bids = append(bidResponse.Bids, b)
 		}
@@ -11,7 +11,7 @@ import (
 
 	"golang.org/x/text/currency"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/errortypes"
@@ -62,8 +62,15 @@ func (a *YieldlabAdapter) makeEndpointURL(req *openrtb2.BidRequest, params *open
 		}
 
 		if req.Device.Geo != nil {
-			q.Set("lat", fmt.Sprintf("%v", req.Device.Geo.Lat))
-			q.Set("lon", fmt.Sprintf("%v", req.Device.Geo.Lon))
+			var lat, lon float64
+			if req.Device.Geo.Lat != nil {
+				lat = *req.Device.Geo.Lat
+			}
+			if req.Device.Geo.Lon != nil {
+				lon = *req.Device.Geo.Lon
+			}
+			q.Set("lat", fmt.Sprintf("%v", lat))
+			q.Set("lon", fmt.Sprintf("%v", lon))
 		}
 	}
 
@@ -0,0 +1,239 @@
+package biddercalls
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/golang/glog"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/analytics"
+	"github.com/prebid/prebid-server/config"
+)
+
+// endpoints the records come from
+const (
+	auction = "auction"
+	amp     = "amp"
+	video   = "video"
+)
+
+// record is the JSON line written for every outbound bidder call
+type record struct {
+	Timestamp int64  `json:"ts"`
+	Endpoint  string `json:"endpoint"`
+	RequestID string `json:"request_id"`
+	AccountID string `json:"account_id,omitempty"`
+	AppBundle string `json:"app_bundle,omitempty"`
+	Country   string `json:"country,omitempty"`
+	*analytics.BidderCall
+}
+
+type bufferConfig struct {
+	timeout time.Duration
+	count   int
+	size    int64
+}
+
+// BidderCallsModule is an analytics module emitting one JSON record per outbound bidder call of the
+// auction, amp and video endpoints. Records are queued without blocking the auction, dropped when the
+// queue is full, and written to the sink in batches.
+type BidderCallsModule struct {
+	// dropped is first to be 64-bit aligned for the atomic operations
+	dropped  int64
+	sink     Sink
+	buffsCfg bufferConfig
+	events   chan []byte
+	closeCh  chan chan struct{}
+	closing  sync.Once
+}
+
+func NewBidderCallsModule(sink Sink, queueSize, maxEventCount int, maxByteSize, maxTime string) (*BidderCallsModule, error) {
+	pDuration, err := time.ParseDuration(maxTime)
+	if err != nil {
+		return nil, fmt.Errorf("fail to parse the module args, arg=analytics.bidder_calls.buffers.timeout, :%v", err)
+	}
+	pSize, err := units.FromHumanSize(maxByteSize)
+	if err != nil {
+		return nil, fmt.Errorf("fail to parse the module args, arg=analytics.bidder_calls.buffers.size, :%v", err)
+	}
+
+	m := &BidderCallsModule{
+		sink: sink,
+		buffsCfg: bufferConfig{
+			timeout: pDuration,
+			count:   maxEventCount,
+			size:    pSize,
+		},
+		events:  make(chan []byte, queueSize),
+		closeCh: make(chan chan struct{}),
+	}
+	go m.start()
+	return m, nil
+}
+
+// NewModuleFromConfig builds the module and its sink from the analytics.bidder_calls config,
+// registering it to flush its buffer when the process is terminated.
+func NewModuleFromConfig(cfg config.BidderCallLogs, client *http.Client) (analytics.PBSAnalyticsModule, error) {
+	var sink Sink
+	if cfg.Endpoint != "" {
+		sink = NewHttpSink(client, cfg.Endpoint)
+	} else {
+		maxSize, err := units.FromHumanSize(cfg.File.MaxSize)
+		if err != nil {
+			return nil, fmt.Errorf("fail to parse the module args, arg=analytics.bidder_calls.file.max_size, :%v", err)
+		}
+		rotateInterval, err := time.ParseDuration(cfg.File.RotateInterval)
+		if err != nil {
+			return nil, fmt.Errorf("fail to parse the module args, arg=analytics.bidder_calls.file.rotate_interval, :%v", err)
+		}
+		if sink, err = NewFileSink(cfg.File.Filename, maxSize, rotateInterval); err != nil {
+			return nil, err
+		}
+	}
+
+	m, err := NewBidderCallsModule(sink, cfg.Buffers.QueueSize, cfg.Buffers.EventCount, cfg.Buffers.BufferSize, cfg.Buffers.Timeout)
+	if err != nil {
+		sink.Close()
+		return nil, err
+	}
+
+	sigTermCh := make(chan os.Signal, 1)
+	signal.Notify(sigTermCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigTermCh
+		m.Close()
+	}()
+	return m, nil
+}
+
+func (m *BidderCallsModule) LogAuctionObject(ao *analytics.AuctionObject) {
+	if ao == nil {
+		return
+	}
+	m.logBidderCalls(auction, ao.Request, ao.Account, ao.StartTime, ao.BidderCalls)
+}
+
+func (m *BidderCallsModule) LogAmpObject(ao *analytics.AmpObject) {
+	if ao == nil {
+		return
+	}
+	m.logBidderCalls(amp, ao.Request, nil, ao.StartTime, ao.BidderCalls)
+}
+
+func (m *BidderCallsModule) LogVideoObject(vo *analytics.VideoObject) {
+	if vo == nil {
+		return
+	}
+	m.logBidderCalls(video, vo.Request, nil, vo.StartTime, vo.BidderCalls)
+}
+
+func (m *BidderCallsModule) LogCookieSyncObject(cso *analytics.CookieSyncObject) {
+}
+
+func (m *BidderCallsModule) LogSetUIDObject(so *analytics.SetUIDObject) {
+}
+
+func (m *BidderCallsModule) LogNotificationEventObject(ne *analytics.NotificationEvent) {
+}
+
+// Close flushes the buffered records and closes the sink. Records logged afterwards are dropped.
+func (m *BidderCallsModule) Close() {
+	m.closing.Do(func() {
+		done := make(chan struct{})
+		m.closeCh <- done
+		<-done
+	})
+}
+
+func (m *BidderCallsModule) logBidderCalls(endpoint string, request *openrtb2.BidRequest, account *config.Account, startTime time.Time, calls []*analytics.BidderCall) {
+	if len(calls) == 0 {
+		return
+	}
+
+	r := record{
+		Timestamp: startTime.UnixNano() / int64(time.Millisecond),
+		Endpoint:  endpoint,
+	}
+	if request != nil {
+		r.RequestID = request.ID
+		if request.App != nil {
+			r.AppBundle = request.App.Bundle
+		}
+		if request.Device != nil && request.Device.Geo != nil {
+			r.Country = request.Device.Geo.Country
+		}
+	}
+	if account != nil {
+		r.AccountID = account.ID
+	}
+
+	for _, call := range calls {
+		r.BidderCall = call
+		payload, err := json.Marshal(r)
+		if err != nil {
+			glog.Warningf("[bidder_calls] Cannot serialize the call to %s: %v", call.Bidder, err)
+			continue
+		}
+		select {
+		case m.events <- payload:
+		default:
+			atomic.AddInt64(&m.dropped, 1)
+		}
+	}
+}
+
+func (m *BidderCallsModule) start() {
+	ticker := time.NewTicker(m.buffsCfg.timeout)
+	defer ticker.Stop()
+
+	var buff bytes.Buffer
+	count := 0
+	flush := func() {
+		if dropped := atomic.SwapInt64(&m.dropped, 0); dropped > 0 {
+			glog.Warningf("[bidder_calls] Dropped %d records, the queue was full", dropped)
+		}
+		if count == 0 {
+			return
+		}
+		if err := m.sink.Write(buff.Bytes()); err != nil {
+			glog.Errorf("[bidder_calls] Fail to write %d records: %v", count, err)
+		}
+		buff.Reset()
+		count = 0
+	}
+
+	for {
+		select {
+		case done := <-m.closeCh:
+			for len(m.events) > 0 {
+				buff.Write(<-m.events)
+				buff.WriteByte('\n')
+				count++
+			}
+			flush()
+			if err := m.sink.Close(); err != nil {
+				glog.Errorf("[bidder_calls] Fail to close the sink: %v", err)
+			}
+			close(done)
+			return
+		case event := <-m.events:
+			buff.Write(event)
+			buff.WriteByte('\n')
+			count++
+			if count >= m.buffsCfg.count || int64(buff.Len()) >= m.buffsCfg.size {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package biddercalls
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/analytics"
+	"github.com/prebid/prebid-server/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSink struct {
+	mux     sync.Mutex
+	batches []string
+	closed  bool
+}
+
+func (s *mockSink) Write(batch []byte) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.batches = append(s.batches, string(batch))
+	return nil
+}
+
+func (s *mockSink) Close() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *mockSink) getBatches() []string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.batches
+}
+
+func TestNewBidderCallsModuleErrors(t *testing.T) {
+	_, err := NewBidderCallsModule(&mockSink{}, 10, 10, "1MB", "15invalid")
+	assert.Error(t, err)
+
+	_, err = NewBidderCallsModule(&mockSink{}, 10, 10, "1invalid", "1s")
+	assert.Error(t, err)
+}
+
+func TestLogAuctionObject(t *testing.T) {
+	sink := &mockSink{}
+	module, err := NewBidderCallsModule(sink, 10, 2, "1MB", "1h")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	module.LogAuctionObject(&analytics.AuctionObject{
+		Request: &openrtb2.BidRequest{
+			ID:     "request-id",
+			App:    &openrtb2.App{Bundle: "com.example.app"},
+			Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA"}},
+		},
+		Account:   &config.Account{ID: "account-id"},
+		StartTime: time.Unix(1600000000, 0),
+		BidderCalls: []*analytics.BidderCall{
+			{Bidder: "appnexus", SKANSent: true, Status: 200, BidCount: 1, Price: 1.5, Currency: "USD"},
+			{Bidder: "rubicon", Timeout: true, ErrorType: "timeout"},
+		},
+	})
+
+	// the batch is written once it holds 2 records
+	assert.Eventually(t, func() bool { return len(sink.getBatches()) == 1 }, time.Second, 5*time.Millisecond)
+
+	lines := strings.Split(strings.TrimSuffix(sink.getBatches()[0], "\n"), "\n")
+	if assert.Len(t, lines, 2) {
+		var first map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+		assert.Equal(t, map[string]interface{}{
+			"ts":                 1600000000000.0,
+			"endpoint":           "auction",
+			"request_id":         "request-id",
+			"account_id":         "account-id",
+			"app_bundle":         "com.example.app",
+			"country":            "USA",
+			"bidder":             "appnexus",
+			"region_fallback":    false,
+			"hedged":             false,
+			"skan_supported":     false,
+			"skan_sent":          true,
+			"mraid_supported":    false,
+			"status":             200.0,
+			"latency_ms":         0.0,
+			"bid_count":          1.0,
+			"rejected_bid_count": 0.0,
+			"price":              1.5,
+			"currency":           "USD",
+			"timeout":            false,
+		}, first)
+		assert.Contains(t, lines[1], `"error_type":"timeout"`)
+	}
+}
+
+func TestCloseFlushesBuffer(t *testing.T) {
+	sink := &mockSink{}
+	module, err := NewBidderCallsModule(sink, 10, 100, "1MB", "1h")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	module.LogAmpObject(&analytics.AmpObject{BidderCalls: []*analytics.BidderCall{{Bidder: "appnexus"}}})
+	module.LogVideoObject(&analytics.VideoObject{BidderCalls: []*analytics.BidderCall{{Bidder: "appnexus"}}})
+	module.LogAuctionObject(&analytics.AuctionObject{})
+	module.Close()
+	module.Close()
+
+	if assert.Len(t, sink.getBatches(), 1) {
+		assert.Equal(t, 2, strings.Count(sink.getBatches()[0], "\n"))
+		assert.Contains(t, sink.getBatches()[0], `"endpoint":"amp"`)
+		assert.Contains(t, sink.getBatches()[0], `"endpoint":"video"`)
+	}
+	assert.True(t, sink.closed)
+}
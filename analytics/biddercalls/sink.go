@@ -0,0 +1,136 @@
+package biddercalls
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Sink receives batches of newline delimited JSON records. Write and Close are only called
+// from the goroutine of the module, so implementations don't need to be safe for concurrent use.
+type Sink interface {
+	Write(batch []byte) error
+	Close() error
+}
+
+// FileSink appends the records to a file, which is rotated once it grows over maxSize or
+// once rotateInterval has passed since it was opened. The rotated files are renamed with
+// the time of their rotation as a suffix, abc.log-YYYYMMDD-HHMMSS.
+type FileSink struct {
+	filename       string
+	maxSize        int64
+	rotateInterval time.Duration
+
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	now      func() time.Time
+}
+
+// NewFileSink opens filename for appending. A zero maxSize or rotateInterval disables the
+// corresponding rotation.
+func NewFileSink(filename string, maxSize int64, rotateInterval time.Duration) (*FileSink, error) {
+	sink := &FileSink{
+		filename:       filename,
+		maxSize:        maxSize,
+		rotateInterval: rotateInterval,
+		now:            time.Now,
+	}
+	if err := sink.open(); err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+func (s *FileSink) Write(batch []byte) error {
+	if s.shouldRotate(int64(len(batch))) {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.file.Write(batch)
+	s.size += int64(n)
+	return err
+}
+
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+func (s *FileSink) open() error {
+	file, err := os.OpenFile(s.filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	s.file = file
+	s.size = info.Size()
+	s.openedAt = s.now()
+	return nil
+}
+
+func (s *FileSink) shouldRotate(batchSize int64) bool {
+	if s.size == 0 {
+		return false
+	}
+	if s.maxSize > 0 && s.size+batchSize > s.maxSize {
+		return true
+	}
+	return s.rotateInterval > 0 && s.now().Sub(s.openedAt) >= s.rotateInterval
+}
+
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	rotated := s.filename + "-" + s.now().Format("20060102-150405")
+	if _, err := os.Stat(rotated); err == nil {
+		rotated = fmt.Sprintf("%s-%d", rotated, s.now().UnixNano())
+	}
+	if err := os.Rename(s.filename, rotated); err != nil {
+		return err
+	}
+	return s.open()
+}
+
+// HttpSink posts every batch to an endpoint as newline delimited JSON.
+type HttpSink struct {
+	client   *http.Client
+	endpoint string
+}
+
+func NewHttpSink(client *http.Client, endpoint string) *HttpSink {
+	return &HttpSink{
+		client:   client,
+		endpoint: endpoint,
+	}
+}
+
+func (s *HttpSink) Write(batch []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, bytes.NewReader(batch))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("wrong code received %d from %s", resp.StatusCode, s.endpoint)
+	}
+	return nil
+}
+
+func (s *HttpSink) Close() error {
+	return nil
+}
@@ -0,0 +1,71 @@
+package biddercalls
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSinkRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "biddercalls")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "calls.log")
+	sink, err := NewFileSink(filename, 11, time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	sink.now = func() time.Time { return now }
+	sink.openedAt = now
+
+	assert.NoError(t, sink.Write([]byte("12345\n")))
+	assert.NoError(t, sink.Write([]byte("1234\n")), "fits in the max size")
+
+	now = now.Add(time.Minute)
+	assert.NoError(t, sink.Write([]byte("1\n")), "rotated by size")
+
+	now = now.Add(time.Hour)
+	assert.NoError(t, sink.Write([]byte("2\n")), "rotated by time")
+	assert.NoError(t, sink.Close())
+
+	assertFileContent(t, filename+"-20210601-100100", "12345\n1234\n")
+	assertFileContent(t, filename+"-20210601-110100", "1\n")
+	assertFileContent(t, filename, "2\n")
+}
+
+func TestHttpSink(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		if r.Header.Get("Content-Type") != "application/x-ndjson" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	sink := NewHttpSink(server.Client(), server.URL)
+	assert.NoError(t, sink.Write([]byte("{}\n")))
+	assert.Equal(t, "{}\n", body)
+
+	sink = NewHttpSink(server.Client(), server.URL+"\n")
+	assert.Error(t, sink.Write([]byte("{}\n")))
+}
+
+func assertFileContent(t *testing.T, filename, expected string) {
+	t.Helper()
+	content, err := ioutil.ReadFile(filename)
+	if assert.NoError(t, err, filename) {
+		assert.Equal(t, expected, string(content), filename)
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"github.com/golang/glog"
 	"github.com/prebid/prebid-server/analytics"
+	"github.com/prebid/prebid-server/analytics/biddercalls"
 	"github.com/prebid/prebid-server/analytics/clients"
 	"github.com/prebid/prebid-server/analytics/filesystem"
 	"github.com/prebid/prebid-server/analytics/pubstack"
@@ -34,6 +35,13 @@ func NewPBSAnalytics(analytics *config.Analytics) analytics.PBSAnalyticsModule {
 			glog.Errorf("Could not initialize PubstackModule: %v", err)
 		}
 	}
+	if analytics.BidderCalls.Enabled {
+		if mod, err := biddercalls.NewModuleFromConfig(analytics.BidderCalls, clients.GetDefaultHttpInstance()); err == nil {
+			modules = append(modules, mod)
+		} else {
+			glog.Errorf("Could not initialize BidderCallsModule: %v", err)
+		}
+	}
 	return modules
 }
 
@@ -3,7 +3,7 @@ package analytics
 import (
 	"time"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/openrtb_ext"
 	"github.com/prebid/prebid-server/usersync"
@@ -26,7 +26,7 @@ type PBSAnalyticsModule interface {
 	LogNotificationEventObject(*NotificationEvent)
 }
 
-//Loggable object of a transaction at /openrtb2/auction endpoint
+// Loggable object of a transaction at /openrtb2/auction endpoint
 type AuctionObject struct {
 	Status    int
 	Errors    []error
@@ -34,9 +34,13 @@ type AuctionObject struct {
 	Response  *openrtb2.BidResponse
 	Account   *config.Account
 	StartTime time.Time
+	// BidderCalls are the outbound calls made to the bidders during the auction
+	BidderCalls []*BidderCall
+	// DealDeliveries are the account deals offered to the bidders during the auction
+	DealDeliveries []*DealDelivery
 }
 
-//Loggable object of a transaction at /openrtb2/amp endpoint
+// Loggable object of a transaction at /openrtb2/amp endpoint
 type AmpObject struct {
 	Status             int
 	Errors             []error
@@ -45,20 +49,66 @@ type AmpObject struct {
 	AmpTargetingValues map[string]string
 	Origin             string
 	StartTime          time.Time
+	BidderCalls        []*BidderCall
+	DealDeliveries     []*DealDelivery
 }
 
-//Loggable object of a transaction at /openrtb2/video endpoint
+// Loggable object of a transaction at /openrtb2/video endpoint
 type VideoObject struct {
-	Status        int
-	Errors        []error
-	Request       *openrtb2.BidRequest
-	Response      *openrtb2.BidResponse
-	VideoRequest  *openrtb_ext.BidRequestVideo
-	VideoResponse *openrtb_ext.BidResponseVideo
-	StartTime     time.Time
+	Status         int
+	Errors         []error
+	Request        *openrtb2.BidRequest
+	Response       *openrtb2.BidResponse
+	VideoRequest   *openrtb_ext.BidRequestVideo
+	VideoResponse  *openrtb_ext.BidResponseVideo
+	StartTime      time.Time
+	BidderCalls    []*BidderCall
+	DealDeliveries []*DealDelivery
 }
 
-//Loggable object of a transaction at /setuid
+// BidderCall is the outcome of one outbound http call to a bidder, along with the Tapjoy data
+// the adapter attached to the request
+type BidderCall struct {
+	Bidder         string `json:"bidder"`
+	PlacementType  string `json:"placement_type,omitempty"`
+	Region         string `json:"region,omitempty"`
+	RegionFallback bool   `json:"region_fallback"`
+	Hedged         bool   `json:"hedged"`
+	HedgeWinner    string `json:"hedge_winner,omitempty"`
+	SKANSupported  bool   `json:"skan_supported"`
+	SKANSent       bool   `json:"skan_sent"`
+	MRAIDSupported bool   `json:"mraid_supported"`
+
+	// Status is the http status of the response, 0 if no response was received
+	Status        int   `json:"status"`
+	LatencyMillis int64 `json:"latency_ms"`
+	// BidCount is the number of bids of the response entering the auction, RejectedBidCount the number of
+	// bids dropped by the validation of the exchange
+	BidCount         int `json:"bid_count"`
+	RejectedBidCount int `json:"rejected_bid_count"`
+	// Price is the highest bid price entering the auction, adjusted and converted to Currency
+	Price    float64 `json:"price,omitempty"`
+	Currency string  `json:"currency,omitempty"`
+	Timeout  bool    `json:"timeout"`
+	// ErrorType is the metrics.AdapterError of the first fatal error of the call, if any,
+	// or circuit_open if the call was skipped by the circuit breaker of the endpoint
+	ErrorType string `json:"error_type,omitempty"`
+}
+
+// DealDelivery is an account deal offered to a bidder on an imp, with the best bid of the bidder on the deal
+type DealDelivery struct {
+	DealID string `json:"deal_id"`
+	Bidder string `json:"bidder"`
+	ImpID  string `json:"imp_id"`
+	// Bid is set when the bidder bid on the deal, Price is then the adjusted price in Currency
+	Bid      bool    `json:"bid"`
+	Price    float64 `json:"price,omitempty"`
+	Currency string  `json:"currency,omitempty"`
+	// Won is set when the bid on the deal is the winning bid of the imp
+	Won bool `json:"won"`
+}
+
+// Loggable object of a transaction at /setuid
 type SetUIDObject struct {
 	Status  int
 	Bidder  string
@@ -67,7 +117,7 @@ type SetUIDObject struct {
 	Success bool
 }
 
-//Loggable object of a transaction at /cookie_sync
+// Loggable object of a transaction at /cookie_sync
 type CookieSyncObject struct {
 	Status       int
 	Errors       []error
@@ -0,0 +1,276 @@
+package biddercontrol
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/config"
+)
+
+// statsBuckets is the number of one minute buckets the recent outcomes of a bidder are counted in
+const statsBuckets = 5
+
+// Outcome is the outcome of a request to a bidder, as counted in the recent stats
+type Outcome int
+
+const (
+	Succeeded Outcome = iota
+	Failed
+	TimedOut
+	Throttled
+)
+
+// Rule throttles a bidder until it expires. A rule with an account or a platform only applies to the
+// requests of that account or platform.
+type Rule struct {
+	ID     string `json:"id"`
+	Bidder string `json:"bidder"`
+	// Percent is the share of the traffic not sent to the bidder, 100 disables it
+	Percent  int       `json:"percent"`
+	Account  string    `json:"account,omitempty"`
+	Platform string    `json:"platform,omitempty"`
+	Expires  time.Time `json:"expires"`
+}
+
+// Stats counts the outcomes of the requests to a bidder over the last few minutes
+type Stats struct {
+	Requests    int     `json:"requests"`
+	Errors      int     `json:"errors"`
+	Timeouts    int     `json:"timeouts"`
+	Throttled   int     `json:"throttled"`
+	ErrorRate   float64 `json:"error_rate"`
+	TimeoutRate float64 `json:"timeout_rate"`
+}
+
+// Status is the live status of a bidder
+type Status struct {
+	Bidder string `json:"bidder"`
+	// Enabled is false for the bidders disabled in the host config, which no rule can enable
+	Enabled bool   `json:"enabled"`
+	Rules   []Rule `json:"rules"`
+	Recent  Stats  `json:"recent"`
+}
+
+// Controls holds the throttling rules set at runtime through the admin endpoints, and the recent outcomes
+// of the bidders. A nil Controls allows every request.
+type Controls struct {
+	bidders map[string]bool
+	now     func() time.Time
+	// percentile returns a number in [0, 100) deciding whether a throttled request is dropped
+	percentile func() int
+
+	mux    sync.RWMutex
+	rules  map[string]Rule
+	lastID int
+	stats  map[string]*[statsBuckets]bucket
+}
+
+type bucket struct {
+	minute    int64
+	requests  int
+	errors    int
+	timeouts  int
+	throttled int
+}
+
+// NewControls creates the controls of the bidders
+func NewControls(infos config.BidderInfos) *Controls {
+	bidders := make(map[string]bool, len(infos))
+	for name, info := range infos {
+		bidders[name] = info.Enabled
+	}
+	return &Controls{
+		bidders:    bidders,
+		now:        time.Now,
+		percentile: func() int { return rand.Intn(100) },
+		rules:      make(map[string]Rule),
+		stats:      make(map[string]*[statsBuckets]bucket),
+	}
+}
+
+// AddRule validates the rule and applies it until it expires. The rule is returned with its ID.
+func (c *Controls) AddRule(rule Rule) (Rule, error) {
+	if c == nil {
+		return rule, errors.New("bidder controls are not available")
+	}
+	if _, ok := c.bidders[rule.Bidder]; !ok {
+		return rule, fmt.Errorf("unknown bidder %q", rule.Bidder)
+	}
+	if rule.Percent < 1 || rule.Percent > 100 {
+		return rule, fmt.Errorf("percent must be between 1 and 100. Got %d", rule.Percent)
+	}
+	if !rule.Expires.After(c.now()) {
+		return rule, errors.New("the rule must expire in the future")
+	}
+	rule.Platform = strings.ToLower(rule.Platform)
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.lastID++
+	rule.ID = strconv.Itoa(c.lastID)
+	c.rules[rule.ID] = rule
+	return rule, nil
+}
+
+// RemoveRule removes a rule before it expires, returning false if there is no such rule
+func (c *Controls) RemoveRule(id string) bool {
+	if c == nil {
+		return false
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if _, ok := c.rules[id]; !ok {
+		return false
+	}
+	delete(c.rules, id)
+	return true
+}
+
+// Rules returns the rules in effect, ordered by ID
+func (c *Controls) Rules() []Rule {
+	if c == nil {
+		return nil
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.activeRules("")
+}
+
+// activeRules returns the unexpired rules of the bidder, or of every bidder if empty, and drops the
+// expired ones. The lock must be held for writing.
+func (c *Controls) activeRules(bidder string) []Rule {
+	now := c.now()
+	rules := make([]Rule, 0)
+	for id, rule := range c.rules {
+		if !rule.Expires.After(now) {
+			delete(c.rules, id)
+			continue
+		}
+		if bidder == "" || rule.Bidder == bidder {
+			rules = append(rules, rule)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		left, _ := strconv.Atoi(rules[i].ID)
+		right, _ := strconv.Atoi(rules[j].ID)
+		return left < right
+	})
+	return rules
+}
+
+// Allow decides whether a request of the account and platform is sent to the bidder. The highest
+// percent of the matching rules applies. The requests not allowed are counted in the recent stats.
+func (c *Controls) Allow(bidder string, account string, platform string) bool {
+	if c == nil {
+		return true
+	}
+	percent := 0
+	now := c.now()
+	c.mux.RLock()
+	for _, rule := range c.rules {
+		if rule.Bidder != bidder || !rule.Expires.After(now) {
+			continue
+		}
+		if rule.Account != "" && rule.Account != account {
+			continue
+		}
+		if rule.Platform != "" && !strings.EqualFold(rule.Platform, platform) {
+			continue
+		}
+		if rule.Percent > percent {
+			percent = rule.Percent
+		}
+	}
+	c.mux.RUnlock()
+
+	if percent == 0 || (percent < 100 && c.percentile() >= percent) {
+		return true
+	}
+	c.Record(bidder, Throttled)
+	return false
+}
+
+// Record counts the outcome of a request to the bidder in its recent stats
+func (c *Controls) Record(bidder string, outcome Outcome) {
+	if c == nil {
+		return
+	}
+	minute := c.now().Unix() / 60
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	buckets, ok := c.stats[bidder]
+	if !ok {
+		buckets = &[statsBuckets]bucket{}
+		c.stats[bidder] = buckets
+	}
+	b := &buckets[minute%statsBuckets]
+	if b.minute != minute {
+		*b = bucket{minute: minute}
+	}
+	switch outcome {
+	case Throttled:
+		b.throttled++
+		return
+	case Failed:
+		b.errors++
+	case TimedOut:
+		b.timeouts++
+	}
+	b.requests++
+}
+
+// Statuses returns the live status of every bidder, ordered by name
+func (c *Controls) Statuses() []Status {
+	if c == nil {
+		return nil
+	}
+	names := make([]string, 0, len(c.bidders))
+	for name := range c.bidders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	statuses := make([]Status, 0, len(names))
+	for _, name := range names {
+		statuses = append(statuses, Status{
+			Bidder:  name,
+			Enabled: c.bidders[name],
+			Rules:   c.activeRules(name),
+			Recent:  c.recentStats(name),
+		})
+	}
+	return statuses
+}
+
+// recentStats sums the buckets of the last few minutes. The lock must be held.
+func (c *Controls) recentStats(bidder string) Stats {
+	var stats Stats
+	buckets, ok := c.stats[bidder]
+	if !ok {
+		return stats
+	}
+	minute := c.now().Unix() / 60
+	for _, b := range buckets {
+		if minute-b.minute >= statsBuckets {
+			continue
+		}
+		stats.Requests += b.requests
+		stats.Errors += b.errors
+		stats.Timeouts += b.timeouts
+		stats.Throttled += b.throttled
+	}
+	if stats.Requests > 0 {
+		stats.ErrorRate = float64(stats.Errors) / float64(stats.Requests)
+		stats.TimeoutRate = float64(stats.Timeouts) / float64(stats.Requests)
+	}
+	return stats
+}
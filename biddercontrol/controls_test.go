@@ -0,0 +1,221 @@
+package biddercontrol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestControls(now time.Time, percentile int) *Controls {
+	c := NewControls(config.BidderInfos{
+		"appnexus": config.BidderInfo{Enabled: true},
+		"rubicon":  config.BidderInfo{Enabled: false},
+	})
+	c.now = func() time.Time { return now }
+	c.percentile = func() int { return percentile }
+	return c
+}
+
+func TestAddRule(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		description   string
+		rule          Rule
+		expectedError string
+	}{
+		{
+			description: "Valid",
+			rule:        Rule{Bidder: "appnexus", Percent: 100, Platform: "iOS", Expires: now.Add(time.Minute)},
+		},
+		{
+			description:   "Unknown Bidder",
+			rule:          Rule{Bidder: "unknown", Percent: 100, Expires: now.Add(time.Minute)},
+			expectedError: `unknown bidder "unknown"`,
+		},
+		{
+			description:   "Percent Too Low",
+			rule:          Rule{Bidder: "appnexus", Percent: 0, Expires: now.Add(time.Minute)},
+			expectedError: "percent must be between 1 and 100. Got 0",
+		},
+		{
+			description:   "Percent Too High",
+			rule:          Rule{Bidder: "appnexus", Percent: 101, Expires: now.Add(time.Minute)},
+			expectedError: "percent must be between 1 and 100. Got 101",
+		},
+		{
+			description:   "Expired",
+			rule:          Rule{Bidder: "appnexus", Percent: 100, Expires: now},
+			expectedError: "the rule must expire in the future",
+		},
+	}
+
+	for _, test := range testCases {
+		c := newTestControls(now, 0)
+
+		rule, err := c.AddRule(test.rule)
+
+		if test.expectedError != "" {
+			assert.EqualError(t, err, test.expectedError, test.description)
+			assert.Empty(t, c.Rules(), test.description)
+			continue
+		}
+		if assert.NoError(t, err, test.description) {
+			assert.Equal(t, "1", rule.ID, test.description)
+			assert.Equal(t, "ios", rule.Platform, test.description)
+			assert.Equal(t, []Rule{rule}, c.Rules(), test.description)
+		}
+	}
+}
+
+func TestAllow(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		description string
+		rules       []Rule
+		percentile  int
+		account     string
+		platform    string
+		expected    bool
+	}{
+		{
+			description: "No Rules",
+			expected:    true,
+		},
+		{
+			description: "Disabled",
+			rules:       []Rule{{Bidder: "appnexus", Percent: 100}},
+			expected:    false,
+		},
+		{
+			description: "Other Bidder Disabled",
+			rules:       []Rule{{Bidder: "rubicon", Percent: 100}},
+			expected:    true,
+		},
+		{
+			description: "Throttled - Dropped",
+			rules:       []Rule{{Bidder: "appnexus", Percent: 30}},
+			percentile:  29,
+			expected:    false,
+		},
+		{
+			description: "Throttled - Sent",
+			rules:       []Rule{{Bidder: "appnexus", Percent: 30}},
+			percentile:  30,
+			expected:    true,
+		},
+		{
+			description: "Highest Percent Applies",
+			rules:       []Rule{{Bidder: "appnexus", Percent: 10}, {Bidder: "appnexus", Percent: 50}},
+			percentile:  30,
+			expected:    false,
+		},
+		{
+			description: "Account Rule - Matching Account",
+			rules:       []Rule{{Bidder: "appnexus", Percent: 100, Account: "1001"}},
+			account:     "1001",
+			expected:    false,
+		},
+		{
+			description: "Account Rule - Other Account",
+			rules:       []Rule{{Bidder: "appnexus", Percent: 100, Account: "1001"}},
+			account:     "1002",
+			expected:    true,
+		},
+		{
+			description: "Platform Rule - Matching Platform",
+			rules:       []Rule{{Bidder: "appnexus", Percent: 100, Platform: "android"}},
+			platform:    "Android",
+			expected:    false,
+		},
+		{
+			description: "Platform Rule - Other Platform",
+			rules:       []Rule{{Bidder: "appnexus", Percent: 100, Platform: "android"}},
+			platform:    "iOS",
+			expected:    true,
+		},
+	}
+
+	for _, test := range testCases {
+		c := newTestControls(now, test.percentile)
+		for _, rule := range test.rules {
+			rule.Expires = now.Add(time.Minute)
+			_, err := c.AddRule(rule)
+			assert.NoError(t, err, test.description)
+		}
+
+		assert.Equal(t, test.expected, c.Allow("appnexus", test.account, test.platform), test.description)
+	}
+}
+
+func TestRuleExpiry(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	c := newTestControls(now, 0)
+	rule, err := c.AddRule(Rule{Bidder: "appnexus", Percent: 100, Expires: now.Add(time.Minute)})
+	assert.NoError(t, err)
+	assert.False(t, c.Allow("appnexus", "", ""), "the rule applies before it expires")
+
+	c.now = func() time.Time { return now.Add(time.Minute) }
+	assert.True(t, c.Allow("appnexus", "", ""), "the rule does not apply once expired")
+	assert.Empty(t, c.Rules(), "the expired rule is dropped")
+	assert.False(t, c.RemoveRule(rule.ID), "the expired rule cannot be removed")
+}
+
+func TestRemoveRule(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	c := newTestControls(now, 0)
+	rule, err := c.AddRule(Rule{Bidder: "appnexus", Percent: 100, Expires: now.Add(time.Minute)})
+	assert.NoError(t, err)
+
+	assert.False(t, c.RemoveRule("unknown"))
+	assert.True(t, c.RemoveRule(rule.ID))
+	assert.True(t, c.Allow("appnexus", "", ""))
+}
+
+func TestStatuses(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	c := newTestControls(now, 0)
+
+	// outcomes older than the stats window are not counted
+	c.now = func() time.Time { return now.Add(-statsBuckets * time.Minute) }
+	c.Record("appnexus", Failed)
+
+	c.now = func() time.Time { return now.Add(-time.Minute) }
+	c.Record("appnexus", Succeeded)
+	c.Record("appnexus", Failed)
+	c.now = func() time.Time { return now }
+	c.Record("appnexus", TimedOut)
+	c.Record("appnexus", Succeeded)
+	rule, err := c.AddRule(Rule{Bidder: "appnexus", Percent: 100, Account: "1001", Expires: now.Add(time.Minute)})
+	assert.NoError(t, err)
+	assert.False(t, c.Allow("appnexus", "1001", ""))
+
+	assert.Equal(t, []Status{
+		{
+			Bidder:  "appnexus",
+			Enabled: true,
+			Rules:   []Rule{rule},
+			Recent:  Stats{Requests: 4, Errors: 1, Timeouts: 1, Throttled: 1, ErrorRate: 0.25, TimeoutRate: 0.25},
+		},
+		{
+			Bidder:  "rubicon",
+			Enabled: false,
+			Rules:   []Rule{},
+		},
+	}, c.Statuses())
+}
+
+func TestNilControls(t *testing.T) {
+	var c *Controls
+
+	assert.True(t, c.Allow("appnexus", "", ""))
+	c.Record("appnexus", Failed)
+	assert.Nil(t, c.Rules())
+	assert.Nil(t, c.Statuses())
+	assert.False(t, c.RemoveRule("1"))
+	_, err := c.AddRule(Rule{Bidder: "appnexus", Percent: 100})
+	assert.Error(t, err)
+}
@@ -8,9 +8,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"sync"
-	"time"
 
-	"github.com/newrelic/go-agent/v3/newrelic"
 	"github.com/prebid/prebid-server/cache/skanidlist/cfg"
 	"github.com/prebid/prebid-server/cache/skanidlist/model"
 	"github.com/prebid/prebid-server/openrtb_ext"
@@ -18,172 +16,95 @@ import (
 )
 
 type cache struct {
-	url        string
-	successTTL time.Duration
-	missTTL    time.Duration
-	bidder     openrtb_ext.BidderName
+	url    string
+	bidder openrtb_ext.BidderName
 
-	ids map[string]bool
+	staticIDs []string
+	ids       map[string]bool
+	// iabIDs maps the lowercased SKAN IDs of the list to their IAB SKAdNetwork ID list entry id
+	iabIDs map[string]int
 
-	mu              *sync.RWMutex
-	expiration      int64
-	updateRequested bool
+	mu *sync.RWMutex
 }
 
 func newCache(cfg cfg.Cache) *cache {
 	c := cache{
-		url:        cfg.Url,
-		successTTL: time.Duration(1 * time.Hour),
-		missTTL:    time.Duration(5 * time.Minute),
-		bidder:     cfg.Bidder,
+		url:    cfg.Url,
+		bidder: cfg.Bidder,
 
-		ids: map[string]bool{},
+		staticIDs: cfg.StaticIDs,
+		ids:       withStaticIDs(map[string]bool{}, cfg.StaticIDs),
+		iabIDs:    map[string]int{},
 
-		mu:              new(sync.RWMutex),
-		expiration:      time.Now().UnixNano(),
-		updateRequested: false,
+		mu: new(sync.RWMutex),
 	}
 
-	if cfg.BidderSKANID != "" {
-		c.ids = map[string]bool{
-			cfg.BidderSKANID: true,
-		}
+	if cfg.Url != "" && cfg.BidderSKANID != "" {
+		c.ids[cfg.BidderSKANID] = true
 	}
 
 	return &c
 }
 
 func (c *cache) get() map[string]bool {
-	if len(c.url) < 1 {
-		// bidder does not support multiple SKAN ID List
-		return map[string]bool{}
-	}
-
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	return c.ids
 }
 
-func (c *cache) update(ctx context.Context, httpClient *http.Client) {
-	chResp := make(chan *response, 1)
-
-	c.mu.Lock()
-	if time.Now().UnixNano() > c.expiration && !c.updateRequested {
-		c.updateRequested = true
-
-		c.mu.Unlock()
-
-		// get newrelic transaction from context
-		txn := newrelic.FromContext(ctx)
-
-		fetchFromServer := func(ctx context.Context, txn *newrelic.Transaction) {
-			ctx = newrelic.NewContext(ctx, txn)
-			chResp <- c.fetchFromServer(ctx, httpClient)
-		}
-		go fetchFromServer(ctx, txn.NewGoroutine())
-
-	} else {
-		c.mu.Unlock()
-
-		chResp <- &response{
-			skanIDList: model.SKANIDList{},
-			updated:    false,
-			err:        nil,
-		}
-	}
-
-	resp := <-chResp
-
-	if resp.err != nil {
-		// http call returned error, list could not fetched, report NR error
-		if txn := newrelic.FromContext(ctx); txn != nil {
-			txn.NoticeError(resp.err)
-		}
+func (c *cache) getIABIDs() map[string]int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
-		// update expiration and updateRequested fields to check back in miss TTL time
-		c.updateMiss()
+	return c.iabIDs
+}
 
-		return
-	}
+// set replaces the ids with the ones of skanIDList, static ids are always kept
+func (c *cache) set(skanIDList model.SKANIDList) {
+	ids := withStaticIDs(extract(skanIDList), c.staticIDs)
+	iabIDs := extractIABIDs(skanIDList)
 
-	if !resp.updated {
-		// update is not required so do not change anything
-		return
-	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	c.updateSuccess(resp.skanIDList)
+	c.ids = ids
+	c.iabIDs = iabIDs
 }
 
-func (c *cache) fetchFromServer(ctx context.Context, httpClient *http.Client) *response {
+func (c *cache) fetchFromServer(ctx context.Context, httpClient *http.Client) (model.SKANIDList, error) {
 	req, err := http.NewRequest("GET", c.url, nil)
 	if err != nil {
-		return &response{
-			skanIDList: model.SKANIDList{},
-			updated:    false,
-			err:        errors.New(fmt.Sprintf("error making request for bidder's servers for: %s - %v", c.url, err)),
-		}
+		return model.SKANIDList{}, errors.New(fmt.Sprintf("error making request for bidder's servers for: %s - %v", c.url, err))
 	}
 
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := ctxhttp.Do(ctx, httpClient, req)
 	if err != nil {
-		return &response{
-			skanIDList: model.SKANIDList{},
-			updated:    false,
-			err:        errors.New(fmt.Sprintf("error fetching skanidlist from bidder's servers for: %s - %v", c.url, err)),
-		}
+		return model.SKANIDList{}, errors.New(fmt.Sprintf("error fetching skanidlist from bidder's servers for: %s - %v", c.url, err))
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return &response{
-			skanIDList: model.SKANIDList{},
-			updated:    false,
-			err:        errors.New(fmt.Sprintf("error statuscode (%d) received from bidder's servers for: %s - %v", resp.StatusCode, c.url, err)),
-		}
+		return model.SKANIDList{}, errors.New(fmt.Sprintf("error statuscode (%d) received from bidder's servers for: %s", resp.StatusCode, c.url))
 	}
 
-	defer resp.Body.Close()
 	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return &response{
-			skanIDList: model.SKANIDList{},
-			updated:    true,
-			err:        errors.New(fmt.Sprintf("error reading skanidlist response body for: %s - %v", c.url, err)),
-		}
+		return model.SKANIDList{}, errors.New(fmt.Sprintf("error reading skanidlist response body for: %s - %v", c.url, err))
 	}
 
 	var skanIDList model.SKANIDList
 	err = json.Unmarshal(data, &skanIDList)
 	if err != nil {
-		return &response{
-			skanIDList: model.SKANIDList{},
-			updated:    true,
-			err:        errors.New(fmt.Sprintf("error unmarshaling response to skanidlist for: %s - %v", c.url, err)),
-		}
+		return model.SKANIDList{}, errors.New(fmt.Sprintf("error unmarshaling response to skanidlist for: %s - %v", c.url, err))
 	}
 
-	return &response{
-		skanIDList: skanIDList,
-		updated:    true,
-		err:        nil,
+	if len(skanIDList.SKAdNetworkIDs) == 0 {
+		// never replace a known good list with an empty one
+		return model.SKANIDList{}, errors.New(fmt.Sprintf("empty skanidlist received from bidder's servers for: %s", c.url))
 	}
-}
-
-func (c *cache) updateSuccess(skanIDList model.SKANIDList) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.ids = extract(skanIDList)
-	c.expiration = time.Now().Add(c.successTTL).UnixNano()
-	c.updateRequested = false
-}
-
-func (c *cache) updateMiss() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
 
-	c.expiration = time.Now().Add(c.missTTL).UnixNano()
-	c.updateRequested = false
+	return skanIDList, nil
 }
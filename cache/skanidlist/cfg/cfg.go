@@ -1,9 +1,35 @@
 package cfg
 
-import "github.com/prebid/prebid-server/openrtb_ext"
+import (
+	"strings"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
 
 type Cache struct {
 	Url          string
 	Bidder       openrtb_ext.BidderName
 	BidderSKANID string
+	StaticIDs    []string
+}
+
+// FromBidderInfo builds the SKAN ID List cache configuration of a bidder from its bidder-info
+// skadnetwork entry. SKAN IDs are lower cased since requests are filtered case insensitively.
+func FromBidderInfo(bidder openrtb_ext.BidderName, info *config.SKAdNetworkInfo) Cache {
+	c := Cache{
+		Bidder: bidder,
+	}
+
+	if info == nil {
+		return c
+	}
+
+	c.Url = info.ListURL
+	c.BidderSKANID = strings.ToLower(info.DefaultID)
+	for _, id := range info.IDs {
+		c.StaticIDs = append(c.StaticIDs, strings.ToLower(id))
+	}
+
+	return c
 }
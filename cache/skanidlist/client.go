@@ -1,72 +1,82 @@
 package skanidlist
 
 import (
-	"context"
-	"errors"
-	"fmt"
-	"net/http"
 	"sync"
 
 	"github.com/prebid/prebid-server/cache/skanidlist/cfg"
+	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/openrtb_ext"
 )
 
 type client struct {
 	caches map[openrtb_ext.BidderName]*cache
-	mu     *sync.Mutex
+	mu     *sync.RWMutex
 }
 
 // Empty skanIDListClient
 var skanIDListClient client = client{
 	caches: map[openrtb_ext.BidderName]*cache{},
-	mu:     new(sync.Mutex),
+	mu:     new(sync.RWMutex),
 }
 
-func (c client) makeCache(cfg cfg.Cache) {
-	if c.caches[cfg.Bidder] == nil {
-		c.caches[cfg.Bidder] = newCache(cfg)
-	}
-}
-
-func cacheClient(bidder openrtb_ext.BidderName) (*cache, error) {
+// Register adds (or replaces) the SKAN ID List cache of a bidder
+func Register(cfg cfg.Cache) {
 	skanIDListClient.mu.Lock()
 	defer skanIDListClient.mu.Unlock()
 
-	// Initialize bidder caches at first call
-	switch bidder {
-	case openrtb_ext.BidderTaurusX:
-		if skanIDListClient.caches[bidder] == nil {
-			skanIDListClient.makeCache(cfg.TaurusX)
-		}
-		return skanIDListClient.caches[bidder], nil
-
-	case openrtb_ext.BidderPubmatic:
-		if skanIDListClient.caches[bidder] == nil {
-			skanIDListClient.makeCache(cfg.Pubmatic)
-		}
-		return skanIDListClient.caches[bidder], nil
+	skanIDListClient.caches[cfg.Bidder] = newCache(cfg)
+}
 
-	case openrtb_ext.BidderRubicon:
-		if skanIDListClient.caches[bidder] == nil {
-			skanIDListClient.makeCache(cfg.Rubicon)
+// Init registers a SKAN ID List cache for every bidder with a skadnetwork entry in its bidder info. The seats
+// use the SKAN ID List of the bidder serving them.
+func Init(infos config.BidderInfos) {
+	for name, info := range infos {
+		if info.SKAdNetwork == nil || (info.Seat != nil && info.Seat.Of != "") {
+			continue
 		}
-		return skanIDListClient.caches[bidder], nil
 
+		Register(cfg.FromBidderInfo(openrtb_ext.BidderName(name), info.SKAdNetwork))
 	}
+}
 
-	return nil, errors.New(fmt.Sprintf("bidder (%s) does not support SKAN ID List", bidder))
+func cacheClient(bidder openrtb_ext.BidderName) (*cache, bool) {
+	skanIDListClient.mu.RLock()
+	defer skanIDListClient.mu.RUnlock()
+
+	c, ok := skanIDListClient.caches[bidder]
+	return c, ok
 }
 
-func Update(ctx context.Context, httpClient *http.Client, bidder openrtb_ext.BidderName) {
-	if c, err := cacheClient(bidder); err == nil {
-		c.update(ctx, httpClient)
+// remoteCaches returns the caches of the bidders hosting a SKAN ID List
+func remoteCaches() []*cache {
+	skanIDListClient.mu.RLock()
+	defer skanIDListClient.mu.RUnlock()
+
+	caches := make([]*cache, 0, len(skanIDListClient.caches))
+	for _, c := range skanIDListClient.caches {
+		if c.url != "" {
+			caches = append(caches, c)
+		}
 	}
+
+	return caches
 }
 
+// Get returns the SKAN IDs supported by the bidder, an empty map if the bidder is not registered
 func Get(bidder openrtb_ext.BidderName) map[string]bool {
-	if c, err := cacheClient(bidder); err == nil {
+	if c, ok := cacheClient(bidder); ok {
 		return c.get()
 	}
 
 	return map[string]bool{}
 }
+
+// IABIDs returns the IAB SKAdNetwork ID list entry ids of the SKAN IDs loaded for the bidder, keyed by lowercased
+// SKAN ID, an empty map if the bidder is not registered. The static ids have no entry id.
+func IABIDs(bidder openrtb_ext.BidderName) map[string]int {
+	if c, ok := cacheClient(bidder); ok {
+		return c.getIABIDs()
+	}
+
+	return map[string]int{}
+}
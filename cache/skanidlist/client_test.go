@@ -0,0 +1,35 @@
+package skanidlist
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUnregistered(t *testing.T) {
+	assert.Equal(t, map[string]bool{}, Get(openrtb_ext.BidderName("unregistered")))
+}
+
+func TestInitStaticIDs(t *testing.T) {
+	bidder := openrtb_ext.BidderName("staticbidder")
+
+	Init(config.BidderInfos{
+		string(bidder): config.BidderInfo{
+			SKAdNetwork: &config.SKAdNetworkInfo{IDs: []string{"XYZ.skadnetwork"}},
+		},
+		"noskanbidder": config.BidderInfo{},
+		"staticseat": config.BidderInfo{
+			SKAdNetwork: &config.SKAdNetworkInfo{IDs: []string{"XYZ.skadnetwork"}},
+			Seat:        &config.AdapterSeat{Of: string(bidder)},
+		},
+	})
+
+	assert.Equal(t, map[string]bool{"xyz.skadnetwork": true}, Get(bidder))
+
+	_, ok := cacheClient("noskanbidder")
+	assert.False(t, ok, "bidder without skadnetwork entry should not be registered")
+	_, ok = cacheClient("staticseat")
+	assert.False(t, ok, "a seat uses the list of the bidder serving it")
+}
@@ -0,0 +1,66 @@
+package skanidlist
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/prebid/prebid-server/cache/skanidlist/model"
+)
+
+func (c *cache) persistPath(dir string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s.json", c.bidder))
+}
+
+// save writes skanIDList to dir so it survives restarts. The file is written to a temporary
+// file first and then renamed so readers never see a partial list.
+func (c *cache) save(dir string, skanIDList model.SKANIDList) error {
+	data, err := json.Marshal(skanIDList)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(dir, fmt.Sprintf(".%s-*.json", c.bidder))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.persistPath(dir))
+}
+
+// restore loads the last list saved in dir, if any
+func (c *cache) restore(dir string) error {
+	data, err := ioutil.ReadFile(c.persistPath(dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var skanIDList model.SKANIDList
+	if err := json.Unmarshal(data, &skanIDList); err != nil {
+		return fmt.Errorf("error unmarshaling persisted skanidlist %s - %v", c.persistPath(dir), err)
+	}
+
+	if len(skanIDList.SKAdNetworkIDs) > 0 {
+		c.set(skanIDList)
+	}
+
+	return nil
+}
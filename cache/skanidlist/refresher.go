@@ -0,0 +1,155 @@
+package skanidlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/metrics"
+)
+
+// Refresher fetches the SKAN ID List of every registered bidder hosting one. It implements
+// task.Runner so the lists are refreshed by a task.TickerTask, off the auction path. Bidders keep
+// serving their last known good list until a fetch succeeds, the failed fetches are retried every
+// retry interval until then rather than at the next tick.
+type Refresher struct {
+	httpClient    *http.Client
+	timeout       time.Duration
+	retryInterval time.Duration
+	persistDir    string
+	me            metrics.MetricsEngine
+
+	// run is incremented by every Run, so the retries of a previous run stop
+	run uint64
+	mu  sync.Mutex
+}
+
+// NewRefresher returns a new Refresher
+func NewRefresher(httpClient *http.Client, cfg config.SKANIDList, me metrics.MetricsEngine) *Refresher {
+	return &Refresher{
+		httpClient:    httpClient,
+		timeout:       time.Duration(cfg.FetchTimeoutMS) * time.Millisecond,
+		retryInterval: time.Duration(cfg.RetryIntervalSeconds) * time.Second,
+		persistDir:    cfg.PersistDir,
+		me:            me,
+	}
+}
+
+// Restore loads the lists persisted by a previous run so they are served until the first refresh
+// completes. It is a noop when persistence is disabled.
+func (r *Refresher) Restore() {
+	if r.persistDir == "" {
+		return
+	}
+
+	for _, c := range remoteCaches() {
+		if err := c.restore(r.persistDir); err != nil {
+			glog.Warningf("Could not restore SKAN ID List for %s: %v", c.bidder, err)
+		}
+	}
+}
+
+// Run refreshes all lists concurrently and waits for the fetches to complete. The lists that could not
+// be fetched are retried in the background every retry interval, until they are fetched or Run is
+// called again.
+func (r *Refresher) Run() error {
+	r.mu.Lock()
+	r.run++
+	run := r.run
+	r.mu.Unlock()
+
+	failed, errs := r.refreshAll(remoteCaches())
+	r.scheduleRetry(run, failed)
+
+	if len(errs) > 0 {
+		return errortypes.NewAggregateError("SKAN ID List refresh", errs)
+	}
+	return nil
+}
+
+// scheduleRetry retries the failed caches after the retry interval, unless Run was called again meanwhile
+func (r *Refresher) scheduleRetry(run uint64, failed []*cache) {
+	if len(failed) == 0 || r.retryInterval <= 0 {
+		return
+	}
+
+	time.AfterFunc(r.retryInterval, func() {
+		if !r.isCurrentRun(run) {
+			return
+		}
+		failed, _ := r.refreshAll(failed)
+		r.scheduleRetry(run, failed)
+	})
+}
+
+func (r *Refresher) isCurrentRun(run uint64) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.run == run
+}
+
+// refreshAll refreshes the caches concurrently, it returns the caches whose fetch failed
+func (r *Refresher) refreshAll(caches []*cache) ([]*cache, []error) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var failed []*cache
+	var errs []error
+
+	for _, c := range caches {
+		wg.Add(1)
+		go func(c *cache) {
+			defer wg.Done()
+			if err := r.refresh(c); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				if !errors.As(err, new(*persistError)) {
+					failed = append(failed, c)
+				}
+				mu.Unlock()
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	return failed, errs
+}
+
+func (r *Refresher) refresh(c *cache) error {
+	ctx := context.Background()
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	skanIDList, err := c.fetchFromServer(ctx, r.httpClient)
+	r.me.RecordSKANIDListFetch(c.bidder, err == nil, time.Since(start), len(skanIDList.SKAdNetworkIDs))
+
+	if err != nil {
+		glog.Warningf("SKAN ID List refresh failed for %s, serving last known good list: %v", c.bidder, err)
+		return err
+	}
+
+	c.set(skanIDList)
+
+	if r.persistDir != "" {
+		if err := c.save(r.persistDir, skanIDList); err != nil {
+			return &persistError{fmt.Errorf("error persisting skanidlist for %s - %v", c.bidder, err)}
+		}
+	}
+
+	return nil
+}
+
+// persistError is a list fetched but not persisted, which is not retried
+type persistError struct {
+	error
+}
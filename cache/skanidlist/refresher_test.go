@@ -0,0 +1,140 @@
+package skanidlist
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/cache/skanidlist/cfg"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/metrics"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testSKANIDList = `{
+  "company_name": "Some Bidder",
+  "skadnetwork_ids": [
+    {"id": 1, "skadnetwork_id": "ABC.skadnetwork"},
+    {"id": 2, "skadnetwork_id": "def.skadnetwork"}
+  ]
+}`
+
+func newTestServer(status *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(*status)
+		if *status == http.StatusOK {
+			w.Write([]byte(testSKANIDList))
+		}
+	}))
+}
+
+func TestRefresherRun(t *testing.T) {
+	status := http.StatusOK
+	server := newTestServer(&status)
+	defer server.Close()
+
+	bidder := openrtb_ext.BidderName("refreshbidder")
+	Register(cfg.Cache{
+		Url:          server.URL,
+		Bidder:       bidder,
+		BidderSKANID: "default.skadnetwork",
+		StaticIDs:    []string{"static.skadnetwork"},
+	})
+	defer Register(cfg.Cache{Bidder: bidder})
+
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordSKANIDListFetch", bidder, true, mock.Anything, 2).Return()
+	me.On("RecordSKANIDListFetch", bidder, false, mock.Anything, 0).Return()
+
+	refresher := NewRefresher(server.Client(), config.SKANIDList{FetchTimeoutMS: 1000}, me)
+
+	assert.Equal(t, map[string]bool{"default.skadnetwork": true, "static.skadnetwork": true}, Get(bidder), "before refresh")
+
+	assert.NoError(t, refresher.Run())
+	assert.Equal(t, map[string]bool{"abc.skadnetwork": true, "def.skadnetwork": true, "static.skadnetwork": true}, Get(bidder), "after refresh")
+	assert.Equal(t, map[string]int{"abc.skadnetwork": 1, "def.skadnetwork": 2}, IABIDs(bidder), "after refresh")
+	me.AssertCalled(t, "RecordSKANIDListFetch", bidder, true, mock.Anything, 2)
+
+	status = http.StatusInternalServerError
+	assert.Error(t, refresher.Run())
+	assert.Equal(t, map[string]bool{"abc.skadnetwork": true, "def.skadnetwork": true, "static.skadnetwork": true}, Get(bidder), "failed refresh keeps last known good list")
+	me.AssertCalled(t, "RecordSKANIDListFetch", bidder, false, mock.Anything, 0)
+}
+
+func TestRefresherRetry(t *testing.T) {
+	status := http.StatusInternalServerError
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			w.Write([]byte(testSKANIDList))
+		}
+	}))
+	defer server.Close()
+
+	bidder := openrtb_ext.BidderName("retrybidder")
+	Register(cfg.Cache{
+		Url:    server.URL,
+		Bidder: bidder,
+	})
+	defer Register(cfg.Cache{Bidder: bidder})
+
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordSKANIDListFetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+
+	refresher := NewRefresher(server.Client(), config.SKANIDList{RetryIntervalSeconds: 300}, me)
+	refresher.retryInterval = 10 * time.Millisecond
+
+	assert.Error(t, refresher.Run())
+	assert.Empty(t, Get(bidder), "failed fetch")
+
+	mu.Lock()
+	status = http.StatusOK
+	mu.Unlock()
+
+	assert.Eventually(t, func() bool {
+		return len(Get(bidder)) == 2
+	}, time.Second, 10*time.Millisecond, "failed fetch retried")
+}
+
+func TestRefresherPersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "skanidlist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	status := http.StatusOK
+	server := newTestServer(&status)
+	defer server.Close()
+
+	bidder := openrtb_ext.BidderName("persistbidder")
+	bidderCfg := cfg.Cache{
+		Url:          server.URL,
+		Bidder:       bidder,
+		BidderSKANID: "default.skadnetwork",
+	}
+	Register(bidderCfg)
+	defer Register(cfg.Cache{Bidder: bidder})
+
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordSKANIDListFetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+
+	refresher := NewRefresher(server.Client(), config.SKANIDList{PersistDir: dir}, me)
+	assert.NoError(t, refresher.Run())
+
+	// simulate a cold start without network
+	Register(bidderCfg)
+	assert.Equal(t, map[string]bool{"default.skadnetwork": true}, Get(bidder), "before restore")
+
+	refresher.Restore()
+	assert.Equal(t, map[string]bool{"abc.skadnetwork": true, "def.skadnetwork": true}, Get(bidder), "after restore")
+}
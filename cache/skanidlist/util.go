@@ -1,12 +1,36 @@
 package skanidlist
 
-import "github.com/prebid/prebid-server/cache/skanidlist/model"
+import (
+	"strings"
+
+	"github.com/prebid/prebid-server/cache/skanidlist/model"
+)
 
 func extract(skanIDList model.SKANIDList) map[string]bool {
 	skanIDs := map[string]bool{}
 
 	for _, skanID := range skanIDList.SKAdNetworkIDs {
-		skanIDs[skanID.SKAdNetworkID] = true
+		skanIDs[strings.ToLower(skanID.SKAdNetworkID)] = true
+	}
+
+	return skanIDs
+}
+
+func extractIABIDs(skanIDList model.SKANIDList) map[string]int {
+	iabIDs := map[string]int{}
+
+	for _, skanID := range skanIDList.SKAdNetworkIDs {
+		if skanID.ID > 0 {
+			iabIDs[strings.ToLower(skanID.SKAdNetworkID)] = skanID.ID
+		}
+	}
+
+	return iabIDs
+}
+
+func withStaticIDs(skanIDs map[string]bool, staticIDs []string) map[string]bool {
+	for _, skanID := range staticIDs {
+		skanIDs[skanID] = true
 	}
 
 	return skanIDs
@@ -1,5 +1,12 @@
 package config
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
 // IntegrationType enumerates the values of integrations Prebid Server can configure for an account
 type IntegrationType string
 
@@ -13,13 +20,158 @@ const (
 
 // Account represents a publisher account configuration
 type Account struct {
-	ID            string      `mapstructure:"id" json:"id"`
-	Disabled      bool        `mapstructure:"disabled" json:"disabled"`
-	CacheTTL      DefaultTTLs `mapstructure:"cache_ttl" json:"cache_ttl"`
-	EventsEnabled bool        `mapstructure:"events_enabled" json:"events_enabled"`
-	CCPA          AccountCCPA `mapstructure:"ccpa" json:"ccpa"`
-	GDPR          AccountGDPR `mapstructure:"gdpr" json:"gdpr"`
-	DebugAllow    bool        `mapstructure:"debug_allow" json:"debug_allow"`
+	ID            string         `mapstructure:"id" json:"id"`
+	Disabled      bool           `mapstructure:"disabled" json:"disabled"`
+	CacheTTL      DefaultTTLs    `mapstructure:"cache_ttl" json:"cache_ttl"`
+	EventsEnabled bool           `mapstructure:"events_enabled" json:"events_enabled"`
+	CCPA          AccountCCPA    `mapstructure:"ccpa" json:"ccpa"`
+	GDPR          AccountGDPR    `mapstructure:"gdpr" json:"gdpr"`
+	DebugAllow    bool           `mapstructure:"debug_allow" json:"debug_allow"`
+	Bidders       AccountBidders `mapstructure:"bidders" json:"bidders"`
+	Auction       AccountAuction `mapstructure:"auction" json:"auction"`
+}
+
+// AccountBidders represents the bidders an account is allowed to call. An empty allowed list allows every
+// bidder not blocked.
+type AccountBidders struct {
+	Allowed []string `mapstructure:"allowed" json:"allowed,omitempty"`
+	Blocked []string `mapstructure:"blocked" json:"blocked,omitempty"`
+}
+
+// Permits indicates whether the account allows calling the bidder
+func (a *AccountBidders) Permits(bidder string) bool {
+	for _, blocked := range a.Blocked {
+		if blocked == bidder {
+			return false
+		}
+	}
+	if len(a.Allowed) == 0 {
+		return true
+	}
+	for _, allowed := range a.Allowed {
+		if allowed == bidder {
+			return true
+		}
+	}
+	return false
+}
+
+// AccountAuction represents the account defaults merged into the auction requests. The values of the request
+// take precedence, except for the blocked advertisers, categories and apps which are added to the request ones.
+// The blocked creatives have no request counterpart, the exchange rejects the bids using them. Neither do the
+// deals, which the exchange offers in the imp.pmp.deals of the requests to the bidders they are made with.
+type AccountAuction struct {
+	TMax                 int64                        `mapstructure:"tmax" json:"tmax,omitempty"`
+	BidAdjustmentFactors map[string]float64           `mapstructure:"bid_adjustment_factors" json:"bid_adjustment_factors,omitempty"`
+	PriceGranularity     string                       `mapstructure:"price_granularity" json:"price_granularity,omitempty"`
+	Floors               *openrtb_ext.PriceFloorRules `mapstructure:"floors" json:"floors,omitempty"`
+	BlockedAdvertisers   []string                     `mapstructure:"blocked_advertisers" json:"blocked_advertisers,omitempty"`
+	BlockedCategories    []string                     `mapstructure:"blocked_categories" json:"blocked_categories,omitempty"`
+	BlockedApps          []string                     `mapstructure:"blocked_apps" json:"blocked_apps,omitempty"`
+	BlockedCreatives     []string                     `mapstructure:"blocked_creatives" json:"blocked_creatives,omitempty"`
+	SKANRouting          SKANRoutingMode              `mapstructure:"skan_routing" json:"skan_routing,omitempty"`
+	Deals                []AccountDeal                `mapstructure:"deals" json:"deals,omitempty"`
+}
+
+// AccountDeal is a deal of the account with some of the bidders, offered on the imps meeting its conditions.
+// The stored imps can have deals of their own in imp.ext.prebid.deals, offered on that imp only, the deals the
+// incoming request sets there are dropped. The bids on the deal win over the bids outside of these deals, the
+// highest priority first.
+type AccountDeal struct {
+	ID      string   `mapstructure:"id" json:"id"`
+	Bidders []string `mapstructure:"bidders" json:"bidders"`
+	// Seats are the buyer seats of the bidders allowed to bid on the deal, any seat if empty. The bids of the
+	// adapters not reporting the seat of their bids never match a deal with seats.
+	Seats []string `mapstructure:"seats" json:"seats,omitempty"`
+	Floor float64  `mapstructure:"floor" json:"floor,omitempty"`
+	// Currency is the currency of the floor, USD if empty
+	Currency   string                `mapstructure:"currency" json:"currency,omitempty"`
+	Priority   int                   `mapstructure:"priority" json:"priority,omitempty"`
+	Conditions AccountDealConditions `mapstructure:"conditions" json:"conditions,omitempty"`
+}
+
+// AccountDealConditions restricts a deal to the requests and imps matching every condition which is not
+// empty. The values are compared case insensitively.
+type AccountDealConditions struct {
+	// Countries are the alpha-3 codes of device.geo.country
+	Countries []string `mapstructure:"countries" json:"countries,omitempty"`
+	OS        []string `mapstructure:"os" json:"os,omitempty"`
+	Bundles   []string `mapstructure:"bundles" json:"bundles,omitempty"`
+	// PlacementTypes are the types of the placements the exchange classifies the imps into
+	PlacementTypes []string `mapstructure:"placement_types" json:"placement_types,omitempty"`
+}
+
+// dealPlacementTypes are the adapters.PlacementType values
+var dealPlacementTypes = map[string]bool{
+	"interstitial": true,
+	"rewarded":     true,
+	"banner":       true,
+	"native":       true,
+}
+
+// Validate returns the errors of the deal, field is the path of the deal in the account or in the imp
+func (d *AccountDeal) Validate(field string) []error {
+	var errs []error
+	if d.ID == "" {
+		errs = append(errs, fmt.Errorf("%s.id must not be empty", field))
+	}
+	if len(d.Bidders) == 0 {
+		errs = append(errs, fmt.Errorf("%s.bidders must not be empty", field))
+	}
+	if d.Floor < 0 {
+		errs = append(errs, fmt.Errorf("%s.floor must be nonnegative. Got %f", field, d.Floor))
+	}
+	if d.Priority < 0 {
+		errs = append(errs, fmt.Errorf("%s.priority must be nonnegative. Got %d", field, d.Priority))
+	}
+	for _, placementType := range d.Conditions.PlacementTypes {
+		if !dealPlacementTypes[strings.ToLower(placementType)] {
+			errs = append(errs, fmt.Errorf("%s.conditions.placement_types %q is not one of interstitial, rewarded, banner or native", field, placementType))
+		}
+	}
+	return errs
+}
+
+// SKANRoutingMode is how the bidders unable to attribute through SKAdNetwork are handled for the iOS requests
+// without App Tracking Transparency consent. The bidders are called as usual when empty.
+type SKANRoutingMode string
+
+const (
+	// SKANRoutingExclude does not send the imps to the bidders
+	SKANRoutingExclude SKANRoutingMode = "exclude"
+	// SKANRoutingDeprioritize only keeps the bids of the bidders for the imps no other bidder bid on
+	SKANRoutingDeprioritize SKANRoutingMode = "deprioritize"
+)
+
+// Validate returns the errors of the account auction defaults, which must not be merged into requests
+// when there are any.
+func (a *AccountAuction) Validate() []error {
+	var errs []error
+	if a.TMax < 0 {
+		errs = append(errs, fmt.Errorf("auction.tmax must be nonnegative. Got %d", a.TMax))
+	}
+	for bidder, factor := range a.BidAdjustmentFactors {
+		if factor <= 0 {
+			errs = append(errs, fmt.Errorf("auction.bid_adjustment_factors.%s must be a positive number. Got %f", bidder, factor))
+		}
+	}
+	if a.PriceGranularity != "" && len(openrtb_ext.PriceGranularityFromString(a.PriceGranularity).Ranges) == 0 {
+		errs = append(errs, fmt.Errorf("auction.price_granularity %q is not one of low, med, medium, high, auto or dense", a.PriceGranularity))
+	}
+	if a.SKANRouting != "" && a.SKANRouting != SKANRoutingExclude && a.SKANRouting != SKANRoutingDeprioritize {
+		errs = append(errs, fmt.Errorf("auction.skan_routing %q is not one of exclude or deprioritize", a.SKANRouting))
+	}
+	ids := make(map[string]bool, len(a.Deals))
+	for i := range a.Deals {
+		errs = append(errs, a.Deals[i].Validate(fmt.Sprintf("auction.deals[%d]", i))...)
+		if id := a.Deals[i].ID; id != "" {
+			if ids[id] {
+				errs = append(errs, fmt.Errorf("auction.deals[%d].id %q is not unique", i, id))
+			}
+			ids[id] = true
+		}
+	}
+	return errs
 }
 
 // AccountCCPA represents account-specific CCPA configuration
@@ -241,3 +241,136 @@ func TestAccountIntegrationGetByIntegrationType(t *testing.T) {
 		}
 	}
 }
+
+func TestAccountBiddersPermits(t *testing.T) {
+	tests := []struct {
+		description string
+		giveBidders AccountBidders
+		giveBidder  string
+		wantPermits bool
+	}{
+		{
+			description: "No lists, permitted",
+			giveBidder:  "appnexus",
+			wantPermits: true,
+		},
+		{
+			description: "Blocked, not permitted",
+			giveBidders: AccountBidders{Blocked: []string{"rubicon", "appnexus"}},
+			giveBidder:  "appnexus",
+			wantPermits: false,
+		},
+		{
+			description: "Not blocked, permitted",
+			giveBidders: AccountBidders{Blocked: []string{"rubicon"}},
+			giveBidder:  "appnexus",
+			wantPermits: true,
+		},
+		{
+			description: "Allowed, permitted",
+			giveBidders: AccountBidders{Allowed: []string{"appnexus"}},
+			giveBidder:  "appnexus",
+			wantPermits: true,
+		},
+		{
+			description: "Not allowed, not permitted",
+			giveBidders: AccountBidders{Allowed: []string{"rubicon"}},
+			giveBidder:  "appnexus",
+			wantPermits: false,
+		},
+		{
+			description: "Allowed and blocked, not permitted",
+			giveBidders: AccountBidders{Allowed: []string{"appnexus"}, Blocked: []string{"appnexus"}},
+			giveBidder:  "appnexus",
+			wantPermits: false,
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.wantPermits, tt.giveBidders.Permits(tt.giveBidder), tt.description)
+	}
+}
+
+func TestAccountAuctionValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		giveAuction AccountAuction
+		wantErrors  []string
+	}{
+		{
+			description: "Empty, valid",
+		},
+		{
+			description: "All set, valid",
+			giveAuction: AccountAuction{
+				TMax:                 500,
+				BidAdjustmentFactors: map[string]float64{"appnexus": 0.9},
+				PriceGranularity:     "dense",
+				BlockedAdvertisers:   []string{"example.com"},
+				BlockedCategories:    []string{"IAB25"},
+				BlockedApps:          []string{"com.example.app"},
+				BlockedCreatives:     []string{"creative-1"},
+				SKANRouting:          SKANRoutingExclude,
+				Deals: []AccountDeal{{
+					ID:         "deal-1",
+					Bidders:    []string{"appnexus"},
+					Floor:      2.5,
+					Priority:   2,
+					Conditions: AccountDealConditions{Countries: []string{"USA"}, PlacementTypes: []string{"Rewarded"}},
+				}},
+			},
+		},
+		{
+			description: "Negative tmax",
+			giveAuction: AccountAuction{TMax: -1},
+			wantErrors:  []string{"auction.tmax must be nonnegative. Got -1"},
+		},
+		{
+			description: "Zero bid adjustment factor",
+			giveAuction: AccountAuction{BidAdjustmentFactors: map[string]float64{"appnexus": 0}},
+			wantErrors:  []string{"auction.bid_adjustment_factors.appnexus must be a positive number. Got 0.000000"},
+		},
+		{
+			description: "Unknown price granularity",
+			giveAuction: AccountAuction{PriceGranularity: "fine"},
+			wantErrors:  []string{`auction.price_granularity "fine" is not one of low, med, medium, high, auto or dense`},
+		},
+		{
+			description: "Unknown SKAN routing",
+			giveAuction: AccountAuction{SKANRouting: "drop"},
+			wantErrors:  []string{`auction.skan_routing "drop" is not one of exclude or deprioritize`},
+		},
+		{
+			description: "Invalid deal",
+			giveAuction: AccountAuction{Deals: []AccountDeal{{
+				Floor:      -1,
+				Priority:   -1,
+				Conditions: AccountDealConditions{PlacementTypes: []string{"video"}},
+			}}},
+			wantErrors: []string{
+				"auction.deals[0].id must not be empty",
+				"auction.deals[0].bidders must not be empty",
+				"auction.deals[0].floor must be nonnegative. Got -1.000000",
+				"auction.deals[0].priority must be nonnegative. Got -1",
+				`auction.deals[0].conditions.placement_types "video" is not one of interstitial, rewarded, banner or native`,
+			},
+		},
+		{
+			description: "Duplicate deal id",
+			giveAuction: AccountAuction{Deals: []AccountDeal{
+				{ID: "deal-1", Bidders: []string{"appnexus"}},
+				{ID: "deal-1", Bidders: []string{"rubicon"}},
+			}},
+			wantErrors: []string{`auction.deals[1].id "deal-1" is not unique`},
+		},
+	}
+
+	for _, tt := range tests {
+		errs := tt.giveAuction.Validate()
+		if assert.Len(t, errs, len(tt.wantErrors), tt.description) {
+			for i, err := range errs {
+				assert.EqualError(t, err, tt.wantErrors[i], tt.description)
+			}
+		}
+	}
+}
@@ -2,10 +2,12 @@ package config
 
 import (
 	"fmt"
+	"strings"
 	"text/template"
 
 	validator "github.com/asaskevich/govalidator"
 	"github.com/prebid/prebid-server/macros"
+	"github.com/prebid/prebid-server/openrtb_ext"
 )
 
 type Adapter struct {
@@ -32,9 +34,26 @@ type Adapter struct {
 	// needed for Rubicon
 	XAPI AdapterXAPI `mapstructure:"xapi"`
 
+	// Regions maps a region name (e.g. us_east, eu, apac) to the bidder endpoint serving it.
+	// See RegionEndpoints for how the legacy xapi regional endpoints are merged in.
+	Regions map[string]AdapterRegion `mapstructure:"regions"`
+	// DeploymentRegion is copied from the host config region for convenience.
+	DeploymentRegion string `mapstructure:"-"`
+
 	// needed for Facebook
 	PlatformID string `mapstructure:"platform_id"`
 	AppSecret  string `mapstructure:"app_secret"`
+
+	// overrides the skadnetwork entry of static/bidder-info/{bidder}.yaml
+	SKAdNetwork AdapterSKAdNetwork `mapstructure:"skadnetwork"`
+
+	CircuitBreaker AdapterCircuitBreaker `mapstructure:"circuit_breaker"`
+
+	Hedge AdapterHedge `mapstructure:"hedge"`
+
+	// overrides the seat entry of static/bidder-info/{bidder}.yaml, which registers the adapter of another
+	// bidder under this bidder code
+	Seat AdapterSeat `mapstructure:"seat"`
 }
 
 type AdapterXAPI struct {
@@ -50,6 +69,102 @@ type AdapterXAPI struct {
 	EndpointSG     string `mapstructure:"endpoint_sg"`
 }
 
+// AdapterRegion is a regional endpoint of a bidder
+type AdapterRegion struct {
+	Endpoint string `mapstructure:"endpoint"`
+	// Countries served by this region, matched against device.geo.country (ISO-3166-1 alpha-3)
+	Countries []string `mapstructure:"countries"`
+	// Fallback is the region retried when this region's endpoint fails with a connection error or a 5xx
+	Fallback string `mapstructure:"fallback"`
+}
+
+// AdapterCircuitBreaker configures the circuit breakers guarding each endpoint of a bidder. A circuit opens
+// when the error or timeout rate of the endpoint over the window crosses its threshold, then rejects the
+// requests to the endpoint for OpenSeconds before letting HalfOpenProbes requests through. The circuit
+// closes once all the probes succeed and opens again as soon as one fails.
+type AdapterCircuitBreaker struct {
+	Enabled       bool `mapstructure:"enabled"`
+	WindowSeconds int  `mapstructure:"window_seconds"`
+	// MinRequests is the number of requests the window needs before the rates are considered
+	MinRequests int `mapstructure:"min_requests"`
+	// ErrorRate is the share of connection errors and 5xx responses opening the circuit, 0 disables it
+	ErrorRate float64 `mapstructure:"error_rate"`
+	// TimeoutRate is the share of timeouts opening the circuit, 0 disables it
+	TimeoutRate    float64 `mapstructure:"timeout_rate"`
+	OpenSeconds    int     `mapstructure:"open_seconds"`
+	HalfOpenProbes int     `mapstructure:"half_open_probes"`
+}
+
+// AdapterHedge configures the hedging of the requests of a bidder to the fallback endpoint of their region. When
+// the endpoint of the region has not answered after DelayMs, the same request is sent to the fallback endpoint
+// and the first valid response wins, the other call is cancelled.
+type AdapterHedge struct {
+	Enabled bool `mapstructure:"enabled"`
+	DelayMs int  `mapstructure:"delay_ms"`
+}
+
+// Legacy region names of the xapi regional endpoints
+const (
+	RegionUSEast = "us_east"
+	RegionUSWest = "us_west"
+	RegionEU     = "eu"
+	RegionAPAC   = "apac"
+	RegionJP     = "jp"
+	RegionSG     = "sg"
+)
+
+// RegionEndpoints returns the regions of the adapter. Non empty xapi regional endpoints are exposed
+// under their legacy region names, regions defined in the regions map take precedence over them.
+func (a Adapter) RegionEndpoints() map[string]AdapterRegion {
+	regions := make(map[string]AdapterRegion, len(a.Regions))
+
+	legacy := map[string]string{
+		RegionUSEast: a.XAPI.EndpointUSEast,
+		RegionUSWest: a.XAPI.EndpointUSWest,
+		RegionEU:     a.XAPI.EndpointEU,
+		RegionAPAC:   a.XAPI.EndpointAPAC,
+		RegionJP:     a.XAPI.EndpointJP,
+		RegionSG:     a.XAPI.EndpointSG,
+	}
+	for name, endpoint := range legacy {
+		if endpoint != "" {
+			regions[name] = AdapterRegion{Endpoint: endpoint}
+		}
+	}
+
+	for name, region := range a.Regions {
+		regions[strings.ToLower(name)] = region
+	}
+
+	return regions
+}
+
+// AdapterSeat is the seat profile of a bidder code served by the adapter of another bidder, set in
+// static/bidder-info/{bidder}.yaml and overridden by the adapter config. The seat has its own endpoint,
+// credentials and metrics under its bidder code, set like those of any other bidder, and overrides the
+// capabilities the adapter would have under its own bidder code. It cannot have a skadnetwork entry, its
+// SKAdNetwork IDs are those of the bidder serving it, which its adapter filters the requests with. A seat set in
+// the adapter config only needs neither a bidder name nor a bidder params schema of its own, it uses those of the
+// bidder serving it.
+type AdapterSeat struct {
+	// Of is the bidder whose adapter serves the seat
+	Of string `mapstructure:"of" yaml:"of"`
+	// MRAID makes the seat bid on the MRAID banners, the imps flagged mraid_supported, instead of the videos
+	MRAID bool `mapstructure:"mraid" yaml:"mraid"`
+	// MediaTypes overrides the media types of the capabilities of static/bidder-info/{bidder}.yaml
+	MediaTypes []openrtb_ext.BidType `mapstructure:"media_types" yaml:"mediaTypes"`
+}
+
+type AdapterSKAdNetwork struct {
+	IDs       []string `mapstructure:"ids"`
+	ListURL   string   `mapstructure:"list_url"`
+	DefaultID string   `mapstructure:"default_id"`
+}
+
+func (s AdapterSKAdNetwork) empty() bool {
+	return len(s.IDs) == 0 && s.ListURL == "" && s.DefaultID == ""
+}
+
 // validateAdapters validates adapter's endpoint and user sync URL
 func validateAdapters(adapterMap map[string]Adapter, errs []error) []error {
 	for adapterName, adapter := range adapterMap {
@@ -59,6 +174,15 @@ func validateAdapters(adapterMap map[string]Adapter, errs []error) []error {
 
 			// Verify that valid user_sync URLs are specified in the config
 			errs = validateAdapterUserSyncURL(adapter.UserSyncURL, adapterName, errs)
+
+			// Verify that regional endpoints and their fallbacks are valid
+			errs = validateAdapterRegions(adapter, adapterName, errs)
+
+			errs = validateAdapterCircuitBreaker(adapter.CircuitBreaker, adapterName, errs)
+
+			errs = validateAdapterHedge(adapter.Hedge, adapterName, errs)
+
+			errs = validateAdapterSeat(adapter.Seat, adapterMap, adapterName, errs)
 		}
 	}
 	return errs
@@ -111,6 +235,101 @@ func validateAdapterEndpoint(endpoint string, adapterName string, errs []error)
 	return errs
 }
 
+// validateAdapterRegions makes sure every region has a valid endpoint and that fallbacks refer to
+// a configured region
+func validateAdapterRegions(adapter Adapter, adapterName string, errs []error) []error {
+	endpoints := adapter.RegionEndpoints()
+	for name, region := range adapter.Regions {
+		if !validator.IsURL(region.Endpoint) || !validator.IsRequestURL(region.Endpoint) {
+			errs = append(errs, fmt.Errorf("The endpoint: %s for %s region %s is not a valid URL", region.Endpoint, adapterName, name))
+		}
+		if region.Fallback == "" {
+			continue
+		}
+		// the fallback may be a legacy xapi region, the regions are resolved case-insensitively
+		fallback := strings.ToLower(region.Fallback)
+		if _, ok := endpoints[fallback]; !ok || fallback == strings.ToLower(name) {
+			errs = append(errs, fmt.Errorf("The fallback region: %s for %s region %s is not a configured region", region.Fallback, adapterName, name))
+		}
+	}
+	return errs
+}
+
+// validateAdapterCircuitBreaker makes sure that an enabled circuit breaker can open and close again
+func validateAdapterCircuitBreaker(cb AdapterCircuitBreaker, adapterName string, errs []error) []error {
+	if !cb.Enabled {
+		return errs
+	}
+	if cb.WindowSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("adapters.%s.circuit_breaker.window_seconds must be > 0. Got %d", adapterName, cb.WindowSeconds))
+	}
+	if cb.MinRequests <= 0 {
+		errs = append(errs, fmt.Errorf("adapters.%s.circuit_breaker.min_requests must be > 0. Got %d", adapterName, cb.MinRequests))
+	}
+	if cb.ErrorRate < 0 || cb.ErrorRate > 1 {
+		errs = append(errs, fmt.Errorf("adapters.%s.circuit_breaker.error_rate must be in the range [0, 1]. Got %g", adapterName, cb.ErrorRate))
+	}
+	if cb.TimeoutRate < 0 || cb.TimeoutRate > 1 {
+		errs = append(errs, fmt.Errorf("adapters.%s.circuit_breaker.timeout_rate must be in the range [0, 1]. Got %g", adapterName, cb.TimeoutRate))
+	}
+	if cb.ErrorRate == 0 && cb.TimeoutRate == 0 {
+		errs = append(errs, fmt.Errorf("adapters.%s.circuit_breaker requires an error_rate or a timeout_rate", adapterName))
+	}
+	if cb.OpenSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("adapters.%s.circuit_breaker.open_seconds must be > 0. Got %d", adapterName, cb.OpenSeconds))
+	}
+	if cb.HalfOpenProbes <= 0 {
+		errs = append(errs, fmt.Errorf("adapters.%s.circuit_breaker.half_open_probes must be > 0. Got %d", adapterName, cb.HalfOpenProbes))
+	}
+	return errs
+}
+
+// validateAdapterHedge makes sure that an enabled hedge waits for the endpoint of the region
+func validateAdapterHedge(hedge AdapterHedge, adapterName string, errs []error) []error {
+	if hedge.Enabled && hedge.DelayMs <= 0 {
+		errs = append(errs, fmt.Errorf("adapters.%s.hedge.delay_ms must be > 0. Got %d", adapterName, hedge.DelayMs))
+	}
+	return errs
+}
+
+// validateAdapterSeat makes sure that a seat is served by the adapter of a configured core bidder which is not a
+// seat itself, with supported media types and without a SKAN ID List of its own
+func validateAdapterSeat(seat AdapterSeat, adapterMap map[string]Adapter, adapterName string, errs []error) []error {
+	if seat.Of != "" {
+		_, isCoreBidder := openrtb_ext.NormalizeBidderName(seat.Of)
+		if of, ok := adapterMap[strings.ToLower(seat.Of)]; !ok || !isCoreBidder || of.Seat.Of != "" {
+			errs = append(errs, fmt.Errorf("adapters.%s.seat.of %s must be a configured core bidder which is not a seat", adapterName, seat.Of))
+		}
+		if !adapterMap[adapterName].SKAdNetwork.empty() {
+			errs = append(errs, fmt.Errorf("adapters.%s.skadnetwork cannot be set on a seat, it uses the SKAN ID List of %s", adapterName, seat.Of))
+		}
+	}
+	for _, mediaType := range seat.MediaTypes {
+		if _, err := openrtb_ext.ParseBidType(string(mediaType)); err != nil {
+			errs = append(errs, fmt.Errorf("adapters.%s.seat.media_types: %v", adapterName, err))
+		}
+	}
+	return errs
+}
+
+// AdapterSeats returns the seats set in the adapter config only, which have no bidder name of their own, mapped
+// to the core bidder serving them. They get the bidder info and the bidder params schema of their core bidder.
+func AdapterSeats(adapterMap map[string]Adapter) map[openrtb_ext.BidderName]openrtb_ext.BidderName {
+	seats := make(map[openrtb_ext.BidderName]openrtb_ext.BidderName)
+	for adapterName, adapter := range adapterMap {
+		if adapter.Seat.Of == "" {
+			continue
+		}
+		if _, ok := openrtb_ext.NormalizeBidderName(adapterName); ok {
+			continue
+		}
+		if core, ok := openrtb_ext.NormalizeBidderName(adapter.Seat.Of); ok {
+			seats[openrtb_ext.BidderName(strings.ToLower(adapterName))] = core
+		}
+	}
+	return seats
+}
+
 // validateAdapterUserSyncURL validates an adapter's user sync URL if it is set
 func validateAdapterUserSyncURL(userSyncURL string, adapterName string, errs []error) []error {
 	if userSyncURL != "" {
@@ -20,8 +20,21 @@ type BidderInfo struct {
 	ModifyingVastXmlAllowed bool              `yaml:"modifyingVastXmlAllowed"`
 	Debug                   *DebugInfo        `yaml:"debug,omitempty"`
 	GVLVendorID             uint16            `yaml:"gvlVendorID,omitempty"`
+	SKAdNetwork             *SKAdNetworkInfo  `yaml:"skadnetwork,omitempty"`
+	Currency                string            `yaml:"currency,omitempty"` // currency of imp.bidfloor sent to the bidder, USD if empty
+	GPP                     bool              `yaml:"gpp,omitempty"`      // the bidder reads regs.ext.gpp, the others get regs.ext.us_privacy
+	Seat                    *AdapterSeat      `yaml:"seat,omitempty"`
+	Gzip                    bool              `yaml:"gzip,omitempty"`           // the bidder accepts gzip request bodies, and is asked for gzip responses
+	OpenRTBVersion          string            `yaml:"openrtbVersion,omitempty"` // OpenRTBVersion26 if the bidder reads the OpenRTB 2.6 fields, OpenRTBVersion25 if empty
 }
 
+// OpenRTB versions a bidder speaks. The requests of the bidders speaking OpenRTB 2.5 are converted down to it, their
+// OpenRTB 2.6 fields are moved to their ext equivalents.
+const (
+	OpenRTBVersion25 = "2.5"
+	OpenRTBVersion26 = "2.6"
+)
+
 // MaintainerInfo is the support email address for a bidder.
 type MaintainerInfo struct {
 	Email string `yaml:"email"`
@@ -43,6 +56,15 @@ type DebugInfo struct {
 	Allow bool `yaml:"allow"`
 }
 
+// SKAdNetworkInfo is the SKAdNetwork ID registry entry for a bidder. IDs are the bidder's static
+// SKAdNetwork IDs, ListURL points to an IAB formatted SKAdNetwork ID list hosted by the bidder and
+// DefaultID is used until the remote list has been fetched.
+type SKAdNetworkInfo struct {
+	IDs       []string `yaml:"ids"`
+	ListURL   string   `yaml:"listURL"`
+	DefaultID string   `yaml:"defaultID"`
+}
+
 // LoadBidderInfoFromDisk parses all static/bidder-info/{bidder}.yaml files from the file system.
 func LoadBidderInfoFromDisk(path string, adapterConfigs map[string]Adapter, bidders []string) (BidderInfos, error) {
 	reader := infoReaderFromDisk{path}
@@ -52,8 +74,17 @@ func LoadBidderInfoFromDisk(path string, adapterConfigs map[string]Adapter, bidd
 func loadBidderInfo(r infoReader, adapterConfigs map[string]Adapter, bidders []string) (BidderInfos, error) {
 	infos := BidderInfos{}
 
+	// a seat set in the adapter config only has the bidder info of its core bidder
+	files := make(map[string]string, len(bidders))
 	for _, bidder := range bidders {
-		data, err := r.Read(bidder)
+		files[bidder] = bidder
+	}
+	for seat, core := range AdapterSeats(adapterConfigs) {
+		files[string(seat)] = string(core)
+	}
+
+	for bidder, file := range files {
+		data, err := r.Read(file)
 		if err != nil {
 			return nil, err
 		}
@@ -63,7 +94,20 @@ func loadBidderInfo(r infoReader, adapterConfigs map[string]Adapter, bidders []s
 			return nil, fmt.Errorf("error parsing yaml for bidder %s: %v", bidder, err)
 		}
 
+		if info.OpenRTBVersion != "" && info.OpenRTBVersion != OpenRTBVersion25 && info.OpenRTBVersion != OpenRTBVersion26 {
+			return nil, fmt.Errorf("openrtbVersion %q of bidder %s is not one of %s or %s", info.OpenRTBVersion, bidder, OpenRTBVersion25, OpenRTBVersion26)
+		}
+
+		// a seat uses the SKAN ID List of the bidder serving it, which its adapter filters the requests with
+		ownSKAdNetwork := file == bidder && info.SKAdNetwork != nil
+
 		info.Enabled = isEnabledByConfig(adapterConfigs, bidder)
+		info.SKAdNetwork = mergeSKAdNetworkConfig(info.SKAdNetwork, adapterConfigs, bidder)
+		info.Seat = mergeSeatConfig(info.Seat, adapterConfigs, bidder)
+		if info.Seat != nil && info.Seat.Of != "" && (ownSKAdNetwork || !adapterConfigs[strings.ToLower(bidder)].SKAdNetwork.empty()) {
+			return nil, fmt.Errorf("seat %s cannot have a skadnetwork entry, it uses the SKAN ID List of %s", bidder, info.Seat.Of)
+		}
+		info.Capabilities = mergeSeatCapabilities(info.Capabilities, info.Seat)
 		infos[bidder] = info
 	}
 
@@ -75,6 +119,75 @@ func isEnabledByConfig(adapterConfigs map[string]Adapter, bidderName string) boo
 	return ok && !a.Disabled
 }
 
+// mergeSKAdNetworkConfig overrides the bidder-info SKAdNetwork entry with any values set in the
+// adapter config.
+func mergeSKAdNetworkConfig(info *SKAdNetworkInfo, adapterConfigs map[string]Adapter, bidderName string) *SKAdNetworkInfo {
+	a, ok := adapterConfigs[strings.ToLower(bidderName)]
+	if !ok || a.SKAdNetwork.empty() {
+		return info
+	}
+
+	merged := SKAdNetworkInfo{}
+	if info != nil {
+		merged = *info
+	}
+
+	if len(a.SKAdNetwork.IDs) > 0 {
+		merged.IDs = a.SKAdNetwork.IDs
+	}
+	if a.SKAdNetwork.ListURL != "" {
+		merged.ListURL = a.SKAdNetwork.ListURL
+	}
+	if a.SKAdNetwork.DefaultID != "" {
+		merged.DefaultID = a.SKAdNetwork.DefaultID
+	}
+
+	return &merged
+}
+
+// mergeSeatConfig overrides the bidder-info seat entry with the seat set in the adapter config or, when it
+// serves no other bidder, with the values it sets.
+func mergeSeatConfig(info *AdapterSeat, adapterConfigs map[string]Adapter, bidderName string) *AdapterSeat {
+	a, ok := adapterConfigs[strings.ToLower(bidderName)]
+	if !ok || (a.Seat.Of == "" && !a.Seat.MRAID && len(a.Seat.MediaTypes) == 0) {
+		return info
+	}
+	if a.Seat.Of != "" {
+		merged := a.Seat
+		return &merged
+	}
+
+	merged := AdapterSeat{}
+	if info != nil {
+		merged = *info
+	}
+	if a.Seat.MRAID {
+		merged.MRAID = true
+	}
+	if len(a.Seat.MediaTypes) > 0 {
+		merged.MediaTypes = a.Seat.MediaTypes
+	}
+
+	return &merged
+}
+
+// mergeSeatCapabilities overrides the media types of the bidder-info capabilities with those of the seat.
+func mergeSeatCapabilities(info *CapabilitiesInfo, seat *AdapterSeat) *CapabilitiesInfo {
+	if info == nil || seat == nil || len(seat.MediaTypes) == 0 {
+		return info
+	}
+
+	merged := CapabilitiesInfo{}
+	if info.App != nil {
+		merged.App = &PlatformInfo{MediaTypes: seat.MediaTypes}
+	}
+	if info.Site != nil {
+		merged.Site = &PlatformInfo{MediaTypes: seat.MediaTypes}
+	}
+
+	return &merged
+}
+
 type infoReader interface {
 	Read(bidder string) ([]byte, error)
 }
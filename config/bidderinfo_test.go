@@ -2,6 +2,7 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -156,6 +157,34 @@ func TestLoadBidderInfo(t *testing.T) {
 				},
 			},
 		},
+		{
+			description:  "Gzip",
+			givenConfigs: map[string]Adapter{strings.ToLower(bidder): {}},
+			givenContent: "gzip: true",
+			expectedInfo: map[string]BidderInfo{
+				bidder: {
+					Enabled: true,
+					Gzip:    true,
+				},
+			},
+		},
+		{
+			description:  "OpenRTB 2.6",
+			givenConfigs: map[string]Adapter{strings.ToLower(bidder): {}},
+			givenContent: "openrtbVersion: \"2.6\"",
+			expectedInfo: map[string]BidderInfo{
+				bidder: {
+					Enabled:        true,
+					OpenRTBVersion: OpenRTBVersion26,
+				},
+			},
+		},
+		{
+			description:   "Unsupported OpenRTB Version",
+			givenConfigs:  map[string]Adapter{strings.ToLower(bidder): {}},
+			givenContent:  "openrtbVersion: \"3.0\"",
+			expectedError: "openrtbVersion \"3.0\" of bidder someBidder is not one of 2.5 or 2.6",
+		},
 		{
 			description:   "Read Error",
 			givenConfigs:  map[string]Adapter{strings.ToLower(bidder): {}},
@@ -208,3 +237,238 @@ func TestToGVLVendorIDMap(t *testing.T) {
 	result := givenBidderInfos.ToGVLVendorIDMap()
 	assert.Equal(t, expectedGVLVendorIDMap, result)
 }
+
+func TestLoadBidderInfoSKAdNetwork(t *testing.T) {
+	bidder := "someBidder"
+	skanYAML := testYAML + `
+skadnetwork:
+  ids:
+    - "abc.skadnetwork"
+  listURL: "https://somebidder.com/skadnetworkids.json"
+  defaultID: "def.skadnetwork"
+`
+
+	testCases := []struct {
+		description  string
+		givenConfigs map[string]Adapter
+		givenContent string
+		expectedSKAN *SKAdNetworkInfo
+	}{
+		{
+			description:  "Not Configured",
+			givenConfigs: map[string]Adapter{strings.ToLower(bidder): {}},
+			givenContent: testYAML,
+			expectedSKAN: nil,
+		},
+		{
+			description:  "Bidder Info Only",
+			givenConfigs: map[string]Adapter{strings.ToLower(bidder): {}},
+			givenContent: skanYAML,
+			expectedSKAN: &SKAdNetworkInfo{
+				IDs:       []string{"abc.skadnetwork"},
+				ListURL:   "https://somebidder.com/skadnetworkids.json",
+				DefaultID: "def.skadnetwork",
+			},
+		},
+		{
+			description: "Adapter Config Overrides Bidder Info",
+			givenConfigs: map[string]Adapter{strings.ToLower(bidder): {
+				SKAdNetwork: AdapterSKAdNetwork{IDs: []string{"xyz.skadnetwork"}},
+			}},
+			givenContent: skanYAML,
+			expectedSKAN: &SKAdNetworkInfo{
+				IDs:       []string{"xyz.skadnetwork"},
+				ListURL:   "https://somebidder.com/skadnetworkids.json",
+				DefaultID: "def.skadnetwork",
+			},
+		},
+		{
+			description: "Adapter Config Only",
+			givenConfigs: map[string]Adapter{strings.ToLower(bidder): {
+				SKAdNetwork: AdapterSKAdNetwork{ListURL: "https://other.com/skadnetworkids.json", DefaultID: "ghi.skadnetwork"},
+			}},
+			givenContent: testYAML,
+			expectedSKAN: &SKAdNetworkInfo{
+				ListURL:   "https://other.com/skadnetworkids.json",
+				DefaultID: "ghi.skadnetwork",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		r := fakeInfoReader{test.givenContent, nil}
+		infos, err := loadBidderInfo(r, test.givenConfigs, []string{bidder})
+
+		assert.NoError(t, err, test.description)
+		assert.Equal(t, test.expectedSKAN, infos[bidder].SKAdNetwork, test.description)
+	}
+}
+
+func TestLoadBidderInfoSeat(t *testing.T) {
+	bidder := "someBidder"
+	seatYAML := testYAML + `
+seat:
+  of: appnexus
+  mraid: true
+`
+
+	testCases := []struct {
+		description          string
+		givenConfigs         map[string]Adapter
+		givenContent         string
+		expectedSeat         *AdapterSeat
+		expectedCapabilities *CapabilitiesInfo
+	}{
+		{
+			description:  "Not Configured",
+			givenConfigs: map[string]Adapter{strings.ToLower(bidder): {}},
+			givenContent: testYAML,
+		},
+		{
+			description:  "Bidder Info Only",
+			givenConfigs: map[string]Adapter{strings.ToLower(bidder): {}},
+			givenContent: seatYAML,
+			expectedSeat: &AdapterSeat{Of: "appnexus", MRAID: true},
+		},
+		{
+			description: "Adapter Config Overrides Bidder Info",
+			givenConfigs: map[string]Adapter{strings.ToLower(bidder): {
+				Seat: AdapterSeat{Of: "rubicon"},
+			}},
+			givenContent: seatYAML,
+			expectedSeat: &AdapterSeat{Of: "rubicon"},
+		},
+		{
+			description: "Adapter Config Media Types",
+			givenConfigs: map[string]Adapter{strings.ToLower(bidder): {
+				Seat: AdapterSeat{MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeVideo}},
+			}},
+			givenContent: seatYAML,
+			expectedSeat: &AdapterSeat{Of: "appnexus", MRAID: true, MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeVideo}},
+			expectedCapabilities: &CapabilitiesInfo{
+				App:  &PlatformInfo{MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeVideo}},
+				Site: &PlatformInfo{MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeVideo}},
+			},
+		},
+		{
+			description: "Adapter Config MRAID Only",
+			givenConfigs: map[string]Adapter{strings.ToLower(bidder): {
+				Seat: AdapterSeat{MRAID: true},
+			}},
+			givenContent: testYAML,
+			expectedSeat: &AdapterSeat{MRAID: true},
+		},
+		{
+			description: "Adapter Config MRAID Kept With The Bidder Info Seat",
+			givenConfigs: map[string]Adapter{strings.ToLower(bidder): {
+				Seat: AdapterSeat{MRAID: true},
+			}},
+			givenContent: testYAML + "seat:\n  of: appnexus\n",
+			expectedSeat: &AdapterSeat{Of: "appnexus", MRAID: true},
+		},
+		{
+			description: "Media Types Of A Single Platform",
+			givenConfigs: map[string]Adapter{strings.ToLower(bidder): {
+				Seat: AdapterSeat{Of: "appnexus", MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeVideo}},
+			}},
+			givenContent: "capabilities:\n  app:\n    mediaTypes:\n      - banner\n",
+			expectedSeat: &AdapterSeat{Of: "appnexus", MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeVideo}},
+			expectedCapabilities: &CapabilitiesInfo{
+				App: &PlatformInfo{MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeVideo}},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		r := fakeInfoReader{test.givenContent, nil}
+		infos, err := loadBidderInfo(r, test.givenConfigs, []string{bidder})
+		if !assert.NoError(t, err, test.description) {
+			continue
+		}
+		assert.Equal(t, test.expectedSeat, infos[bidder].Seat, test.description)
+		if test.expectedCapabilities != nil {
+			assert.Equal(t, test.expectedCapabilities, infos[bidder].Capabilities, test.description)
+		}
+	}
+}
+
+func TestLoadBidderInfoConfigSeat(t *testing.T) {
+	configs := map[string]Adapter{
+		"appnexus": {},
+		"infoseat": {Seat: AdapterSeat{Of: "appnexus", MRAID: true}},
+	}
+
+	r := fileInfoReader{"appnexus": testYAML}
+	infos, err := loadBidderInfo(r, configs, []string{"appnexus"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, infos, 2)
+	assert.True(t, infos["infoseat"].Enabled)
+	assert.Equal(t, "some-email@domain.com", infos["infoseat"].Maintainer.Email, "the bidder info of the core bidder")
+	assert.Equal(t, &AdapterSeat{Of: "appnexus", MRAID: true}, infos["infoseat"].Seat)
+}
+
+func TestLoadBidderInfoSeatSKAdNetwork(t *testing.T) {
+	skanYAML := testYAML + "skadnetwork:\n  ids:\n    - \"abc.skadnetwork\"\n"
+
+	testCases := []struct {
+		description   string
+		givenConfigs  map[string]Adapter
+		givenFiles    fileInfoReader
+		givenBidders  []string
+		expectedError string
+	}{
+		{
+			description:   "Bidder Info Seat",
+			givenConfigs:  map[string]Adapter{"infoseat": {}},
+			givenFiles:    fileInfoReader{"infoseat": skanYAML + "seat:\n  of: appnexus\n"},
+			givenBidders:  []string{"infoseat"},
+			expectedError: "seat infoseat cannot have a skadnetwork entry, it uses the SKAN ID List of appnexus",
+		},
+		{
+			description: "Adapter Config Of A Bidder Info Seat",
+			givenConfigs: map[string]Adapter{"infoseat": {
+				SKAdNetwork: AdapterSKAdNetwork{IDs: []string{"xyz.skadnetwork"}},
+			}},
+			givenFiles:    fileInfoReader{"infoseat": testYAML + "seat:\n  of: appnexus\n"},
+			givenBidders:  []string{"infoseat"},
+			expectedError: "seat infoseat cannot have a skadnetwork entry, it uses the SKAN ID List of appnexus",
+		},
+		{
+			description: "Adapter Config Seat",
+			givenConfigs: map[string]Adapter{"appnexus": {}, "configseat": {
+				Seat:        AdapterSeat{Of: "appnexus"},
+				SKAdNetwork: AdapterSKAdNetwork{IDs: []string{"xyz.skadnetwork"}},
+			}},
+			givenFiles:    fileInfoReader{"appnexus": testYAML},
+			givenBidders:  []string{"appnexus"},
+			expectedError: "seat configseat cannot have a skadnetwork entry, it uses the SKAN ID List of appnexus",
+		},
+		{
+			description:  "Adapter Config Seat Of A Bidder With A SKAN ID List",
+			givenConfigs: map[string]Adapter{"appnexus": {}, "configseat": {Seat: AdapterSeat{Of: "appnexus"}}},
+			givenFiles:   fileInfoReader{"appnexus": skanYAML},
+			givenBidders: []string{"appnexus"},
+		},
+	}
+
+	for _, test := range testCases {
+		_, err := loadBidderInfo(test.givenFiles, test.givenConfigs, test.givenBidders)
+		if test.expectedError != "" {
+			assert.EqualError(t, err, test.expectedError, test.description)
+		} else {
+			assert.NoError(t, err, test.description)
+		}
+	}
+}
+
+type fileInfoReader map[string]string
+
+func (r fileInfoReader) Read(bidder string) ([]byte, error) {
+	content, ok := r[bidder]
+	if !ok {
+		return nil, fmt.Errorf("no bidder info for %s", bidder)
+	}
+	return []byte(content), nil
+}
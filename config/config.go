@@ -21,6 +21,7 @@ type Configuration struct {
 	ExternalURL string     `mapstructure:"external_url"`
 	Host        string     `mapstructure:"host"`
 	Port        int        `mapstructure:"port"`
+	Region      string     `mapstructure:"region"` // deployment region, used to pick regional bidder endpoints
 	Client      HTTPClient `mapstructure:"http_client"`
 	CacheClient HTTPClient `mapstructure:"http_client_cache"`
 	AdminPort   int        `mapstructure:"admin_port"`
@@ -52,8 +53,10 @@ type Configuration struct {
 	AMPTimeoutAdjustment int64              `mapstructure:"amp_timeout_adjustment_ms"`
 	GDPR                 GDPR               `mapstructure:"gdpr"`
 	CCPA                 CCPA               `mapstructure:"ccpa"`
+	GPP                  GPP                `mapstructure:"gpp"`
 	LMT                  LMT                `mapstructure:"lmt"`
 	CurrencyConverter    CurrencyConverter  `mapstructure:"currency_converter"`
+	SKANIDList           SKANIDList         `mapstructure:"skan_id_list"`
 	DefReqConfig         DefReqConfig       `mapstructure:"default_request"`
 
 	VideoStoredRequestRequired bool `mapstructure:"video_stored_request_required"`
@@ -136,9 +139,14 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 	}
 	errs = cfg.GDPR.validate(v, errs)
 	errs = cfg.CurrencyConverter.validate(errs)
+	errs = cfg.SKANIDList.validate(errs)
+	errs = cfg.Analytics.BidderCalls.validate(errs)
 	errs = validateAdapters(cfg.Adapters, errs)
 	errs = cfg.Debug.validate(errs)
 	errs = cfg.ExtCacheURL.validate(errs)
+	for _, err := range cfg.AccountDefaults.Auction.Validate() {
+		errs = append(errs, fmt.Errorf("account_defaults.%v", err))
+	}
 	if cfg.AccountDefaults.Disabled {
 		glog.Warning(`With account_defaults.disabled=true, host-defined accounts must exist and have "disabled":false. All other requests will be rejected.`)
 	}
@@ -150,12 +158,55 @@ type AuctionTimeouts struct {
 	Default uint64 `mapstructure:"default"`
 	// The max timeout is used as an absolute cap, to prevent excessively long ones. Use 0 for no cap
 	Max uint64 `mapstructure:"max"`
+	// Adaptive gives every bidder its own share of the auction timeout
+	Adaptive AdaptiveTimeouts `mapstructure:"adaptive"`
+}
+
+// AdaptiveTimeouts gives every bidder a deadline at a percentile of its recent latencies, plus some headroom,
+// instead of the whole auction timeout. The tmax sent to the bidders is rewritten to the time they are actually
+// left, minus the network overhead.
+type AdaptiveTimeouts struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Samples is the number of recent latencies of a bidder the percentile is computed over
+	Samples int `mapstructure:"samples"`
+	// MinSamples is the number of latencies a bidder needs before it gets its own deadline
+	MinSamples int     `mapstructure:"min_samples"`
+	Percentile float64 `mapstructure:"percentile"`
+	// HeadroomPercent is added to the percentile, letting the deadline grow again when a bidder slows down
+	HeadroomPercent   int `mapstructure:"headroom_percent"`
+	MinBudgetMs       int `mapstructure:"min_budget_ms"`
+	NetworkOverheadMs int `mapstructure:"network_overhead_ms"`
 }
 
 func (cfg *AuctionTimeouts) validate(errs []error) []error {
 	if cfg.Max < cfg.Default {
 		errs = append(errs, fmt.Errorf("auction_timeouts_ms.max cannot be less than auction_timeouts_ms.default. max=%d, default=%d", cfg.Max, cfg.Default))
 	}
+	return cfg.Adaptive.validate(errs)
+}
+
+func (cfg *AdaptiveTimeouts) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.Samples <= 0 {
+		errs = append(errs, fmt.Errorf("auction_timeouts_ms.adaptive.samples must be > 0. Got %d", cfg.Samples))
+	}
+	if cfg.MinSamples <= 0 || cfg.MinSamples > cfg.Samples {
+		errs = append(errs, fmt.Errorf("auction_timeouts_ms.adaptive.min_samples must be in the range [1, samples]. Got %d", cfg.MinSamples))
+	}
+	if cfg.Percentile <= 0 || cfg.Percentile > 100 {
+		errs = append(errs, fmt.Errorf("auction_timeouts_ms.adaptive.percentile must be in the range (0, 100]. Got %g", cfg.Percentile))
+	}
+	if cfg.HeadroomPercent < 0 {
+		errs = append(errs, fmt.Errorf("auction_timeouts_ms.adaptive.headroom_percent must be >= 0. Got %d", cfg.HeadroomPercent))
+	}
+	if cfg.MinBudgetMs < 0 {
+		errs = append(errs, fmt.Errorf("auction_timeouts_ms.adaptive.min_budget_ms must be >= 0. Got %d", cfg.MinBudgetMs))
+	}
+	if cfg.NetworkOverheadMs < 0 {
+		errs = append(errs, fmt.Errorf("auction_timeouts_ms.adaptive.network_overhead_ms must be >= 0. Got %d", cfg.NetworkOverheadMs))
+	}
 	return errs
 }
 
@@ -216,6 +267,7 @@ func (cfg *AuctionTimeouts) LimitAuctionTimeout(requested time.Duration) time.Du
 type Privacy struct {
 	CCPA CCPA
 	GDPR GDPR
+	GPP  GPP
 	LMT  LMT
 }
 
@@ -291,13 +343,19 @@ type CCPA struct {
 	Enforce bool `mapstructure:"enforce"`
 }
 
+// GPP enforces the opt-outs of the US sections of the Global Privacy Platform string of the requests
+type GPP struct {
+	Enforce bool `mapstructure:"enforce"`
+}
+
 type LMT struct {
 	Enforce bool `mapstructure:"enforce"`
 }
 
 type Analytics struct {
-	File     FileLogs `mapstructure:"file"`
-	Pubstack Pubstack `mapstructure:"pubstack"`
+	File        FileLogs       `mapstructure:"file"`
+	Pubstack    Pubstack       `mapstructure:"pubstack"`
+	BidderCalls BidderCallLogs `mapstructure:"bidder_calls"`
 }
 
 type CurrencyConverter struct {
@@ -313,6 +371,31 @@ func (cfg *CurrencyConverter) validate(errs []error) []error {
 	return errs
 }
 
+// SKANIDList configures the background refresh of the SKAN ID Lists hosted by bidders
+type SKANIDList struct {
+	FetchIntervalSeconds int `mapstructure:"fetch_interval_seconds"`
+	FetchTimeoutMS       int `mapstructure:"fetch_timeout_ms"`
+	// RetryIntervalSeconds is the delay before a failed fetch is retried, rather than waiting for the
+	// next fetch interval. Failed fetches are not retried when 0.
+	RetryIntervalSeconds int `mapstructure:"retry_interval_seconds"`
+	// PersistDir is the directory where the last successfully fetched list of each bidder is saved,
+	// so a cold start without network access still has SKAN IDs. Persistence is disabled when empty.
+	PersistDir string `mapstructure:"persist_dir"`
+}
+
+func (cfg *SKANIDList) validate(errs []error) []error {
+	if cfg.FetchIntervalSeconds < 0 {
+		errs = append(errs, fmt.Errorf("skan_id_list.fetch_interval_seconds must be >= 0. Got %d", cfg.FetchIntervalSeconds))
+	}
+	if cfg.FetchTimeoutMS < 0 {
+		errs = append(errs, fmt.Errorf("skan_id_list.fetch_timeout_ms must be >= 0. Got %d", cfg.FetchTimeoutMS))
+	}
+	if cfg.RetryIntervalSeconds < 0 {
+		errs = append(errs, fmt.Errorf("skan_id_list.retry_interval_seconds must be >= 0. Got %d", cfg.RetryIntervalSeconds))
+	}
+	return errs
+}
+
 // FileLogs Corresponding config for FileLogger as a PBS Analytics Module
 type FileLogs struct {
 	Filename string `mapstructure:"filename"`
@@ -332,6 +415,51 @@ type PubstackBuffer struct {
 	Timeout    string `mapstructure:"timeout"`
 }
 
+// BidderCallLogs configures the analytics module writing one JSON record per outbound bidder call.
+// The records are posted to Endpoint when it is set, otherwise they are written to rotating files.
+type BidderCallLogs struct {
+	Enabled  bool                 `mapstructure:"enabled"`
+	Endpoint string               `mapstructure:"endpoint"`
+	File     BidderCallLogsFile   `mapstructure:"file"`
+	Buffers  BidderCallLogsBuffer `mapstructure:"buffers"`
+}
+
+type BidderCallLogsFile struct {
+	Filename string `mapstructure:"filename"`
+	// MaxSize rotates the file once it reaches this size, e.g. "100MB". "0" disables the size rotation.
+	MaxSize string `mapstructure:"max_size"`
+	// RotateInterval rotates the file once it is this old, e.g. "1h". "0s" disables the time rotation.
+	RotateInterval string `mapstructure:"rotate_interval"`
+}
+
+type BidderCallLogsBuffer struct {
+	// QueueSize is the number of records waiting to be buffered, records are dropped when the queue is full
+	QueueSize  int    `mapstructure:"queue_size"`
+	BufferSize string `mapstructure:"size"`
+	EventCount int    `mapstructure:"count"`
+	Timeout    string `mapstructure:"timeout"`
+}
+
+func (cfg *BidderCallLogs) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.Endpoint == "" && cfg.File.Filename == "" {
+		errs = append(errs, errors.New("analytics.bidder_calls requires either an endpoint or a file.filename"))
+	} else if cfg.Endpoint != "" {
+		if _, err := url.ParseRequestURI(cfg.Endpoint); err != nil {
+			errs = append(errs, fmt.Errorf("analytics.bidder_calls.endpoint %s is not a valid URL", cfg.Endpoint))
+		}
+	}
+	if cfg.Buffers.QueueSize <= 0 {
+		errs = append(errs, fmt.Errorf("analytics.bidder_calls.buffers.queue_size must be > 0. Got %d", cfg.Buffers.QueueSize))
+	}
+	if cfg.Buffers.EventCount <= 0 {
+		errs = append(errs, fmt.Errorf("analytics.bidder_calls.buffers.count must be > 0. Got %d", cfg.Buffers.EventCount))
+	}
+	return errs
+}
+
 type VTrack struct {
 	TimeoutMS          int64 `mapstructure:"timeout_ms"`
 	AllowUnknownBidder bool  `mapstructure:"allow_unknown_bidder"`
@@ -364,9 +492,10 @@ type RequestTimeoutHeaders struct {
 }
 
 type Metrics struct {
-	Influxdb   InfluxMetrics     `mapstructure:"influxdb"`
-	Prometheus PrometheusMetrics `mapstructure:"prometheus"`
-	Disabled   DisabledMetrics   `mapstructure:"disabled_metrics"`
+	Influxdb      InfluxMetrics        `mapstructure:"influxdb"`
+	Prometheus    PrometheusMetrics    `mapstructure:"prometheus"`
+	OpenTelemetry OpenTelemetryMetrics `mapstructure:"opentelemetry"`
+	Disabled      DisabledMetrics      `mapstructure:"disabled_metrics"`
 }
 
 type DisabledMetrics struct {
@@ -383,7 +512,8 @@ type DisabledMetrics struct {
 }
 
 func (cfg *Metrics) validate(errs []error) []error {
-	return cfg.Prometheus.validate(errs)
+	errs = cfg.Prometheus.validate(errs)
+	return cfg.OpenTelemetry.validate(errs)
 }
 
 type InfluxMetrics struct {
@@ -412,6 +542,23 @@ func (m *PrometheusMetrics) Timeout() time.Duration {
 	return time.Duration(m.TimeoutMillisRaw) * time.Millisecond
 }
 
+// OpenTelemetryMetrics configures the export of the metrics to an OTLP collector, which is enabled by the endpoint
+type OpenTelemetryMetrics struct {
+	Endpoint             string `mapstructure:"endpoint"`
+	CollectPeriodSeconds int    `mapstructure:"collect_period_seconds"`
+}
+
+func (cfg *OpenTelemetryMetrics) validate(errs []error) []error {
+	if cfg.Endpoint != "" && cfg.CollectPeriodSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("metrics.opentelemetry.collect_period_seconds must be positive if metrics.opentelemetry.endpoint is defined. Got %d", cfg.CollectPeriodSeconds))
+	}
+	return errs
+}
+
+func (m *OpenTelemetryMetrics) CollectPeriod() time.Duration {
+	return time.Duration(m.CollectPeriodSeconds) * time.Second
+}
+
 type DataCache struct {
 	Type       string `mapstructure:"type"`
 	Filename   string `mapstructure:"filename"`
@@ -593,6 +740,8 @@ func (cfg *Configuration) GetCachedAssetURL(uuid string) string {
 // For example, the typical Bidder's usersync URL includes the PBS config.external_url, because it redirects to the `external_url/setuid` endpoint.
 //
 func (cfg *Configuration) setDerivedDefaults() {
+	setDeploymentRegion(cfg.Adapters, cfg.Region)
+
 	externalURL := cfg.ExternalURL
 	setDefaultUsersync(cfg.Adapters, openrtb_ext.Bidder33Across, "https://ic.tynt.com/r/d?m=xch&rt=html&gdpr={{.GDPR}}&gdpr_consent={{.GDPRConsent}}&us_privacy={{.USPrivacy}}&ru="+url.QueryEscape(externalURL)+"%2Fsetuid%3Fbidder%3D33across%26uid%3D33XUSERID33X&id=zzz000000000002zzz")
 	setDefaultUsersync(cfg.Adapters, openrtb_ext.BidderAcuityAds, "https://cs.admanmedia.com/sync/prebid?gdpr={{.GDPR}}&gdpr_consent={{.GDPRConsent}}&us_privacy={{.USPrivacy}}&redir="+url.QueryEscape(externalURL)+"%2Fsetuid%3Fbidder%3Dacuityads%26uid%3D%5BUID%5D")
@@ -688,6 +837,13 @@ func (cfg *Configuration) setDerivedDefaults() {
 	setDefaultUsersync(cfg.Adapters, openrtb_ext.BidderBetween, "https://ads.betweendigital.com/match?bidder_id=pbs&gdpr={{.GDPR}}&gdpr_consent={{.GDPRConsent}}&us_privacy={{.USPrivacy}}&callback_url="+url.QueryEscape(externalURL)+"%2Fsetuid%3Fbidder%3Dbetween%26gdpr%3D0%26gdpr_consent%3D{{.GDPRConsent}}%26uid%3D%24%7BUSER_ID%7D")
 }
 
+func setDeploymentRegion(m map[string]Adapter, region string) {
+	for name, adapter := range m {
+		adapter.DeploymentRegion = region
+		m[name] = adapter
+	}
+}
+
 func setDefaultUsersync(m map[string]Adapter, bidder openrtb_ext.BidderName, defaultValue string) {
 	lowercased := strings.ToLower(string(bidder))
 	if m[lowercased].UserSyncURL == "" {
@@ -711,11 +867,19 @@ func SetupViper(v *viper.Viper, filename string) {
 	v.SetDefault("external_url", "http://localhost:8000")
 	v.SetDefault("host", "")
 	v.SetDefault("port", 8000)
+	v.SetDefault("region", "")
 	v.SetDefault("admin_port", 6060)
 	v.SetDefault("enable_gzip", false)
 	v.SetDefault("status_response", "")
 	v.SetDefault("auction_timeouts_ms.default", 0)
 	v.SetDefault("auction_timeouts_ms.max", 0)
+	v.SetDefault("auction_timeouts_ms.adaptive.enabled", false)
+	v.SetDefault("auction_timeouts_ms.adaptive.samples", 200)
+	v.SetDefault("auction_timeouts_ms.adaptive.min_samples", 50)
+	v.SetDefault("auction_timeouts_ms.adaptive.percentile", 95)
+	v.SetDefault("auction_timeouts_ms.adaptive.headroom_percent", 20)
+	v.SetDefault("auction_timeouts_ms.adaptive.min_budget_ms", 50)
+	v.SetDefault("auction_timeouts_ms.adaptive.network_overhead_ms", 20)
 	v.SetDefault("cache.scheme", "")
 	v.SetDefault("cache.host", "")
 	v.SetDefault("cache.query", "")
@@ -761,6 +925,8 @@ func SetupViper(v *viper.Viper, filename string) {
 	v.SetDefault("metrics.prometheus.namespace", "")
 	v.SetDefault("metrics.prometheus.subsystem", "")
 	v.SetDefault("metrics.prometheus.timeout_ms", 10000)
+	v.SetDefault("metrics.opentelemetry.endpoint", "")
+	v.SetDefault("metrics.opentelemetry.collect_period_seconds", 10)
 	v.SetDefault("monitoring.opentelemetry.enabled", true)
 	v.SetDefault("monitoring.opentelemetry.sample_rate", 0.0)
 	v.SetDefault("monitoring.opentelemetry.endpoint", "otelcol-gateway.observability-system:4139")
@@ -996,6 +1162,15 @@ func SetupViper(v *viper.Viper, filename string) {
 	v.SetDefault("analytics.pubstack.buffers.size", "2MB")
 	v.SetDefault("analytics.pubstack.buffers.count", 100)
 	v.SetDefault("analytics.pubstack.buffers.timeout", "900s")
+	v.SetDefault("analytics.bidder_calls.enabled", false)
+	v.SetDefault("analytics.bidder_calls.endpoint", "")
+	v.SetDefault("analytics.bidder_calls.file.filename", "")
+	v.SetDefault("analytics.bidder_calls.file.max_size", "100MB")
+	v.SetDefault("analytics.bidder_calls.file.rotate_interval", "1h")
+	v.SetDefault("analytics.bidder_calls.buffers.queue_size", 10000)
+	v.SetDefault("analytics.bidder_calls.buffers.size", "1MB")
+	v.SetDefault("analytics.bidder_calls.buffers.count", 1000)
+	v.SetDefault("analytics.bidder_calls.buffers.timeout", "10s")
 	v.SetDefault("amp_timeout_adjustment_ms", 0)
 	// v.BindEnv("gdpr.default_value")
 	v.SetDefault("gdpr.default_value", 0)
@@ -1016,10 +1191,15 @@ func SetupViper(v *viper.Viper, filename string) {
 		"LIE", "LTU", "LUX", "MLT", "MTQ", "MYT", "NLD", "NOR", "POL", "PRT", "REU", "ROU", "BLM", "MAF", "SPM",
 		"SVK", "SVN", "ESP", "SWE", "GBR"})
 	v.SetDefault("ccpa.enforce", false)
+	v.SetDefault("gpp.enforce", false)
 	v.SetDefault("lmt.enforce", true)
 	v.SetDefault("currency_converter.fetch_url", "https://cdn.jsdelivr.net/gh/prebid/currency-file@1/latest.json")
 	v.SetDefault("currency_converter.fetch_interval_seconds", 1800) // fetch currency rates every 30 minutes
 	v.SetDefault("currency_converter.stale_rates_seconds", 0)
+	v.SetDefault("skan_id_list.fetch_interval_seconds", 3600) // refresh bidder SKAN ID Lists every hour
+	v.SetDefault("skan_id_list.fetch_timeout_ms", 5000)
+	v.SetDefault("skan_id_list.retry_interval_seconds", 300) // retry a failed fetch after 5 minutes
+	v.SetDefault("skan_id_list.persist_dir", "")
 	v.SetDefault("default_request.type", "")
 	v.SetDefault("default_request.file.name", "")
 	v.SetDefault("default_request.alias_info", false)
@@ -1110,6 +1290,20 @@ func setBidderDefaults(v *viper.Viper, bidder string) {
 	v.SetDefault(adapterCfgPrefix+bidder+".xapi.endpoint_apac", "")
 	v.SetDefault(adapterCfgPrefix+bidder+".xapi.endpoint_jp", "")
 	v.SetDefault(adapterCfgPrefix+bidder+".xapi.endpoint_sg", "")
+	v.SetDefault(adapterCfgPrefix+bidder+".skadnetwork.ids", []string{})
+	v.SetDefault(adapterCfgPrefix+bidder+".skadnetwork.list_url", "")
+	v.SetDefault(adapterCfgPrefix+bidder+".skadnetwork.default_id", "")
+	v.SetDefault(adapterCfgPrefix+bidder+".circuit_breaker.enabled", false)
+	v.SetDefault(adapterCfgPrefix+bidder+".circuit_breaker.window_seconds", 10)
+	v.SetDefault(adapterCfgPrefix+bidder+".circuit_breaker.min_requests", 20)
+	v.SetDefault(adapterCfgPrefix+bidder+".circuit_breaker.error_rate", 0.5)
+	v.SetDefault(adapterCfgPrefix+bidder+".circuit_breaker.timeout_rate", 0.5)
+	v.SetDefault(adapterCfgPrefix+bidder+".circuit_breaker.open_seconds", 30)
+	v.SetDefault(adapterCfgPrefix+bidder+".circuit_breaker.half_open_probes", 3)
+	v.SetDefault(adapterCfgPrefix+bidder+".hedge.enabled", false)
+	v.SetDefault(adapterCfgPrefix+bidder+".hedge.delay_ms", 100)
+	v.SetDefault(adapterCfgPrefix+bidder+".seat.of", "")
+	v.SetDefault(adapterCfgPrefix+bidder+".seat.mraid", false)
 	v.SetDefault(adapterCfgPrefix+bidder+".disabled", false)
 	v.SetDefault(adapterCfgPrefix+bidder+".partner_id", "")
 	v.SetDefault(adapterCfgPrefix+bidder+".extra_info", "")
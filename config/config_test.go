@@ -149,6 +149,8 @@ gdpr:
   non_standard_publishers: ["siteID","fake-site-id","appID","agltb3B1Yi1pbmNyDAsSA0FwcBiJkfIUDA"]
 ccpa:
   enforce: true
+gpp:
+  enforce: true
 lmt:
   enforce: true
 host_cookie:
@@ -165,6 +167,9 @@ admin_port: 5678
 auction_timeouts_ms:
   max: 123
   default: 50
+  adaptive:
+    enabled: true
+    percentile: 99
 cache:
   scheme: http
   host: prebidcache.net
@@ -334,6 +339,9 @@ func TestFullConfig(t *testing.T) {
 	cmpInts(t, "admin_port", cfg.AdminPort, 5678)
 	cmpInts(t, "auction_timeouts_ms.default", int(cfg.AuctionTimeouts.Default), 50)
 	cmpInts(t, "auction_timeouts_ms.max", int(cfg.AuctionTimeouts.Max), 123)
+	cmpBools(t, "auction_timeouts_ms.adaptive.enabled", cfg.AuctionTimeouts.Adaptive.Enabled, true)
+	cmpInts(t, "auction_timeouts_ms.adaptive.samples", cfg.AuctionTimeouts.Adaptive.Samples, 200)
+	assert.Equal(t, 99.0, cfg.AuctionTimeouts.Adaptive.Percentile, "auction_timeouts_ms.adaptive.percentile")
 	cmpStrings(t, "cache.scheme", cfg.CacheURL.Scheme, "http")
 	cmpStrings(t, "cache.host", cfg.CacheURL.Host, "prebidcache.net")
 	cmpStrings(t, "cache.query", cfg.CacheURL.Query, "uuid=%PBS_CACHE_UUID%")
@@ -367,6 +375,7 @@ func TestFullConfig(t *testing.T) {
 	cmpBools(t, "cfg.GDPR.NonStandardPublisherMap", found, false)
 
 	cmpBools(t, "ccpa.enforce", cfg.CCPA.Enforce, true)
+	cmpBools(t, "gpp.enforce", cfg.GPP.Enforce, true)
 	cmpBools(t, "lmt.enforce", cfg.LMT.Enforce, true)
 
 	//Assert the NonStandardPublishers was correctly unmarshalled
@@ -514,6 +523,17 @@ func TestMigrateConfigFromEnv(t *testing.T) {
 	cmpBools(t, "stored_requests.filesystem.enabled", true, cfg.StoredRequests.Files.Enabled)
 }
 
+func TestAdapterSKAdNetworkIDsFromEnv(t *testing.T) {
+	if oldval, ok := os.LookupEnv("PBS_ADAPTERS_LIFTOFF_SKADNETWORK_IDS"); ok {
+		defer os.Setenv("PBS_ADAPTERS_LIFTOFF_SKADNETWORK_IDS", oldval)
+	} else {
+		defer os.Unsetenv("PBS_ADAPTERS_LIFTOFF_SKADNETWORK_IDS")
+	}
+	os.Setenv("PBS_ADAPTERS_LIFTOFF_SKADNETWORK_IDS", "abc.skadnetwork def.skadnetwork")
+	cfg, _ := newDefaultConfig(t)
+	assert.Equal(t, []string{"abc.skadnetwork", "def.skadnetwork"}, cfg.Adapters["liftoff"].SKAdNetwork.IDs)
+}
+
 func TestMigrateConfigPurposeOneTreatment(t *testing.T) {
 	oldPurposeOneTreatmentConfig := []byte(`
       gdpr:
@@ -607,6 +627,165 @@ func TestInvalidAdapterUserSyncURLConfig(t *testing.T) {
 	assert.Error(t, err, "invalid user_sync URL in config should return an error")
 }
 
+var adapterRegionsConfig = []byte(`
+region: eu
+adapters:
+  liftoff:
+    endpoint: https://liftoff.com/bid
+    xapi:
+      endpoint_us_east: https://useast.liftoff.com/bid
+      endpoint_eu: https://eu.liftoff.com/bid
+    regions:
+      eu:
+        endpoint: https://eu2.liftoff.com/bid
+        countries: ["DEU", "FRA"]
+        fallback: us_east
+      jp:
+        endpoint: https://jp.liftoff.com/bid
+`)
+
+func TestAdapterRegions(t *testing.T) {
+	v := viper.New()
+	SetupViper(v, "")
+	v.Set("gdpr.default_value", "0")
+	v.SetConfigType("yaml")
+	v.ReadConfig(bytes.NewBuffer(adapterRegionsConfig))
+	cfg, err := New(v)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	liftoff := cfg.Adapters["liftoff"]
+	assert.Equal(t, "eu", liftoff.DeploymentRegion)
+	assert.Equal(t, map[string]AdapterRegion{
+		"us_east": {Endpoint: "https://useast.liftoff.com/bid"},
+		"eu":      {Endpoint: "https://eu2.liftoff.com/bid", Countries: []string{"DEU", "FRA"}, Fallback: "us_east"},
+		"jp":      {Endpoint: "https://jp.liftoff.com/bid"},
+	}, liftoff.RegionEndpoints())
+}
+
+func TestInvalidAdapterRegions(t *testing.T) {
+	adapter := Adapter{
+		Regions: map[string]AdapterRegion{
+			"eu":   {Endpoint: "https://eu.liftoff.com/bid", Fallback: "sg"},
+			"jp":   {Endpoint: "https://jp.liftoff.com/bid", Fallback: "JP"},
+			"us":   {Endpoint: "https://us.liftoff.com/bid", Fallback: "EU"},
+			"apac": {Endpoint: "apac.liftoff.com/bid"},
+			"ca":   {Endpoint: "https://ca.liftoff.com/bid", Fallback: "us_east"},
+		},
+		XAPI: AdapterXAPI{EndpointUSEast: "https://useast.liftoff.com/bid"},
+	}
+
+	errs := validateAdapterRegions(adapter, "liftoff", nil)
+	assert.ElementsMatch(t, []error{
+		errors.New("The fallback region: sg for liftoff region eu is not a configured region"),
+		errors.New("The fallback region: JP for liftoff region jp is not a configured region"),
+		errors.New("The endpoint: apac.liftoff.com/bid for liftoff region apac is not a valid URL"),
+	}, errs)
+}
+
+func TestBidderCallLogsWithoutSink(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.Analytics.BidderCalls.Enabled = true
+	assertOneError(t, cfg.validate(v), "analytics.bidder_calls requires either an endpoint or a file.filename")
+
+	cfg.Analytics.BidderCalls.Endpoint = "calls.example.com"
+	assertOneError(t, cfg.validate(v), "analytics.bidder_calls.endpoint calls.example.com is not a valid URL")
+
+	cfg.Analytics.BidderCalls.Endpoint = "https://calls.example.com"
+	assert.Empty(t, cfg.validate(v))
+}
+
+func TestInvalidAdapterCircuitBreaker(t *testing.T) {
+	errs := validateAdapterCircuitBreaker(AdapterCircuitBreaker{
+		Enabled:        true,
+		WindowSeconds:  10,
+		MinRequests:    0,
+		ErrorRate:      1.5,
+		OpenSeconds:    30,
+		HalfOpenProbes: 3,
+	}, "appnexus", nil)
+	assert.ElementsMatch(t, []error{
+		errors.New("adapters.appnexus.circuit_breaker.min_requests must be > 0. Got 0"),
+		errors.New("adapters.appnexus.circuit_breaker.error_rate must be in the range [0, 1]. Got 1.5"),
+	}, errs)
+
+	errs = validateAdapterCircuitBreaker(AdapterCircuitBreaker{Enabled: true, WindowSeconds: 10, MinRequests: 20, OpenSeconds: 30, HalfOpenProbes: 3}, "appnexus", nil)
+	assert.Equal(t, []error{errors.New("adapters.appnexus.circuit_breaker requires an error_rate or a timeout_rate")}, errs)
+
+	assert.Empty(t, validateAdapterCircuitBreaker(AdapterCircuitBreaker{ErrorRate: 2}, "appnexus", nil), "disabled")
+}
+
+func TestInvalidAdaptiveTimeouts(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.AuctionTimeouts.Adaptive = AdaptiveTimeouts{
+		Enabled:    true,
+		Samples:    100,
+		MinSamples: 200,
+		Percentile: 95,
+	}
+	assertOneError(t, cfg.validate(v), "auction_timeouts_ms.adaptive.min_samples must be in the range [1, samples]. Got 200")
+
+	cfg.AuctionTimeouts.Adaptive.MinSamples = 50
+	cfg.AuctionTimeouts.Adaptive.Percentile = 0
+	assertOneError(t, cfg.validate(v), "auction_timeouts_ms.adaptive.percentile must be in the range (0, 100]. Got 0")
+
+	cfg.AuctionTimeouts.Adaptive.Percentile = 95
+	cfg.AuctionTimeouts.Adaptive.NetworkOverheadMs = -1
+	assertOneError(t, cfg.validate(v), "auction_timeouts_ms.adaptive.network_overhead_ms must be >= 0. Got -1")
+
+	cfg.AuctionTimeouts.Adaptive.NetworkOverheadMs = 20
+	assert.Empty(t, cfg.validate(v))
+}
+
+func TestInvalidAdapterHedge(t *testing.T) {
+	errs := validateAdapterHedge(AdapterHedge{Enabled: true}, "liftoff", nil)
+	assert.Equal(t, []error{errors.New("adapters.liftoff.hedge.delay_ms must be > 0. Got 0")}, errs)
+
+	assert.Empty(t, validateAdapterHedge(AdapterHedge{Enabled: true, DelayMs: 80}, "liftoff", nil))
+	assert.Empty(t, validateAdapterHedge(AdapterHedge{DelayMs: -1}, "liftoff", nil), "disabled")
+}
+
+func TestAdapterSeat(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+
+	adapters := map[string]Adapter{
+		"rubicon":      {},
+		"rubiconmraid": {Seat: AdapterSeat{Of: "rubicon", MRAID: true}},
+		"rubiconaudio": {Seat: AdapterSeat{Of: "rubiconmraid", MediaTypes: []openrtb_ext.BidType{"audio", "popup"}}},
+		"configseat":   {Seat: AdapterSeat{Of: "rubicon"}},
+		"notcore":      {},
+		"other":        {Seat: AdapterSeat{Of: "unknown"}},
+		"othernotcore": {Seat: AdapterSeat{Of: "notcore"}},
+		"skanseat":     {Seat: AdapterSeat{Of: "rubicon"}, SKAdNetwork: AdapterSKAdNetwork{IDs: []string{"abc.skadnetwork"}}},
+	}
+	var errs []error
+	for name, adapter := range adapters {
+		errs = validateAdapterSeat(adapter.Seat, adapters, name, errs)
+	}
+	assert.ElementsMatch(t, []error{
+		errors.New("adapters.rubiconaudio.seat.of rubiconmraid must be a configured core bidder which is not a seat"),
+		errors.New("adapters.rubiconaudio.seat.media_types: invalid BidType: popup"),
+		errors.New("adapters.other.seat.of unknown must be a configured core bidder which is not a seat"),
+		errors.New("adapters.othernotcore.seat.of notcore must be a configured core bidder which is not a seat"),
+		errors.New("adapters.skanseat.skadnetwork cannot be set on a seat, it uses the SKAN ID List of rubicon"),
+	}, errs)
+	assert.Empty(t, cfg.validate(v))
+}
+
+func TestAdapterSeats(t *testing.T) {
+	seats := AdapterSeats(map[string]Adapter{
+		"rubicon":      {},
+		"rubiconmraid": {Seat: AdapterSeat{Of: "rubicon", MRAID: true}},
+		"configseat":   {Seat: AdapterSeat{Of: "Rubicon"}},
+		"otherseat":    {Seat: AdapterSeat{Of: "unknown"}},
+	})
+	assert.Equal(t, map[openrtb_ext.BidderName]openrtb_ext.BidderName{"configseat": openrtb_ext.BidderRubicon}, seats)
+
+	_, ok := openrtb_ext.NormalizeBidderName("configseat")
+	assert.False(t, ok, "the seat is not registered as a bidder name")
+}
+
 func TestNegativeRequestSize(t *testing.T) {
 	cfg, v := newDefaultConfig(t)
 	cfg.MaxRequestSize = -1
@@ -620,6 +799,13 @@ func TestNegativePrometheusTimeout(t *testing.T) {
 	assertOneError(t, cfg.validate(v), "metrics.prometheus.timeout_ms must be positive if metrics.prometheus.port is defined. Got timeout=0 and port=8001")
 }
 
+func TestNegativeOpenTelemetryCollectPeriod(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.Metrics.OpenTelemetry.Endpoint = "localhost:4317"
+	cfg.Metrics.OpenTelemetry.CollectPeriodSeconds = 0
+	assertOneError(t, cfg.validate(v), "metrics.opentelemetry.collect_period_seconds must be positive if metrics.opentelemetry.endpoint is defined. Got 0")
+}
+
 func TestInvalidHostVendorID(t *testing.T) {
 	tests := []struct {
 		description  string
@@ -660,6 +846,12 @@ func TestInvalidGDPRDefaultValue(t *testing.T) {
 	assertOneError(t, cfg.validate(v), "gdpr.default_value must be 0 or 1")
 }
 
+func TestInvalidAccountDefaultsAuction(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.AccountDefaults.Auction.PriceGranularity = "fine"
+	assertOneError(t, cfg.validate(v), `account_defaults.auction.price_granularity "fine" is not one of low, med, medium, high, auto or dense`)
+}
+
 func TestMissingGDPRDefaultValue(t *testing.T) {
 	v := viper.New()
 
@@ -0,0 +1,96 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/biddercontrol"
+)
+
+type bidderControls interface {
+	AddRule(rule biddercontrol.Rule) (biddercontrol.Rule, error)
+	RemoveRule(id string) bool
+	Rules() []biddercontrol.Rule
+	Statuses() []biddercontrol.Status
+}
+
+// bidderRuleRequest is the body of the requests adding a rule, which expires after TTLSeconds
+type bidderRuleRequest struct {
+	Bidder     string `json:"bidder"`
+	Percent    int    `json:"percent"`
+	Account    string `json:"account"`
+	Platform   string `json:"platform"`
+	TTLSeconds int    `json:"ttl_seconds"`
+}
+
+// NewBidderStatusEndpoint returns the live status of the bidders: the rules throttling them and their
+// recent request, error and timeout counts.
+func NewBidderStatusEndpoint(controls bidderControls) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, "/bidders/status", http.StatusOK, controls.Statuses())
+	}
+}
+
+// NewBidderRulesEndpoint lists (GET), adds (POST) and removes (DELETE with the id query parameter) the
+// rules disabling or throttling bidders without a restart.
+func NewBidderRulesEndpoint(controls bidderControls) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, "/bidders/rules", http.StatusOK, controls.Rules())
+		case http.MethodPost:
+			var ruleRequest bidderRuleRequest
+			if err := json.NewDecoder(r.Body).Decode(&ruleRequest); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(w, "Invalid rule: %v", err)
+				return
+			}
+			if ruleRequest.TTLSeconds <= 0 {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(w, "Invalid rule: ttl_seconds must be positive. Got %d", ruleRequest.TTLSeconds)
+				return
+			}
+			rule, err := controls.AddRule(biddercontrol.Rule{
+				Bidder:   ruleRequest.Bidder,
+				Percent:  ruleRequest.Percent,
+				Account:  ruleRequest.Account,
+				Platform: ruleRequest.Platform,
+				Expires:  time.Now().Add(time.Duration(ruleRequest.TTLSeconds) * time.Second),
+			})
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(w, "Invalid rule: %v", err)
+				return
+			}
+			glog.Infof("Bidder rule %s added: %d%% of the %s traffic (account %q, platform %q) is dropped until %v", rule.ID, rule.Percent, rule.Bidder, rule.Account, rule.Platform, rule.Expires)
+			writeJSON(w, "/bidders/rules", http.StatusCreated, rule)
+		case http.MethodDelete:
+			id := r.URL.Query().Get("id")
+			if !controls.RemoveRule(id) {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprintf(w, "No bidder rule with id %q", id)
+				return
+			}
+			glog.Infof("Bidder rule %s removed", id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, endpoint string, status int, value interface{}) {
+	jsonOutput, err := json.Marshal(value)
+	if err != nil {
+		glog.Errorf("%s Critical error when trying to marshal the response: %v", endpoint, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(jsonOutput)
+}
@@ -0,0 +1,108 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prebid/prebid-server/biddercontrol"
+	"github.com/prebid/prebid-server/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBidderRulesEndpoint(t *testing.T) {
+	controls := biddercontrol.NewControls(config.BidderInfos{"appnexus": config.BidderInfo{Enabled: true}})
+	endpoint := NewBidderRulesEndpoint(controls)
+
+	// add
+	recorder := httptest.NewRecorder()
+	endpoint(recorder, httptest.NewRequest("POST", "/bidders/rules", strings.NewReader(`{"bidder":"appnexus","percent":50,"platform":"ios","ttl_seconds":600}`)))
+	assert.Equal(t, http.StatusCreated, recorder.Code, "add")
+	var rule biddercontrol.Rule
+	if assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rule), "add") {
+		assert.Equal(t, "appnexus", rule.Bidder, "add")
+		assert.Equal(t, 50, rule.Percent, "add")
+		assert.Equal(t, "ios", rule.Platform, "add")
+	}
+
+	// list
+	recorder = httptest.NewRecorder()
+	endpoint(recorder, httptest.NewRequest("GET", "/bidders/rules", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code, "list")
+	var rules []biddercontrol.Rule
+	if assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rules), "list") {
+		assert.Len(t, rules, 1, "list")
+	}
+
+	// remove
+	recorder = httptest.NewRecorder()
+	endpoint(recorder, httptest.NewRequest("DELETE", "/bidders/rules?id="+rule.ID, nil))
+	assert.Equal(t, http.StatusNoContent, recorder.Code, "remove")
+	assert.Empty(t, controls.Rules(), "remove")
+
+	recorder = httptest.NewRecorder()
+	endpoint(recorder, httptest.NewRequest("DELETE", "/bidders/rules?id="+rule.ID, nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code, "remove twice")
+}
+
+func TestBidderRulesEndpointInvalid(t *testing.T) {
+	controls := biddercontrol.NewControls(config.BidderInfos{"appnexus": config.BidderInfo{Enabled: true}})
+	endpoint := NewBidderRulesEndpoint(controls)
+
+	testCases := []struct {
+		description  string
+		method       string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			description:  "Malformed",
+			method:       "POST",
+			body:         `{`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid rule: unexpected EOF",
+		},
+		{
+			description:  "No TTL",
+			method:       "POST",
+			body:         `{"bidder":"appnexus","percent":100}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid rule: ttl_seconds must be positive. Got 0",
+		},
+		{
+			description:  "Unknown Bidder",
+			method:       "POST",
+			body:         `{"bidder":"unknown","percent":100,"ttl_seconds":60}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: `Invalid rule: unknown bidder "unknown"`,
+		},
+		{
+			description:  "Unsupported Method",
+			method:       "PUT",
+			expectedCode: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, test := range testCases {
+		recorder := httptest.NewRecorder()
+		endpoint(recorder, httptest.NewRequest(test.method, "/bidders/rules", strings.NewReader(test.body)))
+		assert.Equal(t, test.expectedCode, recorder.Code, test.description)
+		assert.Equal(t, test.expectedBody, recorder.Body.String(), test.description)
+	}
+	assert.Empty(t, controls.Rules())
+}
+
+func TestBidderStatusEndpoint(t *testing.T) {
+	controls := biddercontrol.NewControls(config.BidderInfos{"appnexus": config.BidderInfo{Enabled: true}})
+	controls.Record("appnexus", biddercontrol.Failed)
+
+	recorder := httptest.NewRecorder()
+	NewBidderStatusEndpoint(controls)(recorder, httptest.NewRequest("GET", "/bidders/status", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.JSONEq(t, `[{"bidder":"appnexus","enabled":true,"rules":[],"recent":{"requests":1,"errors":1,"timeouts":0,"throttled":0,"error_rate":1,"timeout_rate":0}}]`, recorder.Body.String())
+}
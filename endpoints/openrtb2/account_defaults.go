@@ -0,0 +1,218 @@
+package openrtb2
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/buger/jsonparser"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+// applyAccountDefaults merges the bidder controls and the auction defaults of the account into the request.
+// The request values take precedence, except for the bidders the account does not permit, which are removed,
+// and the blocked advertisers, categories and apps, which add up. Only warnings are returned, an error in the
+// account configuration leaves the request as it is and drops the deals of the account, which the exchange
+// does not validate again.
+func applyAccountDefaults(req *openrtb2.BidRequest, account *config.Account) []error {
+	var aliases map[string]string
+	if len(req.Ext) > 0 {
+		var requestExt openrtb_ext.ExtRequest
+		if err := json.Unmarshal(req.Ext, &requestExt); err == nil {
+			aliases = requestExt.Prebid.Aliases
+		}
+	}
+
+	warnings := removeBlockedBidders(req, &account.Bidders, aliases, account.ID)
+
+	if errs := account.Auction.Validate(); len(errs) > 0 {
+		for _, err := range errs {
+			warnings = append(warnings, &errortypes.Warning{
+				Message: fmt.Sprintf("Account %s defaults are ignored, account.%v", account.ID, err),
+			})
+		}
+		account.Auction.Deals = nil
+		return warnings
+	}
+
+	if req.TMax == 0 {
+		req.TMax = account.Auction.TMax
+	}
+	req.BAdv = appendMissing(req.BAdv, account.Auction.BlockedAdvertisers)
+	req.BCat = appendMissing(req.BCat, account.Auction.BlockedCategories)
+	req.BApp = appendMissing(req.BApp, account.Auction.BlockedApps)
+
+	if err := mergeAccountPrebidExt(req, &account.Auction); err != nil {
+		warnings = append(warnings, &errortypes.Warning{
+			Message: fmt.Sprintf("Account %s defaults are ignored for request.ext.prebid. (%v)", account.ID, err),
+		})
+	}
+	return warnings
+}
+
+// removeBlockedBidders removes from request.imp[].ext the bidders, or aliases of bidders, the account does
+// not permit.
+func removeBlockedBidders(req *openrtb2.BidRequest, bidders *config.AccountBidders, aliases map[string]string, accountID string) []error {
+	if len(bidders.Allowed) == 0 && len(bidders.Blocked) == 0 {
+		return nil
+	}
+
+	var warnings []error
+	var removed []string
+	for i := range req.Imp {
+		imp := &req.Imp[i]
+		var impExt map[string]json.RawMessage
+		if err := json.Unmarshal(imp.Ext, &impExt); err != nil {
+			continue
+		}
+		var prebidBidders map[string]json.RawMessage
+		if prebidExt, ok := impExt[openrtb_ext.PrebidExtKey]; ok {
+			var extPrebid openrtb_ext.ExtImpPrebid
+			if err := json.Unmarshal(prebidExt, &extPrebid); err == nil {
+				prebidBidders = extPrebid.Bidder
+			}
+		}
+
+		for bidder := range impExt {
+			if !isBidderToValidate(bidder) || bidderPermitted(bidders, bidder, aliases) {
+				continue
+			}
+			imp.Ext = jsonparser.Delete(imp.Ext, bidder)
+			removed = appendMissing(removed, []string{bidder})
+		}
+		for bidder := range prebidBidders {
+			if bidderPermitted(bidders, bidder, aliases) {
+				continue
+			}
+			imp.Ext = jsonparser.Delete(imp.Ext, openrtb_ext.PrebidExtKey, openrtb_ext.PrebidExtBidderKey, bidder)
+			removed = appendMissing(removed, []string{bidder})
+		}
+	}
+
+	sort.Strings(removed)
+	for _, bidder := range removed {
+		warnings = append(warnings, &errortypes.Warning{
+			Message:     fmt.Sprintf("Account %s does not permit bidder %s, it was removed from the request", accountID, bidder),
+			WarningCode: errortypes.BidderBlockedByAccountWarningCode,
+		})
+	}
+	return warnings
+}
+
+// bidderPermitted checks the bidder and, for an alias, the core bidder it stands for. Either one being
+// blocked blocks the alias, either one being allowed allows it.
+func bidderPermitted(bidders *config.AccountBidders, bidder string, aliases map[string]string) bool {
+	coreBidder, isAlias := aliases[bidder]
+	if !isAlias {
+		return bidders.Permits(bidder)
+	}
+	if containsString(bidders.Blocked, bidder) || containsString(bidders.Blocked, coreBidder) {
+		return false
+	}
+	return len(bidders.Allowed) == 0 || containsString(bidders.Allowed, bidder) || containsString(bidders.Allowed, coreBidder)
+}
+
+// mergeAccountPrebidExt sets the bid adjustment factors of the bidders without one, the price granularity
+// of the targeting without one and the floors, if the request has none.
+func mergeAccountPrebidExt(req *openrtb2.BidRequest, auction *config.AccountAuction) error {
+	if len(auction.BidAdjustmentFactors) == 0 && auction.PriceGranularity == "" && auction.Floors == nil {
+		return nil
+	}
+
+	requestExt := make(map[string]json.RawMessage)
+	if len(req.Ext) > 0 {
+		if err := json.Unmarshal(req.Ext, &requestExt); err != nil {
+			return err
+		}
+	}
+	prebidExt := make(map[string]json.RawMessage)
+	if prebidJSON, ok := requestExt[openrtb_ext.PrebidExtKey]; ok {
+		if err := json.Unmarshal(prebidJSON, &prebidExt); err != nil {
+			return err
+		}
+	}
+
+	changed := false
+	if len(auction.BidAdjustmentFactors) > 0 {
+		factors := make(map[string]float64, len(auction.BidAdjustmentFactors))
+		if factorsJSON, ok := prebidExt["bidadjustmentfactors"]; ok {
+			if err := json.Unmarshal(factorsJSON, &factors); err != nil {
+				return err
+			}
+		}
+		for bidder, factor := range auction.BidAdjustmentFactors {
+			if _, ok := factors[bidder]; !ok {
+				factors[bidder] = factor
+				changed = true
+			}
+		}
+		if changed {
+			factorsJSON, err := json.Marshal(factors)
+			if err != nil {
+				return err
+			}
+			prebidExt["bidadjustmentfactors"] = factorsJSON
+		}
+	}
+
+	// the price granularity only matters to requests asking for targeting
+	if targetingJSON, ok := prebidExt["targeting"]; ok && auction.PriceGranularity != "" {
+		if _, _, _, err := jsonparser.Get(targetingJSON, "pricegranularity"); err == jsonparser.KeyPathNotFoundError {
+			granularityJSON, err := json.Marshal(openrtb_ext.PriceGranularityFromString(auction.PriceGranularity))
+			if err != nil {
+				return err
+			}
+			if targetingJSON, err = jsonparser.Set(targetingJSON, granularityJSON, "pricegranularity"); err != nil {
+				return err
+			}
+			prebidExt["targeting"] = targetingJSON
+			changed = true
+		}
+	}
+
+	if _, ok := prebidExt["floors"]; !ok && auction.Floors != nil {
+		floorsJSON, err := json.Marshal(auction.Floors)
+		if err != nil {
+			return err
+		}
+		prebidExt["floors"] = floorsJSON
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	prebidJSON, err := json.Marshal(prebidExt)
+	if err != nil {
+		return err
+	}
+	requestExt[openrtb_ext.PrebidExtKey] = prebidJSON
+	extJSON, err := json.Marshal(requestExt)
+	if err != nil {
+		return err
+	}
+	req.Ext = extJSON
+	return nil
+}
+
+// appendMissing appends the values not already in the list
+func appendMissing(list []string, values []string) []string {
+	for _, value := range values {
+		if !containsString(list, value) {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+func containsString(list []string, value string) bool {
+	for _, existing := range list {
+		if existing == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+package openrtb2
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyAccountDefaults(t *testing.T) {
+	enforce := false
+
+	testCases := []struct {
+		description      string
+		account          config.Account
+		request          *openrtb2.BidRequest
+		expectedRequest  *openrtb2.BidRequest
+		expectedWarnings []string
+	}{
+		{
+			description: "No Defaults",
+			account:     config.Account{ID: "1"},
+			request: &openrtb2.BidRequest{
+				Imp: []openrtb2.Imp{{ID: "1", Ext: json.RawMessage(`{"appnexus":{"placementId":1}}`)}},
+				Ext: json.RawMessage(`{"prebid":{"targeting":{}}}`),
+			},
+			expectedRequest: &openrtb2.BidRequest{
+				Imp: []openrtb2.Imp{{ID: "1", Ext: json.RawMessage(`{"appnexus":{"placementId":1}}`)}},
+				Ext: json.RawMessage(`{"prebid":{"targeting":{}}}`),
+			},
+		},
+		{
+			description: "Blocked Bidders Removed",
+			account:     config.Account{ID: "1", Bidders: config.AccountBidders{Blocked: []string{"rubicon", "appnexus"}}},
+			request: &openrtb2.BidRequest{
+				Imp: []openrtb2.Imp{
+					{ID: "1", Ext: json.RawMessage(`{"appnexus":{"placementId":1},"rubicon":{"accountId":1},"openx":{"unit":"1"}}`)},
+					{ID: "2", Ext: json.RawMessage(`{"prebid":{"bidder":{"appnexus":{"placementId":1},"openx":{"unit":"1"}}}}`)},
+				},
+			},
+			expectedRequest: &openrtb2.BidRequest{
+				Imp: []openrtb2.Imp{
+					{ID: "1", Ext: json.RawMessage(`{"openx":{"unit":"1"}}`)},
+					{ID: "2", Ext: json.RawMessage(`{"prebid":{"bidder":{"openx":{"unit":"1"}}}}`)},
+				},
+			},
+			expectedWarnings: []string{
+				"Account 1 does not permit bidder appnexus, it was removed from the request",
+				"Account 1 does not permit bidder rubicon, it was removed from the request",
+			},
+		},
+		{
+			description: "Bidders Not Allowed Removed",
+			account:     config.Account{ID: "1", Bidders: config.AccountBidders{Allowed: []string{"appnexus"}}},
+			request: &openrtb2.BidRequest{
+				Imp: []openrtb2.Imp{{ID: "1", Ext: json.RawMessage(`{"appnexus":{"placementId":1},"openx":{"unit":"1"},"context":{"data":{}}}`)}},
+			},
+			expectedRequest: &openrtb2.BidRequest{
+				Imp: []openrtb2.Imp{{ID: "1", Ext: json.RawMessage(`{"appnexus":{"placementId":1},"context":{"data":{}}}`)}},
+			},
+			expectedWarnings: []string{"Account 1 does not permit bidder openx, it was removed from the request"},
+		},
+		{
+			description: "Aliases Follow Their Core Bidder",
+			account:     config.Account{ID: "1", Bidders: config.AccountBidders{Allowed: []string{"appnexus", "openx"}, Blocked: []string{"openx"}}},
+			request: &openrtb2.BidRequest{
+				Imp: []openrtb2.Imp{{ID: "1", Ext: json.RawMessage(`{"anx":{"placementId":1},"ox":{"unit":"1"}}`)}},
+				Ext: json.RawMessage(`{"prebid":{"aliases":{"anx":"appnexus","ox":"openx"}}}`),
+			},
+			expectedRequest: &openrtb2.BidRequest{
+				Imp: []openrtb2.Imp{{ID: "1", Ext: json.RawMessage(`{"anx":{"placementId":1}}`)}},
+				Ext: json.RawMessage(`{"prebid":{"aliases":{"anx":"appnexus","ox":"openx"}}}`),
+			},
+			expectedWarnings: []string{"Account 1 does not permit bidder ox, it was removed from the request"},
+		},
+		{
+			description: "Auction Defaults Applied",
+			account: config.Account{ID: "1", Auction: config.AccountAuction{
+				TMax:                 300,
+				BidAdjustmentFactors: map[string]float64{"appnexus": 0.9},
+				PriceGranularity:     "low",
+				Floors:               &openrtb_ext.PriceFloorRules{Default: 0.5, Enforcement: &openrtb_ext.PriceFloorEnforcement{EnforcePBS: &enforce}},
+				BlockedAdvertisers:   []string{"example.com"},
+				BlockedCategories:    []string{"IAB25"},
+				BlockedApps:          []string{"com.example.app"},
+			}},
+			request: &openrtb2.BidRequest{
+				Ext: json.RawMessage(`{"prebid":{"targeting":{"includewinners":true}}}`),
+			},
+			expectedRequest: &openrtb2.BidRequest{
+				TMax: 300,
+				BAdv: []string{"example.com"},
+				BCat: []string{"IAB25"},
+				BApp: []string{"com.example.app"},
+				Ext:  json.RawMessage(`{"prebid":{"bidadjustmentfactors":{"appnexus":0.9},"floors":{"schema":{"fields":null},"default":0.5,"enforcement":{"enforcepbs":false}},"targeting":{"includewinners":true,"pricegranularity":{"precision":2,"ranges":[{"min":0,"max":5,"increment":0.5}]}}}}`),
+			},
+		},
+		{
+			description: "Request Values Preferred",
+			account: config.Account{ID: "1", Auction: config.AccountAuction{
+				TMax:                 300,
+				BidAdjustmentFactors: map[string]float64{"appnexus": 0.9, "rubicon": 0.8},
+				PriceGranularity:     "low",
+				Floors:               &openrtb_ext.PriceFloorRules{Default: 0.5},
+				BlockedAdvertisers:   []string{"example.com", "example.org"},
+			}},
+			request: &openrtb2.BidRequest{
+				TMax: 1000,
+				BAdv: []string{"example.org"},
+				Ext:  json.RawMessage(`{"prebid":{"bidadjustmentfactors":{"appnexus":1.1},"floors":{"default":1},"targeting":{"pricegranularity":"high"}}}`),
+			},
+			expectedRequest: &openrtb2.BidRequest{
+				TMax: 1000,
+				BAdv: []string{"example.org", "example.com"},
+				Ext:  json.RawMessage(`{"prebid":{"bidadjustmentfactors":{"appnexus":1.1,"rubicon":0.8},"floors":{"default":1},"targeting":{"pricegranularity":"high"}}}`),
+			},
+		},
+		{
+			description: "Price Granularity Ignored Without Targeting",
+			account:     config.Account{ID: "1", Auction: config.AccountAuction{PriceGranularity: "low"}},
+			request: &openrtb2.BidRequest{
+				Ext: json.RawMessage(`{"prebid":{"debug":true}}`),
+			},
+			expectedRequest: &openrtb2.BidRequest{
+				Ext: json.RawMessage(`{"prebid":{"debug":true}}`),
+			},
+		},
+		{
+			description:     "Invalid Auction Defaults Ignored",
+			account:         config.Account{ID: "1", Auction: config.AccountAuction{TMax: 300, PriceGranularity: "fine"}},
+			request:         &openrtb2.BidRequest{},
+			expectedRequest: &openrtb2.BidRequest{},
+			expectedWarnings: []string{
+				`Account 1 defaults are ignored, account.auction.price_granularity "fine" is not one of low, med, medium, high, auto or dense`,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		warnings := applyAccountDefaults(test.request, &test.account)
+
+		if assert.Len(t, warnings, len(test.expectedWarnings), test.description) {
+			for i, warning := range warnings {
+				assert.EqualError(t, warning, test.expectedWarnings[i], test.description)
+				assert.False(t, errortypes.ContainsFatalError([]error{warning}), test.description)
+			}
+		}
+		assert.Equal(t, test.expectedRequest.TMax, test.request.TMax, test.description)
+		assert.Equal(t, test.expectedRequest.BAdv, test.request.BAdv, test.description)
+		assert.Equal(t, test.expectedRequest.BCat, test.request.BCat, test.description)
+		if assert.Len(t, test.request.Imp, len(test.expectedRequest.Imp), test.description) {
+			for i := range test.request.Imp {
+				assert.JSONEq(t, string(test.expectedRequest.Imp[i].Ext), string(test.request.Imp[i].Ext), test.description)
+			}
+		}
+		if len(test.expectedRequest.Ext) > 0 {
+			assert.JSONEq(t, string(test.expectedRequest.Ext), string(test.request.Ext), test.description)
+		} else {
+			assert.Empty(t, test.request.Ext, test.description)
+		}
+	}
+}
+
+func TestApplyAccountDefaultsInvalidDeals(t *testing.T) {
+	account := config.Account{ID: "1", Auction: config.AccountAuction{Deals: []config.AccountDeal{
+		{ID: "deal", Bidders: []string{"appnexus"}},
+		{Bidders: []string{"appnexus"}},
+	}}}
+
+	warnings := applyAccountDefaults(&openrtb2.BidRequest{}, &account)
+
+	if assert.Len(t, warnings, 1) {
+		assert.EqualError(t, warnings[0], "Account 1 defaults are ignored, account.auction.deals[1].id must not be empty")
+	}
+	assert.Nil(t, account.Auction.Deals, "the exchange is not given the deals of an invalid account")
+}
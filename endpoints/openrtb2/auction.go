@@ -21,9 +21,10 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/golang/glog"
 	"github.com/julienschmidt/httprouter"
-	"github.com/mxmCherry/openrtb/v15/native1"
-	nativeRequests "github.com/mxmCherry/openrtb/v15/native1/request"
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/native1"
+	nativeRequests "github.com/prebid/openrtb/v20/native1/request"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	accountService "github.com/prebid/prebid-server/account"
 	"github.com/prebid/prebid-server/analytics"
 	"github.com/prebid/prebid-server/config"
@@ -33,6 +34,7 @@ import (
 	"github.com/prebid/prebid-server/openrtb_ext"
 	"github.com/prebid/prebid-server/prebid_cache_client"
 	"github.com/prebid/prebid-server/privacy/ccpa"
+	"github.com/prebid/prebid-server/privacy/gpp"
 	"github.com/prebid/prebid-server/privacy/lmt"
 	"github.com/prebid/prebid-server/stored_requests"
 	"github.com/prebid/prebid-server/stored_requests/backends/empty_fetcher"
@@ -151,20 +153,13 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 		}
 	}()
 
-	req, errL := deps.parseRequest(r)
+	req, storedImpDeals, errL := deps.parseRequest(r)
 
 	if errortypes.ContainsFatalError(errL) && writeError(errL, w, &labels) {
 		return
 	}
 	warnings := errortypes.WarningOnly(errL)
 
-	timeout := deps.cfg.AuctionTimeouts.LimitAuctionTimeout(time.Duration(req.TMax) * time.Millisecond)
-	if timeout > 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithDeadline(ctx, start.Add(timeout))
-		defer cancel()
-	}
-
 	usersyncs := usersync.ParsePBSCookieFromRequest(r, &(deps.cfg.HostCookie))
 	if req.App != nil {
 		labels.Source = metrics.DemandApp
@@ -188,6 +183,16 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 		return
 	}
 
+	// The account defaults may set the tmax, so the deadline is only known once they are applied
+	warnings = append(warnings, applyAccountDefaults(req, account)...)
+
+	timeout := deps.cfg.AuctionTimeouts.LimitAuctionTimeout(time.Duration(req.TMax) * time.Millisecond)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, start.Add(timeout))
+		defer cancel()
+	}
+
 	secGPC := r.Header.Get("Sec-GPC")
 
 	auctionRequest := exchange.AuctionRequest{
@@ -199,6 +204,9 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 		LegacyLabels:               labels,
 		Warnings:                   warnings,
 		GlobalPrivacyControlHeader: secGPC,
+		BidderCalls:                &ao.BidderCalls,
+		DealDeliveries:             &ao.DealDeliveries,
+		StoredImpDeals:             storedImpDeals,
 	}
 
 	response, err := deps.ex.HoldAuction(ctx, auctionRequest, nil)
@@ -241,7 +249,9 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 // possible, it will return errors with messages that suggest improvements.
 //
 // If the errors list has at least one element, then no guarantees are made about the returned request.
-func (deps *endpointDeps) parseRequest(httpRequest *http.Request) (req *openrtb2.BidRequest, errs []error) {
+//
+// The deals of the stored imps are returned by imp id, apart from the request.
+func (deps *endpointDeps) parseRequest(httpRequest *http.Request) (req *openrtb2.BidRequest, storedImpDeals map[string]json.RawMessage, errs []error) {
 	req = &openrtb2.BidRequest{}
 	errs = nil
 
@@ -268,7 +278,7 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request) (req *openrtb2
 	defer cancel()
 
 	// Fetch the Stored Request data and merge it into the HTTP request.
-	if requestJson, errs = deps.processStoredRequests(ctx, requestJson); len(errs) > 0 {
+	if requestJson, storedImpDeals, errs = deps.processStoredRequests(ctx, requestJson); len(errs) > 0 {
 		return
 	}
 
@@ -286,6 +296,7 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request) (req *openrtb2
 	}
 
 	lmt.ModifyForIOS(req)
+	lmt.ModifyForAndroid(req)
 
 	errL := deps.validateRequest(req)
 	if len(errL) > 0 {
@@ -362,6 +373,14 @@ func (deps *endpointDeps) validateRequest(req *openrtb2.BidRequest) []error {
 		if err := validateCustomRates(bidExt.Prebid.CurrencyConversions); err != nil {
 			return []error{err}
 		}
+
+		if bidExt.Prebid.MultiBid != nil {
+			warnings, err := validateMultiBid(req, bidExt)
+			if err != nil {
+				return []error{err}
+			}
+			errL = append(errL, warnings...)
+		}
 	}
 
 	if (req.Site == nil && req.App == nil) || (req.Site != nil && req.App != nil) {
@@ -390,7 +409,7 @@ func (deps *endpointDeps) validateRequest(req *openrtb2.BidRequest) []error {
 
 	if ccpaPolicy, err := ccpa.ReadFromRequest(req); err != nil {
 		return append(errL, err)
-	} else if _, err := ccpaPolicy.Parse(exchange.GetValidBidders(aliases)); err != nil {
+	} else if _, err := ccpaPolicy.Parse(exchange.GetValidBidders(aliases, config.AdapterSeats(deps.cfg.Adapters))); err != nil {
 		if _, invalidConsent := err.(*errortypes.Warning); invalidConsent {
 			errL = append(errL, &errortypes.Warning{
 				Message:     fmt.Sprintf("CCPA consent is invalid and will be ignored. (%v)", err),
@@ -404,6 +423,18 @@ func (deps *endpointDeps) validateRequest(req *openrtb2.BidRequest) []error {
 		}
 	}
 
+	if gppPolicy, err := gpp.ReadFromRequest(req); err != nil {
+		return append(errL, err)
+	} else if _, err := gppPolicy.Parse(); err != nil {
+		errL = append(errL, &errortypes.Warning{
+			Message:     fmt.Sprintf("GPP string is invalid and will be ignored. (%v)", err),
+			WarningCode: errortypes.InvalidPrivacyConsentWarningCode})
+		consentWriter := gpp.ConsentWriter{Consent: ""}
+		if err := consentWriter.Write(req); err != nil {
+			return append(errL, fmt.Errorf("Unable to remove invalid GPP string from the request. (%v)", err))
+		}
+	}
+
 	impIDs := make(map[string]int, len(req.Imp))
 	for index := range req.Imp {
 		imp := &req.Imp[index]
@@ -480,6 +511,29 @@ func validateCustomRates(bidReqCurrencyRates *openrtb_ext.ExtRequestCurrency) er
 	return nil
 }
 
+// validateMultiBid replaces request.ext.prebid.multibid with its valid entries, returning warnings
+// for the entries which were dropped or fixed.
+func validateMultiBid(req *openrtb2.BidRequest, bidExt *openrtb_ext.ExtRequest) ([]error, error) {
+	multiBids, warnings := openrtb_ext.ValidateAndBuildExtMultiBid(&bidExt.Prebid)
+	bidExt.Prebid.MultiBid = multiBids
+	if len(warnings) == 0 {
+		return nil, nil
+	}
+
+	if len(multiBids) == 0 {
+		req.Ext = jsonparser.Delete(req.Ext, "prebid", "multibid")
+		return warnings, nil
+	}
+	multiBidJSON, err := json.Marshal(multiBids)
+	if err != nil {
+		return nil, err
+	}
+	if req.Ext, err = jsonparser.Set(req.Ext, multiBidJSON, "prebid", "multibid"); err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
+
 func (deps *endpointDeps) validateEidPermissions(req *openrtb_ext.ExtRequest, aliases map[string]string) error {
 	if req == nil || req.Prebid.Data == nil {
 		return nil
@@ -620,10 +674,10 @@ func validateVideo(video *openrtb2.Video, impIndex int) error {
 
 	// The following fields were previously uints in the OpenRTB library we use, but have
 	// since been changed to ints. We decided to maintain the non-negative check.
-	if video.W < 0 {
+	if video.W != nil && *video.W < 0 {
 		return fmt.Errorf("request.imp[%d].video.w must be a positive number", impIndex)
 	}
-	if video.H < 0 {
+	if video.H != nil && *video.H < 0 {
 		return fmt.Errorf("request.imp[%d].video.h must be a positive number", impIndex)
 	}
 	if video.MinBitRate < 0 {
@@ -904,7 +958,7 @@ func validateNativeAssetData(data *nativeRequests.Data, impIndex int, assetIndex
 	return nil
 }
 
-func validateNativeVideoProtocols(protocols []native1.Protocol, impIndex int, assetIndex int) error {
+func validateNativeVideoProtocols(protocols []adcom1.MediaCreativeSubtype, impIndex int, assetIndex int) error {
 	if len(protocols) < 1 {
 		return fmt.Errorf("request.imp[%d].native.request.assets[%d].video.protocols must be an array with at least one element", impIndex, assetIndex)
 	}
@@ -916,8 +970,8 @@ func validateNativeVideoProtocols(protocols []native1.Protocol, impIndex int, as
 	return nil
 }
 
-func validateNativeVideoProtocol(protocol native1.Protocol, impIndex int, assetIndex int, protocolIndex int) error {
-	if protocol < native1.ProtocolVAST10 || protocol > native1.ProtocolDAAST10Wrapper {
+func validateNativeVideoProtocol(protocol adcom1.MediaCreativeSubtype, impIndex int, assetIndex int, protocolIndex int) error {
+	if protocol < adcom1.CreativeVAST10 || protocol > adcom1.CreativeDAAST10Wrapper {
 		return fmt.Errorf("request.imp[%d].native.request.assets[%d].video.protocols[%d] is invalid. See Section 5.8: https://www.iab.com/wp-content/uploads/2016/03/OpenRTB-API-Specification-Version-2-5-FINAL.pdf#page=52", impIndex, assetIndex, protocolIndex)
 	}
 	return nil
@@ -1137,6 +1191,10 @@ func (deps *endpointDeps) validateUser(user *openrtb2.User, aliases map[string]s
 		return errors.New("request.user.geo.accuracy must be a positive number")
 	}
 
+	if err := validateUserEIDs(user.EIDs); err != nil {
+		return err
+	}
+
 	if user.Ext != nil {
 		// Creating ExtUser object to check if DigiTrust is valid
 		var userExt openrtb_ext.ExtUser
@@ -1196,16 +1254,68 @@ func (deps *endpointDeps) validateUser(user *openrtb2.User, aliases map[string]s
 	return nil
 }
 
+// validateUserEIDs validates the OpenRTB 2.6 request.user.eids like request.user.ext.eids, which carries them in
+// OpenRTB 2.5
+func validateUserEIDs(eids []openrtb2.EID) error {
+	if eids == nil {
+		return nil
+	}
+	if len(eids) == 0 {
+		return errors.New("request.user.eids must contain at least one element or be undefined")
+	}
+	uniqueSources := make(map[string]struct{}, len(eids))
+	for eidIndex, eid := range eids {
+		if eid.Source == "" {
+			return fmt.Errorf("request.user.eids[%d] missing required field: \"source\"", eidIndex)
+		}
+		if _, ok := uniqueSources[eid.Source]; ok {
+			return errors.New("request.user.eids must contain unique sources")
+		}
+		uniqueSources[eid.Source] = struct{}{}
+
+		if len(eid.UIDs) == 0 {
+			return fmt.Errorf("request.user.eids[%d].uids must contain at least one element", eidIndex)
+		}
+		for uidIndex, uid := range eid.UIDs {
+			if uid.ID == "" {
+				return fmt.Errorf("request.user.eids[%d].uids[%d] missing required field: \"id\"", eidIndex, uidIndex)
+			}
+		}
+	}
+	return nil
+}
+
 func validateRegs(regs *openrtb2.Regs) error {
-	if regs != nil && len(regs.Ext) > 0 {
+	if regs == nil {
+		return nil
+	}
+	if err := validateRegsPrivacy("request.regs", regs.GDPR, regs.GPP, regs.GPPSID); err != nil {
+		return err
+	}
+	if len(regs.Ext) > 0 {
 		var regsExt openrtb_ext.ExtRegs
 		if err := json.Unmarshal(regs.Ext, &regsExt); err != nil {
 			return fmt.Errorf("request.regs.ext is invalid: %v", err)
 		}
-		if regsExt.GDPR != nil && (*regsExt.GDPR < 0 || *regsExt.GDPR > 1) {
-			return errors.New("request.regs.ext.gdpr must be either 0 or 1.")
+		return validateRegsPrivacy("request.regs.ext", regsExt.GDPR, regsExt.GPP, regsExt.GPPSID)
+	}
+	return nil
+}
+
+// validateRegsPrivacy validates the privacy fields of request.regs, or of request.regs.ext where OpenRTB 2.5 carries
+// them
+func validateRegsPrivacy(path string, gdpr *int8, gpp string, gppSID []int8) error {
+	if gdpr != nil && (*gdpr < 0 || *gdpr > 1) {
+		return fmt.Errorf("%s.gdpr must be either 0 or 1.", path)
+	}
+	for i, sectionID := range gppSID {
+		if sectionID <= 0 {
+			return fmt.Errorf("%s.gpp_sid[%d] must be a positive section id.", path, i)
 		}
 	}
+	if len(gppSID) > 0 && gpp == "" {
+		return fmt.Errorf("%s.gpp_sid requires %s.gpp.", path, path)
+	}
 	return nil
 }
 
@@ -1332,15 +1442,27 @@ func getJsonSyntaxError(testJSON []byte) (bool, string) {
 	return false, ""
 }
 
-func (deps *endpointDeps) processStoredRequests(ctx context.Context, requestJson []byte) ([]byte, []error) {
+// processStoredRequests merges the Stored Request and the Stored Imps into the request. The imp.ext.prebid.deals
+// of the Stored Imps are taken out of the imps and returned by imp id, those of the incoming request are dropped:
+// only the deals stored by the publisher are offered to the bidders.
+func (deps *endpointDeps) processStoredRequests(ctx context.Context, requestJson []byte) ([]byte, map[string]json.RawMessage, []error) {
 	// Parse the Stored Request IDs from the BidRequest and Imps.
 	storedBidRequestId, hasStoredBidRequest, err := getStoredRequestId(requestJson)
 	if err != nil {
-		return nil, []error{err}
+		return nil, nil, []error{err}
 	}
 	imps, impIds, idIndices, errs := parseImpInfo(requestJson)
 	if len(errs) > 0 {
-		return nil, errs
+		return nil, nil, errs
+	}
+
+	// Drop the deals of the incoming imps before they are merged with the Stored Imps
+	impsChanged := len(impIds) > 0
+	for i := range imps {
+		if _, _, _, err := jsonparser.Get(imps[i], "ext", openrtb_ext.PrebidExtKey, "deals"); err == nil {
+			imps[i] = jsonparser.Delete(append(json.RawMessage(nil), imps[i]...), "ext", openrtb_ext.PrebidExtKey, "deals")
+			impsChanged = true
+		}
 	}
 
 	// Fetch the Stored Request data
@@ -1350,7 +1472,7 @@ func (deps *endpointDeps) processStoredRequests(ctx context.Context, requestJson
 	}
 	storedRequests, storedImps, errs := deps.storedReqFetcher.FetchRequests(ctx, storedReqIds, impIds)
 	if len(errs) != 0 {
-		return nil, errs
+		return nil, nil, errs
 	}
 
 	// Apply the Stored BidRequest, if it exists
@@ -1368,7 +1490,7 @@ func (deps *endpointDeps) processStoredRequests(ctx context.Context, requestJson
 					err = fmt.Errorf("ext.prebid.storedrequest.id refers to Stored Request %s which contains Invalid JSON: %s", storedBidRequestId, Err)
 				}
 			}
-			return nil, []error{err}
+			return nil, nil, []error{err}
 		}
 	}
 
@@ -1385,7 +1507,7 @@ func (deps *endpointDeps) processStoredRequests(ctx context.Context, requestJson
 					err = fmt.Errorf("Invalid JSON in Default Request Settings: %s", Err)
 				}
 			}
-			return nil, []error{err}
+			return nil, nil, []error{err}
 		}
 		resolvedRequest = aliasedRequest
 	}
@@ -1393,6 +1515,7 @@ func (deps *endpointDeps) processStoredRequests(ctx context.Context, requestJson
 	// Apply any Stored Imps, if they exist. Since the JSON Merge Patch overrides arrays,
 	// and Prebid Server defers to the HTTP Request to resolve conflicts, it's safe to
 	// assume that the request.imp data did not change when applying the Stored BidRequest.
+	var storedImpDeals map[string]json.RawMessage
 	for i := 0; i < len(impIds); i++ {
 		resolvedImp, err := jsonpatch.MergePatch(storedImps[impIds[i]], imps[idIndices[i]])
 		if err != nil {
@@ -1405,22 +1528,30 @@ func (deps *endpointDeps) processStoredRequests(ctx context.Context, requestJson
 					err = fmt.Errorf("imp.ext.prebid.storedrequest.id %s: Stored Imp has Invalid JSON: %s", impIds[i], Err)
 				}
 			}
-			return nil, []error{err}
+			return nil, nil, []error{err}
+		}
+		if deals, dataType, _, err := jsonparser.Get(storedImps[impIds[i]], "ext", openrtb_ext.PrebidExtKey, "deals"); err == nil && dataType != jsonparser.Null {
+			impID, _ := jsonparser.GetString(resolvedImp, "id")
+			if storedImpDeals == nil {
+				storedImpDeals = make(map[string]json.RawMessage)
+			}
+			storedImpDeals[impID] = deals
+			resolvedImp = jsonparser.Delete(resolvedImp, "ext", openrtb_ext.PrebidExtKey, "deals")
 		}
 		imps[idIndices[i]] = resolvedImp
 	}
-	if len(impIds) > 0 {
+	if impsChanged {
 		newImpJson, err := json.Marshal(imps)
 		if err != nil {
-			return nil, []error{err}
+			return nil, nil, []error{err}
 		}
 		resolvedRequest, err = jsonparser.Set(resolvedRequest, newImpJson, "imp")
 		if err != nil {
-			return nil, []error{err}
+			return nil, nil, []error{err}
 		}
 	}
 
-	return resolvedRequest, nil
+	return resolvedRequest, storedImpDeals, nil
 }
 
 // parseImpInfo parses the request JSON and returns several things about the Imps
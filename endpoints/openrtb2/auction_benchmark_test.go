@@ -80,6 +80,7 @@ func BenchmarkOpenrtbEndpoint(b *testing.B) {
 		gdpr.AlwaysAllow{},
 		currency.NewRateConverter(&http.Client{}, "", time.Duration(0)),
 		empty_fetcher.EmptyFetcher{},
+		nil,
 	)
 
 	endpoint, _ := NewEndpoint(
@@ -19,9 +19,10 @@ import (
 
 	"github.com/buger/jsonparser"
 	jsonpatch "github.com/evanphx/json-patch"
-	"github.com/mxmCherry/openrtb/v15/native1"
-	nativeRequests "github.com/mxmCherry/openrtb/v15/native1/request"
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/native1"
+	nativeRequests "github.com/prebid/openrtb/v20/native1/request"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
 	analyticsConf "github.com/prebid/prebid-server/analytics/config"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/currency"
@@ -1021,7 +1022,7 @@ func TestStoredRequests(t *testing.T) {
 	}
 
 	for i, requestData := range testStoredRequests {
-		newRequest, errList := deps.processStoredRequests(context.Background(), json.RawMessage(requestData))
+		newRequest, _, errList := deps.processStoredRequests(context.Background(), json.RawMessage(requestData))
 		if len(errList) != 0 {
 			for _, err := range errList {
 				if err != nil {
@@ -1038,6 +1039,40 @@ func TestStoredRequests(t *testing.T) {
 	}
 }
 
+// TestStoredImpDeals makes sure only the deals of the stored imps are offered, apart from the request.
+func TestStoredImpDeals(t *testing.T) {
+	deps := &endpointDeps{
+		&nobidExchange{},
+		newParamsValidator(t),
+		&mockStoredReqFetcher{},
+		empty_fetcher.EmptyFetcher{},
+		empty_fetcher.EmptyFetcher{},
+		&config.Configuration{MaxRequestSize: maxSize},
+		newTestMetrics(),
+		analyticsConf.NewPBSAnalytics(&config.Analytics{}),
+		map[string]string{},
+		false,
+		[]byte{},
+		openrtb_ext.BuildBidderMap(),
+		nil,
+		nil,
+		hardcodedResponseIPValidator{response: true},
+	}
+	requestJson := `{"id":"ThisID","imp":[` +
+		`{"ext":{"prebid":{"storedrequest":{"id":"deals"},"deals":[{"id":"injected-deal","bidders":["appnexus"],"priority":9}]}}},` +
+		`{"id":"adUnit2","ext":{"appnexus":{"placementId":1},"prebid":{"deals":[{"id":"injected-deal","bidders":["appnexus"],"priority":9}]}}}]}`
+
+	newRequest, storedImpDeals, errList := deps.processStoredRequests(context.Background(), json.RawMessage(requestJson))
+
+	assert.Empty(t, errList)
+	assert.Equal(t, map[string]json.RawMessage{
+		"adUnit1": json.RawMessage(`[{"id":"stored-deal","bidders":["appnexus"],"priority":2}]`),
+	}, storedImpDeals, "the deals of the stored imp replace those of the request")
+	assert.JSONEq(t, `{"id":"ThisID","imp":[`+
+		`{"id":"adUnit1","ext":{"appnexus":{"placementId":12345678},"prebid":{"storedrequest":{"id":"deals"}}}},`+
+		`{"id":"adUnit2","ext":{"appnexus":{"placementId":1},"prebid":{}}}]}`, string(newRequest), "the deals are dropped from the imps")
+}
+
 // TestOversizedRequest makes sure we behave properly when the request size exceeds the configured max.
 func TestOversizedRequest(t *testing.T) {
 	reqBody := validRequest(t, "site.json")
@@ -2101,6 +2136,53 @@ func TestValidateEidPermissions(t *testing.T) {
 	}
 }
 
+func TestValidateMultiBid(t *testing.T) {
+	testCases := []struct {
+		description      string
+		ext              string
+		expectedExt      string
+		expectedWarnings int
+	}{
+		{
+			description: "valid multibid is kept",
+			ext:         `{"prebid":{"multibid":[{"bidder":"appnexus","maxbids":2}]}}`,
+			expectedExt: `{"prebid":{"multibid":[{"bidder":"appnexus","maxbids":2}]}}`,
+		},
+		{
+			description:      "invalid entries are dropped",
+			ext:              `{"prebid":{"multibid":[{"bidder":"appnexus","maxbids":2},{"bidder":"rubicon"}]}}`,
+			expectedExt:      `{"prebid":{"multibid":[{"bidder":"appnexus","maxbids":2}]}}`,
+			expectedWarnings: 1,
+		},
+		{
+			description:      "multibid without valid entries is removed",
+			ext:              `{"prebid":{"multibid":[{"maxbids":2}],"debug":true}}`,
+			expectedExt:      `{"prebid":{"debug":true}}`,
+			expectedWarnings: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		req := &openrtb2.BidRequest{Ext: json.RawMessage(test.ext)}
+		var bidExt openrtb_ext.ExtRequest
+		if err := json.Unmarshal(req.Ext, &bidExt); err != nil {
+			t.Fatalf("%s: %v", test.description, err)
+		}
+
+		warnings, err := validateMultiBid(req, &bidExt)
+
+		assert.NoError(t, err, test.description)
+		assert.Len(t, warnings, test.expectedWarnings, test.description)
+		assert.JSONEq(t, test.expectedExt, string(req.Ext), test.description)
+		multiBidJSON, _ := json.Marshal(bidExt.Prebid.MultiBid)
+		expectedMultiBid, _, _, _ := jsonparser.Get([]byte(test.expectedExt), "prebid", "multibid")
+		if len(expectedMultiBid) == 0 {
+			expectedMultiBid = []byte("null")
+		}
+		assert.JSONEq(t, string(expectedMultiBid), string(multiBidJSON), "%s: the parsed ext is validated too", test.description)
+	}
+}
+
 func TestValidateBidders(t *testing.T) {
 	testCases := []struct {
 		description   string
@@ -2275,6 +2357,147 @@ func TestAuctionWarnings(t *testing.T) {
 	assert.Equal(t, errortypes.InvalidPrivacyConsentWarningCode, actualWarning.WarningCode, "Warning code is incorrect")
 }
 
+func TestAuctionGPPWarnings(t *testing.T) {
+	reqBody := validRequest(t, "gpp-invalid.json")
+	deps := &endpointDeps{
+		&warningsCheckExchange{},
+		newParamsValidator(t),
+		&mockStoredReqFetcher{},
+		empty_fetcher.EmptyFetcher{},
+		empty_fetcher.EmptyFetcher{},
+		&config.Configuration{MaxRequestSize: int64(len(reqBody))},
+		newTestMetrics(),
+		analyticsConf.NewPBSAnalytics(&config.Analytics{}),
+		map[string]string{},
+		false,
+		[]byte{},
+		openrtb_ext.BuildBidderMap(),
+		nil,
+		nil,
+		hardcodedResponseIPValidator{response: true},
+	}
+
+	req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(reqBody))
+	recorder := httptest.NewRecorder()
+
+	deps.Auction(recorder, req, nil)
+
+	assert.Equal(t, http.StatusOK, recorder.Code, "Endpoint should return a 200")
+	auctionRequest := deps.ex.(*warningsCheckExchange).auctionRequest
+	if !assert.Len(t, auctionRequest.Warnings, 1, "One warning should be returned from exchange") {
+		t.FailNow()
+	}
+	actualWarning := auctionRequest.Warnings[0].(*errortypes.Warning)
+	assert.Equal(t, "GPP string is invalid and will be ignored. (request.regs.ext.gpp usnat section is truncated)", actualWarning.Message, "Warning message is incorrect")
+	assert.Equal(t, errortypes.InvalidPrivacyConsentWarningCode, actualWarning.WarningCode, "Warning code is incorrect")
+	assert.JSONEq(t, `{"us_privacy":"1YNN"}`, string(auctionRequest.BidRequest.Regs.Ext), "The invalid GPP string is removed")
+}
+
+func TestAuctionAccountDefaults(t *testing.T) {
+	reqBody := validRequest(t, "account-defaults.json")
+	cfg := &config.Configuration{MaxRequestSize: int64(len(reqBody))}
+	if err := cfg.MarshalAccountDefaults(); err != nil {
+		t.Fatalf("Unable to marshal the account defaults: %v", err)
+	}
+	ex := &deadlineCheckExchange{}
+	deps := &endpointDeps{
+		ex,
+		newParamsValidator(t),
+		&mockStoredReqFetcher{},
+		empty_fetcher.EmptyFetcher{},
+		&mockAccountFetcher{},
+		cfg,
+		newTestMetrics(),
+		analyticsConf.NewPBSAnalytics(&config.Analytics{}),
+		map[string]string{},
+		false,
+		[]byte{},
+		openrtb_ext.BuildBidderMap(),
+		nil,
+		nil,
+		hardcodedResponseIPValidator{response: true},
+	}
+
+	req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(reqBody))
+	recorder := httptest.NewRecorder()
+
+	start := time.Now()
+	deps.Auction(recorder, req, nil)
+
+	assert.Equal(t, http.StatusOK, recorder.Code, "Endpoint should return a 200")
+	if !assert.Len(t, ex.auctionRequest.Warnings, 1, "One warning should be returned from exchange") {
+		t.FailNow()
+	}
+	actualWarning := ex.auctionRequest.Warnings[0].(*errortypes.Warning)
+	assert.Equal(t, "Account defaults_acct does not permit bidder rubicon, it was removed from the request", actualWarning.Message, "Warning message is incorrect")
+	assert.Equal(t, errortypes.BidderBlockedByAccountWarningCode, actualWarning.WarningCode, "Warning code is incorrect")
+
+	bidRequest := ex.auctionRequest.BidRequest
+	assert.JSONEq(t, `{"appnexus":{"placementId":12883451}}`, string(bidRequest.Imp[0].Ext), "The blocked bidder is removed")
+	assert.Equal(t, int64(300), bidRequest.TMax, "The account tmax is the default")
+	assert.Equal(t, []string{"example.com", "example.org"}, bidRequest.BAdv, "The account blocked advertisers are added")
+	granularity, _, _, _ := jsonparser.Get(bidRequest.Ext, "prebid", "targeting", "pricegranularity", "precision")
+	assert.Equal(t, "2", string(granularity), "The account price granularity is the default")
+
+	if assert.True(t, ex.hasDeadline, "The auction should have a deadline") {
+		assert.WithinDuration(t, start.Add(300*time.Millisecond), ex.deadline, 100*time.Millisecond, "The deadline should follow the account tmax")
+	}
+}
+
+func TestValidateRegs(t *testing.T) {
+	testCases := []struct {
+		description   string
+		regs          *openrtb2.Regs
+		expectedError string
+	}{
+		{
+			description: "Nil Regs",
+			regs:        nil,
+		},
+		{
+			description: "Valid",
+			regs:        &openrtb2.Regs{Ext: json.RawMessage(`{"gdpr":1,"gpp":"DBABL~BVVqAAAAAg","gpp_sid":[7]}`)},
+		},
+		{
+			description:   "Invalid GDPR",
+			regs:          &openrtb2.Regs{Ext: json.RawMessage(`{"gdpr":2}`)},
+			expectedError: "request.regs.ext.gdpr must be either 0 or 1.",
+		},
+		{
+			description:   "Invalid GPP Section ID",
+			regs:          &openrtb2.Regs{Ext: json.RawMessage(`{"gpp":"DBABL~BVVqAAAAAg","gpp_sid":[7,-1]}`)},
+			expectedError: "request.regs.ext.gpp_sid[1] must be a positive section id.",
+		},
+		{
+			description:   "GPP Section IDs Without GPP String",
+			regs:          &openrtb2.Regs{Ext: json.RawMessage(`{"gpp_sid":[7]}`)},
+			expectedError: "request.regs.ext.gpp_sid requires request.regs.ext.gpp.",
+		}, {
+			description: "Valid OpenRTB 2.6",
+			regs:        &openrtb2.Regs{GDPR: openrtb2.Int8Ptr(1), GPP: "DBABL~BVVqAAAAAg", GPPSID: []int8{7}},
+		},
+		{
+			description:   "Invalid OpenRTB 2.6 GDPR",
+			regs:          &openrtb2.Regs{GDPR: openrtb2.Int8Ptr(2)},
+			expectedError: "request.regs.gdpr must be either 0 or 1.",
+		},
+		{
+			description:   "OpenRTB 2.6 GPP Section IDs Without GPP String",
+			regs:          &openrtb2.Regs{GPPSID: []int8{7}},
+			expectedError: "request.regs.gpp_sid requires request.regs.gpp.",
+		},
+	}
+
+	for _, test := range testCases {
+		err := validateRegs(test.regs)
+		if test.expectedError == "" {
+			assert.NoError(t, err, test.description)
+		} else {
+			assert.EqualError(t, err, test.expectedError, test.description)
+		}
+	}
+}
+
 func TestValidateNativeContextTypes(t *testing.T) {
 	impIndex := 4
 
@@ -2704,6 +2927,18 @@ func (e *warningsCheckExchange) HoldAuction(ctx context.Context, r exchange.Auct
 	return nil, nil
 }
 
+type deadlineCheckExchange struct {
+	auctionRequest exchange.AuctionRequest
+	deadline       time.Time
+	hasDeadline    bool
+}
+
+func (e *deadlineCheckExchange) HoldAuction(ctx context.Context, r exchange.AuctionRequest, debugLog *exchange.DebugLog) (*openrtb2.BidResponse, error) {
+	e.auctionRequest = r
+	e.deadline, e.hasDeadline = ctx.Deadline()
+	return nil, nil
+}
+
 // nobidExchange is a well-behaved exchange which always bids "no bid".
 type nobidExchange struct {
 	gotRequest *openrtb2.BidRequest
@@ -2714,7 +2949,7 @@ func (e *nobidExchange) HoldAuction(ctx context.Context, r exchange.AuctionReque
 	return &openrtb2.BidResponse{
 		ID:    r.BidRequest.ID,
 		BidID: "test bid id",
-		NBR:   openrtb2.NoBidReasonCodeUnknownError.Ptr(),
+		NBR:   openrtb3.NoBidUnknownError.Ptr(),
 	}, nil
 }
 
@@ -2770,7 +3005,7 @@ func (e *mockBidExchange) HoldAuction(ctx context.Context, r exchange.AuctionReq
 	bidResponse := &openrtb2.BidResponse{
 		ID:    r.BidRequest.ID,
 		BidID: "test bid id",
-		NBR:   openrtb2.NoBidReasonCodeUnknownError.Ptr(),
+		NBR:   openrtb3.NoBidUnknownError.Ptr(),
 	}
 
 	// Use currencies inside r.BidRequest.Cur, if any, and convert currencies if needed
@@ -2908,6 +3143,7 @@ var testStoredRequestData = map[string]json.RawMessage{
 // second below has invalid JSON (missing comma after rubicon accountId entry) but otherwise matches schema
 // third below has valid JSON and matches schema
 var testStoredImpData = map[string]json.RawMessage{
+	"deals": json.RawMessage(`{"id":"adUnit1","ext":{"appnexus":{"placementId":12345678},"prebid":{"deals":[{"id":"stored-deal","bidders":["appnexus"],"priority":2}]}}}`),
 	"1": json.RawMessage(`{
 "id": "adUnit1",
 			"ext": {
@@ -3217,7 +3453,8 @@ func (cf mockStoredReqFetcher) FetchRequests(ctx context.Context, requestIDs []s
 }
 
 var mockAccountData = map[string]json.RawMessage{
-	"valid_acct": json.RawMessage(`{"disabled":false}`),
+	"valid_acct":    json.RawMessage(`{"disabled":false}`),
+	"defaults_acct": json.RawMessage(`{"bidders":{"blocked":["rubicon"]},"auction":{"tmax":300,"price_granularity":"dense","blocked_advertisers":["example.com","example.org"]}}`),
 }
 
 type mockAccountFetcher struct {
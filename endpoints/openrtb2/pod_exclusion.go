@@ -0,0 +1,158 @@
+package openrtb2
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/buger/jsonparser"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+type podBid struct {
+	podId    int64
+	seat     string
+	bid      *openrtb2.Bid
+	category string
+}
+
+// applyPodExclusion removes from the response the bids sharing an excluded field with a higher priced bid
+// of the same pod. Only the bids with a cached VAST, those buildVideoResponse puts in the pods, are considered.
+// Bids of equal price are ordered by bid ID then seat, so the outcome does not depend on the order of the
+// seat bids.
+func applyPodExclusion(bidResponse *openrtb2.BidResponse, exclusion *openrtb_ext.PodExclusion) ([]openrtb_ext.ExcludedPodBid, error) {
+	if bidResponse == nil || exclusion == nil || !(exclusion.AdvertiserDomain || exclusion.CreativeID || exclusion.PrimaryCategory) {
+		return nil, nil
+	}
+
+	pods := make(map[int64][]podBid)
+	for i := range bidResponse.SeatBid {
+		seatBid := &bidResponse.SeatBid[i]
+		for j := range seatBid.Bid {
+			bid := &seatBid.Bid[j]
+			if len(bid.Ext) == 0 {
+				continue
+			}
+			var bidExt openrtb_ext.ExtBid
+			if err := json.Unmarshal(bid.Ext, &bidExt); err != nil {
+				return nil, err
+			}
+			if bidExt.Prebid == nil || bidExt.Prebid.Targeting[formatTargetingKey(openrtb_ext.HbVastCacheKey, seatBid.Seat)] == "" {
+				continue
+			}
+			podId, _ := strconv.ParseInt(strings.Split(bid.ImpID, "_")[0], 10, 64)
+			pods[podId] = append(pods[podId], podBid{podId: podId, seat: seatBid.Seat, bid: bid, category: primaryCategory(bid, bidExt)})
+		}
+	}
+
+	podIds := make([]int64, 0, len(pods))
+	for podId := range pods {
+		podIds = append(podIds, podId)
+	}
+	sort.Slice(podIds, func(i, j int) bool { return podIds[i] < podIds[j] })
+
+	var excluded []openrtb_ext.ExcludedPodBid
+	excludedBids := make(map[*openrtb2.Bid]struct{})
+	for _, podId := range podIds {
+		bids := pods[podId]
+		sort.SliceStable(bids, func(i, j int) bool {
+			if bids[i].bid.Price != bids[j].bid.Price {
+				return bids[i].bid.Price > bids[j].bid.Price
+			}
+			if bids[i].bid.ID != bids[j].bid.ID {
+				return bids[i].bid.ID < bids[j].bid.ID
+			}
+			return bids[i].seat < bids[j].seat
+		})
+
+		kept := make([]podBid, 0, len(bids))
+		for _, candidate := range bids {
+			if winner, reason := findExclusion(candidate, kept, exclusion); reason != "" {
+				excluded = append(excluded, openrtb_ext.ExcludedPodBid{
+					PodId:        podId,
+					BidId:        candidate.bid.ID,
+					Seat:         candidate.seat,
+					Reason:       reason,
+					WinningBidId: winner.bid.ID,
+				})
+				excludedBids[candidate.bid] = struct{}{}
+				continue
+			}
+			kept = append(kept, candidate)
+		}
+	}
+
+	if len(excluded) == 0 {
+		return nil, nil
+	}
+
+	seatBids := make([]openrtb2.SeatBid, 0, len(bidResponse.SeatBid))
+	for _, seatBid := range bidResponse.SeatBid {
+		bids := make([]openrtb2.Bid, 0, len(seatBid.Bid))
+		for j := range seatBid.Bid {
+			if _, ok := excludedBids[&seatBid.Bid[j]]; !ok {
+				bids = append(bids, seatBid.Bid[j])
+			}
+		}
+		if len(bids) > 0 {
+			seatBid.Bid = bids
+			seatBids = append(seatBids, seatBid)
+		}
+	}
+	bidResponse.SeatBid = seatBids
+	return excluded, nil
+}
+
+// findExclusion returns the kept bid the candidate conflicts with and the field they share, an empty
+// reason if the candidate can join the pod.
+func findExclusion(candidate podBid, kept []podBid, exclusion *openrtb_ext.PodExclusion) (podBid, string) {
+	for _, winner := range kept {
+		if exclusion.CreativeID && candidate.bid.CrID != "" && candidate.bid.CrID == winner.bid.CrID {
+			return winner, openrtb_ext.PodExclusionCreativeID
+		}
+		if exclusion.AdvertiserDomain && sharesDomain(candidate.bid.ADomain, winner.bid.ADomain) {
+			return winner, openrtb_ext.PodExclusionAdvertiserDomain
+		}
+		if exclusion.PrimaryCategory && candidate.category != "" && candidate.category == winner.category {
+			return winner, openrtb_ext.PodExclusionPrimaryCategory
+		}
+	}
+	return podBid{}, ""
+}
+
+func sharesDomain(domains []string, otherDomains []string) bool {
+	for _, domain := range domains {
+		for _, otherDomain := range otherDomains {
+			if domain != "" && strings.EqualFold(domain, otherDomain) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// primaryCategory returns the category of the bid, preferring the mapped ext.prebid.video.primary_category
+// to the first IAB category of the bid.
+func primaryCategory(bid *openrtb2.Bid, bidExt openrtb_ext.ExtBid) string {
+	if bidExt.Prebid != nil && bidExt.Prebid.Video != nil && bidExt.Prebid.Video.PrimaryCategory != "" {
+		return bidExt.Prebid.Video.PrimaryCategory
+	}
+	if len(bid.Cat) > 0 {
+		return bid.Cat[0]
+	}
+	return ""
+}
+
+// writeExcludedBids reports the bids excluded from the pods in bidresponse.ext.prebid.excludedbids
+func writeExcludedBids(ext json.RawMessage, excluded []openrtb_ext.ExcludedPodBid) (json.RawMessage, error) {
+	excludedJSON, err := json.Marshal(excluded)
+	if err != nil {
+		return ext, err
+	}
+	if len(ext) == 0 {
+		ext = json.RawMessage(`{}`)
+	}
+	return jsonparser.Set(ext, excludedJSON, "prebid", "excludedbids")
+}
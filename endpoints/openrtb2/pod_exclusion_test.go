@@ -0,0 +1,182 @@
+package openrtb2
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyPodExclusion(t *testing.T) {
+	testCases := []struct {
+		description      string
+		exclusion        *openrtb_ext.PodExclusion
+		seatBids         []openrtb2.SeatBid
+		expectedSeatBids []openrtb2.SeatBid
+		expectedExcluded []openrtb_ext.ExcludedPodBid
+	}{
+		{
+			description: "No Exclusion",
+			exclusion:   nil,
+			seatBids: []openrtb2.SeatBid{
+				{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "1", ImpID: "1_0", Price: 2, ADomain: []string{"a.com"}, Ext: cachedBidExt("appnexus")}, {ID: "2", ImpID: "1_1", Price: 1, ADomain: []string{"a.com"}, Ext: cachedBidExt("appnexus")}}},
+			},
+			expectedSeatBids: []openrtb2.SeatBid{
+				{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "1", ImpID: "1_0", Price: 2, ADomain: []string{"a.com"}, Ext: cachedBidExt("appnexus")}, {ID: "2", ImpID: "1_1", Price: 1, ADomain: []string{"a.com"}, Ext: cachedBidExt("appnexus")}}},
+			},
+		},
+		{
+			description: "Advertiser Domain - Highest Price Wins Across Seats",
+			exclusion:   &openrtb_ext.PodExclusion{AdvertiserDomain: true},
+			seatBids: []openrtb2.SeatBid{
+				{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "1", ImpID: "1_0", Price: 1, ADomain: []string{"a.com"}, Ext: cachedBidExt("appnexus")}, {ID: "2", ImpID: "1_1", Price: 3, ADomain: []string{"b.com"}, Ext: cachedBidExt("appnexus")}}},
+				{Seat: "rubicon", Bid: []openrtb2.Bid{{ID: "3", ImpID: "1_0", Price: 2, ADomain: []string{"c.com", "A.com"}, Ext: cachedBidExt("rubicon")}}},
+			},
+			expectedSeatBids: []openrtb2.SeatBid{
+				{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "2", ImpID: "1_1", Price: 3, ADomain: []string{"b.com"}, Ext: cachedBidExt("appnexus")}}},
+				{Seat: "rubicon", Bid: []openrtb2.Bid{{ID: "3", ImpID: "1_0", Price: 2, ADomain: []string{"c.com", "A.com"}, Ext: cachedBidExt("rubicon")}}},
+			},
+			expectedExcluded: []openrtb_ext.ExcludedPodBid{
+				{PodId: 1, BidId: "1", Seat: "appnexus", Reason: "adomain", WinningBidId: "3"},
+			},
+		},
+		{
+			description: "Advertiser Domain - Pods Are Separate",
+			exclusion:   &openrtb_ext.PodExclusion{AdvertiserDomain: true},
+			seatBids: []openrtb2.SeatBid{
+				{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "1", ImpID: "1_0", Price: 1, ADomain: []string{"a.com"}, Ext: cachedBidExt("appnexus")}, {ID: "2", ImpID: "2_0", Price: 3, ADomain: []string{"a.com"}, Ext: cachedBidExt("appnexus")}}},
+			},
+			expectedSeatBids: []openrtb2.SeatBid{
+				{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "1", ImpID: "1_0", Price: 1, ADomain: []string{"a.com"}, Ext: cachedBidExt("appnexus")}, {ID: "2", ImpID: "2_0", Price: 3, ADomain: []string{"a.com"}, Ext: cachedBidExt("appnexus")}}},
+			},
+		},
+		{
+			description: "Creative ID - Equal Prices Ordered By Bid ID",
+			exclusion:   &openrtb_ext.PodExclusion{CreativeID: true},
+			seatBids: []openrtb2.SeatBid{
+				{Seat: "rubicon", Bid: []openrtb2.Bid{{ID: "b", ImpID: "1_0", Price: 2, CrID: "cr1", Ext: cachedBidExt("rubicon")}}},
+				{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "a", ImpID: "1_1", Price: 2, CrID: "cr1", Ext: cachedBidExt("appnexus")}, {ID: "c", ImpID: "1_2", Price: 2, Ext: cachedBidExt("appnexus")}}},
+			},
+			expectedSeatBids: []openrtb2.SeatBid{
+				{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "a", ImpID: "1_1", Price: 2, CrID: "cr1", Ext: cachedBidExt("appnexus")}, {ID: "c", ImpID: "1_2", Price: 2, Ext: cachedBidExt("appnexus")}}},
+			},
+			expectedExcluded: []openrtb_ext.ExcludedPodBid{
+				{PodId: 1, BidId: "b", Seat: "rubicon", Reason: "crid", WinningBidId: "a"},
+			},
+		},
+		{
+			description: "Primary Category - Mapped Category Preferred",
+			exclusion:   &openrtb_ext.PodExclusion{PrimaryCategory: true},
+			seatBids: []openrtb2.SeatBid{
+				{Seat: "appnexus", Bid: []openrtb2.Bid{
+					{ID: "1", ImpID: "1_0", Price: 3, Cat: []string{"IAB1"}, Ext: json.RawMessage(`{"prebid":{"type":"video","targeting":{"hb_uuid_appnexus":"uuid"},"video":{"duration":30,"primary_category":"395"}}}`)},
+					{ID: "2", ImpID: "1_1", Price: 2, Cat: []string{"IAB2"}, Ext: json.RawMessage(`{"prebid":{"type":"video","targeting":{"hb_uuid_appnexus":"uuid"},"video":{"duration":30,"primary_category":"395"}}}`)},
+					{ID: "3", ImpID: "1_2", Price: 1, Cat: []string{"IAB1"}, Ext: cachedBidExt("appnexus")},
+				}},
+			},
+			expectedSeatBids: []openrtb2.SeatBid{
+				{Seat: "appnexus", Bid: []openrtb2.Bid{
+					{ID: "1", ImpID: "1_0", Price: 3, Cat: []string{"IAB1"}, Ext: json.RawMessage(`{"prebid":{"type":"video","targeting":{"hb_uuid_appnexus":"uuid"},"video":{"duration":30,"primary_category":"395"}}}`)},
+					{ID: "3", ImpID: "1_2", Price: 1, Cat: []string{"IAB1"}, Ext: cachedBidExt("appnexus")},
+				}},
+			},
+			expectedExcluded: []openrtb_ext.ExcludedPodBid{
+				{PodId: 1, BidId: "2", Seat: "appnexus", Reason: "primarycategory", WinningBidId: "1"},
+			},
+		},
+		{
+			description: "Excluded Bid Does Not Exclude Others",
+			exclusion:   &openrtb_ext.PodExclusion{AdvertiserDomain: true, CreativeID: true},
+			seatBids: []openrtb2.SeatBid{
+				{Seat: "appnexus", Bid: []openrtb2.Bid{
+					{ID: "1", ImpID: "1_0", Price: 3, ADomain: []string{"a.com"}, Ext: cachedBidExt("appnexus")},
+					{ID: "2", ImpID: "1_1", Price: 2, ADomain: []string{"a.com"}, CrID: "cr1", Ext: cachedBidExt("appnexus")},
+				}},
+				{Seat: "rubicon", Bid: []openrtb2.Bid{{ID: "3", ImpID: "1_0", Price: 1, ADomain: []string{"b.com"}, CrID: "cr1", Ext: cachedBidExt("rubicon")}}},
+			},
+			expectedSeatBids: []openrtb2.SeatBid{
+				{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "1", ImpID: "1_0", Price: 3, ADomain: []string{"a.com"}, Ext: cachedBidExt("appnexus")}}},
+				{Seat: "rubicon", Bid: []openrtb2.Bid{{ID: "3", ImpID: "1_0", Price: 1, ADomain: []string{"b.com"}, CrID: "cr1", Ext: cachedBidExt("rubicon")}}},
+			},
+			expectedExcluded: []openrtb_ext.ExcludedPodBid{
+				{PodId: 1, BidId: "2", Seat: "appnexus", Reason: "adomain", WinningBidId: "1"},
+			},
+		},
+		{
+			description: "Bids Without A Cached VAST Are Not Considered",
+			exclusion:   &openrtb_ext.PodExclusion{AdvertiserDomain: true},
+			seatBids: []openrtb2.SeatBid{
+				{Seat: "appnexus", Bid: []openrtb2.Bid{
+					{ID: "1", ImpID: "1_0", Price: 3, ADomain: []string{"a.com"}, Ext: cachedBidExt("rubicon")},
+					{ID: "2", ImpID: "1_1", Price: 2, ADomain: []string{"a.com"}},
+					{ID: "3", ImpID: "1_2", Price: 1, ADomain: []string{"a.com"}, Ext: cachedBidExt("appnexus")},
+				}},
+			},
+			expectedSeatBids: []openrtb2.SeatBid{
+				{Seat: "appnexus", Bid: []openrtb2.Bid{
+					{ID: "1", ImpID: "1_0", Price: 3, ADomain: []string{"a.com"}, Ext: cachedBidExt("rubicon")},
+					{ID: "2", ImpID: "1_1", Price: 2, ADomain: []string{"a.com"}},
+					{ID: "3", ImpID: "1_2", Price: 1, ADomain: []string{"a.com"}, Ext: cachedBidExt("appnexus")},
+				}},
+			},
+		},
+		{
+			description: "Pod IDs Are Decimal",
+			exclusion:   &openrtb_ext.PodExclusion{AdvertiserDomain: true},
+			seatBids: []openrtb2.SeatBid{
+				{Seat: "appnexus", Bid: []openrtb2.Bid{
+					{ID: "1", ImpID: "010_0", Price: 2, ADomain: []string{"a.com"}, Ext: cachedBidExt("appnexus")},
+					{ID: "2", ImpID: "8_0", Price: 1, ADomain: []string{"a.com"}, Ext: cachedBidExt("appnexus")},
+				}},
+			},
+			expectedSeatBids: []openrtb2.SeatBid{
+				{Seat: "appnexus", Bid: []openrtb2.Bid{
+					{ID: "1", ImpID: "010_0", Price: 2, ADomain: []string{"a.com"}, Ext: cachedBidExt("appnexus")},
+					{ID: "2", ImpID: "8_0", Price: 1, ADomain: []string{"a.com"}, Ext: cachedBidExt("appnexus")},
+				}},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		bidResponse := &openrtb2.BidResponse{SeatBid: test.seatBids}
+
+		excluded, err := applyPodExclusion(bidResponse, test.exclusion)
+
+		assert.NoError(t, err, test.description)
+		assert.Equal(t, test.expectedExcluded, excluded, test.description)
+		assert.Equal(t, test.expectedSeatBids, bidResponse.SeatBid, test.description)
+	}
+}
+
+// cachedBidExt is the ext of a video bid with a cached VAST for the seat
+func cachedBidExt(seat string) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{"prebid":{"type":"video","targeting":{"hb_uuid_%s":"uuid"}}}`, seat))
+}
+
+func TestApplyPodExclusionMalformedBidExt(t *testing.T) {
+	bidResponse := &openrtb2.BidResponse{SeatBid: []openrtb2.SeatBid{
+		{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "1", ImpID: "1_0", Ext: json.RawMessage(`malformed`)}}},
+	}}
+
+	_, err := applyPodExclusion(bidResponse, &openrtb_ext.PodExclusion{PrimaryCategory: true})
+
+	assert.Error(t, err)
+}
+
+func TestWriteExcludedBids(t *testing.T) {
+	excluded := []openrtb_ext.ExcludedPodBid{{PodId: 1, BidId: "2", Seat: "appnexus", Reason: "adomain", WinningBidId: "1"}}
+
+	ext, err := writeExcludedBids(nil, excluded)
+	if assert.NoError(t, err, "Empty Ext") {
+		assert.JSONEq(t, `{"prebid":{"excludedbids":[{"podid":1,"bidid":"2","seat":"appnexus","reason":"adomain","winningbidid":"1"}]}}`, string(ext), "Empty Ext")
+	}
+
+	ext, err = writeExcludedBids(json.RawMessage(`{"debug":{},"prebid":{"auctiontimestamp":1}}`), excluded)
+	if assert.NoError(t, err, "Existing Ext") {
+		assert.JSONEq(t, `{"debug":{},"prebid":{"auctiontimestamp":1,"excludedbids":[{"podid":1,"bidid":"2","seat":"appnexus","reason":"adomain","winningbidid":"1"}]}}`, string(ext), "Existing Ext")
+	}
+}
@@ -12,7 +12,9 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/buger/jsonparser"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/analytics"
 	analyticsConf "github.com/prebid/prebid-server/analytics/config"
 	"github.com/prebid/prebid-server/config"
@@ -102,6 +104,43 @@ func TestVideoEndpointImpressionsDuration(t *testing.T) {
 
 }
 
+func TestVideoEndpointOpenRTB26(t *testing.T) {
+	ex := &mockExchangeVideo{}
+	reqData, err := ioutil.ReadFile("sample-requests/video/video_valid_sample.json")
+	if err != nil {
+		t.Fatalf("Failed to fetch a valid request: %v", err)
+	}
+	reqBody := getRequestPayload(t, reqData)
+	reqBody, _ = jsonparser.Set(reqBody, []byte(`"DBABL~BVVqAAAAAg"`), "regs", "gpp")
+	reqBody, _ = jsonparser.Set(reqBody, []byte(`1`), "video", "plcmt")
+	req := httptest.NewRequest("POST", "/openrtb2/video", bytes.NewReader(reqBody))
+	recorder := httptest.NewRecorder()
+
+	deps := mockDeps(t, ex)
+	deps.VideoAuctionEndpoint(recorder, req, nil)
+
+	if ex.lastRequest == nil {
+		t.Fatalf("The request never made it into the Exchange.")
+	}
+	assert.Equal(t, "DBABL~BVVqAAAAAg", ex.lastRequest.Regs.GPP, "regs.gpp kept")
+	assert.JSONEq(t, `{"gdpr":0}`, string(ex.lastRequest.Regs.Ext), "regs.ext kept")
+	for _, imp := range ex.lastRequest.Imp {
+		assert.Equal(t, adcom1.VideoPlcmtInstream, imp.Video.Plcmt, "video.plcmt kept")
+	}
+}
+
+func TestLoadStoredImpOpenRTB26(t *testing.T) {
+	deps := mockDeps(t, &mockExchangeVideo{})
+	deps.storedReqFetcher = &mockVideoStoredImpFetcher{imps: map[string]json.RawMessage{
+		"imp": json.RawMessage(`{"rwdd":1,"ext":{"appnexus":{"placementId":14997137}}}`),
+	}}
+
+	imp, errs := deps.loadStoredImp("imp")
+	assert.Empty(t, errs)
+	assert.Equal(t, int8(1), imp.Rwdd)
+	assert.JSONEq(t, `{"appnexus":{"placementId":14997137}}`, string(imp.Ext))
+}
+
 func TestCreateBidExtension(t *testing.T) {
 	durationRange := make([]int, 0)
 	durationRange = append(durationRange, 15)
@@ -364,7 +403,7 @@ func TestVideoEndpointValidationsPositive(t *testing.T) {
 	mimes = append(mimes, "mp4")
 	mimes = append(mimes, "")
 
-	videoProtocols := make([]openrtb2.Protocol, 0)
+	videoProtocols := make([]adcom1.MediaCreativeSubtype, 0)
 	videoProtocols = append(videoProtocols, 15)
 	videoProtocols = append(videoProtocols, 30)
 
@@ -407,7 +446,7 @@ func TestVideoEndpointValidationsCritical(t *testing.T) {
 	mimes = append(mimes, "")
 	mimes = append(mimes, "")
 
-	videoProtocols := make([]openrtb2.Protocol, 0)
+	videoProtocols := make([]adcom1.MediaCreativeSubtype, 0)
 
 	req := openrtb_ext.BidRequestVideo{
 		StoredRequestId: "",
@@ -476,7 +515,7 @@ func TestVideoEndpointValidationsPodErrors(t *testing.T) {
 	mimes = append(mimes, "mp4")
 	mimes = append(mimes, "")
 
-	videoProtocols := make([]openrtb2.Protocol, 0)
+	videoProtocols := make([]adcom1.MediaCreativeSubtype, 0)
 	videoProtocols = append(videoProtocols, 15)
 	videoProtocols = append(videoProtocols, 30)
 
@@ -546,7 +585,7 @@ func TestVideoEndpointValidationsSiteAndApp(t *testing.T) {
 	mimes = append(mimes, "mp4")
 	mimes = append(mimes, "")
 
-	videoProtocols := make([]openrtb2.Protocol, 0)
+	videoProtocols := make([]adcom1.MediaCreativeSubtype, 0)
 	videoProtocols = append(videoProtocols, 15)
 	videoProtocols = append(videoProtocols, 30)
 
@@ -604,7 +643,7 @@ func TestVideoEndpointValidationsSiteMissingRequiredField(t *testing.T) {
 	mimes = append(mimes, "mp4")
 	mimes = append(mimes, "")
 
-	videoProtocols := make([]openrtb2.Protocol, 0)
+	videoProtocols := make([]adcom1.MediaCreativeSubtype, 0)
 	videoProtocols = append(videoProtocols, 15)
 	videoProtocols = append(videoProtocols, 30)
 
@@ -1061,25 +1100,25 @@ func TestCreateImpressionTemplate(t *testing.T) {
 
 	imp := openrtb2.Imp{}
 	imp.Video = &openrtb2.Video{}
-	imp.Video.Protocols = []openrtb2.Protocol{1, 2}
+	imp.Video.Protocols = []adcom1.MediaCreativeSubtype{1, 2}
 	imp.Video.MIMEs = []string{"video/mp4"}
-	imp.Video.H = 200
-	imp.Video.W = 400
-	imp.Video.PlaybackMethod = []openrtb2.PlaybackMethod{5, 6}
+	imp.Video.H = openrtb2.Int64Ptr(200)
+	imp.Video.W = openrtb2.Int64Ptr(400)
+	imp.Video.PlaybackMethod = []adcom1.PlaybackMethod{5, 6}
 
 	video := openrtb2.Video{}
-	video.Protocols = []openrtb2.Protocol{3, 4}
+	video.Protocols = []adcom1.MediaCreativeSubtype{3, 4}
 	video.MIMEs = []string{"video/flv"}
-	video.H = 300
-	video.W = 0
-	video.PlaybackMethod = []openrtb2.PlaybackMethod{7, 8}
+	video.H = openrtb2.Int64Ptr(300)
+	video.W = openrtb2.Int64Ptr(0)
+	video.PlaybackMethod = []adcom1.PlaybackMethod{7, 8}
 
 	res := createImpressionTemplate(imp, &video)
-	assert.Equal(t, res.Video.Protocols, []openrtb2.Protocol{3, 4}, "Incorrect video protocols")
+	assert.Equal(t, res.Video.Protocols, []adcom1.MediaCreativeSubtype{3, 4}, "Incorrect video protocols")
 	assert.Equal(t, res.Video.MIMEs, []string{"video/flv"}, "Incorrect video MIMEs")
-	assert.Equal(t, int(res.Video.H), 300, "Incorrect video height")
-	assert.Equal(t, int(res.Video.W), 0, "Incorrect video width")
-	assert.Equal(t, res.Video.PlaybackMethod, []openrtb2.PlaybackMethod{7, 8}, "Incorrect video playback method")
+	assert.Equal(t, int(*res.Video.H), 300, "Incorrect video height")
+	assert.Equal(t, int(*res.Video.W), 0, "Incorrect video width")
+	assert.Equal(t, res.Video.PlaybackMethod, []adcom1.PlaybackMethod{7, 8}, "Incorrect video playback method")
 }
 
 func TestCCPA(t *testing.T) {
@@ -1332,6 +1371,14 @@ func (cf mockVideoStoredReqFetcher) FetchRequests(ctx context.Context, requestID
 	return testVideoStoredRequestData, testVideoStoredImpData, nil
 }
 
+type mockVideoStoredImpFetcher struct {
+	imps map[string]json.RawMessage
+}
+
+func (cf *mockVideoStoredImpFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (requestData map[string]json.RawMessage, impData map[string]json.RawMessage, errs []error) {
+	return nil, cf.imps, nil
+}
+
 type mockExchangeVideo struct {
 	lastRequest *openrtb2.BidRequest
 	cache       *mockCacheClient
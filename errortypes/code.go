@@ -12,6 +12,8 @@ const (
 	BlacklistedAcctErrorCode
 	AcctRequiredErrorCode
 	NoConversionRateErrorCode
+	BidBelowFloorErrorCode
+	BlockedCreativeErrorCode
 )
 
 // Defines numeric codes for well-known warnings.
@@ -21,6 +23,10 @@ const (
 	AccountLevelDebugDisabledWarningCode
 	BidderLevelDebugDisabledWarningCode
 	DisabledCurrencyConversionWarningCode
+	CircuitBreakerOpenWarningCode
+	MultiBidWarningCode
+	BidderBlockedByAccountWarningCode
+	BidderThrottledWarningCode
 )
 
 // Coder provides an error or warning code with severity.
@@ -165,6 +165,43 @@ func (err *BidderTemporarilyDisabled) Severity() Severity {
 	return SeverityWarning
 }
 
+// BidBelowFloor should be used when a bid is rejected because its price is under the floor of its imp.
+// The rejection is a warning, the bidder itself did not fail.
+type BidBelowFloor struct {
+	Message string
+}
+
+func (err *BidBelowFloor) Error() string {
+	return err.Message
+}
+
+func (err *BidBelowFloor) Code() int {
+	return BidBelowFloorErrorCode
+}
+
+func (err *BidBelowFloor) Severity() Severity {
+	return SeverityWarning
+}
+
+// BlockedCreative should be used when a bid is rejected because its creative is blocked by the request or
+// the account. Blocklist names the blocklist the creative is in, e.g. "badv".
+type BlockedCreative struct {
+	Message   string
+	Blocklist string
+}
+
+func (err *BlockedCreative) Error() string {
+	return err.Message
+}
+
+func (err *BlockedCreative) Code() int {
+	return BlockedCreativeErrorCode
+}
+
+func (err *BlockedCreative) Severity() Severity {
+	return SeverityFatal
+}
+
 // Warning is a generic non-fatal error.
 type Warning struct {
 	Message     string
@@ -0,0 +1,287 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/analytics"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+// accountDeals holds the deals of the account and of the stored imps offered to every bidder, by imp id
+type accountDeals struct {
+	offered map[openrtb_ext.BidderName]map[string][]*config.AccountDeal
+}
+
+// injectAccountDeals adds to the imp.pmp.deals of the bidder requests the deals of the account, and the deals
+// of imp.ext.prebid.deals set by the stored imps, made with the bidder or the core bidder of an alias, whose
+// conditions are met by the request and the placement of the imp. The stored imp deals are the ones the endpoint
+// collected while merging the stored imps, by imp id, the deals of the request itself are never trusted. The
+// conditions are checked on the request before it was cleaned for the bidders. The deals already in an imp are
+// kept. The account deals were validated with the account, the invalid imp deals are skipped with a warning.
+// A nil accountDeals is returned when no deal was offered.
+func injectAccountDeals(req *openrtb2.BidRequest, bidderRequests []BidderRequest, deals []config.AccountDeal, storedImpDeals map[string]json.RawMessage, placements map[string]adapters.Placement) (*accountDeals, []error) {
+	var eligible []*config.AccountDeal
+	for i := range deals {
+		if dealRequestConditionsMet(req, &deals[i].Conditions) {
+			eligible = append(eligible, &deals[i])
+		}
+	}
+	impDeals, errs := readImpDeals(req, storedImpDeals)
+	if len(eligible) == 0 && len(impDeals) == 0 {
+		return nil, errs
+	}
+
+	d := &accountDeals{offered: make(map[openrtb_ext.BidderName]map[string][]*config.AccountDeal)}
+	for i := range bidderRequests {
+		bidderRequest := &bidderRequests[i]
+		var imps []openrtb2.Imp
+		for j, imp := range bidderRequest.BidRequest.Imp {
+			var existing []openrtb2.Deal
+			if imp.PMP != nil {
+				existing = imp.PMP.Deals
+			}
+			var offered []openrtb2.Deal
+			for _, deal := range append(impDeals[imp.ID], eligible...) {
+				if !dealMadeWith(deal, bidderRequest) || !dealPlacementMet(placements[imp.ID], &deal.Conditions) || containsDeal(existing, deal.ID) || containsDeal(offered, deal.ID) {
+					continue
+				}
+				offered = append(offered, makeDeal(deal))
+				d.add(bidderRequest.BidderName, imp.ID, deal)
+			}
+			if len(offered) == 0 {
+				continue
+			}
+			if imps == nil {
+				imps = append([]openrtb2.Imp(nil), bidderRequest.BidRequest.Imp...)
+			}
+			pmp := openrtb2.PMP{}
+			if imp.PMP != nil {
+				pmp = *imp.PMP
+			}
+			pmp.Deals = append(append([]openrtb2.Deal(nil), existing...), offered...)
+			imps[j].PMP = &pmp
+		}
+		if imps != nil {
+			request := *bidderRequest.BidRequest
+			request.Imp = imps
+			bidderRequest.BidRequest = &request
+		}
+	}
+	if len(d.offered) == 0 {
+		return nil, errs
+	}
+	return d, errs
+}
+
+// readImpDeals returns the valid stored imp deals meeting the request conditions, by imp id
+func readImpDeals(req *openrtb2.BidRequest, storedImpDeals map[string]json.RawMessage) (map[string][]*config.AccountDeal, []error) {
+	var errs []error
+	impDeals := make(map[string][]*config.AccountDeal)
+	for i, imp := range req.Imp {
+		value, ok := storedImpDeals[imp.ID]
+		if !ok {
+			continue
+		}
+		var deals []config.AccountDeal
+		if err := json.Unmarshal(value, &deals); err != nil {
+			errs = append(errs, &errortypes.Warning{
+				Message: fmt.Sprintf("imp[%d].ext.prebid.deals are ignored: %v", i, err),
+			})
+			continue
+		}
+		for j := range deals {
+			if dealErrs := deals[j].Validate(fmt.Sprintf("imp[%d].ext.prebid.deals[%d]", i, j)); len(dealErrs) > 0 {
+				for _, err := range dealErrs {
+					errs = append(errs, &errortypes.Warning{Message: fmt.Sprintf("The deal is ignored, %v", err)})
+				}
+				continue
+			}
+			if dealRequestConditionsMet(req, &deals[j].Conditions) {
+				impDeals[imp.ID] = append(impDeals[imp.ID], &deals[j])
+			}
+		}
+	}
+	return impDeals, errs
+}
+
+func (d *accountDeals) add(bidder openrtb_ext.BidderName, impID string, deal *config.AccountDeal) {
+	if d.offered[bidder] == nil {
+		d.offered[bidder] = make(map[string][]*config.AccountDeal)
+	}
+	d.offered[bidder][impID] = append(d.offered[bidder][impID], deal)
+}
+
+func dealRequestConditionsMet(req *openrtb2.BidRequest, conditions *config.AccountDealConditions) bool {
+	var country, os, bundle string
+	if req.Device != nil {
+		os = req.Device.OS
+		if req.Device.Geo != nil {
+			country = req.Device.Geo.Country
+		}
+	}
+	if req.App != nil {
+		bundle = req.App.Bundle
+	}
+	return dealConditionMet(conditions.Countries, country) &&
+		dealConditionMet(conditions.OS, os) &&
+		dealConditionMet(conditions.Bundles, bundle)
+}
+
+func dealPlacementMet(placement adapters.Placement, conditions *config.AccountDealConditions) bool {
+	return dealConditionMet(conditions.PlacementTypes, string(placement.Type))
+}
+
+// dealConditionMet is true for an empty condition, or when the value is one of the condition values
+func dealConditionMet(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func dealMadeWith(deal *config.AccountDeal, bidderRequest *BidderRequest) bool {
+	for _, bidder := range deal.Bidders {
+		if bidder == string(bidderRequest.BidderName) || bidder == string(bidderRequest.BidderCoreName) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsDeal(deals []openrtb2.Deal, dealID string) bool {
+	for _, deal := range deals {
+		if deal.ID == dealID {
+			return true
+		}
+	}
+	return false
+}
+
+func makeDeal(deal *config.AccountDeal) openrtb2.Deal {
+	floorCurrency := deal.Currency
+	if floorCurrency == "" {
+		floorCurrency = defaultFloorCurrency
+	}
+	return openrtb2.Deal{
+		ID:          deal.ID,
+		BidFloor:    deal.Floor,
+		BidFloorCur: floorCurrency,
+		WSeat:       deal.Seats,
+	}
+}
+
+// offeredDeal returns the deal of the bid if it was offered to the bidder for the imp of the bid, and the seat
+// of the bid is one of the seats of the deal
+func (d *accountDeals) offeredDeal(bidder openrtb_ext.BidderName, bid *pbsOrtbBid) *config.AccountDeal {
+	if bid.bid.DealID == "" {
+		return nil
+	}
+	for _, deal := range d.offered[bidder][bid.bid.ImpID] {
+		if deal.ID == bid.bid.DealID && dealSeatMet(deal, bid.seat) {
+			return deal
+		}
+	}
+	return nil
+}
+
+func dealSeatMet(deal *config.AccountDeal, seat string) bool {
+	if len(deal.Seats) == 0 {
+		return true
+	}
+	for _, dealSeat := range deal.Seats {
+		if dealSeat == seat {
+			return true
+		}
+	}
+	return false
+}
+
+// markBids sets the account deal of the bids made on a deal offered to their bidder, which newAuction ranks
+// above the other bids. The bids without a deal priority of their own get the priority of the deal, for the
+// deal tiers of the targeting.
+func (d *accountDeals) markBids(adapterBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid) {
+	if d == nil {
+		return
+	}
+	for bidder, seatBid := range adapterBids {
+		if seatBid == nil {
+			continue
+		}
+		for _, bid := range seatBid.bids {
+			if bid.accountDeal = d.offeredDeal(bidder, bid); bid.accountDeal != nil && bid.dealPriority == 0 {
+				bid.dealPriority = bid.accountDeal.Priority
+			}
+		}
+	}
+}
+
+// deliveries reports every deal offered to a bidder on an imp, with the best bid of the bidder on it and
+// whether that bid won the imp. Without targeting, there is no auction and the winners are ranked as
+// newAuction does without preferring the deals of the bidders.
+func (d *accountDeals) deliveries(adapterBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid, numImps int, auc *auction) []*analytics.DealDelivery {
+	if d == nil {
+		return nil
+	}
+	if auc == nil {
+		auc = newAuction(adapterBids, numImps, false, nil)
+	}
+
+	type offer struct {
+		bidder openrtb_ext.BidderName
+		impID  string
+		dealID string
+	}
+	best := make(map[offer]*pbsOrtbBid)
+	for bidder, seatBid := range adapterBids {
+		if seatBid == nil {
+			continue
+		}
+		for _, bid := range seatBid.bids {
+			if bid.accountDeal == nil {
+				continue
+			}
+			key := offer{bidder: bidder, impID: bid.bid.ImpID, dealID: bid.accountDeal.ID}
+			if current, ok := best[key]; !ok || bid.bid.Price > current.bid.Price {
+				best[key] = bid
+			}
+		}
+	}
+
+	var deliveries []*analytics.DealDelivery
+	for bidder, imps := range d.offered {
+		for impID, deals := range imps {
+			for _, deal := range deals {
+				delivery := &analytics.DealDelivery{DealID: deal.ID, Bidder: string(bidder), ImpID: impID}
+				if bid, ok := best[offer{bidder: bidder, impID: impID, dealID: deal.ID}]; ok {
+					delivery.Bid = true
+					delivery.Price = bid.bid.Price
+					delivery.Currency = adapterBids[bidder].currency
+					delivery.Won = auc.winningBids[impID] == bid
+				}
+				deliveries = append(deliveries, delivery)
+			}
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		if deliveries[i].ImpID != deliveries[j].ImpID {
+			return deliveries[i].ImpID < deliveries[j].ImpID
+		}
+		if deliveries[i].Bidder != deliveries[j].Bidder {
+			return deliveries[i].Bidder < deliveries[j].Bidder
+		}
+		return deliveries[i].DealID < deliveries[j].DealID
+	})
+	return deliveries
+}
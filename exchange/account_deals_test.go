@@ -0,0 +1,232 @@
+package exchange
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/analytics"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func accountDealsRequests() (*openrtb2.BidRequest, []BidderRequest) {
+	imps := []openrtb2.Imp{
+		{ID: "banner", Banner: &openrtb2.Banner{}},
+		{ID: "rewarded", Video: &openrtb2.Video{}, Ext: json.RawMessage(`{"prebid":{"is_rewarded_inventory":1}}`)},
+	}
+	request := &openrtb2.BidRequest{
+		App:    &openrtb2.App{Bundle: "com.example.game"},
+		Device: &openrtb2.Device{OS: "iOS", Geo: &openrtb2.Geo{Country: "USA"}},
+		Imp:    imps,
+	}
+	existing := &openrtb2.PMP{Deals: []openrtb2.Deal{{ID: "publisher-deal"}}}
+	bidderRequests := []BidderRequest{
+		{
+			BidderName:     "appnexus",
+			BidderCoreName: "appnexus",
+			BidRequest:     &openrtb2.BidRequest{Imp: []openrtb2.Imp{imps[0], {ID: "rewarded", Video: &openrtb2.Video{}, PMP: existing}}},
+		},
+		{
+			BidderName:     "districtm",
+			BidderCoreName: "appnexus",
+			BidRequest:     &openrtb2.BidRequest{Imp: []openrtb2.Imp{imps[1]}},
+		},
+		{
+			BidderName:     "rubicon",
+			BidderCoreName: "rubicon",
+			BidRequest:     &openrtb2.BidRequest{Imp: imps},
+		},
+	}
+	return request, bidderRequests
+}
+
+func TestInjectAccountDeals(t *testing.T) {
+	request, bidderRequests := accountDealsRequests()
+	deals := []config.AccountDeal{
+		{
+			ID:         "rewarded-deal",
+			Bidders:    []string{"appnexus"},
+			Seats:      []string{"seat1"},
+			Floor:      2.5,
+			Currency:   "EUR",
+			Priority:   2,
+			Conditions: config.AccountDealConditions{Countries: []string{"usa"}, OS: []string{"ios"}, PlacementTypes: []string{"rewarded"}},
+		},
+		{
+			ID:      "any-deal",
+			Bidders: []string{"districtm", "rubicon"},
+			Floor:   1,
+		},
+		{
+			ID:         "canada-deal",
+			Bidders:    []string{"rubicon"},
+			Conditions: config.AccountDealConditions{Countries: []string{"CAN"}},
+		},
+		{
+			ID:         "other-app-deal",
+			Bidders:    []string{"rubicon"},
+			Conditions: config.AccountDealConditions{Bundles: []string{"com.example.other"}},
+		},
+		{
+			ID:      "publisher-deal",
+			Bidders: []string{"appnexus"},
+		},
+	}
+
+	injected, errs := injectAccountDeals(request, bidderRequests, deals, nil, classifyPlacements(request.Imp))
+	assert.Empty(t, errs)
+
+	appnexus := bidderRequests[0].BidRequest
+	if assert.NotNil(t, appnexus.Imp[0].PMP) {
+		assert.Equal(t, []openrtb2.Deal{{ID: "publisher-deal", BidFloorCur: "USD"}}, appnexus.Imp[0].PMP.Deals, "the banner is not a rewarded placement")
+	}
+	if assert.NotNil(t, appnexus.Imp[1].PMP) {
+		assert.Equal(t, []openrtb2.Deal{
+			{ID: "publisher-deal"},
+			{ID: "rewarded-deal", BidFloor: 2.5, BidFloorCur: "EUR", WSeat: []string{"seat1"}},
+		}, appnexus.Imp[1].PMP.Deals, "the deals of the imp are kept and not duplicated")
+	}
+
+	districtm := bidderRequests[1].BidRequest
+	if assert.NotNil(t, districtm.Imp[0].PMP) {
+		assert.Equal(t, []openrtb2.Deal{
+			{ID: "rewarded-deal", BidFloor: 2.5, BidFloorCur: "EUR", WSeat: []string{"seat1"}},
+			{ID: "any-deal", BidFloor: 1, BidFloorCur: "USD"},
+			{ID: "publisher-deal", BidFloorCur: "USD"},
+		}, districtm.Imp[0].PMP.Deals, "the deals of the core bidder are offered to its aliases")
+	}
+
+	rubicon := bidderRequests[2].BidRequest
+	for _, imp := range rubicon.Imp {
+		if assert.NotNil(t, imp.PMP) {
+			assert.Equal(t, []openrtb2.Deal{{ID: "any-deal", BidFloor: 1, BidFloorCur: "USD"}}, imp.PMP.Deals)
+		}
+	}
+
+	assert.Nil(t, request.Imp[0].PMP, "the imps of the request are copied")
+	assert.Nil(t, request.Imp[1].PMP, "the imps of the request are copied")
+	assert.Len(t, bidderRequests[0].BidRequest.Imp[1].PMP.Deals, 2)
+
+	assert.Equal(t, map[openrtb_ext.BidderName]map[string][]*config.AccountDeal{
+		"appnexus":  {"banner": {&deals[4]}, "rewarded": {&deals[0]}},
+		"districtm": {"rewarded": {&deals[0], &deals[1], &deals[4]}},
+		"rubicon":   {"banner": {&deals[1]}, "rewarded": {&deals[1]}},
+	}, injected.offered)
+}
+
+func TestInjectAccountDealsNotOffered(t *testing.T) {
+	request, bidderRequests := accountDealsRequests()
+
+	injected, errs := injectAccountDeals(request, bidderRequests, nil, nil, classifyPlacements(request.Imp))
+	assert.Nil(t, injected)
+	assert.Empty(t, errs)
+
+	deals := []config.AccountDeal{{ID: "deal", Bidders: []string{"pubmatic"}}}
+	injected, errs = injectAccountDeals(request, bidderRequests, deals, nil, classifyPlacements(request.Imp))
+	assert.Nil(t, injected, "no deal with the bidders")
+	assert.Empty(t, errs)
+	for _, bidderRequest := range bidderRequests {
+		for _, imp := range bidderRequest.BidRequest.Imp {
+			assert.True(t, imp.PMP == nil || len(imp.PMP.Deals) == 1)
+		}
+	}
+}
+
+func TestInjectImpDeals(t *testing.T) {
+	request, bidderRequests := accountDealsRequests()
+	request.Imp[0].Ext = json.RawMessage(`{"prebid":{"deals":[{"id":"injected-deal","bidders":["rubicon"],"priority":9}]}}`)
+	storedImpDeals := map[string]json.RawMessage{
+		"rewarded": json.RawMessage(`[` +
+			`{"id":"stored-deal","bidders":["rubicon"],"seats":["seat1"],"floor":3,"priority":4},` +
+			`{"id":"account-deal","bidders":["rubicon"]},` +
+			`{"id":"canada-deal","bidders":["rubicon"],"conditions":{"countries":["CAN"]}},` +
+			`{"bidders":["rubicon"]}]`),
+	}
+	deals := []config.AccountDeal{{ID: "account-deal", Bidders: []string{"rubicon"}, Floor: 1}}
+
+	injected, errs := injectAccountDeals(request, bidderRequests, deals, storedImpDeals, classifyPlacements(request.Imp))
+
+	if assert.Len(t, errs, 1) {
+		assert.EqualError(t, errs[0], "The deal is ignored, imp[1].ext.prebid.deals[3].id must not be empty")
+	}
+	rubicon := bidderRequests[2].BidRequest
+	if assert.NotNil(t, rubicon.Imp[0].PMP) {
+		assert.Equal(t, []openrtb2.Deal{{ID: "account-deal", BidFloor: 1, BidFloorCur: "USD"}}, rubicon.Imp[0].PMP.Deals, "the imp deals are offered on their imp only, the deals of the request are ignored")
+	}
+	if assert.NotNil(t, rubicon.Imp[1].PMP) {
+		assert.Equal(t, []openrtb2.Deal{
+			{ID: "stored-deal", BidFloor: 3, BidFloorCur: "USD", WSeat: []string{"seat1"}},
+			{ID: "account-deal", BidFloorCur: "USD"},
+		}, rubicon.Imp[1].PMP.Deals, "the imp deal is preferred to the account deal with the same id")
+	}
+	assert.Nil(t, bidderRequests[1].BidRequest.Imp[0].PMP, "no deal with the other bidders")
+	if assert.Len(t, injected.offered["rubicon"]["rewarded"], 2) {
+		assert.Equal(t, 4, injected.offered["rubicon"]["rewarded"][0].Priority)
+	}
+}
+
+func TestAccountDealsAuction(t *testing.T) {
+	low := &config.AccountDeal{ID: "low", Priority: 1}
+	high := &config.AccountDeal{ID: "high", Priority: 5}
+	seats := &config.AccountDeal{ID: "seats", Priority: 9, Seats: []string{"seat1"}}
+	deals := &accountDeals{offered: map[openrtb_ext.BidderName]map[string][]*config.AccountDeal{
+		"appnexus": {"imp1": {low, high}},
+		"rubicon":  {"imp1": {low}, "imp2": {low}},
+		"pangle":   {"imp1": {seats}},
+	}}
+
+	open := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "open", ImpID: "imp1", Price: 10}}
+	onLow := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "on-low", ImpID: "imp1", Price: 4, DealID: "low"}, dealPriority: 3}
+	onHigh := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "on-high", ImpID: "imp1", Price: 2, DealID: "high"}}
+	notOffered := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "not-offered", ImpID: "imp2", Price: 1, DealID: "high"}}
+	onLow2 := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "on-low-2", ImpID: "imp2", Price: 0.5, DealID: "low"}}
+	otherSeat := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "other-seat", ImpID: "imp1", Price: 1, DealID: "seats"}, seat: "seat2"}
+	adapterBids := map[openrtb_ext.BidderName]*pbsOrtbSeatBid{
+		"appnexus": {bids: []*pbsOrtbBid{open, onHigh}, currency: "USD"},
+		"rubicon":  {bids: []*pbsOrtbBid{onLow, notOffered, onLow2}, currency: "USD"},
+		"pangle":   {bids: []*pbsOrtbBid{otherSeat}, currency: "USD"},
+		"pubmatic": nil,
+	}
+
+	deals.markBids(adapterBids)
+
+	assert.Nil(t, open.accountDeal)
+	assert.Equal(t, low, onLow.accountDeal)
+	assert.Equal(t, 3, onLow.dealPriority, "the deal priority of the bidder is kept")
+	assert.Equal(t, high, onHigh.accountDeal)
+	assert.Equal(t, 5, onHigh.dealPriority)
+	assert.Nil(t, notOffered.accountDeal, "the deal was not offered on the imp")
+	assert.Nil(t, otherSeat.accountDeal, "the seat of the bid is not a seat of the deal")
+
+	otherSeat.seat = "seat1"
+	deals.markBids(map[openrtb_ext.BidderName]*pbsOrtbSeatBid{"pangle": adapterBids["pangle"]})
+	assert.Equal(t, seats, otherSeat.accountDeal)
+	otherSeat.seat = "seat2"
+	deals.markBids(adapterBids)
+
+	auc := newAuction(adapterBids, 2, false, nil)
+	assert.Equal(t, onHigh, auc.winningBids["imp1"], "the highest deal priority wins")
+	assert.Equal(t, onLow2, auc.winningBids["imp2"], "the account deal wins over a higher bid")
+	assert.Equal(t, []*pbsOrtbBid{onHigh}, auc.winningBidsByBidder["imp1"]["appnexus"], "the bid on the deal is targeted")
+
+	assert.Equal(t, []*analytics.DealDelivery{
+		{DealID: "high", Bidder: "appnexus", ImpID: "imp1", Bid: true, Price: 2, Currency: "USD", Won: true},
+		{DealID: "low", Bidder: "appnexus", ImpID: "imp1"},
+		{DealID: "seats", Bidder: "pangle", ImpID: "imp1"},
+		{DealID: "low", Bidder: "rubicon", ImpID: "imp1", Bid: true, Price: 4, Currency: "USD"},
+		{DealID: "low", Bidder: "rubicon", ImpID: "imp2", Bid: true, Price: 0.5, Currency: "USD", Won: true},
+	}, deals.deliveries(adapterBids, 2, nil))
+}
+
+func TestAccountDealsNotOffered(t *testing.T) {
+	var deals *accountDeals
+	bid := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "bid", ImpID: "imp1", Price: 1, DealID: "deal"}}
+	adapterBids := map[openrtb_ext.BidderName]*pbsOrtbSeatBid{"appnexus": {bids: []*pbsOrtbBid{bid}}}
+
+	deals.markBids(adapterBids)
+
+	assert.Nil(t, bid.accountDeal)
+	assert.Nil(t, deals.deliveries(adapterBids, 2, nil))
+}
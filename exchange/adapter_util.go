@@ -3,6 +3,7 @@ package exchange
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/config"
@@ -19,8 +20,8 @@ func BuildAdapters(client *http.Client, cfg *config.Configuration, infos config.
 	exchangeBidders := make(map[openrtb_ext.BidderName]adaptedBidder, len(bidders))
 	for bidderName, bidder := range bidders {
 		info := infos[string(bidderName)]
-		exchangeBidder := adaptBidder(bidder, client, cfg, me, bidderName, info.Debug)
-		exchangeBidder = addValidatedBidderMiddleware(exchangeBidder)
+		exchangeBidder := adaptBidder(bidder, client, cfg, me, bidderName, &info)
+		exchangeBidder = addValidatedBidderMiddleware(exchangeBidder, skanBidder(bidderName, infos))
 		exchangeBidders[bidderName] = exchangeBidder
 	}
 	return exchangeBidders, nil
@@ -32,6 +33,10 @@ func buildBidders(adapterConfig map[string]config.Adapter, infos config.BidderIn
 
 	for bidder, cfg := range adapterConfig {
 		bidderName, bidderNameFound := openrtb_ext.NormalizeBidderName(bidder)
+		// a seat set in the adapter config only is a bidder under the name of its config
+		if !bidderNameFound && cfg.Seat.Of != "" {
+			bidderName, bidderNameFound = openrtb_ext.BidderName(strings.ToLower(bidder)), true
+		}
 		if !bidderNameFound {
 			errs = append(errs, fmt.Errorf("%v: unknown bidder", bidder))
 			continue
@@ -43,7 +48,17 @@ func buildBidders(adapterConfig map[string]config.Adapter, infos config.BidderIn
 			continue
 		}
 
-		builder, builderFound := builders[bidderName]
+		// a seat is built by the builder of the bidder serving it, under its own bidder code and config
+		builderName := bidderName
+		if info.Seat != nil && info.Seat.Of != "" {
+			if builderName, bidderNameFound = openrtb_ext.NormalizeBidderName(info.Seat.Of); !bidderNameFound {
+				errs = append(errs, fmt.Errorf("%v: seat of unknown bidder %v", bidder, info.Seat.Of))
+				continue
+			}
+			cfg.Seat = *info.Seat
+		}
+
+		builder, builderFound := builders[builderName]
 		if !builderFound {
 			errs = append(errs, fmt.Errorf("%v: builder not registered", bidder))
 			continue
@@ -5,7 +5,7 @@ import (
 	"net/http"
 	"testing"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/adapters/appnexus"
 	"github.com/prebid/prebid-server/adapters/rubicon"
@@ -27,12 +27,12 @@ func TestBuildAdapters(t *testing.T) {
 	appnexusBidder, _ := appnexus.Builder(openrtb_ext.BidderAppnexus, config.Adapter{})
 	appnexusBidderWithInfo := adapters.BuildInfoAwareBidder(appnexusBidder, infoEnabled)
 	appnexusBidderAdapted := adaptBidder(appnexusBidderWithInfo, client, &config.Configuration{}, metricEngine, openrtb_ext.BidderAppnexus, nil)
-	appnexusValidated := addValidatedBidderMiddleware(appnexusBidderAdapted)
+	appnexusValidated := addValidatedBidderMiddleware(appnexusBidderAdapted, openrtb_ext.BidderAppnexus)
 
 	rubiconBidder, _ := rubicon.Builder(openrtb_ext.BidderRubicon, config.Adapter{})
 	rubiconBidderWithInfo := adapters.BuildInfoAwareBidder(rubiconBidder, infoEnabled)
 	rubiconBidderAdapted := adaptBidder(rubiconBidderWithInfo, client, &config.Configuration{}, metricEngine, openrtb_ext.BidderRubicon, nil)
-	rubiconbidderValidated := addValidatedBidderMiddleware(rubiconBidderAdapted)
+	rubiconbidderValidated := addValidatedBidderMiddleware(rubiconBidderAdapted, openrtb_ext.BidderRubicon)
 
 	testCases := []struct {
 		description     string
@@ -90,6 +90,7 @@ func TestBuildBidders(t *testing.T) {
 
 	rubiconBidder := fakeBidder{"b"}
 	rubiconBuilder := fakeBuilder{rubiconBidder, nil}.Builder
+	infoSeat := config.BidderInfo{Enabled: true, Seat: &config.AdapterSeat{Of: "Rubicon", MRAID: true}}
 
 	testCases := []struct {
 		description     string
@@ -169,6 +170,33 @@ func TestBuildBidders(t *testing.T) {
 				openrtb_ext.BidderRubicon: adapters.BuildInfoAwareBidder(rubiconBidder, infoEnabled),
 			},
 		},
+		{
+			description:   "Success - Seat",
+			adapterConfig: map[string]config.Adapter{"rubiconmraid": {}},
+			bidderInfos:   map[string]config.BidderInfo{"rubiconmraid": infoSeat},
+			builders:      map[openrtb_ext.BidderName]adapters.Builder{openrtb_ext.BidderRubicon: rubiconBuilder},
+			expectedBidders: map[openrtb_ext.BidderName]adapters.Bidder{
+				openrtb_ext.BidderRubiconMRAID: adapters.BuildInfoAwareBidder(rubiconBidder, infoSeat),
+			},
+		},
+		{
+			description:   "Success - Seat Set In The Config Only",
+			adapterConfig: map[string]config.Adapter{"exchangeseat": {Seat: config.AdapterSeat{Of: "rubicon", MRAID: true}}},
+			bidderInfos:   map[string]config.BidderInfo{"exchangeseat": infoSeat},
+			builders:      map[openrtb_ext.BidderName]adapters.Builder{openrtb_ext.BidderRubicon: rubiconBuilder},
+			expectedBidders: map[openrtb_ext.BidderName]adapters.Bidder{
+				openrtb_ext.BidderName("exchangeseat"): adapters.BuildInfoAwareBidder(rubiconBidder, infoSeat),
+			},
+		},
+		{
+			description:   "Invalid - Seat Of Unknown Bidder",
+			adapterConfig: map[string]config.Adapter{"rubiconmraid": {}},
+			bidderInfos:   map[string]config.BidderInfo{"rubiconmraid": {Enabled: true, Seat: &config.AdapterSeat{Of: "unknown"}}},
+			builders:      map[openrtb_ext.BidderName]adapters.Builder{openrtb_ext.BidderRubicon: rubiconBuilder},
+			expectedErrors: []error{
+				errors.New("rubiconmraid: seat of unknown bidder unknown"),
+			},
+		},
 		{
 			description:   "Success - Ignores Adapter Config Case",
 			adapterConfig: map[string]config.Adapter{"AppNexus": {}},
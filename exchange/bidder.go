@@ -2,14 +2,18 @@ package exchange
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/http/httptrace"
+	"strings"
 	"time"
 
 	"github.com/golang/glog"
@@ -18,14 +22,16 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 
-	nativeRequests "github.com/mxmCherry/openrtb/v15/native1/request"
-	nativeResponse "github.com/mxmCherry/openrtb/v15/native1/response"
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	nativeRequests "github.com/prebid/openrtb/v20/native1/request"
+	nativeResponse "github.com/prebid/openrtb/v20/native1/response"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/analytics"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/metrics"
 	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/prebid/prebid-server/ortb"
 	"golang.org/x/net/context/ctxhttp"
 )
 
@@ -36,6 +42,8 @@ var (
 	skanSupportedKey  = attribute.Key("app.bidder.skan.supported")
 	skanSentKey       = attribute.Key("app.bidder.skan.sent")
 	mraidSupportedKey = attribute.Key("app.bidder.mraid.supported")
+	regionFallbackKey = attribute.Key("app.bidder.region.fallback")
+	hedgedKey         = attribute.Key("app.bidder.hedged")
 
 	debugVerboseState = "verbose"
 	debugStateKey     = attribute.Key("debug_state")
@@ -89,6 +97,14 @@ type pbsOrtbBid struct {
 	dealPriority      int
 	dealTierSatisfied bool
 	generatedBidID    string
+	// targetBidderCode is the bidder code of the targeting keys of the bids from a multibid bidder
+	targetBidderCode string
+	// accountDeal is the account deal offered to the bidder the bid was made on, if any
+	accountDeal *config.AccountDeal
+	// seat is the buyer seat of the bid, if the adapter reports it
+	seat string
+	// bidderCall is the outcome of the http call the bid was made on, for the analytics modules
+	bidderCall *analytics.BidderCall
 }
 
 // pbsOrtbSeatBid is a SeatBid returned by an adaptedBidder.
@@ -103,13 +119,23 @@ type pbsOrtbSeatBid struct {
 	// httpCalls is the list of debugging info. It should only be populated if the request.test == 1.
 	// This will become response.ext.debug.httpcalls.{bidder} on the final Response.
 	httpCalls []*openrtb_ext.ExtHttpCall
+	// bidderCalls is the outcome of every http call made to the bidder, for the analytics modules.
+	bidderCalls []*analytics.BidderCall
 }
 
 // adaptBidder converts an adapters.Bidder into an exchange.adaptedBidder.
 //
 // The name refers to the "Adapter" architecture pattern, and should not be confused with a Prebid "Adapter"
 // (which is being phased out and replaced by Bidder for OpenRTB auctions)
-func adaptBidder(bidder adapters.Bidder, client *http.Client, cfg *config.Configuration, me metrics.MetricsEngine, name openrtb_ext.BidderName, debugInfo *config.DebugInfo) adaptedBidder {
+func adaptBidder(bidder adapters.Bidder, client *http.Client, cfg *config.Configuration, me metrics.MetricsEngine, name openrtb_ext.BidderName, info *config.BidderInfo) adaptedBidder {
+	if info == nil {
+		info = &config.BidderInfo{}
+	}
+	adapterCfg := cfg.Adapters[strings.ToLower(string(name))]
+	var hedgeDelay time.Duration
+	if adapterCfg.Hedge.Enabled {
+		hedgeDelay = time.Duration(adapterCfg.Hedge.DelayMs) * time.Millisecond
+	}
 	return &bidderAdapter{
 		Bidder:     bidder,
 		BidderName: name,
@@ -118,8 +144,15 @@ func adaptBidder(bidder adapters.Bidder, client *http.Client, cfg *config.Config
 		config: bidderAdapterConfig{
 			Debug:              cfg.Debug,
 			DisableConnMetrics: cfg.Metrics.Disabled.AdapterConnectionMetrics,
-			DebugInfo:          config.DebugInfo{Allow: parseDebugInfo(debugInfo)},
+			DebugInfo:          config.DebugInfo{Allow: parseDebugInfo(info.Debug)},
+			HedgeDelay:         hedgeDelay,
+			Gzip:               info.Gzip,
+			OpenRTBVersion:     info.OpenRTBVersion,
 		},
+		breakers: newCircuitBreakers(adapterCfg.CircuitBreaker, func(endpoint string, state metrics.CircuitBreakerState) {
+			glog.Warningf("Circuit breaker of %s endpoint %s is now %s", name, endpoint, state)
+			me.RecordAdapterCircuitBreaker(name, state)
+		}),
 	}
 }
 
@@ -136,15 +169,31 @@ type bidderAdapter struct {
 	Client     *http.Client
 	me         metrics.MetricsEngine
 	config     bidderAdapterConfig
+	breakers   *circuitBreakers
 }
 
 type bidderAdapterConfig struct {
 	Debug              config.Debug
 	DisableConnMetrics bool
 	DebugInfo          config.DebugInfo
+	// HedgeDelay is the time after which the requests with a fallback endpoint are sent to it too, 0 disables
+	// the hedging
+	HedgeDelay time.Duration
+	// Gzip compresses the request bodies and asks for gzip responses
+	Gzip bool
+	// OpenRTBVersion is the OpenRTB version of the requests given to the bidder, see config.BidderInfo
+	OpenRTBVersion string
 }
 
 func (bidder *bidderAdapter) requestBid(ctx context.Context, request *openrtb2.BidRequest, name openrtb_ext.BidderName, bidAdjustment float64, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, accountDebugAllowed, headerDebugAllowed bool) (*pbsOrtbSeatBid, []error) {
+	if bidder.config.OpenRTBVersion != config.OpenRTBVersion26 {
+		request25 := *request
+		if err := ortb.ConvertDownTo25(&request25); err != nil {
+			return nil, []error{err}
+		}
+		request = &request25
+	}
+
 	reqData, errs := bidder.Bidder.MakeRequests(request, reqInfo)
 
 	if len(reqData) == 0 {
@@ -183,15 +232,18 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, request *openrtb2.B
 
 	defaultCurrency := "USD"
 	seatBid := &pbsOrtbSeatBid{
-		bids:      make([]*pbsOrtbBid, 0, len(reqData)),
-		currency:  defaultCurrency,
-		httpCalls: make([]*openrtb_ext.ExtHttpCall, 0, len(reqData)),
+		bids:        make([]*pbsOrtbBid, 0, len(reqData)),
+		currency:    defaultCurrency,
+		httpCalls:   make([]*openrtb_ext.ExtHttpCall, 0, len(reqData)),
+		bidderCalls: make([]*analytics.BidderCall, 0, len(reqData)),
 	}
 
 	// If the bidder made multiple requests, we still want them to enter as many bids as possible...
 	// even if the timeout occurs sometime halfway through.
 	for i := 0; i < len(reqData); i++ {
 		httpInfo := <-responseChannel
+		bidderCall := makeBidderCall(name, httpInfo)
+		seatBid.bidderCalls = append(seatBid.bidderCalls, bidderCall)
 		// If this is a test bid, capture debugging info from the requests.
 		// Write debug data to ext in case if:
 		// - headerDebugAllowed (debug override header specified correct) - it overrides all other debug restrictions
@@ -220,6 +272,7 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, request *openrtb2.B
 		if httpInfo.err == nil {
 			bidResponse, moreErrs := bidder.Bidder.MakeBids(request, httpInfo.request, httpInfo.response)
 			errs = append(errs, moreErrs...)
+			recordBidderCallError(bidderCall, moreErrs)
 
 			if bidResponse != nil {
 				// Setup default currency as `USD` is not set in bid request nor bid response
@@ -272,11 +325,19 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, request *openrtb2.B
 							bidType:      bidResponse.Bids[i].BidType,
 							bidVideo:     bidResponse.Bids[i].BidVideo,
 							dealPriority: bidResponse.Bids[i].DealPriority,
+							seat:         bidResponse.Bids[i].Seat,
+							bidderCall:   bidderCall,
 						})
+						if bidResponse.Bids[i].Bid != nil {
+							bidderCall.BidCount++
+							bidderCall.Price = math.Max(bidderCall.Price, bidResponse.Bids[i].Bid.Price)
+						}
 					}
+					bidderCall.Currency = seatBid.currency
 				} else {
 					// If no conversions found, do not handle the bid
 					errs = append(errs, err)
+					recordBidderCallError(bidderCall, []error{err})
 				}
 			}
 		} else {
@@ -396,21 +457,190 @@ func makeExt(httpInfo *httpCallInfo) *openrtb_ext.ExtHttpCall {
 	return ext
 }
 
+// makeBidderCall records the outcome of an http call made to the bidder for the analytics modules.
+// The bids are counted by the caller once they are parsed, and recounted by the validatedBidder once the
+// invalid bids are excised.
+func makeBidderCall(name openrtb_ext.BidderName, httpInfo *httpCallInfo) *analytics.BidderCall {
+	tjData := httpInfo.request.TapjoyData
+	bidderCall := &analytics.BidderCall{
+		Bidder:         string(name),
+		PlacementType:  string(tjData.PlacementType),
+		Region:         tjData.Region,
+		RegionFallback: tjData.RegionFallback,
+		Hedged:         tjData.Hedged,
+		HedgeWinner:    tjData.HedgeWinner,
+		SKANSupported:  tjData.SKAN.Supported,
+		SKANSent:       tjData.SKAN.Sent,
+		MRAIDSupported: tjData.MRAID.Supported,
+		LatencyMillis:  httpInfo.latency.Milliseconds(),
+	}
+	if httpInfo.response != nil {
+		bidderCall.Status = httpInfo.response.StatusCode
+	}
+	if errortypes.ReadCode(httpInfo.err) == errortypes.CircuitBreakerOpenWarningCode {
+		bidderCall.ErrorType = circuitOpenErrorType
+	} else if httpInfo.err != nil {
+		recordBidderCallError(bidderCall, []error{httpInfo.err})
+	}
+	return bidderCall
+}
+
+// recordBidderCallError keeps the first fatal error of the call
+func recordBidderCallError(bidderCall *analytics.BidderCall, errs []error) {
+	fatal := errortypes.FatalOnly(errs)
+	if bidderCall.ErrorType != "" || len(fatal) == 0 {
+		return
+	}
+	bidderCall.ErrorType = string(errorToMetric(fatal[0]))
+	bidderCall.Timeout = errortypes.ReadCode(fatal[0]) == errortypes.TimeoutErrorCode
+}
+
 // doRequest makes a request, handles the response, and returns the data needed by the
 // Bidder interface.
 func (bidder *bidderAdapter) doRequest(ctx context.Context, req *adapters.RequestData) *httpCallInfo {
-	return bidder.doRequestImpl(ctx, req, glog.Warningf)
+	startTime := time.Now()
+	var info *httpCallInfo
+	if bidder.config.HedgeDelay > 0 && req.FallbackUri != "" {
+		info = bidder.doHedgedRequest(ctx, req)
+	} else {
+		info = bidder.doGuardedRequest(ctx, req)
+	}
+	if shouldUseFallback(ctx, info, time.Since(startTime)) {
+		fallbackReq := *req
+		fallbackReq.Uri = req.FallbackUri
+		fallbackReq.FallbackUri = ""
+		fallbackReq.FallbackRegion = ""
+		fallbackReq.TapjoyData.Region = req.FallbackRegion
+		fallbackReq.TapjoyData.RegionFallback = true
+		info = bidder.doGuardedRequest(ctx, &fallbackReq)
+	}
+	info.latency = time.Since(startTime)
+	return info
+}
+
+// hedgedCall is the outcome of one of the calls of a hedged request
+type hedgedCall struct {
+	info   *httpCallInfo
+	winner metrics.HedgeWinner
+}
+
+// doHedgedRequest makes the request and, if its endpoint has not answered after the hedge delay, sends it to
+// the fallback endpoint too. The first call without error wins and the other one is cancelled. A request
+// answered before the delay is not hedged, and is retried against the fallback endpoint as usual if it failed.
+func (bidder *bidderAdapter) doHedgedRequest(ctx context.Context, req *adapters.RequestData) *httpCallInfo {
+	hedgeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// buffered so that the losing call does not block once the request returned
+	calls := make(chan hedgedCall, 2)
+	call := func(req *adapters.RequestData, winner metrics.HedgeWinner) {
+		calls <- hedgedCall{info: bidder.doGuardedRequest(hedgeCtx, req), winner: winner}
+	}
+	go call(req, metrics.HedgeWinnerPrimary)
+
+	timer := time.NewTimer(bidder.config.HedgeDelay)
+	defer timer.Stop()
+	select {
+	case primary := <-calls:
+		return primary.info
+	case <-timer.C:
+	}
+
+	secondaryReq := *req
+	secondaryReq.Uri = req.FallbackUri
+	secondaryReq.FallbackUri = ""
+	secondaryReq.FallbackRegion = ""
+	secondaryReq.TapjoyData.Region = req.FallbackRegion
+	secondaryReq.TapjoyData.RegionFallback = true
+	secondaryReq.TapjoyData.Hedged = true
+	go call(&secondaryReq, metrics.HedgeWinnerSecondary)
+
+	// the error of the primary call is kept when both fail
+	var failed *httpCallInfo
+	for i := 0; i < 2; i++ {
+		result := <-calls
+		if result.info.err == nil {
+			return bidder.hedgeOutcome(result.info, result.winner)
+		}
+		if failed == nil || result.winner == metrics.HedgeWinnerPrimary {
+			failed = result.info
+		}
+	}
+	return bidder.hedgeOutcome(failed, metrics.HedgeWinnerNone)
+}
+
+// hedgeOutcome records the winner of a hedged request and flags the request of the call kept
+func (bidder *bidderAdapter) hedgeOutcome(info *httpCallInfo, winner metrics.HedgeWinner) *httpCallInfo {
+	bidder.me.RecordAdapterHedge(bidder.BidderName, winner)
+
+	hedgedReq := *info.request
+	// the fallback endpoint was already called
+	hedgedReq.FallbackUri = ""
+	hedgedReq.FallbackRegion = ""
+	hedgedReq.TapjoyData.Hedged = true
+	hedgedReq.TapjoyData.HedgeWinner = string(winner)
+	info.request = &hedgedReq
+	return info
+}
+
+// doGuardedRequest makes the request unless the circuit breaker of its endpoint is open, in which case
+// it returns a warning without calling the bidder.
+func (bidder *bidderAdapter) doGuardedRequest(ctx context.Context, req *adapters.RequestData) *httpCallInfo {
+	endpoint := circuitEndpoint(req.Uri)
+	if !bidder.breakers.allow(endpoint) {
+		return &httpCallInfo{
+			request: req,
+			err: &errortypes.Warning{
+				WarningCode: errortypes.CircuitBreakerOpenWarningCode,
+				Message:     fmt.Sprintf("The request to %s was not sent, the circuit breaker of the endpoint is open", endpoint),
+			},
+		}
+	}
+	info := bidder.doRequestImpl(ctx, req, glog.Warningf)
+	bidder.breakers.record(endpoint, httpCallOutcome(info))
+	return info
+}
+
+// shouldUseFallback decides whether a failed call is retried against the fallback endpoint of its region.
+// Only connection errors and 5xx responses are retried, and only when the time left on the request is
+// at least the time the first call took.
+func shouldUseFallback(ctx context.Context, info *httpCallInfo, elapsed time.Duration) bool {
+	if info.request.FallbackUri == "" || info.err == nil || ctx.Err() != nil {
+		return false
+	}
+	if _, ok := info.err.(*errortypes.Timeout); ok {
+		return false
+	}
+	if info.response != nil && info.response.StatusCode < http.StatusInternalServerError {
+		return false
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < elapsed {
+		return false
+	}
+	return true
 }
 
 func (bidder *bidderAdapter) doRequestImpl(ctx context.Context, req *adapters.RequestData, logger util.LogMsg) *httpCallInfo {
-	httpReq, err := http.NewRequest(req.Method, req.Uri, bytes.NewBuffer(req.Body))
+	body, headers := req.Body, req.Headers
+	if bidder.config.Gzip {
+		var err error
+		if body, err = gzipBody(req.Body); err != nil {
+			return &httpCallInfo{
+				request: req,
+				err:     err,
+			}
+		}
+		headers = gzipHeaders(req.Headers, len(req.Body) > 0)
+		bidder.me.RecordAdapterPayload(bidder.BidderName, metrics.PayloadRequest, len(req.Body), len(body))
+	}
+	httpReq, err := http.NewRequest(req.Method, req.Uri, bytes.NewBuffer(body))
 	if err != nil {
 		return &httpCallInfo{
 			request: req,
 			err:     err,
 		}
 	}
-	httpReq.Header = req.Headers
+	httpReq.Header = headers
 
 	// If adapter connection metrics are not disabled, add the client trace
 	// to get complete connection info into our metrics
@@ -428,19 +658,15 @@ func (bidder *bidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 		skanSentKey.Bool(tjData.SKAN.Sent),
 		mraidSupportedKey.Bool(tjData.MRAID.Supported),
 		placementTypeKey.String(string(tjData.PlacementType)),
+		regionFallbackKey.Bool(tjData.RegionFallback),
+		hedgedKey.Bool(tjData.Hedged),
 	}
 	span.SetAttributes(attrs...)
 
 	// Only print verbose debug logs if calling service added value in span context
 	if span.SpanContext().TraceState().Get(debugStateKey).AsString() == debugVerboseState {
-		// read and close body
-		bodyBytes, _ := ioutil.ReadAll(httpReq.Body)
-		httpReq.Body.Close()
-
-		// reset body to be able to be read by other middleware
-		httpReq.Body = ioutil.NopCloser(bytes.NewBuffer(bodyBytes))
-
-		reqAttrs := append(attrs, debugReqBodyKey.String(string(bodyBytes)))
+		// the body before compression
+		reqAttrs := append(attrs, debugReqBodyKey.String(string(req.Body)))
 		span.AddEvent(fmt.Sprintf("%s.%s", debugVerboseState, debugReqBodyKey), trace.WithAttributes(
 			reqAttrs...,
 		))
@@ -480,6 +706,17 @@ func (bidder *bidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 	}
 	defer httpResp.Body.Close()
 
+	if bidder.config.Gzip {
+		wireBytes := len(respBody)
+		if respBody, err = gunzipResponse(httpResp, respBody); err != nil {
+			return &httpCallInfo{
+				request: req,
+				err:     err,
+			}
+		}
+		bidder.me.RecordAdapterPayload(bidder.BidderName, metrics.PayloadResponse, len(respBody), wireBytes)
+	}
+
 	// Only print verbose response logs if calling service added value in span context
 	if span.SpanContext().TraceState().Get(debugStateKey).AsString() == debugVerboseState {
 		respAttrs := append(attrs, debugRespBodyKey.String(string(respBody)))
@@ -505,6 +742,62 @@ func (bidder *bidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 	}
 }
 
+// gzipBody compresses the body of a request, an empty body is sent as is
+func gzipBody(body []byte) ([]byte, error) {
+	if len(body) == 0 {
+		return body, nil
+	}
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// gzipHeaders asks for a gzip response and flags the compressed body. Setting Accept-Encoding keeps the
+// transport from decoding the response, so that its compressed size can be recorded.
+func gzipHeaders(headers http.Header, compressedBody bool) http.Header {
+	if headers == nil {
+		headers = http.Header{}
+	} else {
+		headers = headers.Clone()
+	}
+	headers.Set("Accept-Encoding", "gzip")
+	if compressedBody {
+		headers.Set("Content-Encoding", "gzip")
+	}
+	return headers
+}
+
+// maxGunzippedResponseBytes caps the decoded size of the gzip responses, a bid response far above it is rejected
+// rather than decompressed in memory
+const maxGunzippedResponseBytes = 16 * 1024 * 1024
+
+// gunzipResponse decodes the body of a gzip response, the other responses are returned as is
+func gunzipResponse(httpResp *http.Response, body []byte) ([]byte, error) {
+	if !strings.EqualFold(httpResp.Header.Get("Content-Encoding"), "gzip") || len(body) == 0 {
+		return body, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, &errortypes.BadServerResponse{Message: fmt.Sprintf("The gzip response could not be decoded: %v", err)}
+	}
+	defer reader.Close()
+	decoded, err := ioutil.ReadAll(io.LimitReader(reader, maxGunzippedResponseBytes+1))
+	if err != nil {
+		return nil, &errortypes.BadServerResponse{Message: fmt.Sprintf("The gzip response could not be decoded: %v", err)}
+	}
+	if len(decoded) > maxGunzippedResponseBytes {
+		return nil, &errortypes.BadServerResponse{Message: fmt.Sprintf("The gzip response is larger than %d bytes once decoded", maxGunzippedResponseBytes)}
+	}
+	httpResp.Header.Del("Content-Encoding")
+	return decoded, nil
+}
+
 func (bidder *bidderAdapter) doTimeoutNotification(timeoutBidder adapters.TimeoutBidder, req *adapters.RequestData, logger util.LogMsg) {
 	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 	defer cancel()
@@ -550,6 +843,8 @@ type httpCallInfo struct {
 	request  *adapters.RequestData
 	response *adapters.ResponseData
 	err      error
+	// latency of the call, including the retry against the fallback region
+	latency time.Duration
 }
 
 // This function adds an httptrace.ClientTrace object to the context so, if connection with the bidder
@@ -2,6 +2,7 @@ package exchange
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/tls"
 	"encoding/json"
@@ -16,9 +17,10 @@ import (
 	"time"
 
 	"github.com/golang/glog"
-	nativeRequests "github.com/mxmCherry/openrtb/v15/native1/request"
-	nativeResponse "github.com/mxmCherry/openrtb/v15/native1/response"
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/adcom1"
+	nativeRequests "github.com/prebid/openrtb/v20/native1/request"
+	nativeResponse "github.com/prebid/openrtb/v20/native1/response"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/currency"
@@ -91,7 +93,7 @@ func TestSingleBidder(t *testing.T) {
 		}
 		bidderImpl.bidResponse = mockBidderResponse
 
-		bidder := adaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.DummyMetricsEngine{}, openrtb_ext.BidderAppnexus, test.debugInfo)
+		bidder := adaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.DummyMetricsEngine{}, openrtb_ext.BidderAppnexus, &config.BidderInfo{Debug: test.debugInfo})
 		currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
 
 		seatBid, errs := bidder.requestBid(ctx, &openrtb2.BidRequest{}, "test", bidAdjustment, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, true, false)
@@ -165,7 +167,7 @@ func TestRequestBidRemovesSensitiveHeaders(t *testing.T) {
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, DebugContextKey, true)
 
-	bidder := adaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.DummyMetricsEngine{}, openrtb_ext.BidderAppnexus, debugInfo)
+	bidder := adaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.DummyMetricsEngine{}, openrtb_ext.BidderAppnexus, &config.BidderInfo{Debug: debugInfo})
 	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
 	seatBid, errs := bidder.requestBid(ctx, &openrtb2.BidRequest{}, "test", 1, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, true, false)
 
@@ -206,7 +208,7 @@ func TestSetGPCHeader(t *testing.T) {
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, DebugContextKey, true)
 
-	bidder := adaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.DummyMetricsEngine{}, openrtb_ext.BidderAppnexus, debugInfo)
+	bidder := adaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.DummyMetricsEngine{}, openrtb_ext.BidderAppnexus, &config.BidderInfo{Debug: debugInfo})
 	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
 	seatBid, errs := bidder.requestBid(ctx, &openrtb2.BidRequest{}, "test", 1, currencyConverter.Rates(), &adapters.ExtraRequestInfo{GlobalPrivacyControlHeader: "1"}, true, false)
 
@@ -244,7 +246,7 @@ func TestSetGPCHeaderNil(t *testing.T) {
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, DebugContextKey, true)
 
-	bidder := adaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.DummyMetricsEngine{}, openrtb_ext.BidderAppnexus, debugInfo)
+	bidder := adaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.DummyMetricsEngine{}, openrtb_ext.BidderAppnexus, &config.BidderInfo{Debug: debugInfo})
 	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
 	seatBid, errs := bidder.requestBid(ctx, &openrtb2.BidRequest{}, "test", 1, currencyConverter.Rates(), &adapters.ExtraRequestInfo{GlobalPrivacyControlHeader: "1"}, true, false)
 
@@ -358,6 +360,413 @@ func TestBidderTimeout(t *testing.T) {
 	}
 }
 
+// TestRegionFallback makes sure that bidderAdapter.doRequest retries the fallback region on 5xx responses only.
+func TestRegionFallback(t *testing.T) {
+	fallbackServer := httptest.NewServer(mockHandler(200, "getBody", "fallbackBody"))
+	defer fallbackServer.Close()
+
+	testCases := []struct {
+		description  string
+		statusCode   int
+		fallbackUri  string
+		expectedBody string
+		expectedUri  string
+	}{
+		{
+			description:  "5xx is retried against the fallback region",
+			statusCode:   http.StatusServiceUnavailable,
+			fallbackUri:  fallbackServer.URL,
+			expectedBody: "fallbackBody",
+			expectedUri:  fallbackServer.URL,
+		},
+		{
+			description:  "4xx is not retried",
+			statusCode:   http.StatusBadRequest,
+			fallbackUri:  fallbackServer.URL,
+			expectedBody: "primaryBody",
+		},
+		{
+			description:  "5xx without fallback region",
+			statusCode:   http.StatusServiceUnavailable,
+			expectedBody: "primaryBody",
+		},
+	}
+
+	for _, test := range testCases {
+		server := httptest.NewServer(mockHandler(test.statusCode, "getBody", "primaryBody"))
+
+		bidder := &bidderAdapter{
+			Bidder:     &mixedMultiBidder{},
+			Client:     server.Client(),
+			BidderName: openrtb_ext.BidderAppnexus,
+			me:         &metricsConfig.DummyMetricsEngine{},
+		}
+
+		callInfo := bidder.doRequest(context.Background(), &adapters.RequestData{
+			Method:         "POST",
+			Uri:            server.URL,
+			FallbackUri:    test.fallbackUri,
+			FallbackRegion: "us_east",
+			TapjoyData:     adapters.TapjoyData{Region: "eu"},
+		})
+		server.Close()
+
+		if assert.NotNil(t, callInfo.response, test.description) {
+			assert.Equal(t, test.expectedBody, string(callInfo.response.Body), test.description)
+		}
+		if test.expectedUri != "" {
+			assert.Equal(t, test.expectedUri, callInfo.request.Uri, test.description)
+			assert.True(t, callInfo.request.TapjoyData.RegionFallback, test.description)
+			assert.Equal(t, "us_east", callInfo.request.TapjoyData.Region, test.description+":region called")
+		} else {
+			assert.Equal(t, "eu", callInfo.request.TapjoyData.Region, test.description+":region called")
+		}
+	}
+}
+
+// TestHedgedRequest makes sure that bidderAdapter.doRequest sends the requests slower than the hedge delay to the
+// fallback region too and keeps the first valid response.
+func TestHedgedRequest(t *testing.T) {
+	slowHandler := func(delay time.Duration, statusCode int, body string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+			w.WriteHeader(statusCode)
+			w.Write([]byte(body))
+		})
+	}
+
+	testCases := []struct {
+		description    string
+		primary        http.Handler
+		secondary      http.Handler
+		expectedBody   string
+		expectedWinner metrics.HedgeWinner
+	}{
+		{
+			description:  "primary faster than the delay is not hedged",
+			primary:      slowHandler(0, 200, "primaryBody"),
+			secondary:    slowHandler(0, 200, "secondaryBody"),
+			expectedBody: "primaryBody",
+		},
+		{
+			description:    "slow primary",
+			primary:        slowHandler(time.Second, 200, "primaryBody"),
+			secondary:      slowHandler(0, 200, "secondaryBody"),
+			expectedBody:   "secondaryBody",
+			expectedWinner: metrics.HedgeWinnerSecondary,
+		},
+		{
+			description:    "primary answering while the secondary is slower",
+			primary:        slowHandler(100*time.Millisecond, 200, "primaryBody"),
+			secondary:      slowHandler(time.Second, 200, "secondaryBody"),
+			expectedBody:   "primaryBody",
+			expectedWinner: metrics.HedgeWinnerPrimary,
+		},
+		{
+			description:    "failing secondary",
+			primary:        slowHandler(100*time.Millisecond, 200, "primaryBody"),
+			secondary:      slowHandler(0, http.StatusServiceUnavailable, "secondaryBody"),
+			expectedBody:   "primaryBody",
+			expectedWinner: metrics.HedgeWinnerPrimary,
+		},
+		{
+			description:    "both failing",
+			primary:        slowHandler(100*time.Millisecond, http.StatusServiceUnavailable, "primaryBody"),
+			secondary:      slowHandler(0, http.StatusServiceUnavailable, "secondaryBody"),
+			expectedBody:   "primaryBody",
+			expectedWinner: metrics.HedgeWinnerNone,
+		},
+	}
+
+	for _, test := range testCases {
+		server := httptest.NewServer(test.primary)
+		secondaryServer := httptest.NewServer(test.secondary)
+
+		me := &metrics.MetricsEngineMock{}
+		if test.expectedWinner != "" {
+			me.On("RecordAdapterHedge", openrtb_ext.BidderLiftoff, test.expectedWinner).Once()
+		}
+		cfg := &config.Configuration{
+			Adapters: map[string]config.Adapter{
+				"liftoff": {Hedge: config.AdapterHedge{Enabled: true, DelayMs: 50}},
+			},
+			Metrics: config.Metrics{Disabled: config.DisabledMetrics{AdapterConnectionMetrics: true}},
+		}
+		bidder := adaptBidder(&mixedMultiBidder{}, http.DefaultClient, cfg, me, openrtb_ext.BidderLiftoff, nil).(*bidderAdapter)
+
+		callInfo := bidder.doRequest(context.Background(), &adapters.RequestData{
+			Method:         "POST",
+			Uri:            server.URL,
+			FallbackUri:    secondaryServer.URL,
+			FallbackRegion: "us_east",
+			TapjoyData:     adapters.TapjoyData{Region: "eu"},
+		})
+		server.Close()
+		secondaryServer.Close()
+
+		if assert.NotNil(t, callInfo.response, test.description) {
+			assert.Equal(t, test.expectedBody, string(callInfo.response.Body), test.description)
+		}
+		assert.Equal(t, test.expectedWinner != "", callInfo.request.TapjoyData.Hedged, test.description)
+		assert.Equal(t, string(test.expectedWinner), callInfo.request.TapjoyData.HedgeWinner, test.description)
+		assert.Equal(t, test.expectedWinner == metrics.HedgeWinnerSecondary, callInfo.request.TapjoyData.RegionFallback, test.description)
+		if test.expectedWinner == metrics.HedgeWinnerSecondary {
+			assert.Equal(t, "us_east", callInfo.request.TapjoyData.Region, test.description+":region called")
+		} else {
+			assert.Equal(t, "eu", callInfo.request.TapjoyData.Region, test.description+":region called")
+		}
+		me.AssertExpectations(t)
+	}
+}
+
+// TestGzipRequest makes sure that bidderAdapter.doRequest compresses the requests of the bidders accepting gzip and
+// decodes their gzip responses, while the debug info keeps the decoded bodies.
+func TestGzipRequest(t *testing.T) {
+	requestBody := `{"id":"req-1","app":{"bundle":"com.example.app"}}`
+	responseBody := `{"id":"req-1","seatbid":[]}`
+	compressedResponse := gzipString(t, responseBody)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+		assert.Equal(t, "gzip", r.Header.Get("Accept-Encoding"))
+		reader, err := gzip.NewReader(r.Body)
+		if assert.NoError(t, err) {
+			body, _ := ioutil.ReadAll(reader)
+			assert.Equal(t, requestBody, string(body))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(compressedResponse)
+	}))
+	defer server.Close()
+
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAdapterPayload", openrtb_ext.BidderLiftoff, metrics.PayloadRequest, len(requestBody), mock.AnythingOfType("int")).Once()
+	me.On("RecordAdapterPayload", openrtb_ext.BidderLiftoff, metrics.PayloadResponse, len(responseBody), len(compressedResponse)).Once()
+	cfg := &config.Configuration{Metrics: config.Metrics{Disabled: config.DisabledMetrics{AdapterConnectionMetrics: true}}}
+	bidder := adaptBidder(&mixedMultiBidder{}, server.Client(), cfg, me, openrtb_ext.BidderLiftoff, &config.BidderInfo{Gzip: true}).(*bidderAdapter)
+
+	headers := http.Header{"Content-Type": {"application/json"}}
+	callInfo := bidder.doRequest(context.Background(), &adapters.RequestData{
+		Method:  "POST",
+		Uri:     server.URL,
+		Body:    []byte(requestBody),
+		Headers: headers,
+	})
+
+	assert.NoError(t, callInfo.err)
+	ext := makeExt(callInfo)
+	assert.Equal(t, requestBody, ext.RequestBody)
+	assert.Equal(t, responseBody, ext.ResponseBody)
+	assert.Equal(t, http.Header{"Content-Type": {"application/json"}}, headers, "the headers of the adapter are left as is")
+	me.AssertExpectations(t)
+}
+
+func TestGzipInvalidResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write([]byte("not gzip"))
+	}))
+	defer server.Close()
+
+	cfg := &config.Configuration{Metrics: config.Metrics{Disabled: config.DisabledMetrics{AdapterConnectionMetrics: true}}}
+	bidder := adaptBidder(&mixedMultiBidder{}, server.Client(), cfg, &metricsConfig.DummyMetricsEngine{}, openrtb_ext.BidderLiftoff, &config.BidderInfo{Gzip: true}).(*bidderAdapter)
+
+	callInfo := bidder.doRequest(context.Background(), &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte(`{}`)})
+	assert.IsType(t, &errortypes.BadServerResponse{}, callInfo.err)
+}
+
+func TestGunzipResponseUncompressed(t *testing.T) {
+	httpResp := &http.Response{Header: http.Header{}}
+	body, err := gunzipResponse(httpResp, []byte(`{"id":"req-1"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":"req-1"}`, string(body), "a bidder may answer uncompressed")
+}
+
+func TestGunzipResponseTooLarge(t *testing.T) {
+	httpResp := &http.Response{Header: http.Header{"Content-Encoding": []string{"gzip"}}}
+	body := gzipString(t, strings.Repeat(" ", maxGunzippedResponseBytes+1))
+
+	_, err := gunzipResponse(httpResp, body)
+	assert.Equal(t, &errortypes.BadServerResponse{Message: "The gzip response is larger than 16777216 bytes once decoded"}, err)
+
+	decoded, err := gunzipResponse(httpResp, gzipString(t, strings.Repeat(" ", maxGunzippedResponseBytes)))
+	assert.NoError(t, err)
+	assert.Len(t, decoded, maxGunzippedResponseBytes)
+}
+
+func gzipString(t *testing.T, value string) []byte {
+	compressed, err := gzipBody([]byte(value))
+	if err != nil {
+		t.Fatalf("Failed to gzip: %v", err)
+	}
+	return compressed
+}
+
+// TestCircuitBreakerOpen makes sure that bidderAdapter.doRequest skips the endpoints whose circuit is open.
+// TestOpenRTBVersion makes sure that the bidders speaking OpenRTB 2.6 get its fields, and that the requests of the
+// others are converted down to OpenRTB 2.5.
+func TestOpenRTBVersion(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID:   "req-1",
+		Imp:  []openrtb2.Imp{{ID: "imp-1", Rwdd: 1, Video: &openrtb2.Video{Plcmt: adcom1.VideoPlcmtInterstitial}, Ext: json.RawMessage(`{"bidder":{}}`)}},
+		Regs: &openrtb2.Regs{GPP: "DBABL~BVVqAAAAAg", GPPSID: []int8{7}},
+		User: &openrtb2.User{EIDs: []openrtb2.EID{{Source: "example.com", UIDs: []openrtb2.UID{{ID: "id"}}}}},
+	}
+	requestJSON, _ := json.Marshal(request)
+
+	testCases := []struct {
+		description     string
+		info            *config.BidderInfo
+		expectedRequest string
+	}{
+		{
+			description:     "OpenRTB 2.6",
+			info:            &config.BidderInfo{OpenRTBVersion: config.OpenRTBVersion26},
+			expectedRequest: `{"id":"req-1","imp":[{"id":"imp-1","video":{"mimes":null,"plcmt":3},"rwdd":1,"ext":{"bidder":{}}}],"user":{"eids":[{"source":"example.com","uids":[{"id":"id"}]}]},"regs":{"gpp":"DBABL~BVVqAAAAAg","gpp_sid":[7]}}`,
+		},
+		{
+			description:     "OpenRTB 2.5",
+			info:            &config.BidderInfo{OpenRTBVersion: config.OpenRTBVersion25},
+			expectedRequest: `{"id":"req-1","imp":[{"id":"imp-1","video":{"mimes":null,"ext":{"plcmt":3}},"ext":{"bidder":{},"prebid":{"is_rewarded_inventory":1}}}],"user":{"ext":{"eids":[{"source":"example.com","uids":[{"id":"id"}]}]}},"regs":{"ext":{"gpp":"DBABL~BVVqAAAAAg","gpp_sid":[7]}}}`,
+		},
+		{
+			description:     "OpenRTB 2.5 By Default",
+			info:            nil,
+			expectedRequest: `{"id":"req-1","imp":[{"id":"imp-1","video":{"mimes":null,"ext":{"plcmt":3}},"ext":{"bidder":{},"prebid":{"is_rewarded_inventory":1}}}],"user":{"ext":{"eids":[{"source":"example.com","uids":[{"id":"id"}]}]}},"regs":{"ext":{"gpp":"DBABL~BVVqAAAAAg","gpp_sid":[7]}}}`,
+		},
+	}
+
+	for _, test := range testCases {
+		bidderImpl := &mixedMultiBidder{}
+		bidder := adaptBidder(bidderImpl, http.DefaultClient, &config.Configuration{}, &metricsConfig.DummyMetricsEngine{}, openrtb_ext.BidderAppnexus, test.info)
+		bidder.requestBid(context.Background(), request, openrtb_ext.BidderAppnexus, 1.0, currency.NewRateConverter(&http.Client{}, "", time.Duration(0)).Rates(), &adapters.ExtraRequestInfo{}, true, true)
+
+		if assert.NotNil(t, bidderImpl.bidRequest, test.description) {
+			bidRequest, _ := json.Marshal(bidderImpl.bidRequest)
+			assert.JSONEq(t, test.expectedRequest, string(bidRequest), test.description)
+		}
+		unchangedRequest, _ := json.Marshal(request)
+		assert.JSONEq(t, string(requestJSON), string(unchangedRequest), test.description+" - the request of the exchange is left as is")
+	}
+}
+
+func TestCircuitBreakerOpen(t *testing.T) {
+	server := httptest.NewServer(mockHandler(http.StatusServiceUnavailable, "getBody", "primaryBody"))
+	defer server.Close()
+	fallbackServer := httptest.NewServer(mockHandler(200, "getBody", "fallbackBody"))
+	defer fallbackServer.Close()
+
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAdapterCircuitBreaker", openrtb_ext.BidderAppnexus, metrics.CircuitBreakerOpen).Once()
+
+	cfg := &config.Configuration{
+		Adapters: map[string]config.Adapter{
+			"appnexus": {
+				CircuitBreaker: config.AdapterCircuitBreaker{
+					Enabled:        true,
+					WindowSeconds:  60,
+					MinRequests:    1,
+					ErrorRate:      1,
+					OpenSeconds:    60,
+					HalfOpenProbes: 1,
+				},
+			},
+		},
+		Metrics: config.Metrics{Disabled: config.DisabledMetrics{AdapterConnectionMetrics: true}},
+	}
+	bidder := adaptBidder(&mixedMultiBidder{}, server.Client(), cfg, me, openrtb_ext.BidderAppnexus, nil).(*bidderAdapter)
+
+	callInfo := bidder.doRequest(context.Background(), &adapters.RequestData{Method: "POST", Uri: server.URL})
+	if assert.NotNil(t, callInfo.response) {
+		assert.Equal(t, "primaryBody", string(callInfo.response.Body), "the failure opens the circuit")
+	}
+
+	callInfo = bidder.doRequest(context.Background(), &adapters.RequestData{Method: "POST", Uri: server.URL})
+	assert.Nil(t, callInfo.response)
+	assert.Equal(t, errortypes.CircuitBreakerOpenWarningCode, errortypes.ReadCode(callInfo.err))
+
+	callInfo = bidder.doRequest(context.Background(), &adapters.RequestData{Method: "POST", Uri: server.URL, FallbackUri: fallbackServer.URL})
+	if assert.NotNil(t, callInfo.response) {
+		assert.Equal(t, "fallbackBody", string(callInfo.response.Body), "the fallback region is used while the circuit is open")
+	}
+	me.AssertExpectations(t)
+}
+
+// TestBidderCalls makes sure that requestBid records the outcome of its http calls for the analytics modules.
+func TestBidderCalls(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", "responseBody"))
+	defer server.Close()
+
+	bidderImpl := &goodSingleBidder{
+		httpRequest: &adapters.RequestData{
+			Method: "POST",
+			Uri:    server.URL,
+			TapjoyData: adapters.TapjoyData{
+				Bidder:        "appnexus",
+				PlacementType: adapters.Rewarded,
+				Region:        "us_east",
+				SKAN:          adapters.SKAN{Supported: true, Sent: true},
+			},
+		},
+		bidResponse: &adapters.BidderResponse{
+			Bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{Price: 1}, BidType: openrtb_ext.BidTypeVideo},
+				{Bid: &openrtb2.Bid{Price: 3}, BidType: openrtb_ext.BidTypeVideo},
+			},
+		},
+	}
+	bidder := adaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.DummyMetricsEngine{}, openrtb_ext.BidderAppnexus, nil)
+
+	seatBid, _ := bidder.requestBid(context.Background(), &openrtb2.BidRequest{}, "appnexus", 2, currency.NewConstantRates(), &adapters.ExtraRequestInfo{}, true, false)
+	if assert.Len(t, seatBid.bidderCalls, 1) {
+		call := seatBid.bidderCalls[0]
+		assert.Equal(t, "appnexus", call.Bidder)
+		assert.Equal(t, "rewarded", call.PlacementType)
+		assert.Equal(t, "us_east", call.Region)
+		assert.True(t, call.SKANSupported)
+		assert.True(t, call.SKANSent)
+		assert.False(t, call.MRAIDSupported)
+		assert.Equal(t, 200, call.Status)
+		assert.Equal(t, 2, call.BidCount)
+		assert.Equal(t, 6.0, call.Price)
+		assert.Equal(t, "USD", call.Currency)
+		assert.False(t, call.Timeout)
+		assert.Empty(t, call.ErrorType)
+	}
+}
+
+func TestMakeBidderCallErrors(t *testing.T) {
+	call := makeBidderCall("appnexus", &httpCallInfo{
+		request: &adapters.RequestData{},
+		err:     &errortypes.Timeout{Message: "context deadline exceeded"},
+	})
+	assert.True(t, call.Timeout)
+	assert.Equal(t, string(metrics.AdapterErrorTimeout), call.ErrorType)
+	assert.Zero(t, call.Status)
+
+	call = makeBidderCall("appnexus", &httpCallInfo{
+		request:  &adapters.RequestData{},
+		response: &adapters.ResponseData{StatusCode: 503},
+		err:      &errortypes.BadServerResponse{Message: "Server responded with failure status: 503."},
+	})
+	assert.False(t, call.Timeout)
+	assert.Equal(t, string(metrics.AdapterErrorBadServerResponse), call.ErrorType)
+	assert.Equal(t, 503, call.Status)
+
+	recordBidderCallError(call, []error{&errortypes.Warning{Message: "warning"}, &errortypes.BadInput{Message: "bad input"}})
+	assert.Equal(t, string(metrics.AdapterErrorBadServerResponse), call.ErrorType, "the first fatal error is kept")
+
+	call = makeBidderCall("appnexus", &httpCallInfo{
+		request: &adapters.RequestData{},
+		err:     &errortypes.Warning{WarningCode: errortypes.CircuitBreakerOpenWarningCode, Message: "circuit open"},
+	})
+	assert.Equal(t, circuitOpenErrorType, call.ErrorType)
+}
+
 // TestInvalidRequest makes sure that bidderAdapter.doRequest returns errors on bad requests.
 func TestInvalidRequest(t *testing.T) {
 	server := httptest.NewServer(mockHandler(200, "getBody", "postBody"))
@@ -4,12 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"strings"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/analytics"
+	"github.com/prebid/prebid-server/cache/skanidlist"
 	"github.com/prebid/prebid-server/currency"
+	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/openrtb_ext"
 	goCurrency "golang.org/x/text/currency"
 )
@@ -19,21 +23,48 @@ import (
 //
 // The goal here is to make sure that the response contains Bids which are valid given the initial Request,
 // so that Publishers can trust the Bids they get from Prebid Server.
-func addValidatedBidderMiddleware(bidder adaptedBidder) adaptedBidder {
+//
+// The SKADN bids are validated against the SKAN ID List of skanBidder, see skanBidder.
+func addValidatedBidderMiddleware(bidder adaptedBidder, skanBidder openrtb_ext.BidderName) adaptedBidder {
 	return &validatedBidder{
-		bidder: bidder,
+		bidder:     bidder,
+		skanBidder: skanBidder,
 	}
 }
 
 type validatedBidder struct {
-	bidder adaptedBidder
+	bidder     adaptedBidder
+	skanBidder openrtb_ext.BidderName
 }
 
 func (v *validatedBidder) requestBid(ctx context.Context, request *openrtb2.BidRequest, name openrtb_ext.BidderName, bidAdjustment float64, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, accountDebugAllowed, headerDebugAllowed bool) (*pbsOrtbSeatBid, []error) {
-	seatBid, errs := v.bidder.requestBid(ctx, request, name, bidAdjustment, conversions, reqInfo, accountDebugAllowed, headerDebugAllowed)
+	// the adapters rewrite the imps of the request they are given, in place or by replacing them, the bids are
+	// validated against the imps of the exchange
+	bidderRequest := *request
+	bidderRequest.Imp = append([]openrtb2.Imp(nil), request.Imp...)
+	seatBid, errs := v.bidder.requestBid(ctx, &bidderRequest, name, bidAdjustment, conversions, reqInfo, accountDebugAllowed, headerDebugAllowed)
 	if validationErrors := removeInvalidBids(request, seatBid); len(validationErrors) > 0 {
 		errs = append(errs, validationErrors...)
 	}
+	if skadnErrors := removeInvalidSKADNBids(request, seatBid, skanidlist.Get(v.skanBidder), skanidlist.IABIDs(v.skanBidder)); len(skadnErrors) > 0 {
+		errs = append(errs, skadnErrors...)
+	}
+	var blockedCreatives []string
+	if reqInfo != nil {
+		blockedCreatives = reqInfo.BlockedCreatives
+	}
+	if policyErrors := removeBlockedCreatives(request, seatBid, blockedCreatives); len(policyErrors) > 0 {
+		errs = append(errs, policyErrors...)
+	}
+	if reqInfo != nil && reqInfo.EnforceFloors {
+		if floorErrors := removeBidsBelowFloor(request, seatBid, conversions); len(floorErrors) > 0 {
+			errs = append(errs, floorErrors...)
+		}
+	}
+	if dealErrors := removeBidsBelowDealFloor(request, seatBid, conversions); len(dealErrors) > 0 {
+		errs = append(errs, dealErrors...)
+	}
+	countValidBids(seatBid)
 	return seatBid, errs
 }
 
@@ -41,6 +72,30 @@ func (v *validatedBidder) client() *http.Client {
 	return http.DefaultClient
 }
 
+// countValidBids recounts the bids and the highest price of every bidder call on the bids left once the invalid
+// bids are excised, the bids counted on the response of the call and excised since are rejected
+func countValidBids(seatBid *pbsOrtbSeatBid) {
+	if seatBid == nil {
+		return
+	}
+
+	received := make(map[*analytics.BidderCall]int, len(seatBid.bidderCalls))
+	for _, call := range seatBid.bidderCalls {
+		received[call] = call.BidCount
+		call.BidCount = 0
+		call.Price = 0
+	}
+	for _, bid := range seatBid.bids {
+		if bid.bidderCall != nil && bid.bid != nil {
+			bid.bidderCall.BidCount++
+			bid.bidderCall.Price = math.Max(bid.bidderCall.Price, bid.bid.Price)
+		}
+	}
+	for call, count := range received {
+		call.RejectedBidCount = count - call.BidCount
+	}
+}
+
 // validateBids will run some validation checks on the returned bids and excise any invalid bids
 func removeInvalidBids(request *openrtb2.BidRequest, seatBid *pbsOrtbSeatBid) []error {
 	// Exit early if there is nothing to do.
@@ -67,6 +122,113 @@ func removeInvalidBids(request *openrtb2.BidRequest, seatBid *pbsOrtbSeatBid) []
 	return errs
 }
 
+// removeBidsBelowFloor excises the bids priced under the bidfloor of their imp. The bids on a deal of the imp
+// with a bidfloor of its own are left to removeBidsBelowDealFloor, the deal floor replaces the imp floor.
+func removeBidsBelowFloor(request *openrtb2.BidRequest, seatBid *pbsOrtbSeatBid, conversions currency.Conversions) []error {
+	if seatBid == nil || len(seatBid.bids) == 0 {
+		return nil
+	}
+
+	bidCurrency := seatBid.currency
+	if bidCurrency == "" {
+		bidCurrency = defaultFloorCurrency
+	}
+
+	floors := make(map[string]float64, len(request.Imp))
+	var errs []error
+	for _, imp := range request.Imp {
+		if imp.BidFloor <= 0 {
+			continue
+		}
+		floor, err := convertFloor(imp.BidFloor, imp.BidFloorCur, bidCurrency, conversions)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Unable to enforce floor of imp %s: %v", imp.ID, err))
+			continue
+		}
+		floors[imp.ID] = floor
+	}
+
+	deals := dealsWithFloor(request)
+	validBids := make([]*pbsOrtbBid, 0, len(seatBid.bids))
+	for _, bid := range seatBid.bids {
+		if _, onDeal := deals[bid.bid.ImpID][bid.bid.DealID]; onDeal {
+			validBids = append(validBids, bid)
+			continue
+		}
+		if floor, ok := floors[bid.bid.ImpID]; ok && bid.bid.Price < floor {
+			errs = append(errs, &errortypes.BidBelowFloor{
+				Message: fmt.Sprintf("Bid \"%s\" price %.4f %s is below the floor %.4f of imp %s", bid.bid.ID, bid.bid.Price, bidCurrency, floor, bid.bid.ImpID),
+			})
+			continue
+		}
+		validBids = append(validBids, bid)
+	}
+	seatBid.bids = validBids
+	return errs
+}
+
+// removeBidsBelowDealFloor excises the bids on a deal of their imp priced under the bidfloor of the deal
+func removeBidsBelowDealFloor(request *openrtb2.BidRequest, seatBid *pbsOrtbSeatBid, conversions currency.Conversions) []error {
+	if seatBid == nil || len(seatBid.bids) == 0 {
+		return nil
+	}
+
+	bidCurrency := seatBid.currency
+	if bidCurrency == "" {
+		bidCurrency = defaultFloorCurrency
+	}
+
+	deals := dealsWithFloor(request)
+	if len(deals) == 0 {
+		return nil
+	}
+
+	var errs []error
+	validBids := make([]*pbsOrtbBid, 0, len(seatBid.bids))
+	for _, bid := range seatBid.bids {
+		deal, ok := deals[bid.bid.ImpID][bid.bid.DealID]
+		if !ok {
+			validBids = append(validBids, bid)
+			continue
+		}
+		floor, err := convertFloor(deal.BidFloor, deal.BidFloorCur, bidCurrency, conversions)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Unable to enforce floor of deal %s: %v", deal.ID, err))
+			validBids = append(validBids, bid)
+			continue
+		}
+		if bid.bid.Price < floor {
+			errs = append(errs, &errortypes.BidBelowFloor{
+				Message: fmt.Sprintf("Bid \"%s\" price %.4f %s is below the floor %.4f of deal %s", bid.bid.ID, bid.bid.Price, bidCurrency, floor, deal.ID),
+			})
+			continue
+		}
+		validBids = append(validBids, bid)
+	}
+	seatBid.bids = validBids
+	return errs
+}
+
+// dealsWithFloor maps the imp ids to the deals of the imp with a bidfloor, by deal id
+func dealsWithFloor(request *openrtb2.BidRequest) map[string]map[string]openrtb2.Deal {
+	deals := make(map[string]map[string]openrtb2.Deal)
+	for _, imp := range request.Imp {
+		if imp.PMP == nil {
+			continue
+		}
+		for _, deal := range imp.PMP.Deals {
+			if deal.ID == "" || deal.BidFloor <= 0 {
+				continue
+			}
+			if deals[imp.ID] == nil {
+				deals[imp.ID] = make(map[string]openrtb2.Deal)
+			}
+			deals[imp.ID][deal.ID] = deal
+		}
+	}
+	return deals
+}
+
 // validateCurrency will run currency validation checks and return true if it passes, false otherwise.
 func validateCurrency(requestAllowedCurrencies []string, bidCurrency string) error {
 	// Default currency is `USD` by design.
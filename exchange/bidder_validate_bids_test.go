@@ -2,12 +2,16 @@ package exchange
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/analytics"
 	"github.com/prebid/prebid-server/currency"
+	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/openrtb_ext"
 	"github.com/stretchr/testify/assert"
 )
@@ -42,7 +46,7 @@ func TestAllValidBids(t *testing.T) {
 				},
 			},
 		},
-	})
+	}, openrtb_ext.BidderAppnexus)
 	seatBid, errs := bidder.requestBid(context.Background(), &openrtb2.BidRequest{}, openrtb_ext.BidderAppnexus, 1.0, currency.NewConstantRates(), &adapters.ExtraRequestInfo{}, true, false)
 	assert.Len(t, seatBid.bids, 3)
 	assert.Len(t, errs, 0)
@@ -83,7 +87,7 @@ func TestAllBadBids(t *testing.T) {
 				{},
 			},
 		},
-	})
+	}, openrtb_ext.BidderAppnexus)
 	seatBid, errs := bidder.requestBid(context.Background(), &openrtb2.BidRequest{}, openrtb_ext.BidderAppnexus, 1.0, currency.NewConstantRates(), &adapters.ExtraRequestInfo{}, true, false)
 	assert.Len(t, seatBid.bids, 0)
 	assert.Len(t, errs, 5)
@@ -126,7 +130,7 @@ func TestMixedBids(t *testing.T) {
 				{},
 			},
 		},
-	})
+	}, openrtb_ext.BidderAppnexus)
 	seatBid, errs := bidder.requestBid(context.Background(), &openrtb2.BidRequest{}, openrtb_ext.BidderAppnexus, 1.0, currency.NewConstantRates(), &adapters.ExtraRequestInfo{}, true, false)
 	assert.Len(t, seatBid.bids, 2)
 	assert.Len(t, errs, 3)
@@ -232,7 +236,7 @@ func TestCurrencyBids(t *testing.T) {
 				currency: tc.brpCur,
 				bids:     bids,
 			},
-		})
+		}, openrtb_ext.BidderAppnexus)
 
 		expectedValidBids := len(bids)
 		expectedErrs := 0
@@ -253,12 +257,159 @@ func TestCurrencyBids(t *testing.T) {
 	}
 }
 
+func TestBidsBelowFloor(t *testing.T) {
+	newBidder := func() adaptedBidder {
+		return addValidatedBidderMiddleware(&mockAdaptedBidder{
+			bidResponse: &pbsOrtbSeatBid{
+				currency: "EUR",
+				bids: []*pbsOrtbBid{
+					{bid: &openrtb2.Bid{ID: "under", ImpID: "imp1", Price: 0.9, CrID: "creative"}},
+					{bid: &openrtb2.Bid{ID: "over", ImpID: "imp1", Price: 1.1, CrID: "creative"}},
+					{bid: &openrtb2.Bid{ID: "nofloor", ImpID: "imp2", Price: 0.1, CrID: "creative"}},
+				},
+			},
+		}, openrtb_ext.BidderAppnexus)
+	}
+	request := &openrtb2.BidRequest{
+		Cur: []string{"EUR"},
+		Imp: []openrtb2.Imp{
+			{ID: "imp1", BidFloor: 2, BidFloorCur: "USD"},
+			{ID: "imp2"},
+		},
+	}
+	conversions := currency.NewRates(time.Time{}, map[string]map[string]float64{"USD": {"EUR": 0.5}})
+
+	seatBid, errs := newBidder().requestBid(context.Background(), request, openrtb_ext.BidderAppnexus, 1.0, conversions, &adapters.ExtraRequestInfo{EnforceFloors: true}, true, false)
+	if assert.Len(t, seatBid.bids, 2) {
+		assert.Equal(t, "over", seatBid.bids[0].bid.ID)
+		assert.Equal(t, "nofloor", seatBid.bids[1].bid.ID)
+	}
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, errortypes.BidBelowFloorErrorCode, errortypes.ReadCode(errs[0]))
+		assert.False(t, errortypes.ContainsFatalError(errs), "a bid below the floor is a warning")
+	}
+
+	seatBid, errs = newBidder().requestBid(context.Background(), request, openrtb_ext.BidderAppnexus, 1.0, conversions, &adapters.ExtraRequestInfo{}, true, false)
+	assert.Len(t, seatBid.bids, 3, "floors are not enforced")
+	assert.Len(t, errs, 0)
+}
+
+func TestBidsBelowDealFloor(t *testing.T) {
+	bidder := addValidatedBidderMiddleware(&mockAdaptedBidder{
+		bidResponse: &pbsOrtbSeatBid{
+			currency: "EUR",
+			bids: []*pbsOrtbBid{
+				{bid: &openrtb2.Bid{ID: "under", ImpID: "imp1", Price: 0.9, CrID: "creative", DealID: "deal"}},
+				{bid: &openrtb2.Bid{ID: "over", ImpID: "imp1", Price: 1.1, CrID: "creative", DealID: "deal"}},
+				{bid: &openrtb2.Bid{ID: "open", ImpID: "imp1", Price: 0.1, CrID: "creative"}},
+				{bid: &openrtb2.Bid{ID: "nofloor", ImpID: "imp2", Price: 0.1, CrID: "creative", DealID: "deal"}},
+			},
+		},
+	}, openrtb_ext.BidderAppnexus)
+	request := &openrtb2.BidRequest{
+		Cur: []string{"EUR"},
+		Imp: []openrtb2.Imp{
+			{ID: "imp1", PMP: &openrtb2.PMP{Deals: []openrtb2.Deal{{ID: "deal", BidFloor: 2, BidFloorCur: "USD"}}}},
+			{ID: "imp2", PMP: &openrtb2.PMP{Deals: []openrtb2.Deal{{ID: "deal"}}}},
+		},
+	}
+	conversions := currency.NewRates(time.Time{}, map[string]map[string]float64{"USD": {"EUR": 0.5}})
+
+	seatBid, errs := bidder.requestBid(context.Background(), request, openrtb_ext.BidderAppnexus, 1.0, conversions, &adapters.ExtraRequestInfo{}, true, false)
+	if assert.Len(t, seatBid.bids, 3) {
+		assert.Equal(t, "over", seatBid.bids[0].bid.ID)
+		assert.Equal(t, "open", seatBid.bids[1].bid.ID, "the imp floor is not enforced")
+		assert.Equal(t, "nofloor", seatBid.bids[2].bid.ID)
+	}
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, errortypes.BidBelowFloorErrorCode, errortypes.ReadCode(errs[0]))
+		assert.EqualError(t, errs[0], `Bid "under" price 0.9000 EUR is below the floor 1.0000 of deal deal`)
+	}
+}
+
+func TestDealFloorReplacesImpFloor(t *testing.T) {
+	bidder := addValidatedBidderMiddleware(&mockAdaptedBidder{
+		bidResponse: &pbsOrtbSeatBid{
+			bids: []*pbsOrtbBid{
+				{bid: &openrtb2.Bid{ID: "deal", ImpID: "imp1", Price: 3, CrID: "creative", DealID: "deal"}},
+				{bid: &openrtb2.Bid{ID: "open", ImpID: "imp1", Price: 3, CrID: "creative"}},
+				{bid: &openrtb2.Bid{ID: "deal-without-floor", ImpID: "imp1", Price: 3, CrID: "creative", DealID: "nofloor"}},
+			},
+		},
+	}, openrtb_ext.BidderAppnexus)
+	request := &openrtb2.BidRequest{
+		Imp: []openrtb2.Imp{{
+			ID:       "imp1",
+			BidFloor: 5,
+			PMP:      &openrtb2.PMP{Deals: []openrtb2.Deal{{ID: "deal", BidFloor: 2}, {ID: "nofloor"}}},
+		}},
+	}
+
+	seatBid, errs := bidder.requestBid(context.Background(), request, openrtb_ext.BidderAppnexus, 1.0, currency.NewConstantRates(), &adapters.ExtraRequestInfo{EnforceFloors: true}, true, false)
+	if assert.Len(t, seatBid.bids, 1) {
+		assert.Equal(t, "deal", seatBid.bids[0].bid.ID, "the deal floor replaces the imp floor")
+	}
+	if assert.Len(t, errs, 2) {
+		assert.EqualError(t, errs[0], `Bid "open" price 3.0000 USD is below the floor 5.0000 of imp imp1`)
+		assert.EqualError(t, errs[1], `Bid "deal-without-floor" price 3.0000 USD is below the floor 5.0000 of imp imp1`)
+	}
+}
+
+func TestBidsValidatedAgainstExchangeImps(t *testing.T) {
+	bidder := addValidatedBidderMiddleware(&mockAdaptedBidder{
+		bidResponse: &pbsOrtbSeatBid{
+			bids: []*pbsOrtbBid{
+				{bid: &openrtb2.Bid{ID: "skadn", ImpID: "imp1", Price: 1, CrID: "creative", Ext: json.RawMessage(`{"skadn":{"version":"2.0","network":"cdkw7geqsh.skadnetwork","campaign":"45","itunesitem":"123456789","nonce":"473b1a16","timestamp":"1594406341","signature":"MEQCIEQ"}}`)}},
+			},
+		},
+		rewriteImps: true,
+	}, openrtb_ext.BidderAppnexus)
+	request := &openrtb2.BidRequest{
+		Imp: []openrtb2.Imp{
+			{ID: "imp1", Ext: json.RawMessage(`{"prebid":{"skadn":{"version":"2.0","skadnetids":["cdkw7geqsh.skadnetwork"]}}}`)},
+		},
+	}
+
+	seatBid, errs := bidder.requestBid(context.Background(), request, openrtb_ext.BidderAppnexus, 1.0, currency.Conversions(nil), &adapters.ExtraRequestInfo{}, true, false)
+	assert.Len(t, seatBid.bids, 1)
+	assert.Len(t, errs, 0)
+	assert.Equal(t, json.RawMessage(`{"prebid":{"skadn":{"version":"2.0","skadnetids":["cdkw7geqsh.skadnetwork"]}}}`), request.Imp[0].Ext, "the imps of the exchange are not rewritten")
+}
+
+func TestBidderCallCountsValidBids(t *testing.T) {
+	call := &analytics.BidderCall{Bidder: "appnexus", BidCount: 2, Price: 3}
+	bidder := addValidatedBidderMiddleware(&mockAdaptedBidder{
+		bidResponse: &pbsOrtbSeatBid{
+			bids: []*pbsOrtbBid{
+				{bid: &openrtb2.Bid{ID: "valid", ImpID: "imp1", Price: 1, CrID: "creative"}, bidderCall: call},
+				{bid: &openrtb2.Bid{ID: "no-creative", ImpID: "imp1", Price: 3}, bidderCall: call},
+			},
+			bidderCalls: []*analytics.BidderCall{call},
+		},
+	}, openrtb_ext.BidderAppnexus)
+	request := &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}}}
+
+	seatBid, errs := bidder.requestBid(context.Background(), request, openrtb_ext.BidderAppnexus, 1.0, currency.Conversions(nil), &adapters.ExtraRequestInfo{}, true, false)
+	assert.Len(t, seatBid.bids, 1)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, 1, call.BidCount, "the bids entering the auction")
+	assert.Equal(t, 1, call.RejectedBidCount)
+	assert.Equal(t, 1.0, call.Price, "the highest price entering the auction")
+}
+
 type mockAdaptedBidder struct {
 	bidResponse   *pbsOrtbSeatBid
 	errorResponse []error
+	// rewriteImps rewrites the imps of the request in place, as pruneImps does, then replaces them, as the
+	// adapters making a request per imp do
+	rewriteImps bool
 }
 
 func (b *mockAdaptedBidder) requestBid(ctx context.Context, request *openrtb2.BidRequest, name openrtb_ext.BidderName, bidAdjustment float64, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, accountDebugAllowed, headerDebugAllowed bool) (*pbsOrtbSeatBid, []error) {
+	if b.rewriteImps {
+		request.Imp[0].Ext = json.RawMessage(`{"rewarded":0}`)
+		request.Imp = []openrtb2.Imp{{ID: request.Imp[0].ID, Ext: request.Imp[0].Ext}}
+	}
 	return b.bidResponse, b.errorResponse
 }
 
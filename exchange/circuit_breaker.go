@@ -0,0 +1,192 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/metrics"
+)
+
+// circuitOpenErrorType is the analytics error type of the requests skipped by an open circuit
+const circuitOpenErrorType = "circuit_open"
+
+type callOutcome int
+
+const (
+	callSucceeded callOutcome = iota
+	callFailed
+	callTimedOut
+	// callCancelled is a call given up by the caller, e.g. the losing call of a hedged request, which tells
+	// nothing about the endpoint
+	callCancelled
+)
+
+// circuitBreakers guards every endpoint of a bidder with its own circuit. A nil circuitBreakers lets
+// every request through.
+type circuitBreakers struct {
+	cfg config.AdapterCircuitBreaker
+	// onStateChange is called with the endpoint and the new state of its circuit, the lock held
+	onStateChange func(endpoint string, state metrics.CircuitBreakerState)
+	now           func() time.Time
+
+	mux      sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state metrics.CircuitBreakerState
+
+	windowStart time.Time
+	requests    int
+	errors      int
+	timeouts    int
+
+	openedAt       time.Time
+	probes         int
+	probeSuccesses int
+}
+
+func newCircuitBreakers(cfg config.AdapterCircuitBreaker, onStateChange func(string, metrics.CircuitBreakerState)) *circuitBreakers {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &circuitBreakers{
+		cfg:           cfg,
+		onStateChange: onStateChange,
+		now:           time.Now,
+		circuits:      make(map[string]*circuit),
+	}
+}
+
+// allow returns false if the circuit of the endpoint is open, or half-open with all its probes in flight
+func (cb *circuitBreakers) allow(endpoint string) bool {
+	if cb == nil {
+		return true
+	}
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+
+	c := cb.getCircuit(endpoint)
+	if c.state == metrics.CircuitBreakerOpen {
+		if cb.now().Sub(c.openedAt) < time.Duration(cb.cfg.OpenSeconds)*time.Second {
+			return false
+		}
+		c.probes = 0
+		c.probeSuccesses = 0
+		cb.setState(endpoint, c, metrics.CircuitBreakerHalfOpen)
+	}
+	if c.state == metrics.CircuitBreakerHalfOpen {
+		if c.probes >= cb.cfg.HalfOpenProbes {
+			return false
+		}
+		c.probes++
+	}
+	return true
+}
+
+// record updates the circuit of the endpoint with the outcome of a request allowed through
+func (cb *circuitBreakers) record(endpoint string, outcome callOutcome) {
+	if cb == nil {
+		return
+	}
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+
+	c := cb.getCircuit(endpoint)
+	if outcome == callCancelled {
+		// the probe did not tell whether the endpoint is back up, another request may probe it
+		if c.state == metrics.CircuitBreakerHalfOpen && c.probes > 0 {
+			c.probes--
+		}
+		return
+	}
+	switch c.state {
+	case metrics.CircuitBreakerClosed:
+		now := cb.now()
+		if now.Sub(c.windowStart) >= time.Duration(cb.cfg.WindowSeconds)*time.Second {
+			c.resetWindow(now)
+		}
+		c.requests++
+		switch outcome {
+		case callFailed:
+			c.errors++
+		case callTimedOut:
+			c.timeouts++
+		}
+		if c.requests >= cb.cfg.MinRequests && (exceedsRate(c.errors, c.requests, cb.cfg.ErrorRate) || exceedsRate(c.timeouts, c.requests, cb.cfg.TimeoutRate)) {
+			c.openedAt = now
+			cb.setState(endpoint, c, metrics.CircuitBreakerOpen)
+		}
+	case metrics.CircuitBreakerHalfOpen:
+		if outcome != callSucceeded {
+			c.openedAt = cb.now()
+			cb.setState(endpoint, c, metrics.CircuitBreakerOpen)
+			return
+		}
+		c.probeSuccesses++
+		if c.probeSuccesses >= cb.cfg.HalfOpenProbes {
+			c.resetWindow(cb.now())
+			cb.setState(endpoint, c, metrics.CircuitBreakerClosed)
+		}
+	}
+	// requests which were in flight when the circuit opened are ignored
+}
+
+func (cb *circuitBreakers) getCircuit(endpoint string) *circuit {
+	c, ok := cb.circuits[endpoint]
+	if !ok {
+		c = &circuit{state: metrics.CircuitBreakerClosed, windowStart: cb.now()}
+		cb.circuits[endpoint] = c
+	}
+	return c
+}
+
+func (cb *circuitBreakers) setState(endpoint string, c *circuit, state metrics.CircuitBreakerState) {
+	c.state = state
+	if cb.onStateChange != nil {
+		cb.onStateChange(endpoint, state)
+	}
+}
+
+func (c *circuit) resetWindow(now time.Time) {
+	c.windowStart = now
+	c.requests = 0
+	c.errors = 0
+	c.timeouts = 0
+}
+
+func exceedsRate(count, total int, rate float64) bool {
+	return rate > 0 && float64(count) >= rate*float64(total)
+}
+
+// circuitEndpoint is the key of the circuit guarding a request uri. The query string is left out as
+// bidders often pass request specific values in it.
+func circuitEndpoint(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+	return u.Scheme + "://" + u.Host + u.Path
+}
+
+// httpCallOutcome classifies an http call for the circuit breakers. Responses under 500 mean the endpoint is up.
+func httpCallOutcome(info *httpCallInfo) callOutcome {
+	if info.err == nil {
+		return callSucceeded
+	}
+	if _, ok := info.err.(*errortypes.Timeout); ok {
+		return callTimedOut
+	}
+	if errors.Is(info.err, context.Canceled) {
+		return callCancelled
+	}
+	if info.response != nil && info.response.StatusCode < 500 {
+		return callSucceeded
+	}
+	return callFailed
+}
@@ -0,0 +1,164 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreakers(t *testing.T) {
+	var transitions []metrics.CircuitBreakerState
+	breakers := newCircuitBreakers(config.AdapterCircuitBreaker{
+		Enabled:        true,
+		WindowSeconds:  10,
+		MinRequests:    4,
+		ErrorRate:      0.5,
+		TimeoutRate:    0.75,
+		OpenSeconds:    30,
+		HalfOpenProbes: 2,
+	}, func(endpoint string, state metrics.CircuitBreakerState) {
+		assert.Equal(t, "https://bidder.com/bid", endpoint)
+		transitions = append(transitions, state)
+	})
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	breakers.now = func() time.Time { return now }
+	endpoint := "https://bidder.com/bid"
+
+	// failures of an expired window are forgotten
+	breakers.record(endpoint, callFailed)
+	breakers.record(endpoint, callFailed)
+	now = now.Add(10 * time.Second)
+	breakers.record(endpoint, callSucceeded)
+	breakers.record(endpoint, callFailed)
+	breakers.record(endpoint, callSucceeded)
+	assert.True(t, breakers.allow(endpoint), "under min requests")
+	assert.Empty(t, transitions)
+
+	breakers.record(endpoint, callFailed)
+	assert.Equal(t, []metrics.CircuitBreakerState{metrics.CircuitBreakerOpen}, transitions, "2 errors out of 4 requests")
+	assert.False(t, breakers.allow(endpoint))
+	assert.True(t, breakers.allow("https://other.bidder.com/bid"), "every endpoint has its own circuit")
+
+	now = now.Add(30 * time.Second)
+	assert.True(t, breakers.allow(endpoint), "first probe")
+	assert.True(t, breakers.allow(endpoint), "second probe")
+	assert.False(t, breakers.allow(endpoint), "probes in flight")
+	breakers.record(endpoint, callSucceeded)
+	breakers.record(endpoint, callFailed)
+	assert.Equal(t, []metrics.CircuitBreakerState{
+		metrics.CircuitBreakerOpen,
+		metrics.CircuitBreakerHalfOpen,
+		metrics.CircuitBreakerOpen,
+	}, transitions, "a failed probe opens the circuit again")
+	assert.False(t, breakers.allow(endpoint))
+
+	now = now.Add(30 * time.Second)
+	assert.True(t, breakers.allow(endpoint))
+	assert.True(t, breakers.allow(endpoint))
+	breakers.record(endpoint, callSucceeded)
+	breakers.record(endpoint, callSucceeded)
+	assert.Equal(t, metrics.CircuitBreakerClosed, transitions[len(transitions)-1], "all probes succeeded")
+
+	for i := 0; i < 3; i++ {
+		breakers.record(endpoint, callTimedOut)
+	}
+	breakers.record(endpoint, callSucceeded)
+	assert.Equal(t, metrics.CircuitBreakerOpen, transitions[len(transitions)-1], "3 timeouts out of 4 requests")
+}
+
+func TestCircuitBreakersCancelledCall(t *testing.T) {
+	breakers := newCircuitBreakers(config.AdapterCircuitBreaker{
+		Enabled:        true,
+		WindowSeconds:  10,
+		MinRequests:    1,
+		ErrorRate:      0.5,
+		OpenSeconds:    30,
+		HalfOpenProbes: 1,
+	}, nil)
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	breakers.now = func() time.Time { return now }
+	endpoint := "https://bidder.com/bid"
+
+	breakers.record(endpoint, callCancelled)
+	assert.True(t, breakers.allow(endpoint), "a cancelled call is not an error")
+
+	breakers.record(endpoint, callFailed)
+	now = now.Add(30 * time.Second)
+	assert.True(t, breakers.allow(endpoint), "probe")
+	breakers.record(endpoint, callCancelled)
+	assert.True(t, breakers.allow(endpoint), "the cancelled probe is given back")
+	breakers.record(endpoint, callSucceeded)
+	assert.Equal(t, metrics.CircuitBreakerClosed, breakers.circuits[endpoint].state)
+}
+
+func TestCircuitBreakersDisabled(t *testing.T) {
+	breakers := newCircuitBreakers(config.AdapterCircuitBreaker{}, nil)
+	assert.Nil(t, breakers)
+
+	breakers.record("https://bidder.com/bid", callFailed)
+	assert.True(t, breakers.allow("https://bidder.com/bid"))
+}
+
+func TestCircuitEndpoint(t *testing.T) {
+	assert.Equal(t, "https://bidder.com/bid", circuitEndpoint("https://bidder.com/bid?pubid=12"))
+	assert.Equal(t, "http://bidder.com:8080/bid", circuitEndpoint("http://bidder.com:8080/bid"))
+}
+
+func TestHttpCallOutcome(t *testing.T) {
+	testCases := []struct {
+		description string
+		info        *httpCallInfo
+		expected    callOutcome
+	}{
+		{
+			description: "success",
+			info:        &httpCallInfo{response: &adapters.ResponseData{StatusCode: 204}},
+			expected:    callSucceeded,
+		},
+		{
+			description: "4xx",
+			info:        &httpCallInfo{response: &adapters.ResponseData{StatusCode: 400}, err: &errortypes.BadServerResponse{}},
+			expected:    callSucceeded,
+		},
+		{
+			description: "5xx",
+			info:        &httpCallInfo{response: &adapters.ResponseData{StatusCode: 503}, err: &errortypes.BadServerResponse{}},
+			expected:    callFailed,
+		},
+		{
+			description: "connection error",
+			info:        &httpCallInfo{err: errors.New("connection refused")},
+			expected:    callFailed,
+		},
+		{
+			description: "timeout",
+			info:        &httpCallInfo{err: &errortypes.Timeout{}},
+			expected:    callTimedOut,
+		},
+		{
+			description: "cancelled",
+			info:        &httpCallInfo{err: fmt.Errorf("Post https://bidder.com/bid: %w", context.Canceled)},
+			expected:    callCancelled,
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, httpCallOutcome(test.info), test.description)
+	}
+}
+
+func TestCircuitOpenNotAnAdapterError(t *testing.T) {
+	circuitOpen := &errortypes.Warning{WarningCode: errortypes.CircuitBreakerOpenWarningCode, Message: "circuit open"}
+
+	assert.Nil(t, errorsToMetric([]error{circuitOpen}))
+	assert.Equal(t, map[metrics.AdapterError]struct{}{metrics.AdapterErrorTimeout: {}},
+		errorsToMetric([]error{circuitOpen, &errortypes.Timeout{}}))
+}
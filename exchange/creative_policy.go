@@ -0,0 +1,141 @@
+package exchange
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/metrics"
+)
+
+// removeBlockedCreatives excises the bids whose creative is blocked by the request, as bidders are free to
+// ignore its blocklists: bid.adomain against badv, bid.cat against bcat, bid.attr against the battr of the
+// imp and bid.bundle against bapp. blockedCreatives are the creative ids blocked by the account.
+func removeBlockedCreatives(request *openrtb2.BidRequest, seatBid *pbsOrtbSeatBid, blockedCreatives []string) []error {
+	if seatBid == nil || len(seatBid.bids) == 0 {
+		return nil
+	}
+
+	var blockedAttrs map[string][]adcom1.CreativeAttribute
+	for _, imp := range request.Imp {
+		if attrs := impBlockedAttributes(&imp); len(attrs) > 0 {
+			if blockedAttrs == nil {
+				blockedAttrs = make(map[string][]adcom1.CreativeAttribute, len(request.Imp))
+			}
+			blockedAttrs[imp.ID] = attrs
+		}
+	}
+
+	var errs []error
+	validBids := make([]*pbsOrtbBid, 0, len(seatBid.bids))
+	for _, bid := range seatBid.bids {
+		if err := checkCreativePolicy(request, bid.bid, blockedAttrs[bid.bid.ImpID], blockedCreatives); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		validBids = append(validBids, bid)
+	}
+	seatBid.bids = validBids
+	return errs
+}
+
+func checkCreativePolicy(request *openrtb2.BidRequest, bid *openrtb2.Bid, blockedAttrs []adcom1.CreativeAttribute, blockedCreatives []string) error {
+	for _, domain := range bid.ADomain {
+		if blocked, ok := matchBlockedDomain(request.BAdv, domain); ok {
+			return blockedCreative(bid, metrics.CreativeBlocklistAdvertiser, fmt.Sprintf("advertiser %s is blocked by %s", domain, blocked))
+		}
+	}
+	for _, cat := range bid.Cat {
+		if blocked, ok := matchBlockedCategory(request.BCat, cat); ok {
+			return blockedCreative(bid, metrics.CreativeBlocklistCategory, fmt.Sprintf("category %s is blocked by %s", cat, blocked))
+		}
+	}
+	for _, attr := range bid.Attr {
+		for _, blocked := range blockedAttrs {
+			if attr == blocked {
+				return blockedCreative(bid, metrics.CreativeBlocklistAttribute, fmt.Sprintf("attribute %d is blocked", attr))
+			}
+		}
+	}
+	if bid.Bundle != "" {
+		for _, blocked := range request.BApp {
+			if strings.EqualFold(bid.Bundle, blocked) {
+				return blockedCreative(bid, metrics.CreativeBlocklistApp, fmt.Sprintf("app %s is blocked", bid.Bundle))
+			}
+		}
+	}
+	for _, blocked := range blockedCreatives {
+		if bid.CrID == blocked {
+			return blockedCreative(bid, metrics.CreativeBlocklistCreative, fmt.Sprintf("creative %s is blocked by the account", bid.CrID))
+		}
+	}
+	return nil
+}
+
+func blockedCreative(bid *openrtb2.Bid, blocklist metrics.CreativeBlocklist, reason string) error {
+	return &errortypes.BlockedCreative{
+		Message:   fmt.Sprintf("Bid \"%s\" dropped: %s", bid.ID, reason),
+		Blocklist: string(blocklist),
+	}
+}
+
+// impBlockedAttributes returns the battr of every media type of the imp
+func impBlockedAttributes(imp *openrtb2.Imp) []adcom1.CreativeAttribute {
+	var attrs []adcom1.CreativeAttribute
+	if imp.Banner != nil {
+		attrs = append(attrs, imp.Banner.BAttr...)
+	}
+	if imp.Video != nil {
+		attrs = append(attrs, imp.Video.BAttr...)
+	}
+	if imp.Audio != nil {
+		attrs = append(attrs, imp.Audio.BAttr...)
+	}
+	if imp.Native != nil {
+		attrs = append(attrs, imp.Native.BAttr...)
+	}
+	return attrs
+}
+
+// matchBlockedDomain returns the entry of badv blocking the domain, which blocks its subdomains too. The
+// domains are compared case insensitively, regardless of a scheme or a leading "www.".
+func matchBlockedDomain(badv []string, domain string) (string, bool) {
+	domain = normalizeDomain(domain)
+	if domain == "" {
+		return "", false
+	}
+	for _, blocked := range badv {
+		normalized := normalizeDomain(blocked)
+		if normalized == "" {
+			continue
+		}
+		if domain == normalized || strings.HasSuffix(domain, "."+normalized) {
+			return blocked, true
+		}
+	}
+	return "", false
+}
+
+func normalizeDomain(domain string) string {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if i := strings.Index(domain, "://"); i >= 0 {
+		domain = domain[i+3:]
+	}
+	if i := strings.IndexAny(domain, "/?#"); i >= 0 {
+		domain = domain[:i]
+	}
+	return strings.TrimPrefix(domain, "www.")
+}
+
+// matchBlockedCategory returns the entry of bcat blocking the IAB category, a tier 1 category, e.g. IAB7,
+// blocks its subcategories, e.g. IAB7-1.
+func matchBlockedCategory(bcat []string, cat string) (string, bool) {
+	for _, blocked := range bcat {
+		if strings.EqualFold(cat, blocked) || (len(cat) > len(blocked) && strings.EqualFold(cat[:len(blocked)+1], blocked+"-")) {
+			return blocked, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,110 @@
+package exchange
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/metrics"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveBlockedCreatives(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		Imp: []openrtb2.Imp{
+			{ID: "banner-imp", Banner: &openrtb2.Banner{BAttr: []adcom1.CreativeAttribute{adcom1.AttrAudioAuto}}},
+			{ID: "video-imp", Video: &openrtb2.Video{BAttr: []adcom1.CreativeAttribute{adcom1.AttrHasSkipButton}}},
+		},
+		BAdv: []string{"Blocked.com"},
+		BCat: []string{"IAB25", "IAB7-39"},
+		BApp: []string{"com.blocked.app"},
+	}
+
+	testCases := []struct {
+		description       string
+		bid               openrtb2.Bid
+		expectedBlocklist metrics.CreativeBlocklist
+	}{
+		{
+			description: "allowed",
+			bid:         openrtb2.Bid{ImpID: "banner-imp", ADomain: []string{"allowed.com", "notblocked.com"}, Cat: []string{"IAB7", "IAB7-3", "IAB250"}, Attr: []adcom1.CreativeAttribute{adcom1.AttrHasSkipButton}, Bundle: "com.allowed.app"},
+		},
+		{
+			description:       "blocked advertiser",
+			bid:               openrtb2.Bid{ImpID: "banner-imp", ADomain: []string{"allowed.com", "blocked.com"}},
+			expectedBlocklist: metrics.CreativeBlocklistAdvertiser,
+		},
+		{
+			description:       "subdomain of a blocked advertiser",
+			bid:               openrtb2.Bid{ImpID: "banner-imp", ADomain: []string{"https://www.ads.BLOCKED.com/landing"}},
+			expectedBlocklist: metrics.CreativeBlocklistAdvertiser,
+		},
+		{
+			description:       "blocked category",
+			bid:               openrtb2.Bid{ImpID: "banner-imp", Cat: []string{"IAB7-39"}},
+			expectedBlocklist: metrics.CreativeBlocklistCategory,
+		},
+		{
+			description:       "subcategory of a blocked category",
+			bid:               openrtb2.Bid{ImpID: "banner-imp", Cat: []string{"iab25-3"}},
+			expectedBlocklist: metrics.CreativeBlocklistCategory,
+		},
+		{
+			description:       "attribute blocked by the imp",
+			bid:               openrtb2.Bid{ImpID: "video-imp", Attr: []adcom1.CreativeAttribute{adcom1.AttrHasSkipButton}},
+			expectedBlocklist: metrics.CreativeBlocklistAttribute,
+		},
+		{
+			description:       "blocked app",
+			bid:               openrtb2.Bid{ImpID: "banner-imp", Bundle: "com.blocked.app"},
+			expectedBlocklist: metrics.CreativeBlocklistApp,
+		},
+		{
+			description:       "creative blocked by the account",
+			bid:               openrtb2.Bid{ImpID: "banner-imp", CrID: "blocked-creative"},
+			expectedBlocklist: metrics.CreativeBlocklistCreative,
+		},
+	}
+
+	for _, test := range testCases {
+		bid := test.bid
+		bid.ID = "bid"
+		seatBid := &pbsOrtbSeatBid{bids: []*pbsOrtbBid{{bid: &bid}}}
+
+		errs := removeBlockedCreatives(request, seatBid, []string{"blocked-creative"})
+		if test.expectedBlocklist == "" {
+			assert.Len(t, seatBid.bids, 1, test.description)
+			assert.Empty(t, errs, test.description)
+			continue
+		}
+		assert.Empty(t, seatBid.bids, test.description)
+		if assert.Len(t, errs, 1, test.description) {
+			assert.Equal(t, errortypes.BlockedCreativeErrorCode, errortypes.ReadCode(errs[0]), test.description)
+			assert.Equal(t, string(test.expectedBlocklist), errs[0].(*errortypes.BlockedCreative).Blocklist, test.description)
+		}
+	}
+}
+
+func TestRecordBlockedCreatives(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAdapterBlockedCreative", openrtb_ext.BidderAppnexus, metrics.CreativeBlocklistCategory).Twice()
+	me.On("RecordAdapterBlockedCreative", openrtb_ext.BidderAppnexus, metrics.CreativeBlocklistAdvertiser).Once()
+
+	recordBlockedCreatives(me, openrtb_ext.BidderAppnexus, []error{
+		&errortypes.BlockedCreative{Blocklist: "bcat"},
+		&errortypes.BlockedCreative{Blocklist: "badv"},
+		&errortypes.BidBelowFloor{},
+		errors.New("other"),
+		&errortypes.BlockedCreative{Blocklist: "bcat"},
+	})
+	me.AssertExpectations(t)
+}
+
+func TestBlockedCreativesAreNotAdapterErrors(t *testing.T) {
+	assert.Nil(t, errorsToMetric([]error{&errortypes.BlockedCreative{Blocklist: "badv"}}))
+	assert.Equal(t, map[metrics.AdapterError]struct{}{metrics.AdapterErrorBidBelowFloor: {}},
+		errorsToMetric([]error{&errortypes.BlockedCreative{Blocklist: "badv"}, &errortypes.BidBelowFloor{}}))
+}
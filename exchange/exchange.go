@@ -14,15 +14,17 @@ import (
 	"strings"
 	"time"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
 	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
 	"github.com/prebid/prebid-server/stored_requests"
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/gofrs/uuid"
 	"github.com/golang/glog"
 	"github.com/prebid/prebid-server/adapters"
-	"github.com/prebid/prebid-server/cache/skanidlist"
+	"github.com/prebid/prebid-server/analytics"
+	"github.com/prebid/prebid-server/biddercontrol"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/currency"
 	"github.com/prebid/prebid-server/errortypes"
@@ -67,6 +69,12 @@ type exchange struct {
 	privacyConfig     config.Privacy
 	categoriesFetcher stored_requests.CategoryFetcher
 	bidIDGenerator    BidIDGenerator
+	// bidderControls are the runtime rules disabling or throttling bidders, nil if there are none
+	bidderControls *biddercontrol.Controls
+	// timeoutBudgets gives every bidder its own deadline, nil unless adaptive timeouts are enabled
+	timeoutBudgets *timeoutBudgets
+	// seats are the seats set in the adapter config only, mapped to the core bidder serving them
+	seats map[openrtb_ext.BidderName]openrtb_ext.BidderName
 }
 
 // Container to pass out response ext data from the GetAllBids goroutines back into the main thread
@@ -77,6 +85,11 @@ type seatResponseExtra struct {
 	// httpCalls is the list of debugging info. It should only be populated if the request.test == 1.
 	// This will become response.ext.debug.httpcalls.{bidder} on the final Response.
 	HttpCalls []*openrtb_ext.ExtHttpCall
+	// BidderCalls is the outcome of every http call made to the bidder, for the analytics modules.
+	BidderCalls []*analytics.BidderCall
+	// TimeoutBudget is the time the bidder was given, nil unless adaptive timeouts are enabled. This will become
+	// response.ext.debug.timeoutbudgets.{bidder} on the final Response.
+	TimeoutBudget *openrtb_ext.ExtTimeoutBudget
 }
 
 type bidResponseWrapper struct {
@@ -113,7 +126,7 @@ func (randomDeduplicateBidBooleanGenerator) Generate() bool {
 	return rand.Intn(100) < 50
 }
 
-func NewExchange(adapters map[openrtb_ext.BidderName]adaptedBidder, cache prebid_cache_client.Client, cfg *config.Configuration, metricsEngine metrics.MetricsEngine, infos config.BidderInfos, gDPR gdpr.Permissions, currencyConverter *currency.RateConverter, categoriesFetcher stored_requests.CategoryFetcher) Exchange {
+func NewExchange(adapters map[openrtb_ext.BidderName]adaptedBidder, cache prebid_cache_client.Client, cfg *config.Configuration, metricsEngine metrics.MetricsEngine, infos config.BidderInfos, gDPR gdpr.Permissions, currencyConverter *currency.RateConverter, categoriesFetcher stored_requests.CategoryFetcher, bidderControls *biddercontrol.Controls) Exchange {
 	gdprDefaultValue := gdpr.SignalYes
 	if cfg.GDPR.DefaultValue == "0" {
 		gdprDefaultValue = gdpr.SignalNo
@@ -121,6 +134,7 @@ func NewExchange(adapters map[openrtb_ext.BidderName]adaptedBidder, cache prebid
 
 	return &exchange{
 		adapterMap:        adapters,
+		bidderControls:    bidderControls,
 		bidderInfo:        infos,
 		cache:             cache,
 		cacheTime:         time.Duration(cfg.CacheURL.ExpectedTimeMillis) * time.Millisecond,
@@ -130,12 +144,15 @@ func NewExchange(adapters map[openrtb_ext.BidderName]adaptedBidder, cache prebid
 		gDPR:              gDPR,
 		me:                metricsEngine,
 		gdprDefaultValue:  gdprDefaultValue,
+		timeoutBudgets:    newTimeoutBudgets(cfg.AuctionTimeouts.Adaptive),
 		privacyConfig: config.Privacy{
 			CCPA: cfg.CCPA,
 			GDPR: cfg.GDPR,
+			GPP:  cfg.GPP,
 			LMT:  cfg.LMT,
 		},
 		bidIDGenerator: &bidIDGenerator{cfg.GenerateBidID},
+		seats:          config.AdapterSeats(cfg.Adapters),
 	}
 }
 
@@ -150,6 +167,15 @@ type AuctionRequest struct {
 	Warnings                   []error
 	GlobalPrivacyControlHeader string
 
+	// BidderCalls, when not nil, receives the outcome of every http call made to the bidders
+	// so the endpoint can hand them to the analytics modules.
+	BidderCalls *[]*analytics.BidderCall
+	// DealDeliveries, when not nil, receives the account deals offered to the bidders and their bids on them.
+	DealDeliveries *[]*analytics.DealDelivery
+	// StoredImpDeals holds the imp.ext.prebid.deals of the stored imps, by imp id. They are collected by the
+	// endpoint while it merges the stored imps, the deals the request sets itself are dropped.
+	StoredImpDeals map[string]json.RawMessage
+
 	// LegacyLabels is included here for temporary compatability with cleanOpenRTBRequests
 	// in HoldAuction until we get to factoring it away. Do not use for anything new.
 	LegacyLabels metrics.Labels
@@ -197,8 +223,27 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 	// Make our best guess if GDPR applies
 	gdprDefaultValue := e.parseGDPRDefaultValue(r.BidRequest)
 
+	// Get currency rates conversions for the auction
+	conversions := e.getAuctionCurrencyRates(requestExt.Prebid.CurrencyConversions)
+
+	// Classify the imps before they are cleaned for the bidders
+	placements := classifyPlacements(r.BidRequest.Imp)
+
+	// Resolve the floor of every imp from request.ext.prebid.floors
+	floors, floorErrs := resolveFloors(r.BidRequest, requestExt.Prebid.Floors, placements, conversions)
+
 	// Slice of BidRequests, each a copy of the original cleaned to only contain bidder data for the named bidder
-	bidderRequests, privacyLabels, errs := cleanOpenRTBRequests(ctx, r, requestExt, e.gDPR, e.me, gdprDefaultValue, e.privacyConfig, &r.Account)
+	bidderRequests, privacyLabels, errs := cleanOpenRTBRequests(ctx, r, requestExt, e.gDPR, e.me, gdprDefaultValue, e.privacyConfig, &r.Account, e.seats)
+	errs = append(errs, floorErrs...)
+	errs = append(errs, applyBidderFloors(bidderRequests, floors, e.bidderInfo, conversions)...)
+	errs = append(errs, writeBidderGPP(bidderRequests, e.bidderInfo)...)
+
+	// Keep the bidders unable to attribute through SKAdNetwork from the iOS requests without ATT consent
+	bidderRequests, skanRouting := routeSKAN(r.BidRequest, bidderRequests, r.Account.Auction.SKANRouting, e.bidderInfo)
+
+	// Offer the deals of the account and of the stored imps to the bidders they are made with
+	accountDeals, dealErrs := injectAccountDeals(r.BidRequest, bidderRequests, r.Account.Auction.Deals, r.StoredImpDeals, placements)
+	errs = append(errs, dealErrs...)
 
 	e.me.RecordRequestPrivacy(privacyLabels)
 
@@ -210,15 +255,19 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 	auctionCtx, cancel := e.makeAuctionContext(ctx, cacheInstructions.cacheBids)
 	defer cancel()
 
-	// Get currency rates conversions for the auction
-	conversions := e.getAuctionCurrencyRates(requestExt.Prebid.CurrencyConversions)
-
-	adapterBids, adapterExtra, anyBidsReturned := e.getAllBids(auctionCtx, bidderRequests, bidAdjustmentFactors, conversions, r.Account.DebugAllow, r.GlobalPrivacyControlHeader, debugLog.DebugOverride)
+	adapterBids, adapterExtra, anyBidsReturned := e.getAllBids(auctionCtx, bidderRequests, bidAdjustmentFactors, conversions, r.Account.DebugAllow, r.GlobalPrivacyControlHeader, debugLog.DebugOverride, requestExt.Prebid.Floors.GetEnforcePBS(), placements, r.Account.Auction.BlockedCreatives)
+	if r.BidderCalls != nil {
+		for _, extra := range adapterExtra {
+			*r.BidderCalls = append(*r.BidderCalls, extra.BidderCalls...)
+		}
+	}
 
 	var auc *auction
 	var cacheErrs []error
 	var bidResponseExt *openrtb_ext.ExtBidResponse
 	if anyBidsReturned {
+		accountDeals.markBids(adapterBids)
+		skanRouting.removeDeprioritizedBids(adapterBids)
 
 		var bidCategory map[string]string
 		//If includebrandcategory is present in ext then CE feature is on.
@@ -234,6 +283,9 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 			}
 		}
 
+		multiBids := getMultiBids(requestExt)
+		applyMaxBids(adapterBids, multiBids, targData != nil && targData.preferDeals)
+
 		if e.bidIDGenerator.Enabled() {
 			for _, seatBid := range adapterBids {
 				for _, pbsBid := range seatBid.bids {
@@ -250,7 +302,7 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 
 		if targData != nil {
 			// A non-nil auction is only needed if targeting is active. (It is used below this block to extract cache keys)
-			auc = newAuction(adapterBids, len(r.BidRequest.Imp), targData.preferDeals)
+			auc = newAuction(adapterBids, len(r.BidRequest.Imp), targData.preferDeals, multiBids)
 			auc.setRoundedPrices(targData.priceGranularity)
 
 			if requestExt.Prebid.SupportDeals {
@@ -258,7 +310,7 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 				errs = append(errs, dealErrs...)
 			}
 
-			bidResponseExt = e.makeExtBidResponse(adapterBids, adapterExtra, r, debugInfo, errs)
+			bidResponseExt = e.makeExtBidResponse(adapterBids, adapterExtra, r, debugInfo, errs, skanRouting)
 			if debugLog.DebugEnabledOrOverridden {
 				if bidRespExtBytes, err := json.Marshal(bidResponseExt); err == nil {
 					debugLog.Data.Response = string(bidRespExtBytes)
@@ -276,9 +328,9 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 			targData.setTargeting(auc, r.BidRequest.App != nil, bidCategory)
 
 		}
-		bidResponseExt = e.makeExtBidResponse(adapterBids, adapterExtra, r, debugInfo, errs)
+		bidResponseExt = e.makeExtBidResponse(adapterBids, adapterExtra, r, debugInfo, errs, skanRouting)
 	} else {
-		bidResponseExt = e.makeExtBidResponse(adapterBids, adapterExtra, r, debugInfo, errs)
+		bidResponseExt = e.makeExtBidResponse(adapterBids, adapterExtra, r, debugInfo, errs, skanRouting)
 
 		if debugLog.DebugEnabledOrOverridden {
 
@@ -291,6 +343,10 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 		}
 	}
 
+	if r.DealDeliveries != nil {
+		*r.DealDeliveries = accountDeals.deliveries(adapterBids, len(r.BidRequest.Imp), auc)
+	}
+
 	if !r.Account.DebugAllow && requestDebugInfo && !debugLog.DebugOverride {
 		accountDebugDisabledWarning := openrtb_ext.ExtBidderMessage{
 			Code:    errortypes.AccountLevelDebugDisabledWarningCode,
@@ -346,6 +402,15 @@ func recordImpMetrics(bidRequest *openrtb2.BidRequest, metricsEngine metrics.Met
 	}
 }
 
+// classifyPlacements maps every imp id to its placement, given to the adapters through adapters.ExtraRequestInfo
+func classifyPlacements(imps []openrtb2.Imp) map[string]adapters.Placement {
+	placements := make(map[string]adapters.Placement, len(imps))
+	for i := range imps {
+		placements[imps[i].ID] = adapters.ClassifyPlacement(&imps[i])
+	}
+	return placements
+}
+
 // applyDealSupport updates targeting keys with deal prefixes if minimum deal tier exceeded
 func applyDealSupport(bidRequest *openrtb2.BidRequest, auc *auction, bidCategory map[string]string) []error {
 	errs := []error{}
@@ -353,12 +418,14 @@ func applyDealSupport(bidRequest *openrtb2.BidRequest, auc *auction, bidCategory
 
 	for impID, topBidsPerImp := range auc.winningBidsByBidder {
 		impDeal := impDealMap[impID]
-		for bidder, topBidPerBidder := range topBidsPerImp {
-			if topBidPerBidder.dealPriority > 0 {
-				if validateDealTier(impDeal[bidder]) {
-					updateHbPbCatDur(topBidPerBidder, impDeal[bidder], bidCategory)
-				} else {
-					errs = append(errs, fmt.Errorf("dealTier configuration invalid for bidder '%s', imp ID '%s'", string(bidder), impID))
+		for bidder, topBidsPerBidder := range topBidsPerImp {
+			for _, topBid := range topBidsPerBidder {
+				if topBid.dealPriority > 0 {
+					if validateDealTier(impDeal[bidder]) {
+						updateHbPbCatDur(topBid, impDeal[bidder], bidCategory)
+					} else {
+						errs = append(errs, fmt.Errorf("dealTier configuration invalid for bidder '%s', imp ID '%s'", string(bidder), impID))
+					}
 				}
 			}
 		}
@@ -425,7 +492,10 @@ func (e *exchange) getAllBids(
 	conversions currency.Conversions,
 	accountDebugAllowed bool,
 	globalPrivacyControlHeader string,
-	headerDebugAllowed bool) (
+	headerDebugAllowed bool,
+	enforceFloors bool,
+	placements map[string]adapters.Placement,
+	blockedCreatives []string) (
 	map[openrtb_ext.BidderName]*pbsOrtbSeatBid,
 	map[openrtb_ext.BidderName]*seatResponseExtra, bool) {
 	// Set up pointers to the bid results
@@ -435,6 +505,10 @@ func (e *exchange) getAllBids(
 	bidsFound := false
 
 	for _, bidder := range bidderRequests {
+		if !e.bidderControls.Allow(string(bidder.BidderCoreName), bidder.BidderLabels.PubID, requestPlatform(bidder.BidRequest)) {
+			chBids <- throttledBidResponse(bidder)
+			continue
+		}
 		txn := newrelic.FromContext(ctx)
 
 		// Here we actually call the adapters and collect the bids.
@@ -444,8 +518,6 @@ func (e *exchange) getAllBids(
 			ctx, span := trace.SpanFromContext(ctx).Tracer().Start(ctx, string(bidderRequest.BidderName))
 			defer span.End()
 
-			skanidlist.Update(ctx, e.adapterMap[bidderRequest.BidderCoreName].client(), bidderRequest.BidderCoreName)
-
 			// Passing in aName so a doesn't change out from under the go routine
 			if bidderRequest.BidderLabels.Adapter == "" {
 				glog.Errorf("Exchange: bidlables for %s (%s) missing adapter string", bidderRequest.BidderName, bidderRequest.BidderCoreName)
@@ -466,7 +538,12 @@ func (e *exchange) getAllBids(
 			var reqInfo adapters.ExtraRequestInfo
 			reqInfo.PbsEntryPoint = bidderRequest.BidderLabels.RType
 			reqInfo.GlobalPrivacyControlHeader = globalPrivacyControlHeader
-			bids, err := e.adapterMap[bidderRequest.BidderCoreName].requestBid(ctx, bidderRequest.BidRequest, bidderRequest.BidderName, adjustmentFactor, conversions, &reqInfo, accountDebugAllowed, headerDebugAllowed)
+			reqInfo.EnforceFloors = enforceFloors
+			reqInfo.Placements = placements
+			reqInfo.BlockedCreatives = blockedCreatives
+			bidderCtx, cancel, timeoutBudget := e.timeoutBudgets.apply(ctx, bidderRequest.BidderCoreName, bidderRequest.BidRequest)
+			defer cancel()
+			bids, err := e.adapterMap[bidderRequest.BidderCoreName].requestBid(bidderCtx, bidderRequest.BidRequest, bidderRequest.BidderName, adjustmentFactor, conversions, &reqInfo, accountDebugAllowed, headerDebugAllowed)
 
 			// Add in time reporting
 			elapsed := time.Since(start)
@@ -476,12 +553,20 @@ func (e *exchange) getAllBids(
 			ae.ResponseTimeMillis = int(elapsed / time.Millisecond)
 			if bids != nil {
 				ae.HttpCalls = bids.httpCalls
+				ae.BidderCalls = bids.bidderCalls
+			}
+			ae.TimeoutBudget = timeoutBudget
+			if calledBidder(ae.BidderCalls) {
+				e.timeoutBudgets.record(bidderRequest.BidderCoreName, elapsed)
 			}
+			setCallLabels(&bidderRequest.BidderLabels, ae.BidderCalls)
 
 			// Timing statistics
 			e.me.RecordAdapterTime(bidderRequest.BidderLabels, time.Since(start))
 			bidderRequest.BidderLabels.AdapterBids = bidsToMetric(brw.adapterBids)
 			bidderRequest.BidderLabels.AdapterErrors = errorsToMetric(err)
+			e.bidderControls.Record(string(bidderRequest.BidderCoreName), callOutcomeOf(ae.BidderCalls))
+			recordBlockedCreatives(e.me, bidderRequest.BidderLabels.Adapter, err)
 			// Append any bid validation errors to the error list
 			ae.Errors = errsToBidderErrors(err)
 			ae.Warnings = errsToBidderWarnings(err)
@@ -516,6 +601,65 @@ func (e *exchange) getAllBids(
 	return adapterBids, adapterExtra, bidsFound
 }
 
+// throttledBidResponse is the response of a bidder the bidder controls did not let the request through
+func throttledBidResponse(bidder BidderRequest) *bidResponseWrapper {
+	return &bidResponseWrapper{
+		bidder: bidder.BidderName,
+		adapterExtra: &seatResponseExtra{
+			Warnings: []openrtb_ext.ExtBidderMessage{{
+				Code:    errortypes.BidderThrottledWarningCode,
+				Message: fmt.Sprintf("The request to %s was not sent, the bidder is throttled", bidder.BidderName),
+			}},
+		},
+	}
+}
+
+// requestPlatform returns the device OS the bidder controls rules can target
+func requestPlatform(request *openrtb2.BidRequest) string {
+	if request == nil || request.Device == nil {
+		return ""
+	}
+	return request.Device.OS
+}
+
+// callOutcomeOf classifies the http calls made to a bidder for the recent stats of the bidder controls. Only the
+// calls which timed out, got no response or got a failure status count against the bidder, the bids rejected by
+// the exchange do not.
+func callOutcomeOf(calls []*analytics.BidderCall) biddercontrol.Outcome {
+	outcome := biddercontrol.Succeeded
+	for _, call := range calls {
+		switch {
+		case call.ErrorType == circuitOpenErrorType:
+			continue
+		case call.Timeout:
+			return biddercontrol.TimedOut
+		case call.Status < 200 || call.Status >= 400:
+			outcome = biddercontrol.Failed
+		}
+	}
+	return outcome
+}
+
+// recordBlockedCreatives counts the bids of the adapter rejected by each creative blocklist
+func recordBlockedCreatives(me metrics.MetricsEngine, adapter openrtb_ext.BidderName, errs []error) {
+	for _, err := range errs {
+		if blocked, ok := err.(*errortypes.BlockedCreative); ok {
+			me.RecordAdapterBlockedCreative(adapter, metrics.CreativeBlocklist(blocked.Blocklist))
+		}
+	}
+}
+
+// setCallLabels labels the adapter metrics with the region, placement type and SKAN data of the first
+// call made to the bidder
+func setCallLabels(labels *metrics.AdapterLabels, calls []*analytics.BidderCall) {
+	if len(calls) == 0 {
+		return
+	}
+	labels.Region = calls[0].Region
+	labels.PlacementType = calls[0].PlacementType
+	labels.SKANSent = calls[0].SKANSent
+}
+
 func (e *exchange) recoverSafely(bidderRequests []BidderRequest,
 	inner func(context.Context, *newrelic.Transaction, BidderRequest, currency.Conversions),
 	chBids chan *bidResponseWrapper) func(context.Context, *newrelic.Transaction, BidderRequest, currency.Conversions) {
@@ -558,6 +702,9 @@ func bidsToMetric(bids *pbsOrtbSeatBid) metrics.AdapterBid {
 	return metrics.AdapterBidPresent
 }
 
+// errorsToMetric returns the adapter errors of errs. The bids rejected by a creative blocklist and the requests
+// not sent by an open circuit breaker are not adapter errors, they are counted by RecordAdapterBlockedCreative
+// and RecordAdapterCircuitBreaker.
 func errorsToMetric(errs []error) map[metrics.AdapterError]struct{} {
 	if len(errs) == 0 {
 		return nil
@@ -565,22 +712,34 @@ func errorsToMetric(errs []error) map[metrics.AdapterError]struct{} {
 	ret := make(map[metrics.AdapterError]struct{}, len(errs))
 	var s struct{}
 	for _, err := range errs {
-		switch errortypes.ReadCode(err) {
-		case errortypes.TimeoutErrorCode:
-			ret[metrics.AdapterErrorTimeout] = s
-		case errortypes.BadInputErrorCode:
-			ret[metrics.AdapterErrorBadInput] = s
-		case errortypes.BadServerResponseErrorCode:
-			ret[metrics.AdapterErrorBadServerResponse] = s
-		case errortypes.FailedToRequestBidsErrorCode:
-			ret[metrics.AdapterErrorFailedToRequestBids] = s
-		default:
-			ret[metrics.AdapterErrorUnknown] = s
+		if code := errortypes.ReadCode(err); code == errortypes.BlockedCreativeErrorCode || code == errortypes.CircuitBreakerOpenWarningCode {
+			continue
 		}
+		ret[errorToMetric(err)] = s
+	}
+	if len(ret) == 0 {
+		return nil
 	}
 	return ret
 }
 
+func errorToMetric(err error) metrics.AdapterError {
+	switch errortypes.ReadCode(err) {
+	case errortypes.TimeoutErrorCode:
+		return metrics.AdapterErrorTimeout
+	case errortypes.BadInputErrorCode:
+		return metrics.AdapterErrorBadInput
+	case errortypes.BadServerResponseErrorCode:
+		return metrics.AdapterErrorBadServerResponse
+	case errortypes.FailedToRequestBidsErrorCode:
+		return metrics.AdapterErrorFailedToRequestBids
+	case errortypes.BidBelowFloorErrorCode:
+		return metrics.AdapterErrorBidBelowFloor
+	default:
+		return metrics.AdapterErrorUnknown
+	}
+}
+
 func errsToBidderErrors(errs []error) []openrtb_ext.ExtBidderMessage {
 	sErr := make([]openrtb_ext.ExtBidderMessage, 0)
 	for _, err := range errortypes.FatalOnly(errs) {
@@ -614,7 +773,7 @@ func (e *exchange) buildBidResponse(ctx context.Context, liveAdapters []openrtb_
 	bidResponse.ID = bidRequest.ID
 	if len(liveAdapters) == 0 {
 		// signal "Invalid Request" if no valid bidders.
-		bidResponse.NBR = openrtb2.NoBidReasonCode.Ptr(openrtb2.NoBidReasonCodeInvalidRequest)
+		bidResponse.NBR = openrtb3.NoBidReason.Ptr(openrtb3.NoBidInvalidRequest)
 	}
 
 	// Create the SeatBids. We use a zero sized slice so that we can append non-zero seat bids, and not include seatBid
@@ -876,7 +1035,7 @@ func getPrimaryAdServer(adServerId int) (string, error) {
 }
 
 // Extract all the data from the SeatBids and build the ExtBidResponse
-func (e *exchange) makeExtBidResponse(adapterBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid, adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra, r AuctionRequest, debugInfo bool, errList []error) *openrtb_ext.ExtBidResponse {
+func (e *exchange) makeExtBidResponse(adapterBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid, adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra, r AuctionRequest, debugInfo bool, errList []error, skanRouting *skanRouting) *openrtb_ext.ExtBidResponse {
 	req := r.BidRequest
 	bidResponseExt := &openrtb_ext.ExtBidResponse{
 		Errors:               make(map[openrtb_ext.BidderName][]openrtb_ext.ExtBidderMessage, len(adapterBids)),
@@ -891,17 +1050,29 @@ func (e *exchange) makeExtBidResponse(adapterBids map[openrtb_ext.BidderName]*pb
 		}
 	}
 	if !r.StartTime.IsZero() {
-		// auctiontimestamp is the only response.ext.prebid attribute we may emit
+		// auctiontimestamp is the only response.ext.prebid attribute we may emit outside of debugging
 		bidResponseExt.Prebid = &openrtb_ext.ExtResponsePrebid{
 			AuctionTimestamp: r.StartTime.UnixNano() / 1e+6,
 		}
 	}
+	if debugInfo && skanRouting != nil {
+		if bidResponseExt.Prebid == nil {
+			bidResponseExt.Prebid = &openrtb_ext.ExtResponsePrebid{}
+		}
+		bidResponseExt.Prebid.SKANRouting = skanRouting.debug()
+	}
 
 	for bidderName, responseExtra := range adapterExtra {
 
 		if debugInfo && len(responseExtra.HttpCalls) > 0 {
 			bidResponseExt.Debug.HttpCalls[bidderName] = responseExtra.HttpCalls
 		}
+		if debugInfo && responseExtra.TimeoutBudget != nil {
+			if bidResponseExt.Debug.TimeoutBudgets == nil {
+				bidResponseExt.Debug.TimeoutBudgets = make(map[openrtb_ext.BidderName]*openrtb_ext.ExtTimeoutBudget)
+			}
+			bidResponseExt.Debug.TimeoutBudgets[bidderName] = responseExtra.TimeoutBudget
+		}
 		if len(responseExtra.Warnings) > 0 {
 			bidResponseExt.Warnings[bidderName] = responseExtra.Warnings
 		}
@@ -949,6 +1120,7 @@ func (e *exchange) makeBid(bids []*pbsOrtbBid, auc *auction, returnCreative bool
 			Type:              bid.bidType,
 			Video:             bid.bidVideo,
 			BidId:             bid.generatedBidID,
+			TargetBidderCode:  bid.targetBidderCode,
 		}
 
 		if cacheInfo, found := e.getBidCacheInfo(bid, auc); found {
@@ -0,0 +1,253 @@
+package exchange
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/currency"
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+const (
+	defaultFloorCurrency  = "USD"
+	defaultFloorDelimiter = "|"
+)
+
+var floorFields = map[string]bool{
+	openrtb_ext.FloorFieldMediaType:     true,
+	openrtb_ext.FloorFieldSize:          true,
+	openrtb_ext.FloorFieldBundle:        true,
+	openrtb_ext.FloorFieldCountry:       true,
+	openrtb_ext.FloorFieldPlacementType: true,
+}
+
+// impFloor is the floor resolved for an imp, in the floors currency.
+type impFloor struct {
+	floor    float64
+	currency string
+}
+
+// resolveFloors applies the ext.prebid.floors rules to the imps of the request. The floor of an imp becomes the
+// highest of its own bidfloor and of the most specific matching rule, expressed in the floors currency.
+// The request is left untouched, the floors are set on the bidder requests by applyBidderFloors.
+func resolveFloors(request *openrtb2.BidRequest, floors *openrtb_ext.PriceFloorRules, placements map[string]adapters.Placement, conversions currency.Conversions) (map[string]impFloor, []error) {
+	if !floors.GetEnabled() {
+		return nil, nil
+	}
+
+	for _, field := range floors.Schema.Fields {
+		if !floorFields[field] {
+			return nil, []error{&errortypes.BadInput{
+				Message: fmt.Sprintf("request.ext.prebid.floors.schema.fields contains unsupported field \"%s\"", field),
+			}}
+		}
+	}
+
+	floorCurrency := floors.Currency
+	if floorCurrency == "" {
+		floorCurrency = defaultFloorCurrency
+	}
+	delimiter := floors.Schema.Delimiter
+	if delimiter == "" {
+		delimiter = defaultFloorDelimiter
+	}
+	values := make(map[string]float64, len(floors.Values))
+	for key, value := range floors.Values {
+		values[strings.ToLower(key)] = value
+	}
+
+	resolved := make(map[string]impFloor, len(request.Imp))
+	var errs []error
+	for i := range request.Imp {
+		imp := &request.Imp[i]
+
+		floor, found := matchFloorRule(values, floorFieldValues(request, imp, placements[imp.ID], floors.Schema.Fields), delimiter)
+		if !found {
+			floor = floors.Default
+		}
+
+		if imp.BidFloor > 0 {
+			impFloor, err := convertFloor(imp.BidFloor, imp.BidFloorCur, floorCurrency, conversions)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Unable to resolve floor of imp %s: %v", imp.ID, err))
+				continue
+			}
+			floor = math.Max(floor, impFloor)
+		}
+
+		if floor > 0 {
+			resolved[imp.ID] = impFloor{floor: floor, currency: floorCurrency}
+		}
+	}
+	return resolved, errs
+}
+
+// floorFieldValues returns the value of every schema field for the imp. Fields with an empty value only match "*".
+func floorFieldValues(request *openrtb2.BidRequest, imp *openrtb2.Imp, placement adapters.Placement, fields []string) []string {
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		switch field {
+		case openrtb_ext.FloorFieldMediaType:
+			values[i] = impMediaType(imp)
+		case openrtb_ext.FloorFieldSize:
+			values[i] = impSize(imp)
+		case openrtb_ext.FloorFieldBundle:
+			if request.App != nil {
+				values[i] = request.App.Bundle
+			}
+		case openrtb_ext.FloorFieldCountry:
+			if request.Device != nil && request.Device.Geo != nil {
+				values[i] = request.Device.Geo.Country
+			}
+		case openrtb_ext.FloorFieldPlacementType:
+			values[i] = impPlacementType(imp, placement)
+		}
+		values[i] = strings.ToLower(values[i])
+	}
+	return values
+}
+
+// matchFloorRule looks up the most specific rule for the field values. Rules with fewer wildcards win,
+// between rules with as many wildcards the one matching the leftmost fields wins.
+func matchFloorRule(rules map[string]float64, values []string, delimiter string) (float64, bool) {
+	if len(rules) == 0 || len(values) == 0 {
+		return 0, false
+	}
+
+	// bit i of a mask is set when values[i] is replaced by a wildcard
+	n := len(values)
+	masks := make([]uint, 0, 1<<n)
+	for mask := uint(0); mask < 1<<n; mask++ {
+		masks = append(masks, mask)
+	}
+	sort.Slice(masks, func(i, j int) bool {
+		if wi, wj := bits.OnesCount(masks[i]), bits.OnesCount(masks[j]); wi != wj {
+			return wi < wj
+		}
+		return bits.Reverse(masks[i]) < bits.Reverse(masks[j])
+	})
+
+	key := make([]string, n)
+	for _, mask := range masks {
+		matchable := true
+		for i, value := range values {
+			if mask&(1<<i) != 0 {
+				key[i] = openrtb_ext.FloorWildcard
+			} else if value == "" {
+				matchable = false
+				break
+			} else {
+				key[i] = value
+			}
+		}
+		if !matchable {
+			continue
+		}
+		if floor, ok := rules[strings.Join(key, delimiter)]; ok {
+			return floor, true
+		}
+	}
+	return 0, false
+}
+
+func impMediaType(imp *openrtb2.Imp) string {
+	mediaType := ""
+	count := 0
+	if imp.Banner != nil {
+		mediaType = string(openrtb_ext.BidTypeBanner)
+		count++
+	}
+	if imp.Video != nil {
+		mediaType = string(openrtb_ext.BidTypeVideo)
+		count++
+	}
+	if imp.Audio != nil {
+		mediaType = string(openrtb_ext.BidTypeAudio)
+		count++
+	}
+	if imp.Native != nil {
+		mediaType = string(openrtb_ext.BidTypeNative)
+		count++
+	}
+	if count != 1 {
+		return ""
+	}
+	return mediaType
+}
+
+func impSize(imp *openrtb2.Imp) string {
+	var w, h int64
+	switch {
+	case imp.Banner != nil && imp.Video == nil:
+		if len(imp.Banner.Format) == 1 {
+			w, h = imp.Banner.Format[0].W, imp.Banner.Format[0].H
+		} else if len(imp.Banner.Format) == 0 && imp.Banner.W != nil && imp.Banner.H != nil {
+			w, h = *imp.Banner.W, *imp.Banner.H
+		}
+	case imp.Video != nil && imp.Banner == nil && imp.Video.W != nil && imp.Video.H != nil:
+		w, h = *imp.Video.W, *imp.Video.H
+	}
+	if w == 0 || h == 0 {
+		return ""
+	}
+	return strconv.FormatInt(w, 10) + "x" + strconv.FormatInt(h, 10)
+}
+
+// impPlacementType only keys the floors on the placements the imp signals. The other placements, such as the
+// interstitial classified from a video imp, only match the wildcard.
+func impPlacementType(imp *openrtb2.Imp, placement adapters.Placement) string {
+	if placement.Type == adapters.Rewarded || imp.Instl == 1 {
+		return string(placement.Type)
+	}
+	return ""
+}
+
+// applyBidderFloors sets the resolved floors on the imps of every bidder request and converts the imp floors to
+// the currency the bidder expects them in.
+func applyBidderFloors(bidderRequests []BidderRequest, floors map[string]impFloor, bidderInfos config.BidderInfos, conversions currency.Conversions) []error {
+	var errs []error
+	for _, bidderRequest := range bidderRequests {
+		bidderCurrency := bidderInfos[string(bidderRequest.BidderCoreName)].Currency
+		if bidderCurrency == "" {
+			bidderCurrency = defaultFloorCurrency
+		}
+
+		for i := range bidderRequest.BidRequest.Imp {
+			imp := &bidderRequest.BidRequest.Imp[i]
+			if resolved, ok := floors[imp.ID]; ok {
+				imp.BidFloor = resolved.floor
+				imp.BidFloorCur = resolved.currency
+			}
+			if imp.BidFloor <= 0 || imp.BidFloorCur == bidderCurrency {
+				continue
+			}
+			floor, err := convertFloor(imp.BidFloor, imp.BidFloorCur, bidderCurrency, conversions)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Unable to convert floor of imp %s for bidder %s: %v", imp.ID, bidderRequest.BidderName, err))
+				continue
+			}
+			imp.BidFloor = floor
+			imp.BidFloorCur = bidderCurrency
+		}
+	}
+	return errs
+}
+
+func convertFloor(floor float64, from, to string, conversions currency.Conversions) (float64, error) {
+	if from == "" {
+		from = defaultFloorCurrency
+	}
+	rate, err := conversions.GetRate(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return floor * rate, nil
+}
@@ -0,0 +1,142 @@
+package exchange
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/currency"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveFloors(t *testing.T) {
+	floors := &openrtb_ext.PriceFloorRules{
+		Currency: "EUR",
+		Schema: openrtb_ext.PriceFloorSchema{
+			Fields: []string{
+				openrtb_ext.FloorFieldMediaType,
+				openrtb_ext.FloorFieldSize,
+				openrtb_ext.FloorFieldCountry,
+				openrtb_ext.FloorFieldPlacementType,
+			},
+		},
+		Values: map[string]float64{
+			"video|*|USA|rewarded":   5,
+			"video|*|*|interstitial": 4,
+			"video|*|*|*":            3,
+			"banner|320x50|*|*":      2,
+			"banner|*|*|*":           1,
+		},
+		Default: 0.5,
+	}
+	conversions := currency.NewRates(time.Time{}, map[string]map[string]float64{"USD": {"EUR": 0.5}})
+
+	request := &openrtb2.BidRequest{
+		Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "usa"}},
+		Imp: []openrtb2.Imp{
+			{ID: "rewarded-video", Video: &openrtb2.Video{W: openrtb2.Int64Ptr(320), H: openrtb2.Int64Ptr(480)}, Ext: json.RawMessage(`{"prebid":{"is_rewarded_inventory":1}}`)},
+			{ID: "sdk-rewarded-video", Video: &openrtb2.Video{W: openrtb2.Int64Ptr(320), H: openrtb2.Int64Ptr(480)}, Ext: json.RawMessage(`{"taurusx":{"reward":1}}`)},
+			{ID: "interstitial-video", Video: &openrtb2.Video{W: openrtb2.Int64Ptr(320), H: openrtb2.Int64Ptr(480)}, Instl: 1},
+			{ID: "video", Video: &openrtb2.Video{W: openrtb2.Int64Ptr(320), H: openrtb2.Int64Ptr(480)}},
+			{ID: "banner", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 320, H: 50}}}},
+			{ID: "multi-size-banner", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 320, H: 50}, {W: 300, H: 250}}}},
+			{ID: "native", Native: &openrtb2.Native{}},
+			{ID: "higher-imp-floor", Native: &openrtb2.Native{}, BidFloor: 4, BidFloorCur: "USD"},
+		},
+	}
+
+	original := make([]openrtb2.Imp, len(request.Imp))
+	copy(original, request.Imp)
+
+	resolved, errs := resolveFloors(request, floors, classifyPlacements(request.Imp), conversions)
+	assert.Empty(t, errs)
+	assert.Equal(t, original, request.Imp, "the request must be left untouched")
+
+	expected := map[string]float64{
+		"rewarded-video":     5,
+		"sdk-rewarded-video": 5,
+		"interstitial-video": 4,
+		"video":              3,
+		"banner":             2,
+		"multi-size-banner":  1,
+		"native":             0.5,
+		"higher-imp-floor":   2,
+	}
+	assert.Len(t, resolved, len(expected))
+	for impID, floor := range expected {
+		assert.Equal(t, impFloor{floor: floor, currency: "EUR"}, resolved[impID], impID)
+	}
+}
+
+func TestResolveFloorsDisabled(t *testing.T) {
+	disabled := false
+	request := &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp", Banner: &openrtb2.Banner{}}}}
+
+	resolved, errs := resolveFloors(request, &openrtb_ext.PriceFloorRules{Enabled: &disabled, Default: 1}, nil, currency.NewConstantRates())
+	assert.Empty(t, errs)
+	assert.Empty(t, resolved)
+
+	resolved, errs = resolveFloors(request, nil, nil, currency.NewConstantRates())
+	assert.Empty(t, errs)
+	assert.Empty(t, resolved)
+}
+
+func TestResolveFloorsUnsupportedField(t *testing.T) {
+	request := &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp", Banner: &openrtb2.Banner{}}}}
+	floors := &openrtb_ext.PriceFloorRules{Schema: openrtb_ext.PriceFloorSchema{Fields: []string{"domain"}}}
+
+	_, errs := resolveFloors(request, floors, nil, currency.NewConstantRates())
+	assert.Len(t, errs, 1)
+}
+
+func TestMatchFloorRule(t *testing.T) {
+	rules := map[string]float64{
+		"banner*320x50": 1,
+		"*~320x50":      2,
+		"banner~*":      3,
+	}
+	floor, found := matchFloorRule(rules, []string{"banner", "320x50"}, "~")
+	assert.True(t, found)
+	assert.Equal(t, 3.0, floor, "leftmost field wins between rules with as many wildcards")
+
+	floor, found = matchFloorRule(rules, []string{"video", "320x50"}, "~")
+	assert.True(t, found)
+	assert.Equal(t, 2.0, floor)
+
+	_, found = matchFloorRule(rules, []string{"video", ""}, "~")
+	assert.False(t, found)
+}
+
+func TestApplyBidderFloors(t *testing.T) {
+	bidderRequests := []BidderRequest{
+		{
+			BidderName:     "appnexus",
+			BidderCoreName: openrtb_ext.BidderAppnexus,
+			BidRequest:     &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp", BidFloor: 2, BidFloorCur: "USD"}}},
+		},
+		{
+			BidderName:     "rubicon",
+			BidderCoreName: openrtb_ext.BidderRubicon,
+			BidRequest:     &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp", BidFloor: 2, BidFloorCur: "USD"}}},
+		},
+		{
+			BidderName:     "pubmatic",
+			BidderCoreName: openrtb_ext.BidderPubmatic,
+			BidRequest:     &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "resolved-imp", BidFloor: 1, BidFloorCur: "USD"}}},
+		},
+	}
+	floors := map[string]impFloor{"resolved-imp": {floor: 3, currency: "EUR"}}
+	bidderInfos := config.BidderInfos{
+		"rubicon": config.BidderInfo{Currency: "EUR"},
+	}
+	conversions := currency.NewRates(time.Time{}, map[string]map[string]float64{"USD": {"EUR": 0.5}})
+
+	errs := applyBidderFloors(bidderRequests, floors, bidderInfos, conversions)
+	assert.Empty(t, errs)
+	assert.Equal(t, openrtb2.Imp{ID: "imp", BidFloor: 2, BidFloorCur: "USD"}, bidderRequests[0].BidRequest.Imp[0])
+	assert.Equal(t, openrtb2.Imp{ID: "imp", BidFloor: 1, BidFloorCur: "EUR"}, bidderRequests[1].BidRequest.Imp[0])
+	assert.Equal(t, openrtb2.Imp{ID: "resolved-imp", BidFloor: 6, BidFloorCur: "USD"}, bidderRequests[2].BidRequest.Imp[0])
+}
@@ -3,12 +3,17 @@ package exchange
 import (
 	"encoding/json"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/gdpr"
 )
 
-// ExtractGDPR will pull the gdpr flag from an openrtb request
+// ExtractGDPR will pull the gdpr flag from an openrtb request, the OpenRTB 2.6 regs.gdpr takes precedence over
+// regs.ext.gdpr
 func extractGDPR(bidRequest *openrtb2.BidRequest) (gdpr.Signal, error) {
+	if bidRequest.Regs != nil && bidRequest.Regs.GDPR != nil {
+		return gdpr.Signal(*bidRequest.Regs.GDPR), nil
+	}
+
 	var re regsExt
 	var err error
 	if bidRequest.Regs != nil && bidRequest.Regs.Ext != nil {
@@ -20,8 +25,13 @@ func extractGDPR(bidRequest *openrtb2.BidRequest) (gdpr.Signal, error) {
 	return gdpr.Signal(*re.GDPR), nil
 }
 
-// ExtractConsent will pull the consent string from an openrtb request
+// ExtractConsent will pull the consent string from an openrtb request, the OpenRTB 2.6 user.consent takes
+// precedence over user.ext.consent
 func extractConsent(bidRequest *openrtb2.BidRequest) (consent string, err error) {
+	if bidRequest.User != nil && bidRequest.User.Consent != "" {
+		return bidRequest.User.Consent, nil
+	}
+
 	var ue userExt
 	if bidRequest.User != nil && bidRequest.User.Ext != nil {
 		err = json.Unmarshal(bidRequest.User.Ext, &ue)
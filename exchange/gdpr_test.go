@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/gdpr"
 	"github.com/stretchr/testify/assert"
 )
@@ -31,6 +31,16 @@ func TestExtractGDPR(t *testing.T) {
 			giveRegs:    &openrtb2.Regs{Ext: json.RawMessage(`{"gdpr": null}`)},
 			wantGDPR:    gdpr.SignalAmbiguous,
 		},
+		{
+			description: "Regs GDPR = 1",
+			giveRegs:    &openrtb2.Regs{GDPR: openrtb2.Int8Ptr(1)},
+			wantGDPR:    gdpr.SignalYes,
+		},
+		{
+			description: "Regs GDPR Preferred Over Regs Ext GDPR",
+			giveRegs:    &openrtb2.Regs{GDPR: openrtb2.Int8Ptr(0), Ext: json.RawMessage(`{"gdpr": 1}`)},
+			wantGDPR:    gdpr.SignalNo,
+		},
 		{
 			description: "Regs is nil",
 			giveRegs:    nil,
@@ -82,6 +92,11 @@ func TestExtractConsent(t *testing.T) {
 			giveUser:    &openrtb2.User{Ext: json.RawMessage(`{"consent": ""}`)},
 			wantConsent: "",
 		},
+		{
+			description: "User Consent Preferred Over User Ext Consent",
+			giveUser:    &openrtb2.User{Consent: "BOS2bx5OS2bx5ABABBAAABoAAAAAFA", Ext: json.RawMessage(`{"consent": "other"}`)},
+			wantConsent: "BOS2bx5OS2bx5ABABBAAABoAAAAAFA",
+		},
 		{
 			description: "User Ext is nil",
 			giveUser:    &openrtb2.User{Ext: nil},
@@ -0,0 +1,95 @@
+package exchange
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+// getMultiBids maps every bidder of bidrequest.ext.prebid.multibid to its entry. The entries are
+// expected to be validated by the endpoint, see openrtb_ext.ValidateAndBuildExtMultiBid.
+func getMultiBids(requestExt *openrtb_ext.ExtRequest) map[openrtb_ext.BidderName]openrtb_ext.ExtMultiBid {
+	if requestExt == nil || len(requestExt.Prebid.MultiBid) == 0 {
+		return nil
+	}
+
+	multiBids := make(map[openrtb_ext.BidderName]openrtb_ext.ExtMultiBid, len(requestExt.Prebid.MultiBid))
+	for _, multiBid := range requestExt.Prebid.MultiBid {
+		if multiBid == nil || multiBid.MaxBids == nil {
+			continue
+		}
+		if multiBid.Bidder != "" {
+			multiBids[openrtb_ext.BidderName(multiBid.Bidder)] = *multiBid
+			continue
+		}
+		for _, bidder := range multiBid.Bidders {
+			multiBids[openrtb_ext.BidderName(bidder)] = openrtb_ext.ExtMultiBid{
+				Bidder:  bidder,
+				MaxBids: multiBid.MaxBids,
+			}
+		}
+	}
+	return multiBids
+}
+
+// applyMaxBids drops the bids of the multibid bidders beyond their maxbids on every imp, keeping the best ones
+func applyMaxBids(seatBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid, multiBids map[openrtb_ext.BidderName]openrtb_ext.ExtMultiBid, preferDeals bool) {
+	for bidderName, seatBid := range seatBids {
+		multiBid, ok := multiBids[bidderName]
+		if !ok || seatBid == nil {
+			continue
+		}
+
+		bidsByImp := make(map[string][]*pbsOrtbBid)
+		for _, bid := range seatBid.bids {
+			bidsByImp[bid.bid.ImpID] = append(bidsByImp[bid.bid.ImpID], bid)
+		}
+		dropped := make(map[*pbsOrtbBid]struct{})
+		for _, bids := range bidsByImp {
+			if len(bids) <= *multiBid.MaxBids {
+				continue
+			}
+			sortBids(bids, preferDeals)
+			for _, bid := range bids[*multiBid.MaxBids:] {
+				dropped[bid] = struct{}{}
+			}
+		}
+		if len(dropped) == 0 {
+			continue
+		}
+
+		kept := make([]*pbsOrtbBid, 0, len(seatBid.bids)-len(dropped))
+		for _, bid := range seatBid.bids {
+			if _, ok := dropped[bid]; !ok {
+				kept = append(kept, bid)
+			}
+		}
+		seatBid.bids = kept
+	}
+}
+
+// sortBids orders the bids of an imp from the best to the worst, ties keeping their order
+func sortBids(bids []*pbsOrtbBid, preferDeals bool) {
+	sort.SliceStable(bids, func(i, j int) bool {
+		return isNewWinningPbsBid(bids[i], bids[j], preferDeals)
+	})
+}
+
+// targetedBidLimit is the number of bids per imp of a bidder getting targeting keys and cache entries.
+// The extra bids of a multibid bidder are only targeted when it has a targetbiddercodeprefix.
+func targetedBidLimit(multiBid openrtb_ext.ExtMultiBid, isMultiBid bool) int {
+	if !isMultiBid || multiBid.TargetBidderCodePrefix == "" {
+		return openrtb_ext.DefaultBidLimit
+	}
+	return *multiBid.MaxBids
+}
+
+// targetBidderCode is the bidder code of the targeting keys of the i-th best bid of a multibid bidder.
+// The best bid keeps the bidder name, the next ones are <targetbiddercodeprefix>2, <targetbiddercodeprefix>3...
+func targetBidderCode(bidderName openrtb_ext.BidderName, multiBid openrtb_ext.ExtMultiBid, i int) string {
+	if i == 0 {
+		return string(bidderName)
+	}
+	return fmt.Sprintf("%s%d", multiBid.TargetBidderCodePrefix, i+1)
+}
@@ -0,0 +1,138 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMultiBids(t *testing.T) {
+	two, three := 2, 3
+	requestExt := &openrtb_ext.ExtRequest{
+		Prebid: openrtb_ext.ExtRequestPrebid{
+			MultiBid: []*openrtb_ext.ExtMultiBid{
+				{Bidder: "appnexus", MaxBids: &two, TargetBidderCodePrefix: "appn"},
+				{Bidders: []string{"rubicon", "pubmatic"}, MaxBids: &three},
+			},
+		},
+	}
+
+	assert.Equal(t, map[openrtb_ext.BidderName]openrtb_ext.ExtMultiBid{
+		"appnexus": {Bidder: "appnexus", MaxBids: &two, TargetBidderCodePrefix: "appn"},
+		"rubicon":  {Bidder: "rubicon", MaxBids: &three},
+		"pubmatic": {Bidder: "pubmatic", MaxBids: &three},
+	}, getMultiBids(requestExt))
+	assert.Nil(t, getMultiBids(nil))
+	assert.Nil(t, getMultiBids(&openrtb_ext.ExtRequest{}))
+}
+
+func TestApplyMaxBids(t *testing.T) {
+	two := 2
+	bid1p100 := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "1", ImpID: "imp1", Price: 1.00}}
+	bid2p300 := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "2", ImpID: "imp1", Price: 3.00}}
+	bid3p200 := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "3", ImpID: "imp1", Price: 2.00}}
+	bid4p050 := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "4", ImpID: "imp2", Price: 0.50}}
+	bid5p050d := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "5", ImpID: "imp1", Price: 0.50, DealID: "deal"}}
+	rubicon1 := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "r1", ImpID: "imp1", Price: 1.00}}
+	rubicon2 := &pbsOrtbBid{bid: &openrtb2.Bid{ID: "r2", ImpID: "imp1", Price: 2.00}}
+
+	testCases := []struct {
+		description    string
+		appnexusBids   []*pbsOrtbBid
+		preferDeals    bool
+		expectedBids   []*pbsOrtbBid
+		expectedOthers []*pbsOrtbBid
+	}{
+		{
+			description:    "the best bids of each imp are kept in their order",
+			appnexusBids:   []*pbsOrtbBid{bid1p100, bid2p300, bid3p200, bid4p050},
+			expectedBids:   []*pbsOrtbBid{bid2p300, bid3p200, bid4p050},
+			expectedOthers: []*pbsOrtbBid{rubicon1, rubicon2},
+		},
+		{
+			description:    "deals are preferred",
+			appnexusBids:   []*pbsOrtbBid{bid1p100, bid2p300, bid5p050d},
+			preferDeals:    true,
+			expectedBids:   []*pbsOrtbBid{bid2p300, bid5p050d},
+			expectedOthers: []*pbsOrtbBid{rubicon1, rubicon2},
+		},
+		{
+			description:    "no bid dropped under the limit",
+			appnexusBids:   []*pbsOrtbBid{bid1p100, bid4p050},
+			expectedBids:   []*pbsOrtbBid{bid1p100, bid4p050},
+			expectedOthers: []*pbsOrtbBid{rubicon1, rubicon2},
+		},
+	}
+
+	for _, test := range testCases {
+		seatBids := map[openrtb_ext.BidderName]*pbsOrtbSeatBid{
+			"appnexus": {bids: test.appnexusBids},
+			"rubicon":  {bids: []*pbsOrtbBid{rubicon1, rubicon2}},
+			"openx":    nil,
+		}
+		multiBids := map[openrtb_ext.BidderName]openrtb_ext.ExtMultiBid{
+			"appnexus": {Bidder: "appnexus", MaxBids: &two},
+			"openx":    {Bidder: "openx", MaxBids: &two},
+		}
+
+		applyMaxBids(seatBids, multiBids, test.preferDeals)
+
+		assert.Equal(t, test.expectedBids, seatBids["appnexus"].bids, test.description)
+		assert.Equal(t, test.expectedOthers, seatBids["rubicon"].bids, "%s: bidders without multibid keep their bids", test.description)
+	}
+}
+
+func TestMultiBidAuction(t *testing.T) {
+	two, three := 2, 3
+	bid1p100 := pbsOrtbBid{bid: &openrtb2.Bid{ID: "1", ImpID: "imp1", Price: 1.00}}
+	bid2p300 := pbsOrtbBid{bid: &openrtb2.Bid{ID: "2", ImpID: "imp1", Price: 3.00}}
+	bid3p200 := pbsOrtbBid{bid: &openrtb2.Bid{ID: "3", ImpID: "imp1", Price: 2.00}}
+	rubicon1 := pbsOrtbBid{bid: &openrtb2.Bid{ID: "r1", ImpID: "imp1", Price: 1.50}}
+	rubicon2 := pbsOrtbBid{bid: &openrtb2.Bid{ID: "r2", ImpID: "imp1", Price: 2.50}}
+
+	seatBids := map[openrtb_ext.BidderName]*pbsOrtbSeatBid{
+		"appnexus": {bids: []*pbsOrtbBid{&bid1p100, &bid2p300, &bid3p200}},
+		"rubicon":  {bids: []*pbsOrtbBid{&rubicon1, &rubicon2}},
+	}
+	multiBids := map[openrtb_ext.BidderName]openrtb_ext.ExtMultiBid{
+		"appnexus": {Bidder: "appnexus", MaxBids: &three, TargetBidderCodePrefix: "appnexusmultibid"},
+		"rubicon":  {Bidder: "rubicon", MaxBids: &two},
+	}
+
+	auc := newAuction(seatBids, 1, false, multiBids)
+
+	assert.Equal(t, &bid2p300, auc.winningBids["imp1"])
+	assert.Equal(t, map[openrtb_ext.BidderName][]*pbsOrtbBid{
+		"appnexus": {&bid2p300, &bid3p200, &bid1p100},
+		"rubicon":  {&rubicon2},
+	}, auc.winningBidsByBidder["imp1"], "the bids without a target bidder code prefix are not targeted")
+	assert.Equal(t, "appnexus", bid2p300.targetBidderCode)
+	assert.Equal(t, "appnexusmultibid2", bid3p200.targetBidderCode)
+	assert.Equal(t, "appnexusmultibid3", bid1p100.targetBidderCode)
+	assert.Equal(t, "rubicon", rubicon2.targetBidderCode)
+
+	targData := &targetData{
+		priceGranularity:  openrtb_ext.PriceGranularityFromString("med"),
+		includeWinners:    true,
+		includeBidderKeys: true,
+	}
+	auc.setRoundedPrices(targData.priceGranularity)
+	targData.setTargeting(auc, false, nil)
+
+	assert.Equal(t, map[string]string{
+		"hb_bidder":          "appnexus",
+		"hb_bidder_appnexus": "appnexus",
+		"hb_pb":              "3.00",
+		"hb_pb_appnexus":     "3.00",
+	}, bid2p300.bidTargets)
+	assert.Equal(t, map[string]string{
+		"hb_bidder_appnexusmu": "appnexusmultibid2",
+		"hb_pb_appnexusmultib": "2.00",
+	}, bid3p200.bidTargets, "the keys are truncated to the max key length")
+	assert.Equal(t, map[string]string{
+		"hb_bidder_rubicon": "rubicon",
+		"hb_pb_rubicon":     "2.50",
+	}, rubicon2.bidTargets)
+}
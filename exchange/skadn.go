@@ -0,0 +1,210 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/buger/jsonparser"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+// removeInvalidSKADNBids excises the bids whose bid.ext.skadn doesn't match the SKAdNetwork request of their imp.
+// allowedNetworks are the lowercased network ids registered for the bidder, an empty map allows any network
+// requested by the imp. iabIDs are their IAB SKAdNetwork ID list entry ids, matched against skadnetlist.excl.
+func removeInvalidSKADNBids(request *openrtb2.BidRequest, seatBid *pbsOrtbSeatBid, allowedNetworks map[string]bool, iabIDs map[string]int) []error {
+	if seatBid == nil || len(seatBid.bids) == 0 {
+		return nil
+	}
+
+	var imps map[string]*openrtb_ext.SKADN
+	var errs []error
+	validBids := make([]*pbsOrtbBid, 0, len(seatBid.bids))
+	for _, bid := range seatBid.bids {
+		value, dataType, _, err := jsonparser.Get(bid.bid.Ext, "skadn")
+		if err != nil || dataType != jsonparser.Object {
+			validBids = append(validBids, bid)
+			continue
+		}
+
+		if imps == nil {
+			imps = requestedSKADN(request)
+		}
+
+		var skadn openrtb_ext.ExtBidSKADN
+		if err := json.Unmarshal(value, &skadn); err != nil {
+			errs = append(errs, invalidSKADNBid(bid.bid, fmt.Sprintf("malformed bid.ext.skadn: %v", err)))
+			continue
+		}
+		if err := validateSKADNBid(&skadn, imps[bid.bid.ImpID], allowedNetworks, iabIDs); err != nil {
+			errs = append(errs, invalidSKADNBid(bid.bid, err.Error()))
+			continue
+		}
+		validBids = append(validBids, bid)
+	}
+	seatBid.bids = validBids
+	return errs
+}
+
+func invalidSKADNBid(bid *openrtb2.Bid, reason string) error {
+	return &errortypes.BadServerResponse{
+		Message: fmt.Sprintf("Bid \"%s\" dropped: %s", bid.ID, reason),
+	}
+}
+
+// requestedSKADN returns the imp.ext.prebid.skadn of every imp requesting SKAdNetwork, keyed by imp id
+func requestedSKADN(request *openrtb2.BidRequest) map[string]*openrtb_ext.SKADN {
+	imps := make(map[string]*openrtb_ext.SKADN, len(request.Imp))
+	for i := range request.Imp {
+		if skadn := impSKADN(&request.Imp[i]); skadn != nil {
+			imps[request.Imp[i].ID] = skadn
+		}
+	}
+	return imps
+}
+
+// impSKADN returns the imp.ext.prebid.skadn of the imp, nil if it does not request SKAdNetwork
+func impSKADN(imp *openrtb2.Imp) *openrtb_ext.SKADN {
+	value, dataType, _, err := jsonparser.Get(imp.Ext, openrtb_ext.PrebidExtKey, "skadn")
+	if err != nil || dataType != jsonparser.Object {
+		return nil
+	}
+	var skadn openrtb_ext.SKADN
+	if err := json.Unmarshal(value, &skadn); err != nil {
+		return nil
+	}
+	return &skadn
+}
+
+func validateSKADNBid(skadn *openrtb_ext.ExtBidSKADN, requested *openrtb_ext.SKADN, allowedNetworks map[string]bool, iabIDs map[string]int) error {
+	if requested == nil {
+		return fmt.Errorf("bid.ext.skadn returned for an imp without SKAdNetwork support")
+	}
+
+	if !skadnVersionRequested(skadn.Version, requested) {
+		return fmt.Errorf("SKAdNetwork version \"%s\" was not requested", skadn.Version)
+	}
+
+	network := strings.ToLower(skadn.Network)
+	if skadnNetworkExcluded(network, requested, iabIDs) {
+		return fmt.Errorf("SKAdNetwork network \"%s\" is excluded by skadnetlist.excl", skadn.Network)
+	}
+	if !skadnNetworkRequested(network, requested, allowedNetworks, iabIDs) {
+		return fmt.Errorf("SKAdNetwork network \"%s\" was not requested", skadn.Network)
+	}
+	if len(allowedNetworks) > 0 && !allowedNetworks[network] {
+		return fmt.Errorf("SKAdNetwork network \"%s\" is not registered for the bidder", skadn.Network)
+	}
+
+	if skadn.ITunesItem == "" {
+		return fmt.Errorf("bid.ext.skadn.itunesitem is required")
+	}
+
+	if compareSKADNVersions(skadn.Version, "4.0") >= 0 {
+		if skadn.SourceIdentifier == "" {
+			return fmt.Errorf("bid.ext.skadn.sourceidentifier is required as of SKAdNetwork 4.0")
+		}
+	} else if skadn.Campaign == "" {
+		return fmt.Errorf("bid.ext.skadn.campaign is required before SKAdNetwork 4.0")
+	}
+
+	if compareSKADNVersions(skadn.Version, "2.2") >= 0 {
+		if len(skadn.Fidelities) == 0 {
+			return fmt.Errorf("bid.ext.skadn.fidelities are required as of SKAdNetwork 2.2")
+		}
+		for i, fidelity := range skadn.Fidelities {
+			if fidelity.Fidelity != 0 && fidelity.Fidelity != 1 {
+				return fmt.Errorf("bid.ext.skadn.fidelities[%d].fidelity must be 0 or 1", i)
+			}
+			if fidelity.Nonce == "" || fidelity.Timestamp == "" || fidelity.Signature == "" {
+				return fmt.Errorf("bid.ext.skadn.fidelities[%d] requires nonce, timestamp and signature", i)
+			}
+		}
+	} else if skadn.Nonce == "" || skadn.Timestamp == "" || skadn.Signature == "" {
+		return fmt.Errorf("bid.ext.skadn requires nonce, timestamp and signature before SKAdNetwork 2.2")
+	}
+
+	return nil
+}
+
+func skadnVersionRequested(version string, requested *openrtb_ext.SKADN) bool {
+	if version == "" {
+		return false
+	}
+	if len(requested.Versions) == 0 {
+		return compareSKADNVersions(version, requested.Version) == 0
+	}
+	for _, v := range requested.Versions {
+		if compareSKADNVersions(version, v) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// skadnNetworkRequested checks the network against the ids and additional ids the imp lists. The IAB list
+// version of skadnetlist.max is not resolved, the loaded SKAN ids of the bidder stand for it. The networks
+// excluded by skadnetlist.excl are never requested.
+func skadnNetworkRequested(network string, requested *openrtb_ext.SKADN, bidderNetworks map[string]bool, iabIDs map[string]int) bool {
+	if network == "" || skadnNetworkExcluded(network, requested, iabIDs) {
+		return false
+	}
+	for _, id := range requested.SKADNetIDs {
+		if strings.ToLower(id) == network {
+			return true
+		}
+	}
+	if requested.SKADNetList != nil {
+		for _, id := range requested.SKADNetList.Addl {
+			if strings.ToLower(id) == network {
+				return true
+			}
+		}
+		if requested.SKADNetList.Max > 0 && bidderNetworks[network] {
+			return true
+		}
+	}
+	return false
+}
+
+// skadnNetworkExcluded checks the IAB SKAdNetwork ID list entry id of the network, as loaded for the bidder,
+// against the entries skadnetlist.excl excludes
+func skadnNetworkExcluded(network string, requested *openrtb_ext.SKADN, iabIDs map[string]int) bool {
+	if requested.SKADNetList == nil || len(requested.SKADNetList.Excl) == 0 {
+		return false
+	}
+	id, ok := iabIDs[network]
+	if !ok {
+		return false
+	}
+	for _, excl := range requested.SKADNetList.Excl {
+		if excl == id {
+			return true
+		}
+	}
+	return false
+}
+
+// compareSKADNVersions compares two dotted SKAdNetwork versions, "2.0" and "2" are equal
+func compareSKADNVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,165 @@
+package exchange
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveInvalidSKADNBids(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		Imp: []openrtb2.Imp{
+			{
+				ID:  "skadn-imp",
+				Ext: json.RawMessage(`{"prebid":{"skadn":{"versions":["2.2","4.0"],"skadnetids":["net1.skadnetwork","net2.skadnetwork"],"skadnetlist":{"max":300,"addl":["net3.skadnetwork"]}}}}`),
+			},
+			{
+				ID:  "legacy-imp",
+				Ext: json.RawMessage(`{"prebid":{"skadn":{"version":"2.0","skadnetids":["net1.skadnetwork"]}}}`),
+			},
+			{
+				ID:  "max-only-imp",
+				Ext: json.RawMessage(`{"prebid":{"skadn":{"versions":["2.2"],"skadnetlist":{"max":300}}}}`),
+			},
+			{
+				ID:  "excl-imp",
+				Ext: json.RawMessage(`{"prebid":{"skadn":{"versions":["2.2"],"skadnetids":["net1.skadnetwork"],"skadnetlist":{"max":300,"excl":[7]}}}}`),
+			},
+			{
+				ID:  "no-skadn-imp",
+				Ext: json.RawMessage(`{"prebid":{}}`),
+			},
+		},
+	}
+
+	testCases := []struct {
+		description     string
+		impID           string
+		skadn           string
+		allowedNetworks map[string]bool
+		iabIDs          map[string]int
+		valid           bool
+	}{
+		{
+			description: "no skadn",
+			impID:       "no-skadn-imp",
+			valid:       true,
+		},
+		{
+			description: "4.0",
+			impID:       "skadn-imp",
+			skadn:       `{"version":"4.0","network":"NET1.skadnetwork","itunesitem":"123","sourceidentifier":"1234","fidelities":[{"fidelity":1,"nonce":"n","timestamp":"1","signature":"s"}]}`,
+			valid:       true,
+		},
+		{
+			description: "network from the additional list",
+			impID:       "skadn-imp",
+			skadn:       `{"version":"2.2","network":"net3.skadnetwork","itunesitem":"123","campaign":"4","fidelities":[{"fidelity":0,"nonce":"n","timestamp":"1","signature":"s"}]}`,
+			valid:       true,
+		},
+		{
+			description:     "network from the IAB list version of the bidder",
+			impID:           "max-only-imp",
+			skadn:           `{"version":"2.2","network":"net1.skadnetwork","itunesitem":"123","campaign":"4","fidelities":[{"fidelity":0,"nonce":"n","timestamp":"1","signature":"s"}]}`,
+			allowedNetworks: map[string]bool{"net1.skadnetwork": true},
+			valid:           true,
+		},
+		{
+			description:     "network not in the IAB list version of the bidder",
+			impID:           "max-only-imp",
+			skadn:           `{"version":"2.2","network":"other.skadnetwork","itunesitem":"123","campaign":"4","fidelities":[{"fidelity":0,"nonce":"n","timestamp":"1","signature":"s"}]}`,
+			allowedNetworks: map[string]bool{"net1.skadnetwork": true},
+		},
+		{
+			description: "2.0 with top level signature",
+			impID:       "legacy-imp",
+			skadn:       `{"version":"2.0","network":"net1.skadnetwork","itunesitem":"123","campaign":"4","nonce":"n","timestamp":"1","signature":"s"}`,
+			valid:       true,
+		},
+		{
+			description: "4.0 without sourceidentifier",
+			impID:       "skadn-imp",
+			skadn:       `{"version":"4.0","network":"net1.skadnetwork","itunesitem":"123","campaign":"4","fidelities":[{"fidelity":1,"nonce":"n","timestamp":"1","signature":"s"}]}`,
+		},
+		{
+			description: "2.2 without fidelities",
+			impID:       "skadn-imp",
+			skadn:       `{"version":"2.2","network":"net1.skadnetwork","itunesitem":"123","campaign":"4","nonce":"n","timestamp":"1","signature":"s"}`,
+		},
+		{
+			description: "fidelity without signature",
+			impID:       "skadn-imp",
+			skadn:       `{"version":"2.2","network":"net1.skadnetwork","itunesitem":"123","campaign":"4","fidelities":[{"fidelity":1,"nonce":"n","timestamp":"1"}]}`,
+		},
+		{
+			description: "version not requested",
+			impID:       "skadn-imp",
+			skadn:       `{"version":"3.0","network":"net1.skadnetwork","itunesitem":"123","campaign":"4","fidelities":[{"fidelity":1,"nonce":"n","timestamp":"1","signature":"s"}]}`,
+		},
+		{
+			description: "network not requested",
+			impID:       "skadn-imp",
+			skadn:       `{"version":"2.2","network":"other.skadnetwork","itunesitem":"123","campaign":"4","fidelities":[{"fidelity":1,"nonce":"n","timestamp":"1","signature":"s"}]}`,
+		},
+		{
+			description:     "network not registered for the bidder",
+			impID:           "skadn-imp",
+			skadn:           `{"version":"2.2","network":"net2.skadnetwork","itunesitem":"123","campaign":"4","fidelities":[{"fidelity":1,"nonce":"n","timestamp":"1","signature":"s"}]}`,
+			allowedNetworks: map[string]bool{"net1.skadnetwork": true},
+		},
+		{
+			description:     "network excluded by the app",
+			impID:           "excl-imp",
+			skadn:           `{"version":"2.2","network":"net1.skadnetwork","itunesitem":"123","campaign":"4","fidelities":[{"fidelity":0,"nonce":"n","timestamp":"1","signature":"s"}]}`,
+			allowedNetworks: map[string]bool{"net1.skadnetwork": true},
+			iabIDs:          map[string]int{"net1.skadnetwork": 7},
+		},
+		{
+			description:     "network not excluded by the app",
+			impID:           "excl-imp",
+			skadn:           `{"version":"2.2","network":"net1.skadnetwork","itunesitem":"123","campaign":"4","fidelities":[{"fidelity":0,"nonce":"n","timestamp":"1","signature":"s"}]}`,
+			allowedNetworks: map[string]bool{"net1.skadnetwork": true},
+			iabIDs:          map[string]int{"net1.skadnetwork": 8},
+			valid:           true,
+		},
+		{
+			description: "imp without skadn",
+			impID:       "no-skadn-imp",
+			skadn:       `{"version":"2.0","network":"net1.skadnetwork","itunesitem":"123","campaign":"4","nonce":"n","timestamp":"1","signature":"s"}`,
+		},
+		{
+			description: "malformed skadn",
+			impID:       "skadn-imp",
+			skadn:       `{"version":2.2}`,
+		},
+	}
+
+	for _, test := range testCases {
+		bid := &openrtb2.Bid{ID: "bid", ImpID: test.impID}
+		if test.skadn != "" {
+			bid.Ext = json.RawMessage(`{"skadn":` + test.skadn + `}`)
+		}
+		seatBid := &pbsOrtbSeatBid{bids: []*pbsOrtbBid{{bid: bid}}}
+
+		errs := removeInvalidSKADNBids(request, seatBid, test.allowedNetworks, test.iabIDs)
+		if test.valid {
+			assert.Len(t, seatBid.bids, 1, test.description)
+			assert.Empty(t, errs, test.description)
+		} else {
+			assert.Empty(t, seatBid.bids, test.description)
+			if assert.Len(t, errs, 1, test.description) {
+				assert.IsType(t, &errortypes.BadServerResponse{}, errs[0], test.description)
+			}
+		}
+	}
+}
+
+func TestCompareSKADNVersions(t *testing.T) {
+	assert.Equal(t, 0, compareSKADNVersions("2", "2.0"))
+	assert.Equal(t, -1, compareSKADNVersions("2.2", "4.0"))
+	assert.Equal(t, 1, compareSKADNVersions("4.0", "3.10"))
+	assert.Equal(t, -1, compareSKADNVersions("3.9", "3.10"))
+}
@@ -0,0 +1,161 @@
+package exchange
+
+import (
+	"github.com/buger/jsonparser"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/cache/skanidlist"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/prebid/prebid-server/privacy/lmt"
+)
+
+// Reasons a bidder cannot attribute an imp through SKAdNetwork, reported in bidresponse.ext.prebid.skanrouting
+const (
+	skanNotSupported   = "skadn_not_supported"
+	skanIDNotRequested = "skan_id_not_requested"
+)
+
+// skanBidder returns the bidder whose SKAN ID List the bids of a core bidder are checked against, the bidder
+// serving it for a seat. The aliases are resolved by passing their core bidder.
+func skanBidder(coreBidder openrtb_ext.BidderName, infos config.BidderInfos) openrtb_ext.BidderName {
+	if info, ok := infos[string(coreBidder)]; ok && info.Seat != nil && info.Seat.Of != "" {
+		if of, ok := openrtb_ext.NormalizeBidderName(info.Seat.Of); ok {
+			return of
+		}
+	}
+	return coreBidder
+}
+
+// skanRouting holds the imps the bidders cannot attribute through SKAdNetwork, for an iOS request without
+// App Tracking Transparency consent
+type skanRouting struct {
+	mode           config.SKANRoutingMode
+	unattributable map[openrtb_ext.BidderName]map[string]bool
+	decisions      map[openrtb_ext.BidderName][]openrtb_ext.ExtSKANRoutingDecision
+}
+
+// routeSKAN applies the SKAN routing of the account when the request cannot be attributed through the IFA.
+// With config.SKANRoutingExclude, the imps are removed from the requests of the bidders unable to attribute
+// them, and the bidders left without imps are not called. A nil routing is returned when it does not apply.
+func routeSKAN(req *openrtb2.BidRequest, bidderRequests []BidderRequest, mode config.SKANRoutingMode, infos config.BidderInfos) ([]BidderRequest, *skanRouting) {
+	if mode != config.SKANRoutingExclude && mode != config.SKANRoutingDeprioritize {
+		return bidderRequests, nil
+	}
+	if !lmt.IsTrackingDeniedForIOS(req) {
+		return bidderRequests, nil
+	}
+
+	routing := &skanRouting{
+		mode:           mode,
+		unattributable: make(map[openrtb_ext.BidderName]map[string]bool),
+		decisions:      make(map[openrtb_ext.BidderName][]openrtb_ext.ExtSKANRoutingDecision),
+	}
+	routed := make([]BidderRequest, 0, len(bidderRequests))
+	for _, bidderRequest := range bidderRequests {
+		networks := skanidlist.Get(skanBidder(bidderRequest.BidderCoreName, infos))
+		iabIDs := skanidlist.IABIDs(skanBidder(bidderRequest.BidderCoreName, infos))
+		imps := make([]openrtb2.Imp, 0, len(bidderRequest.BidRequest.Imp))
+		for _, imp := range bidderRequest.BidRequest.Imp {
+			reason := skanAttributionProblem(&imp, networks, iabIDs)
+			if reason == "" || mode == config.SKANRoutingDeprioritize {
+				imps = append(imps, imp)
+			}
+			if reason != "" {
+				routing.add(bidderRequest.BidderName, imp.ID, reason)
+			}
+		}
+		if len(imps) == 0 {
+			continue
+		}
+		if len(imps) < len(bidderRequest.BidRequest.Imp) {
+			request := *bidderRequest.BidRequest
+			request.Imp = imps
+			bidderRequest.BidRequest = &request
+		}
+		routed = append(routed, bidderRequest)
+	}
+	return routed, routing
+}
+
+func (r *skanRouting) add(bidder openrtb_ext.BidderName, impID string, reason string) {
+	if r.unattributable[bidder] == nil {
+		r.unattributable[bidder] = make(map[string]bool)
+	}
+	r.unattributable[bidder][impID] = true
+	r.decisions[bidder] = append(r.decisions[bidder], openrtb_ext.ExtSKANRoutingDecision{ImpID: impID, Reason: reason})
+}
+
+// skanAttributionProblem returns why the bidder cannot attribute the imp through SKAdNetwork, or an empty
+// string if it can or the imp does not support SKAdNetwork at all. The bidder supports SKAdNetwork as set by
+// the skadn_supported param or, without the param, when it has registered SKAN IDs, one of which must then
+// be requested, and not excluded, by the imp.
+func skanAttributionProblem(imp *openrtb2.Imp, networks map[string]bool, iabIDs map[string]int) string {
+	requested := impSKADN(imp)
+	if requested == nil {
+		return ""
+	}
+
+	supported, err := jsonparser.GetBoolean(imp.Ext, openrtb_ext.PrebidExtBidderKey, "skadn_supported")
+	if err != nil {
+		supported = len(networks) > 0
+	}
+	if !supported {
+		return skanNotSupported
+	}
+
+	if len(networks) == 0 {
+		return ""
+	}
+	for network := range networks {
+		if skadnNetworkRequested(network, requested, networks, iabIDs) {
+			return ""
+		}
+	}
+	return skanIDNotRequested
+}
+
+// removeDeprioritizedBids drops, with config.SKANRoutingDeprioritize, the bids on the imps the bidders cannot
+// attribute if another bidder bid on them.
+func (r *skanRouting) removeDeprioritizedBids(adapterBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid) {
+	if r == nil || r.mode != config.SKANRoutingDeprioritize {
+		return
+	}
+
+	attributed := make(map[string]bool)
+	for bidder, seatBid := range adapterBids {
+		if seatBid == nil {
+			continue
+		}
+		for _, bid := range seatBid.bids {
+			if !r.unattributable[bidder][bid.bid.ImpID] {
+				attributed[bid.bid.ImpID] = true
+			}
+		}
+	}
+
+	for bidder, seatBid := range adapterBids {
+		if seatBid == nil || len(r.unattributable[bidder]) == 0 {
+			continue
+		}
+		bids := make([]*pbsOrtbBid, 0, len(seatBid.bids))
+		for _, bid := range seatBid.bids {
+			if r.unattributable[bidder][bid.bid.ImpID] && attributed[bid.bid.ImpID] {
+				continue
+			}
+			bids = append(bids, bid)
+		}
+		seatBid.bids = bids
+	}
+}
+
+// debug returns the decisions for bidresponse.ext.prebid.skanrouting
+func (r *skanRouting) debug() *openrtb_ext.ExtResponseSKANRouting {
+	if r == nil {
+		return nil
+	}
+	routing := &openrtb_ext.ExtResponseSKANRouting{Mode: string(r.mode)}
+	if len(r.decisions) > 0 {
+		routing.Bidders = r.decisions
+	}
+	return routing
+}
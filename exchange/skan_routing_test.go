@@ -0,0 +1,213 @@
+package exchange
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/cache/skanidlist"
+	skanidlistcfg "github.com/prebid/prebid-server/cache/skanidlist/cfg"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func skanRoutingRequests() (*openrtb2.BidRequest, []BidderRequest) {
+	request := &openrtb2.BidRequest{
+		App:    &openrtb2.App{},
+		Device: &openrtb2.Device{OS: "iOS", OSV: "14.6", IFA: "00000000-0000-0000-0000-000000000000"},
+	}
+	skadnImp := func(id string, params string) openrtb2.Imp {
+		return openrtb2.Imp{ID: id, Ext: json.RawMessage(`{"prebid":{"skadn":{"version":"2.2","skadnetids":["net1.skadnetwork"]}},"bidder":` + params + `}`)}
+	}
+	bidderRequests := []BidderRequest{
+		{
+			BidderName: "supported",
+			BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{skadnImp("imp1", `{"skadn_supported":true}`), skadnImp("imp2", `{"skadn_supported":true}`)}},
+		},
+		{
+			BidderName: "unsupported",
+			BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{skadnImp("imp1", `{"skadn_supported":false}`), skadnImp("imp2", `{}`)}},
+		},
+		{
+			BidderName: "partial",
+			BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{
+				skadnImp("imp1", `{"skadn_supported":false}`),
+				{ID: "imp3", Ext: json.RawMessage(`{"bidder":{}}`)},
+			}},
+		},
+	}
+	return request, bidderRequests
+}
+
+func TestRouteSKANExclude(t *testing.T) {
+	request, bidderRequests := skanRoutingRequests()
+
+	routed, routing := routeSKAN(request, bidderRequests, config.SKANRoutingExclude, nil)
+
+	if assert.Len(t, routed, 2) {
+		assert.Equal(t, openrtb_ext.BidderName("supported"), routed[0].BidderName)
+		assert.Len(t, routed[0].BidRequest.Imp, 2)
+		assert.Equal(t, openrtb_ext.BidderName("partial"), routed[1].BidderName)
+		if assert.Len(t, routed[1].BidRequest.Imp, 1) {
+			assert.Equal(t, "imp3", routed[1].BidRequest.Imp[0].ID, "the imp without SKAdNetwork is kept")
+		}
+	}
+	assert.Len(t, bidderRequests[2].BidRequest.Imp, 2, "the bidder request is copied")
+	assert.Equal(t, &openrtb_ext.ExtResponseSKANRouting{
+		Mode: "exclude",
+		Bidders: map[openrtb_ext.BidderName][]openrtb_ext.ExtSKANRoutingDecision{
+			"unsupported": {{ImpID: "imp1", Reason: "skadn_not_supported"}, {ImpID: "imp2", Reason: "skadn_not_supported"}},
+			"partial":     {{ImpID: "imp1", Reason: "skadn_not_supported"}},
+		},
+	}, routing.debug())
+}
+
+func TestRouteSKANDeprioritize(t *testing.T) {
+	request, bidderRequests := skanRoutingRequests()
+
+	routed, routing := routeSKAN(request, bidderRequests, config.SKANRoutingDeprioritize, nil)
+	assert.Equal(t, bidderRequests, routed, "every bidder is called")
+
+	adapterBids := map[openrtb_ext.BidderName]*pbsOrtbSeatBid{
+		"supported":   {bids: []*pbsOrtbBid{{bid: &openrtb2.Bid{ID: "s1", ImpID: "imp1"}}}},
+		"unsupported": {bids: []*pbsOrtbBid{{bid: &openrtb2.Bid{ID: "u1", ImpID: "imp1"}}, {bid: &openrtb2.Bid{ID: "u2", ImpID: "imp2"}}}},
+		"partial":     nil,
+	}
+	routing.removeDeprioritizedBids(adapterBids)
+
+	assert.Len(t, adapterBids["supported"].bids, 1)
+	if assert.Len(t, adapterBids["unsupported"].bids, 1) {
+		assert.Equal(t, "u2", adapterBids["unsupported"].bids[0].bid.ID, "the bids are kept on the imps no other bidder bid on")
+	}
+}
+
+func TestRouteSKANNotApplied(t *testing.T) {
+	testCases := []struct {
+		description string
+		device      *openrtb2.Device
+		mode        config.SKANRoutingMode
+	}{
+		{
+			description: "no mode",
+			device:      &openrtb2.Device{OS: "iOS", IFA: "00000000-0000-0000-0000-000000000000"},
+		},
+		{
+			description: "tracking authorized",
+			device:      &openrtb2.Device{OS: "iOS", IFA: "abc", Ext: json.RawMessage(`{"atts":3}`)},
+			mode:        config.SKANRoutingExclude,
+		},
+		{
+			description: "android",
+			device:      &openrtb2.Device{OS: "Android"},
+			mode:        config.SKANRoutingExclude,
+		},
+	}
+
+	for _, test := range testCases {
+		request, bidderRequests := skanRoutingRequests()
+		request.Device = test.device
+
+		routed, routing := routeSKAN(request, bidderRequests, test.mode, nil)
+		assert.Equal(t, bidderRequests, routed, test.description)
+		assert.Nil(t, routing, test.description)
+		assert.Nil(t, routing.debug(), test.description)
+	}
+}
+
+func TestRouteSKANCoreBidderList(t *testing.T) {
+	skanidlist.Register(skanidlistcfg.Cache{Bidder: "skancore", StaticIDs: []string{"net2.skadnetwork"}})
+	request, _ := skanRoutingRequests()
+	impExt := json.RawMessage(`{"prebid":{"skadn":{"version":"2.2","skadnetids":["net1.skadnetwork"]}},"bidder":{}}`)
+	bidderRequests := []BidderRequest{
+		{
+			BidderName:     "skanalias",
+			BidderCoreName: "skancore",
+			BidRequest:     &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1", Ext: impExt}}},
+		},
+	}
+
+	_, routing := routeSKAN(request, bidderRequests, config.SKANRoutingDeprioritize, nil)
+	assert.Equal(t, map[openrtb_ext.BidderName][]openrtb_ext.ExtSKANRoutingDecision{
+		"skanalias": {{ImpID: "imp1", Reason: "skan_id_not_requested"}},
+	}, routing.debug().Bidders, "the alias is checked against the list of its core bidder")
+}
+
+func TestSKANBidder(t *testing.T) {
+	infos := config.BidderInfos{
+		"rubiconmraid": {Seat: &config.AdapterSeat{Of: "Rubicon"}},
+		"liftoff":      {},
+	}
+	assert.Equal(t, openrtb_ext.BidderRubicon, skanBidder(openrtb_ext.BidderRubiconMRAID, infos))
+	assert.Equal(t, openrtb_ext.BidderLiftoff, skanBidder(openrtb_ext.BidderLiftoff, infos))
+	assert.Equal(t, openrtb_ext.BidderMoloco, skanBidder(openrtb_ext.BidderMoloco, infos))
+}
+
+func TestSKANAttributionProblem(t *testing.T) {
+	testCases := []struct {
+		description string
+		impExt      string
+		networks    map[string]bool
+		expected    string
+	}{
+		{
+			description: "imp without skadn",
+			impExt:      `{"bidder":{"skadn_supported":false}}`,
+		},
+		{
+			description: "supported",
+			impExt:      `{"prebid":{"skadn":{"skadnetids":["net1.skadnetwork"]}},"bidder":{"skadn_supported":true}}`,
+		},
+		{
+			description: "not supported",
+			impExt:      `{"prebid":{"skadn":{"skadnetids":["net1.skadnetwork"]}},"bidder":{"skadn_supported":false}}`,
+			networks:    map[string]bool{"net1.skadnetwork": true},
+			expected:    skanNotSupported,
+		},
+		{
+			description: "no param nor SKAN ids",
+			impExt:      `{"prebid":{"skadn":{"skadnetids":["net1.skadnetwork"]}},"bidder":{}}`,
+			expected:    skanNotSupported,
+		},
+		{
+			description: "no param, requested SKAN id",
+			impExt:      `{"prebid":{"skadn":{"skadnetids":["NET1.skadnetwork"]}},"bidder":{}}`,
+			networks:    map[string]bool{"net1.skadnetwork": true},
+		},
+		{
+			description: "SKAN id from the additional list",
+			impExt:      `{"prebid":{"skadn":{"skadnetids":["net1.skadnetwork"],"skadnetlist":{"addl":["net2.skadnetwork"]}}},"bidder":{}}`,
+			networks:    map[string]bool{"net2.skadnetwork": true},
+		},
+		{
+			description: "SKAN ids from the IAB list version",
+			impExt:      `{"prebid":{"skadn":{"skadnetlist":{"max":300}}},"bidder":{}}`,
+			networks:    map[string]bool{"net2.skadnetwork": true},
+		},
+		{
+			description: "SKAN id not requested",
+			impExt:      `{"prebid":{"skadn":{"skadnetids":["net1.skadnetwork"]}},"bidder":{"skadn_supported":true}}`,
+			networks:    map[string]bool{"net2.skadnetwork": true},
+			expected:    skanIDNotRequested,
+		},
+	}
+
+	for _, test := range testCases {
+		imp := &openrtb2.Imp{ID: "imp", Ext: json.RawMessage(test.impExt)}
+		assert.Equal(t, test.expected, skanAttributionProblem(imp, test.networks, nil), test.description)
+	}
+}
+
+func TestSKANRoutingDebugOutput(t *testing.T) {
+	request, bidderRequests := skanRoutingRequests()
+	_, routing := routeSKAN(request, bidderRequests, config.SKANRoutingExclude, nil)
+	e := &exchange{}
+
+	ext := e.makeExtBidResponse(nil, nil, AuctionRequest{BidRequest: request}, true, nil, routing)
+	if assert.NotNil(t, ext.Prebid) {
+		assert.Equal(t, routing.debug(), ext.Prebid.SKANRouting)
+	}
+
+	ext = e.makeExtBidResponse(nil, nil, AuctionRequest{BidRequest: request}, false, nil, routing)
+	assert.Nil(t, ext.Prebid, "only emitted for debugging")
+}
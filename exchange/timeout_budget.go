@@ -0,0 +1,129 @@
+package exchange
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/analytics"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+// timeoutBudgets gives every bidder a deadline at a percentile of its recent latencies, capped by the auction
+// deadline. A nil timeoutBudgets leaves the auction deadline and the tmax of the requests as they are.
+type timeoutBudgets struct {
+	cfg config.AdaptiveTimeouts
+	now func() time.Time
+
+	mux       sync.Mutex
+	latencies map[openrtb_ext.BidderName]*latencyWindow
+}
+
+// latencyWindow keeps the last latencies of a bidder, overwriting the oldest one once full
+type latencyWindow struct {
+	samples []time.Duration
+	next    int
+}
+
+func newTimeoutBudgets(cfg config.AdaptiveTimeouts) *timeoutBudgets {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &timeoutBudgets{
+		cfg:       cfg,
+		now:       time.Now,
+		latencies: make(map[openrtb_ext.BidderName]*latencyWindow),
+	}
+}
+
+// record adds the latency of a request to the bidder to its window
+func (tb *timeoutBudgets) record(bidder openrtb_ext.BidderName, latency time.Duration) {
+	if tb == nil {
+		return
+	}
+	tb.mux.Lock()
+	defer tb.mux.Unlock()
+
+	window, ok := tb.latencies[bidder]
+	if !ok {
+		window = &latencyWindow{samples: make([]time.Duration, 0, tb.cfg.Samples)}
+		tb.latencies[bidder] = window
+	}
+	if len(window.samples) < tb.cfg.Samples {
+		window.samples = append(window.samples, latency)
+		return
+	}
+	window.samples[window.next] = latency
+	window.next = (window.next + 1) % tb.cfg.Samples
+}
+
+// percentile returns the configured percentile of the recent latencies of the bidder, along with the number
+// of latencies it was computed over
+func (tb *timeoutBudgets) percentile(bidder openrtb_ext.BidderName) (time.Duration, int) {
+	tb.mux.Lock()
+	window, ok := tb.latencies[bidder]
+	if !ok {
+		tb.mux.Unlock()
+		return 0, 0
+	}
+	samples := append([]time.Duration(nil), window.samples...)
+	tb.mux.Unlock()
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	// nearest rank
+	rank := int(math.Ceil(tb.cfg.Percentile / 100 * float64(len(samples))))
+	if rank < 1 {
+		rank = 1
+	}
+	return samples[rank-1], len(samples)
+}
+
+// apply derives the context of the request to the bidder from the auction context, with a deadline at the
+// budget of the bidder, and rewrites the tmax of the request to the time left minus the network overhead.
+// The bidders with too few recent latencies are given the auction deadline.
+func (tb *timeoutBudgets) apply(ctx context.Context, bidder openrtb_ext.BidderName, request *openrtb2.BidRequest) (context.Context, context.CancelFunc, *openrtb_ext.ExtTimeoutBudget) {
+	if tb == nil {
+		return ctx, func() {}, nil
+	}
+
+	debug := &openrtb_ext.ExtTimeoutBudget{TMax: request.TMax}
+	percentile, samples := tb.percentile(bidder)
+	debug.PercentileMillis = percentile.Milliseconds()
+	debug.Samples = samples
+
+	cancel := func() {}
+	if samples >= tb.cfg.MinSamples {
+		budget := percentile + percentile*time.Duration(tb.cfg.HeadroomPercent)/100
+		if minBudget := time.Duration(tb.cfg.MinBudgetMs) * time.Millisecond; budget < minBudget {
+			budget = minBudget
+		}
+		// the auction deadline is kept when it comes first
+		ctx, cancel = context.WithDeadline(ctx, tb.now().Add(budget))
+		debug.BudgetMillis = budget.Milliseconds()
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		tmax := (deadline.Sub(tb.now()) - time.Duration(tb.cfg.NetworkOverheadMs)*time.Millisecond).Milliseconds()
+		if tmax < 1 {
+			tmax = 1
+		}
+		request.TMax = tmax
+		debug.TMax = tmax
+	}
+	return ctx, cancel, debug
+}
+
+// calledBidder tells whether any of the calls reached the bidder, the calls skipped by an open circuit do not
+// tell how fast the bidder is
+func calledBidder(calls []*analytics.BidderCall) bool {
+	for _, call := range calls {
+		if call.ErrorType != circuitOpenErrorType {
+			return true
+		}
+	}
+	return false
+}
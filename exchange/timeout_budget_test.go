@@ -0,0 +1,226 @@
+package exchange
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/analytics"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/currency"
+	metricsConf "github.com/prebid/prebid-server/metrics/config"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTimeoutBudgets() *timeoutBudgets {
+	budgets := newTimeoutBudgets(config.AdaptiveTimeouts{
+		Enabled:           true,
+		Samples:           20,
+		MinSamples:        10,
+		Percentile:        95,
+		HeadroomPercent:   20,
+		MinBudgetMs:       50,
+		NetworkOverheadMs: 20,
+	})
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	budgets.now = func() time.Time { return now }
+	return budgets
+}
+
+func TestTimeoutBudgetsPercentile(t *testing.T) {
+	budgets := newTestTimeoutBudgets()
+
+	percentile, samples := budgets.percentile(openrtb_ext.BidderAppnexus)
+	assert.Equal(t, time.Duration(0), percentile)
+	assert.Equal(t, 0, samples)
+
+	for i := 1; i <= 20; i++ {
+		budgets.record(openrtb_ext.BidderAppnexus, time.Duration(i)*time.Millisecond)
+	}
+	percentile, samples = budgets.percentile(openrtb_ext.BidderAppnexus)
+	assert.Equal(t, 19*time.Millisecond, percentile, "the 19th of 20 latencies")
+	assert.Equal(t, 20, samples)
+
+	for i := 0; i < 20; i++ {
+		budgets.record(openrtb_ext.BidderAppnexus, 100*time.Millisecond)
+	}
+	percentile, samples = budgets.percentile(openrtb_ext.BidderAppnexus)
+	assert.Equal(t, 100*time.Millisecond, percentile, "the oldest latencies are overwritten")
+	assert.Equal(t, 20, samples)
+}
+
+func TestTimeoutBudgetsApply(t *testing.T) {
+	testCases := []struct {
+		description      string
+		latency          time.Duration
+		samples          int
+		auctionTimeout   time.Duration
+		expectedDeadline time.Duration
+		expected         *openrtb_ext.ExtTimeoutBudget
+	}{
+		{
+			description:      "too few samples",
+			latency:          100 * time.Millisecond,
+			samples:          9,
+			auctionTimeout:   500 * time.Millisecond,
+			expectedDeadline: 500 * time.Millisecond,
+			expected:         &openrtb_ext.ExtTimeoutBudget{PercentileMillis: 100, Samples: 9, TMax: 480},
+		},
+		{
+			description:      "percentile and headroom",
+			latency:          100 * time.Millisecond,
+			samples:          10,
+			auctionTimeout:   500 * time.Millisecond,
+			expectedDeadline: 120 * time.Millisecond,
+			expected:         &openrtb_ext.ExtTimeoutBudget{BudgetMillis: 120, PercentileMillis: 100, Samples: 10, TMax: 100},
+		},
+		{
+			description:      "min budget",
+			latency:          10 * time.Millisecond,
+			samples:          10,
+			auctionTimeout:   500 * time.Millisecond,
+			expectedDeadline: 50 * time.Millisecond,
+			expected:         &openrtb_ext.ExtTimeoutBudget{BudgetMillis: 50, PercentileMillis: 10, Samples: 10, TMax: 30},
+		},
+		{
+			description:      "capped by the auction deadline",
+			latency:          400 * time.Millisecond,
+			samples:          10,
+			auctionTimeout:   300 * time.Millisecond,
+			expectedDeadline: 300 * time.Millisecond,
+			expected:         &openrtb_ext.ExtTimeoutBudget{BudgetMillis: 480, PercentileMillis: 400, Samples: 10, TMax: 280},
+		},
+		{
+			description:      "no time left for the network",
+			latency:          10 * time.Millisecond,
+			samples:          10,
+			auctionTimeout:   10 * time.Millisecond,
+			expectedDeadline: 10 * time.Millisecond,
+			expected:         &openrtb_ext.ExtTimeoutBudget{BudgetMillis: 50, PercentileMillis: 10, Samples: 10, TMax: 1},
+		},
+	}
+
+	for _, test := range testCases {
+		budgets := newTestTimeoutBudgets()
+		for i := 0; i < test.samples; i++ {
+			budgets.record(openrtb_ext.BidderAppnexus, test.latency)
+		}
+		now := budgets.now()
+		auctionCtx, auctionCancel := context.WithDeadline(context.Background(), now.Add(test.auctionTimeout))
+		request := &openrtb2.BidRequest{TMax: 1000}
+
+		ctx, cancel, debug := budgets.apply(auctionCtx, openrtb_ext.BidderAppnexus, request)
+
+		deadline, ok := ctx.Deadline()
+		if assert.True(t, ok, test.description) {
+			assert.Equal(t, now.Add(test.expectedDeadline), deadline, test.description)
+		}
+		assert.Equal(t, test.expected, debug, test.description)
+		assert.Equal(t, test.expected.TMax, request.TMax, test.description)
+		cancel()
+		auctionCancel()
+	}
+}
+
+func TestTimeoutBudgetsApplyWithoutAuctionDeadline(t *testing.T) {
+	budgets := newTestTimeoutBudgets()
+	request := &openrtb2.BidRequest{TMax: 1000}
+
+	ctx, cancel, debug := budgets.apply(context.Background(), openrtb_ext.BidderAppnexus, request)
+	defer cancel()
+
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+	assert.Equal(t, &openrtb_ext.ExtTimeoutBudget{TMax: 1000}, debug)
+	assert.Equal(t, int64(1000), request.TMax, "the tmax of the publisher is kept")
+}
+
+func TestTimeoutBudgetsDisabled(t *testing.T) {
+	var budgets *timeoutBudgets = newTimeoutBudgets(config.AdaptiveTimeouts{})
+	assert.Nil(t, budgets)
+
+	budgets.record(openrtb_ext.BidderAppnexus, time.Second)
+	auctionCtx, auctionCancel := context.WithTimeout(context.Background(), time.Second)
+	defer auctionCancel()
+	request := &openrtb2.BidRequest{TMax: 1000}
+
+	ctx, cancel, debug := budgets.apply(auctionCtx, openrtb_ext.BidderAppnexus, request)
+	defer cancel()
+
+	assert.Equal(t, auctionCtx, ctx)
+	assert.Nil(t, debug)
+	assert.Equal(t, int64(1000), request.TMax)
+}
+
+func TestCalledBidder(t *testing.T) {
+	assert.False(t, calledBidder(nil))
+	assert.False(t, calledBidder([]*analytics.BidderCall{{ErrorType: circuitOpenErrorType}}))
+	assert.True(t, calledBidder([]*analytics.BidderCall{{ErrorType: circuitOpenErrorType}, {Status: 204}}))
+}
+
+// deadlineBidder records the deadline and the tmax of the request it is given
+type deadlineBidder struct {
+	deadline time.Time
+	tmax     int64
+}
+
+func (b *deadlineBidder) requestBid(ctx context.Context, request *openrtb2.BidRequest, name openrtb_ext.BidderName, bidAdjustment float64, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, accountDebugAllowed, headerDebugAllowed bool) (*pbsOrtbSeatBid, []error) {
+	b.deadline, _ = ctx.Deadline()
+	b.tmax = request.TMax
+	return &pbsOrtbSeatBid{bidderCalls: []*analytics.BidderCall{{Bidder: string(name), Status: 204}}}, nil
+}
+
+func (b *deadlineBidder) client() *http.Client {
+	return http.DefaultClient
+}
+
+func TestGetAllBidsTimeoutBudgets(t *testing.T) {
+	budgets := newTestTimeoutBudgets()
+	for i := 0; i < 10; i++ {
+		budgets.record(openrtb_ext.BidderAppnexus, 100*time.Millisecond)
+	}
+	budgets.now = time.Now
+	bidder := &deadlineBidder{}
+	e := &exchange{
+		adapterMap:     map[openrtb_ext.BidderName]adaptedBidder{openrtb_ext.BidderAppnexus: bidder},
+		me:             &metricsConf.DummyMetricsEngine{},
+		timeoutBudgets: budgets,
+	}
+	bidderRequests := []BidderRequest{
+		{BidderName: "appnexus", BidderCoreName: openrtb_ext.BidderAppnexus, BidRequest: &openrtb2.BidRequest{TMax: 1000}},
+	}
+	auctionCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, adapterExtra, _ := e.getAllBids(auctionCtx, bidderRequests, nil, nil, true, "", true, false, nil, nil)
+
+	assert.WithinDuration(t, start.Add(120*time.Millisecond), bidder.deadline, 20*time.Millisecond, "the deadline is the budget of the bidder")
+	assert.InDelta(t, 100, bidder.tmax, 20, "the tmax is the budget minus the network overhead")
+	if assert.NotNil(t, adapterExtra[openrtb_ext.BidderAppnexus].TimeoutBudget) {
+		assert.Equal(t, int64(120), adapterExtra[openrtb_ext.BidderAppnexus].TimeoutBudget.BudgetMillis)
+	}
+	_, samples := budgets.percentile(openrtb_ext.BidderAppnexus)
+	assert.Equal(t, 11, samples, "the latency of the request is recorded")
+}
+
+func TestTimeoutBudgetsDebugOutput(t *testing.T) {
+	budget := &openrtb_ext.ExtTimeoutBudget{BudgetMillis: 120, PercentileMillis: 100, Samples: 10, TMax: 100}
+	adapterExtra := map[openrtb_ext.BidderName]*seatResponseExtra{
+		openrtb_ext.BidderAppnexus: {TimeoutBudget: budget},
+		openrtb_ext.BidderRubicon:  {},
+	}
+	e := &exchange{}
+
+	ext := e.makeExtBidResponse(nil, adapterExtra, AuctionRequest{BidRequest: &openrtb2.BidRequest{}}, true, nil, nil)
+	if assert.NotNil(t, ext.Debug) {
+		assert.Equal(t, map[openrtb_ext.BidderName]*openrtb_ext.ExtTimeoutBudget{openrtb_ext.BidderAppnexus: budget}, ext.Debug.TimeoutBudgets)
+	}
+
+	ext = e.makeExtBidResponse(nil, adapterExtra, AuctionRequest{BidRequest: &openrtb2.BidRequest{}}, false, nil, nil)
+	assert.Nil(t, ext.Debug, "only emitted for debugging")
+}
@@ -6,8 +6,8 @@ import (
 	"fmt"
 	"math/rand"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
 	"github.com/prebid/go-gdpr/vendorconsent"
+	"github.com/prebid/openrtb/v20/openrtb2"
 
 	"github.com/buger/jsonparser"
 	"github.com/prebid/prebid-server/config"
@@ -16,6 +16,7 @@ import (
 	"github.com/prebid/prebid-server/openrtb_ext"
 	"github.com/prebid/prebid-server/privacy"
 	"github.com/prebid/prebid-server/privacy/ccpa"
+	"github.com/prebid/prebid-server/privacy/gpp"
 	"github.com/prebid/prebid-server/privacy/lmt"
 )
 
@@ -59,7 +60,8 @@ func cleanOpenRTBRequests(ctx context.Context,
 	metricsEngine metrics.MetricsEngine,
 	gdprDefaultValue gdpr.Signal,
 	privacyConfig config.Privacy,
-	account *config.Account) (allowedBidderRequests []BidderRequest, privacyLabels metrics.PrivacyLabels, errs []error) {
+	account *config.Account,
+	seats map[openrtb_ext.BidderName]openrtb_ext.BidderName) (allowedBidderRequests []BidderRequest, privacyLabels metrics.PrivacyLabels, errs []error) {
 
 	impsByBidder, err := splitImps(req.BidRequest.Imp)
 	if err != nil {
@@ -89,7 +91,12 @@ func cleanOpenRTBRequests(ctx context.Context,
 	}
 	gdprEnforced := gdprSignal == gdpr.SignalYes || (gdprSignal == gdpr.SignalAmbiguous && gdprDefaultValue == gdpr.SignalYes)
 
-	ccpaEnforcer, err := extractCCPA(req.BidRequest, privacyConfig, &req.Account, aliases, integrationTypeMap[req.LegacyLabels.RType])
+	ccpaEnforcer, err := extractCCPA(req.BidRequest, privacyConfig, &req.Account, aliases, seats, integrationTypeMap[req.LegacyLabels.RType])
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	gppEnforcer, err := extractGPP(req.BidRequest, privacyConfig)
 	if err != nil {
 		errs = append(errs, err)
 	}
@@ -99,6 +106,7 @@ func cleanOpenRTBRequests(ctx context.Context,
 	// request level privacy policies
 	privacyEnforcement := privacy.Enforcement{
 		COPPA: req.BidRequest.Regs != nil && req.BidRequest.Regs.COPPA == 1,
+		GPP:   gppEnforcer.ShouldEnforce(unknownBidder),
 		LMT:   lmtEnforcer.ShouldEnforce(unknownBidder),
 	}
 
@@ -177,13 +185,13 @@ func ccpaEnabled(account *config.Account, privacyConfig config.Privacy, requestT
 	return privacyConfig.CCPA.Enforce
 }
 
-func extractCCPA(orig *openrtb2.BidRequest, privacyConfig config.Privacy, account *config.Account, aliases map[string]string, requestType config.IntegrationType) (privacy.PolicyEnforcer, error) {
+func extractCCPA(orig *openrtb2.BidRequest, privacyConfig config.Privacy, account *config.Account, aliases map[string]string, seats map[openrtb_ext.BidderName]openrtb_ext.BidderName, requestType config.IntegrationType) (privacy.PolicyEnforcer, error) {
 	ccpaPolicy, err := ccpa.ReadFromRequest(orig)
 	if err != nil {
 		return privacy.NilPolicyEnforcer{}, err
 	}
 
-	validBidders := GetValidBidders(aliases)
+	validBidders := GetValidBidders(aliases, seats)
 	ccpaParsedPolicy, err := ccpaPolicy.Parse(validBidders)
 	if err != nil {
 		return privacy.NilPolicyEnforcer{}, err
@@ -196,6 +204,54 @@ func extractCCPA(orig *openrtb2.BidRequest, privacyConfig config.Privacy, accoun
 	return ccpaEnforcer, nil
 }
 
+func extractGPP(orig *openrtb2.BidRequest, privacyConfig config.Privacy) (privacy.PolicyEnforcer, error) {
+	gppPolicy, err := gpp.ReadFromRequest(orig)
+	if err != nil {
+		return privacy.NilPolicyEnforcer{}, err
+	}
+
+	gppParsedPolicy, err := gppPolicy.Parse()
+	if err != nil {
+		return privacy.NilPolicyEnforcer{}, err
+	}
+
+	return privacy.EnabledPolicyEnforcer{
+		Enabled:        privacyConfig.GPP.Enforce,
+		PolicyEnforcer: gppParsedPolicy,
+	}, nil
+}
+
+// writeBidderGPP removes the GPP string from the requests of the bidders not reading it, their requests get the
+// CCPA string equivalent to its US sections instead when they carry no CCPA string.
+func writeBidderGPP(bidderRequests []BidderRequest, bidderInfos config.BidderInfos) []error {
+	var errs []error
+	for _, bidderRequest := range bidderRequests {
+		if bidderInfos[string(bidderRequest.BidderCoreName)].GPP {
+			continue
+		}
+
+		gppPolicy, err := gpp.ReadFromRequest(bidderRequest.BidRequest)
+		if err != nil || gppPolicy.Consent == "" {
+			continue
+		}
+		if err := (gpp.ConsentWriter{}).Write(bidderRequest.BidRequest); err != nil {
+			errs = append(errs, fmt.Errorf("Unable to remove the GPP string from the request of bidder %s. (%v)", bidderRequest.BidderName, err))
+			continue
+		}
+
+		gppParsedPolicy, err := gppPolicy.Parse()
+		if err != nil || gppParsedPolicy.USPrivacy() == "" {
+			continue
+		}
+		if ccpaPolicy, err := ccpa.ReadFromRequest(bidderRequest.BidRequest); err == nil && ccpaPolicy.Consent == "" {
+			if err := (ccpa.ConsentWriter{Consent: gppParsedPolicy.USPrivacy()}).Write(bidderRequest.BidRequest); err != nil {
+				errs = append(errs, fmt.Errorf("Unable to write the CCPA string of the GPP string to the request of bidder %s. (%v)", bidderRequest.BidderName, err))
+			}
+		}
+	}
+	return errs
+}
+
 func extractLMT(orig *openrtb2.BidRequest, privacyConfig config.Privacy) privacy.PolicyEnforcer {
 	return privacy.EnabledPolicyEnforcer{
 		Enabled:        privacyConfig.LMT.Enforce,
@@ -274,6 +330,8 @@ func getExtJson(req *openrtb2.BidRequest, unpackedExt *openrtb_ext.ExtRequest) (
 
 	extCopy := *unpackedExt
 	extCopy.Prebid.SChains = nil
+	extCopy.Prebid.Floors = nil
+	extCopy.Prebid.MultiBid = nil
 	return json.Marshal(extCopy)
 }
 
@@ -295,17 +353,20 @@ func prepareSource(req *openrtb2.BidRequest, bidder string, sChainsByBidder map[
 		selectedSChain = wildCardSChain
 	}
 
-	// set source
-	if req.Source == nil {
-		req.Source = &openrtb2.Source{}
+	// set source, the schain selected replaces the OpenRTB 2.6 source.schain
+	var source openrtb2.Source
+	if req.Source != nil {
+		source = *req.Source
 	}
 	schain := openrtb_ext.ExtRequestPrebidSChain{
 		SChain: *selectedSChain,
 	}
 	sourceExt, err := json.Marshal(schain)
 	if err == nil {
-		req.Source.Ext = sourceExt
+		source.SChain = nil
+		source.Ext = sourceExt
 	}
+	req.Source = &source
 }
 
 // extractBuyerUIDs parses the values from user.ext.prebid.buyeruids, and then deletes those values from the ext.
@@ -400,6 +461,8 @@ func createSanitizedImpExt(impExt, impExtPrebid map[string]json.RawMessage) (map
 	sanitizedImpExt := make(map[string]json.RawMessage, 3)
 
 	delete(impExtPrebid, openrtb_ext.PrebidExtBidderKey)
+	// the deals of the imp are offered in imp.pmp.deals to the bidders they are made with
+	delete(impExtPrebid, "deals")
 	if len(impExtPrebid) > 0 {
 		if impExtPrebidJSON, err := json.Marshal(impExtPrebid); err == nil {
 			sanitizedImpExt[openrtb_ext.PrebidExtKey] = impExtPrebidJSON
@@ -485,10 +548,10 @@ func copyWithBuyerUID(user *openrtb2.User, buyerUID string) *openrtb2.User {
 	return user
 }
 
-// removeUnpermissionedEids modifies the request to remove any request.user.ext.eids not permissions for the specific bidder
+// removeUnpermissionedEids modifies the request to remove any request.user.eids and request.user.ext.eids not permissions for the specific bidder
 func removeUnpermissionedEids(request *openrtb2.BidRequest, bidder string, requestExt *openrtb_ext.ExtRequest) error {
 	// ensure request might have eids (as much as we can check before unmarshalling)
-	if request.User == nil || len(request.User.Ext) == 0 {
+	if request.User == nil || (len(request.User.Ext) == 0 && len(request.User.EIDs) == 0) {
 		return nil
 	}
 
@@ -497,6 +560,17 @@ func removeUnpermissionedEids(request *openrtb2.BidRequest, bidder string, reque
 		return nil
 	}
 
+	// translate eid permissions to a map for quick lookup
+	eidRules := make(map[string][]string)
+	for _, p := range requestExt.Prebid.Data.EidPermissions {
+		eidRules[p.Source] = p.Bidders
+	}
+
+	removeUnpermissionedUserEids(request, bidder, eidRules)
+	if len(request.User.Ext) == 0 {
+		return nil
+	}
+
 	// low level unmarshal to preserve other request.user.ext values. prebid server is non-destructive.
 	var userExt map[string]json.RawMessage
 	if err := json.Unmarshal(request.User.Ext, &userExt); err != nil {
@@ -518,27 +592,9 @@ func removeUnpermissionedEids(request *openrtb2.BidRequest, bidder string, reque
 		return nil
 	}
 
-	// translate eid permissions to a map for quick lookup
-	eidRules := make(map[string][]string)
-	for _, p := range requestExt.Prebid.Data.EidPermissions {
-		eidRules[p.Source] = p.Bidders
-	}
-
 	eidsAllowed := make([]openrtb_ext.ExtUserEid, 0, len(eids))
 	for _, eid := range eids {
-		allowed := false
-		if rule, hasRule := eidRules[eid.Source]; hasRule {
-			for _, ruleBidder := range rule {
-				if ruleBidder == "*" || ruleBidder == bidder {
-					allowed = true
-					break
-				}
-			}
-		} else {
-			allowed = true
-		}
-
-		if allowed {
+		if eidPermitted(eidRules, eid.Source, bidder) {
 			eidsAllowed = append(eidsAllowed, eid)
 		}
 	}
@@ -573,6 +629,39 @@ func removeUnpermissionedEids(request *openrtb2.BidRequest, bidder string, reque
 	return nil
 }
 
+// removeUnpermissionedUserEids removes the OpenRTB 2.6 request.user.eids not permitted for the bidder
+func removeUnpermissionedUserEids(request *openrtb2.BidRequest, bidder string, eidRules map[string][]string) {
+	eidsAllowed := make([]openrtb2.EID, 0, len(request.User.EIDs))
+	for _, eid := range request.User.EIDs {
+		if eidPermitted(eidRules, eid.Source, bidder) {
+			eidsAllowed = append(eidsAllowed, eid)
+		}
+	}
+	if len(eidsAllowed) == len(request.User.EIDs) {
+		return
+	}
+
+	userCopy := *request.User
+	userCopy.EIDs = nil
+	if len(eidsAllowed) > 0 {
+		userCopy.EIDs = eidsAllowed
+	}
+	request.User = &userCopy
+}
+
+func eidPermitted(eidRules map[string][]string, source string, bidder string) bool {
+	rule, hasRule := eidRules[source]
+	if !hasRule {
+		return true
+	}
+	for _, ruleBidder := range rule {
+		if ruleBidder == "*" || ruleBidder == bidder {
+			return true
+		}
+	}
+	return false
+}
+
 func setUserExtWithCopy(request *openrtb2.BidRequest, userExtJSON json.RawMessage) {
 	userCopy := *request.User
 	userCopy.Ext = userExtJSON
@@ -600,13 +689,18 @@ func parseAliases(orig *openrtb2.BidRequest) (map[string]string, []error) {
 	return aliases, nil
 }
 
-func GetValidBidders(aliases map[string]string) map[string]struct{} {
+// GetValidBidders returns the core bidders, the aliases of the request and the seats set in the adapter config.
+func GetValidBidders(aliases map[string]string, seats map[openrtb_ext.BidderName]openrtb_ext.BidderName) map[string]struct{} {
 	validBidders := openrtb_ext.BuildBidderNameHashSet()
 
 	for k := range aliases {
 		validBidders[k] = struct{}{}
 	}
 
+	for seat := range seats {
+		validBidders[string(seat)] = struct{}{}
+	}
+
 	return validBidders
 }
 
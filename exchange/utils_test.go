@@ -7,7 +7,7 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/gdpr"
@@ -479,7 +479,7 @@ func TestCleanOpenRTBRequests(t *testing.T) {
 	for _, test := range testCases {
 		metricsMock := metrics.MetricsEngineMock{}
 		permissions := permissionsMock{allowAllBidders: true, passGeo: true, passID: true}
-		bidderRequests, _, err := cleanOpenRTBRequests(context.Background(), test.req, nil, &permissions, &metricsMock, gdpr.SignalNo, privacyConfig, nil)
+		bidderRequests, _, err := cleanOpenRTBRequests(context.Background(), test.req, nil, &permissions, &metricsMock, gdpr.SignalNo, privacyConfig, nil, nil)
 		if test.hasError {
 			assert.NotNil(t, err, "Error shouldn't be nil")
 		} else {
@@ -638,6 +638,7 @@ func TestCleanOpenRTBRequestsCCPA(t *testing.T) {
 			&metrics.MetricsEngineMock{},
 			gdpr.SignalNo,
 			privacyConfig,
+			nil,
 			nil)
 		result := bidderRequests[0]
 
@@ -698,7 +699,7 @@ func TestCleanOpenRTBRequestsCCPAErrors(t *testing.T) {
 		}
 		permissions := permissionsMock{allowAllBidders: true, passGeo: true, passID: true}
 		metrics := metrics.MetricsEngineMock{}
-		_, _, errs := cleanOpenRTBRequests(context.Background(), auctionReq, &reqExtStruct, &permissions, &metrics, gdpr.SignalNo, privacyConfig, nil)
+		_, _, errs := cleanOpenRTBRequests(context.Background(), auctionReq, &reqExtStruct, &permissions, &metrics, gdpr.SignalNo, privacyConfig, nil, nil)
 
 		assert.ElementsMatch(t, []error{test.expectError}, errs, test.description)
 	}
@@ -740,7 +741,7 @@ func TestCleanOpenRTBRequestsCOPPA(t *testing.T) {
 
 		permissions := permissionsMock{allowAllBidders: true, passGeo: true, passID: true}
 		metrics := metrics.MetricsEngineMock{}
-		bidderRequests, privacyLabels, errs := cleanOpenRTBRequests(context.Background(), auctionReq, nil, &permissions, &metrics, gdpr.SignalNo, config.Privacy{}, nil)
+		bidderRequests, privacyLabels, errs := cleanOpenRTBRequests(context.Background(), auctionReq, nil, &permissions, &metrics, gdpr.SignalNo, config.Privacy{}, nil, nil)
 		result := bidderRequests[0]
 
 		assert.Nil(t, errs)
@@ -849,7 +850,7 @@ func TestCleanOpenRTBRequestsSChain(t *testing.T) {
 
 		permissions := permissionsMock{allowAllBidders: true, passGeo: true, passID: true}
 		metrics := metrics.MetricsEngineMock{}
-		bidderRequests, _, errs := cleanOpenRTBRequests(context.Background(), auctionReq, extRequest, &permissions, &metrics, gdpr.SignalNo, config.Privacy{}, nil)
+		bidderRequests, _, errs := cleanOpenRTBRequests(context.Background(), auctionReq, extRequest, &permissions, &metrics, gdpr.SignalNo, config.Privacy{}, nil, nil)
 		if test.hasError == true {
 			assert.NotNil(t, errs)
 			assert.Len(t, bidderRequests, 0)
@@ -1432,7 +1433,7 @@ func TestCleanOpenRTBRequestsLMT(t *testing.T) {
 
 		permissions := permissionsMock{allowAllBidders: true, passGeo: true, passID: true}
 		metrics := metrics.MetricsEngineMock{}
-		results, privacyLabels, errs := cleanOpenRTBRequests(context.Background(), auctionReq, nil, &permissions, &metrics, gdpr.SignalNo, privacyConfig, nil)
+		results, privacyLabels, errs := cleanOpenRTBRequests(context.Background(), auctionReq, nil, &permissions, &metrics, gdpr.SignalNo, privacyConfig, nil, nil)
 		result := results[0]
 
 		assert.Nil(t, errs)
@@ -1447,6 +1448,98 @@ func TestCleanOpenRTBRequestsLMT(t *testing.T) {
 	}
 }
 
+func TestCleanOpenRTBRequestsGPP(t *testing.T) {
+	testCases := []struct {
+		description     string
+		gpp             string
+		enforceGPP      bool
+		expectDataScrub bool
+		expectError     bool
+	}{
+		{
+			description:     "Feature Flag Enabled - Opt Out",
+			gpp:             "DBABL~BVVaAAAAAg",
+			enforceGPP:      true,
+			expectDataScrub: true,
+		},
+		{
+			description:     "Feature Flag Disabled - Opt Out",
+			gpp:             "DBABL~BVVaAAAAAg",
+			enforceGPP:      false,
+			expectDataScrub: false,
+		},
+		{
+			description:     "Feature Flag Enabled - No Opt Out",
+			gpp:             "DBABL~BVVqAAAAAg",
+			enforceGPP:      true,
+			expectDataScrub: false,
+		},
+		{
+			description:     "Feature Flag Enabled - Invalid",
+			gpp:             "DBABL~BVV",
+			enforceGPP:      true,
+			expectDataScrub: false,
+			expectError:     true,
+		},
+	}
+
+	for _, test := range testCases {
+		req := newBidRequest(t)
+		req.Regs = &openrtb2.Regs{Ext: json.RawMessage(`{"gpp":"` + test.gpp + `"}`)}
+
+		auctionReq := AuctionRequest{
+			BidRequest: req,
+			UserSyncs:  &emptyUsersync{},
+		}
+
+		privacyConfig := config.Privacy{
+			GPP: config.GPP{
+				Enforce: test.enforceGPP,
+			},
+		}
+
+		permissions := permissionsMock{allowAllBidders: true, passGeo: true, passID: true}
+		metrics := metrics.MetricsEngineMock{}
+		results, _, errs := cleanOpenRTBRequests(context.Background(), auctionReq, nil, &permissions, &metrics, gdpr.SignalNo, privacyConfig, nil, nil)
+		result := results[0]
+
+		if test.expectError {
+			assert.Len(t, errs, 1, test.description)
+		} else {
+			assert.Nil(t, errs, test.description)
+		}
+		if test.expectDataScrub {
+			assert.Equal(t, result.BidRequest.User.BuyerUID, "", test.description+":User.BuyerUID")
+			assert.Equal(t, result.BidRequest.Device.DIDMD5, "", test.description+":Device.DIDMD5")
+		} else {
+			assert.NotEqual(t, result.BidRequest.User.BuyerUID, "", test.description+":User.BuyerUID")
+			assert.NotEqual(t, result.BidRequest.Device.DIDMD5, "", test.description+":Device.DIDMD5")
+		}
+	}
+}
+
+func TestWriteBidderGPP(t *testing.T) {
+	regs := &openrtb2.Regs{Ext: json.RawMessage(`{"gpp":"DBABL~BVVaAAAAAg","gpp_sid":[7]}`)}
+	bidderRequests := []BidderRequest{
+		{BidderName: "appnexus", BidderCoreName: "appnexus", BidRequest: &openrtb2.BidRequest{Regs: regs}},
+		{BidderName: "rubicon", BidderCoreName: "rubicon", BidRequest: &openrtb2.BidRequest{Regs: regs}},
+		{BidderName: "pubmatic", BidderCoreName: "pubmatic", BidRequest: &openrtb2.BidRequest{
+			Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"us_privacy":"1NNN","gpp":"DBABL~BVVaAAAAAg"}`)},
+		}},
+		{BidderName: "openx", BidderCoreName: "openx", BidRequest: &openrtb2.BidRequest{}},
+	}
+	bidderInfos := config.BidderInfos{"appnexus": {GPP: true}}
+
+	errs := writeBidderGPP(bidderRequests, bidderInfos)
+
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"gpp":"DBABL~BVVaAAAAAg","gpp_sid":[7]}`, string(bidderRequests[0].BidRequest.Regs.Ext), "the GPP string is sent to the bidders reading it")
+	assert.JSONEq(t, `{"us_privacy":"1YYN"}`, string(bidderRequests[1].BidRequest.Regs.Ext), "the other bidders get its CCPA string")
+	assert.JSONEq(t, `{"us_privacy":"1NNN"}`, string(bidderRequests[2].BidRequest.Regs.Ext), "the CCPA string of the request is kept")
+	assert.Nil(t, bidderRequests[3].BidRequest.Regs)
+	assert.JSONEq(t, `{"gpp":"DBABL~BVVaAAAAAg","gpp_sid":[7]}`, string(regs.Ext), "the regs shared by the bidder requests are not mutated")
+}
+
 func TestCleanOpenRTBRequestsGDPR(t *testing.T) {
 	tcf2Consent := "COzTVhaOzTVhaGvAAAENAiCIAP_AAH_AAAAAAEEUACCKAAA"
 	trueValue, falseValue := true, false
@@ -1652,6 +1745,7 @@ func TestCleanOpenRTBRequestsGDPR(t *testing.T) {
 			&metrics.MetricsEngineMock{},
 			gdprDefaultValue,
 			privacyConfig,
+			nil,
 			nil)
 		result := results[0]
 
@@ -1743,6 +1837,7 @@ func TestCleanOpenRTBRequestsGDPRBlockBidRequest(t *testing.T) {
 			&metricsMock,
 			gdpr.SignalNo,
 			privacyConfig,
+			nil,
 			nil)
 
 		// extract bidder name from each request in the results
@@ -2109,6 +2204,51 @@ func TestRemoveUnpermissionedEids(t *testing.T) {
 	}
 }
 
+func TestRemoveUnpermissionedUserEids(t *testing.T) {
+	requestExt := &openrtb_ext.ExtRequest{
+		Prebid: openrtb_ext.ExtRequestPrebid{
+			Data: &openrtb_ext.ExtRequestPrebidData{
+				EidPermissions: []openrtb_ext.ExtRequestPrebidDataEidPermission{
+					{Source: "source1", Bidders: []string{"bidderB"}},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		description  string
+		eids         []openrtb2.EID
+		expectedEids []openrtb2.EID
+	}{
+		{
+			description:  "Allowed Sources",
+			eids:         []openrtb2.EID{{Source: "source2"}},
+			expectedEids: []openrtb2.EID{{Source: "source2"}},
+		},
+		{
+			description:  "Unpermissioned Source Removed",
+			eids:         []openrtb2.EID{{Source: "source1"}, {Source: "source2"}},
+			expectedEids: []openrtb2.EID{{Source: "source2"}},
+		},
+		{
+			description:  "All Removed",
+			eids:         []openrtb2.EID{{Source: "source1"}},
+			expectedEids: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		user := &openrtb2.User{EIDs: test.eids, Ext: json.RawMessage(`{"eids":[{"source":"source1"}]}`)}
+		request := &openrtb2.BidRequest{User: user}
+
+		resultErr := removeUnpermissionedEids(request, "bidderA", requestExt)
+		assert.NoError(t, resultErr, test.description)
+		assert.Equal(t, test.expectedEids, request.User.EIDs, test.description)
+		assert.Nil(t, request.User.Ext, test.description)
+		assert.Equal(t, test.eids, user.EIDs, test.description+" - the user of the request is not modified")
+	}
+}
+
 func TestRemoveUnpermissionedEidsUnmarshalErrors(t *testing.T) {
 	testCases := []struct {
 		description string
@@ -2216,3 +2356,12 @@ func TestRemoveUnpermissionedEidsEmptyValidations(t *testing.T) {
 		assert.Equal(t, &requestExpected, test.request, test.description+":request")
 	}
 }
+
+func TestGetValidBidders(t *testing.T) {
+	validBidders := GetValidBidders(map[string]string{"alias": "appnexus"}, map[openrtb_ext.BidderName]openrtb_ext.BidderName{"configseat": openrtb_ext.BidderRubicon})
+
+	assert.Contains(t, validBidders, "appnexus")
+	assert.Contains(t, validBidders, "alias")
+	assert.Contains(t, validBidders, "configseat")
+	assert.NotContains(t, validBidders, "unknown")
+}
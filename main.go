@@ -4,11 +4,13 @@ import (
 	"flag"
 	"math/rand"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/currency"
 	pbc "github.com/prebid/prebid-server/prebid_cache_client"
+	"github.com/prebid/prebid-server/replay"
 	"github.com/prebid/prebid-server/router"
 	"github.com/prebid/prebid-server/server"
 	"github.com/prebid/prebid-server/util/task"
@@ -31,6 +33,11 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		flag.CommandLine.Parse(nil) // glog writes to its log files rather than to stderr
+		os.Exit(replay.Run(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	flag.Parse() // required for glog flags and testing package flags
 
 	cfg, err := loadConfig()
@@ -93,7 +100,7 @@ func serve(revision string, cfg *config.Configuration) error {
 		}),
 	)
 
-	server.Listen(cfg, router.NoCache{Handler: otelHandler}, router.Admin(revision, currencyConverter, fetchingInterval), r.MetricsEngine)
+	server.Listen(cfg, router.NoCache{Handler: otelHandler}, router.Admin(revision, currencyConverter, fetchingInterval, r.BidderControls), r.MetricsEngine)
 
 	doneCB()
 	r.Shutdown()
@@ -3,8 +3,10 @@ package config
 import (
 	"time"
 
+	"github.com/golang/glog"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/metrics"
+	otelmetrics "github.com/prebid/prebid-server/metrics/opentelemetry"
 	prometheusmetrics "github.com/prebid/prebid-server/metrics/prometheus"
 	"github.com/prebid/prebid-server/openrtb_ext"
 	gometrics "github.com/rcrowley/go-metrics"
@@ -15,9 +17,9 @@ import (
 // for this instance.
 func NewMetricsEngine(cfg *config.Configuration, adapterList []openrtb_ext.BidderName) *DetailedMetricsEngine {
 	// Create a list of metrics engines to use.
-	// Capacity of 2, as unlikely to have more than 2 metrics backends, and in the case
+	// Capacity of 3, as unlikely to have more than 3 metrics backends, and in the case
 	// of 1 we won't use the list so it will be garbage collected.
-	engineList := make(MultiMetricsEngine, 0, 2)
+	engineList := make(MultiMetricsEngine, 0, 3)
 	returnEngine := DetailedMetricsEngine{}
 
 	if cfg.Metrics.Influxdb.Host != "" {
@@ -40,6 +42,16 @@ func NewMetricsEngine(cfg *config.Configuration, adapterList []openrtb_ext.Bidde
 		returnEngine.PrometheusMetrics = prometheusmetrics.NewMetrics(cfg.Metrics.Prometheus, cfg.Metrics.Disabled)
 		engineList = append(engineList, returnEngine.PrometheusMetrics)
 	}
+	if cfg.Metrics.OpenTelemetry.Endpoint != "" {
+		// Set up the OpenTelemetry metrics, exported to the OTLP collector.
+		otelMetrics, err := otelmetrics.NewMetrics(cfg.Metrics.OpenTelemetry, cfg.Region, cfg.Metrics.Disabled)
+		if err != nil {
+			glog.Errorf("Failed to set up the OpenTelemetry metrics, they are not exported: %v", err)
+		} else {
+			returnEngine.OpenTelemetryMetrics = otelMetrics
+			engineList = append(engineList, returnEngine.OpenTelemetryMetrics)
+		}
+	}
 
 	// Now return the proper metrics engine
 	if len(engineList) > 1 {
@@ -56,8 +68,18 @@ func NewMetricsEngine(cfg *config.Configuration, adapterList []openrtb_ext.Bidde
 // DetailedMetricsEngine is a MultiMetricsEngine that preserves links to underlying metrics engines.
 type DetailedMetricsEngine struct {
 	metrics.MetricsEngine
-	GoMetrics         *metrics.Metrics
-	PrometheusMetrics *prometheusmetrics.Metrics
+	GoMetrics            *metrics.Metrics
+	PrometheusMetrics    *prometheusmetrics.Metrics
+	OpenTelemetryMetrics *otelmetrics.Metrics
+}
+
+// Shutdown exports the metrics one last time to the backends pushing them periodically
+func (me *DetailedMetricsEngine) Shutdown() {
+	if me.OpenTelemetryMetrics != nil {
+		if err := me.OpenTelemetryMetrics.Stop(); err != nil {
+			glog.Errorf("Failed to stop the OpenTelemetry metrics: %v", err)
+		}
+	}
 }
 
 // MultiMetricsEngine logs metrics to multiple metrics databases The can be useful in transitioning
@@ -251,6 +273,41 @@ func (me *MultiMetricsEngine) RecordAdapterGDPRRequestBlocked(adapter openrtb_ex
 	}
 }
 
+// RecordSKANIDListFetch across all engines
+func (me *MultiMetricsEngine) RecordSKANIDListFetch(adapter openrtb_ext.BidderName, success bool, length time.Duration, listSize int) {
+	for _, thisME := range *me {
+		thisME.RecordSKANIDListFetch(adapter, success, length, listSize)
+	}
+}
+
+// RecordAdapterCircuitBreaker across all engines
+func (me *MultiMetricsEngine) RecordAdapterCircuitBreaker(adapter openrtb_ext.BidderName, state metrics.CircuitBreakerState) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterCircuitBreaker(adapter, state)
+	}
+}
+
+// RecordAdapterBlockedCreative across all engines
+func (me *MultiMetricsEngine) RecordAdapterBlockedCreative(adapter openrtb_ext.BidderName, blocklist metrics.CreativeBlocklist) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterBlockedCreative(adapter, blocklist)
+	}
+}
+
+// RecordAdapterHedge across all engines
+func (me *MultiMetricsEngine) RecordAdapterHedge(adapter openrtb_ext.BidderName, winner metrics.HedgeWinner) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterHedge(adapter, winner)
+	}
+}
+
+// RecordAdapterPayload across all engines
+func (me *MultiMetricsEngine) RecordAdapterPayload(adapter openrtb_ext.BidderName, direction metrics.PayloadDirection, rawBytes int, wireBytes int) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterPayload(adapter, direction, rawBytes, wireBytes)
+	}
+}
+
 // DummyMetricsEngine is a Noop metrics engine in case no metrics are configured. (may also be useful for tests)
 type DummyMetricsEngine struct{}
 
@@ -361,3 +418,23 @@ func (me *DummyMetricsEngine) RecordRequestPrivacy(privacy metrics.PrivacyLabels
 // RecordAdapterGDPRRequestBlocked as a noop
 func (me *DummyMetricsEngine) RecordAdapterGDPRRequestBlocked(adapter openrtb_ext.BidderName) {
 }
+
+// RecordSKANIDListFetch as a noop
+func (me *DummyMetricsEngine) RecordSKANIDListFetch(adapter openrtb_ext.BidderName, success bool, length time.Duration, listSize int) {
+}
+
+// RecordAdapterCircuitBreaker as a noop
+func (me *DummyMetricsEngine) RecordAdapterCircuitBreaker(adapter openrtb_ext.BidderName, state metrics.CircuitBreakerState) {
+}
+
+// RecordAdapterBlockedCreative as a noop
+func (me *DummyMetricsEngine) RecordAdapterBlockedCreative(adapter openrtb_ext.BidderName, blocklist metrics.CreativeBlocklist) {
+}
+
+// RecordAdapterHedge as a noop
+func (me *DummyMetricsEngine) RecordAdapterHedge(adapter openrtb_ext.BidderName, winner metrics.HedgeWinner) {
+}
+
+// RecordAdapterPayload as a noop
+func (me *DummyMetricsEngine) RecordAdapterPayload(adapter openrtb_ext.BidderName, direction metrics.PayloadDirection, rawBytes int, wireBytes int) {
+}
@@ -6,6 +6,7 @@ import (
 
 	mainConfig "github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/metrics"
+	otelmetrics "github.com/prebid/prebid-server/metrics/opentelemetry"
 	"github.com/prebid/prebid-server/openrtb_ext"
 	gometrics "github.com/rcrowley/go-metrics"
 )
@@ -32,6 +33,19 @@ func TestGoMetricsEngine(t *testing.T) {
 	}
 }
 
+func TestOpenTelemetryMetricsEngine(t *testing.T) {
+	cfg := mainConfig.Configuration{}
+	cfg.Metrics.OpenTelemetry.Endpoint = "localhost:4317"
+	cfg.Metrics.OpenTelemetry.CollectPeriodSeconds = 3600
+	adapterList := make([]openrtb_ext.BidderName, 0, 2)
+	testEngine := NewMetricsEngine(&cfg, adapterList)
+	defer testEngine.Shutdown()
+	_, ok := testEngine.MetricsEngine.(*otelmetrics.Metrics)
+	if !ok {
+		t.Error("Expected an OpenTelemetry Metrics as MetricsEngine, but didn't get it")
+	}
+}
+
 // Test the multiengine
 func TestMultiMetricsEngine(t *testing.T) {
 	cfg := mainConfig.Configuration{}
@@ -88,6 +88,19 @@ type AdapterMetrics struct {
 	ConnReused         metrics.Counter
 	ConnWaitTime       metrics.Timer
 	GDPRRequestBlocked metrics.Meter
+
+	SKANIDListFetchSuccessTimer metrics.Timer
+	SKANIDListFetchErrorTimer   metrics.Timer
+	SKANIDListSize              metrics.Gauge
+
+	CircuitBreakerMeters map[CircuitBreakerState]metrics.Meter
+
+	BlockedCreativeMeters map[CreativeBlocklist]metrics.Meter
+
+	HedgeMeters map[HedgeWinner]metrics.Meter
+
+	PayloadRawBytes  map[PayloadDirection]metrics.Counter
+	PayloadWireBytes map[PayloadDirection]metrics.Counter
 }
 
 type MarkupDeliveryMetrics struct {
@@ -301,6 +314,19 @@ func makeBlankAdapterMetrics(disabledMetrics config.DisabledMetrics) *AdapterMet
 		BidsReceivedMeter: blankMeter,
 		PanicMeter:        blankMeter,
 		MarkupMetrics:     makeBlankBidMarkupMetrics(),
+
+		SKANIDListFetchSuccessTimer: &metrics.NilTimer{},
+		SKANIDListFetchErrorTimer:   &metrics.NilTimer{},
+		SKANIDListSize:              metrics.NilGauge{},
+
+		CircuitBreakerMeters: make(map[CircuitBreakerState]metrics.Meter),
+
+		BlockedCreativeMeters: make(map[CreativeBlocklist]metrics.Meter),
+
+		HedgeMeters: make(map[HedgeWinner]metrics.Meter),
+
+		PayloadRawBytes:  make(map[PayloadDirection]metrics.Counter),
+		PayloadWireBytes: make(map[PayloadDirection]metrics.Counter),
 	}
 	if !disabledMetrics.AdapterConnectionMetrics {
 		newAdapter.ConnCreated = metrics.NilCounter{}
@@ -313,6 +339,19 @@ func makeBlankAdapterMetrics(disabledMetrics config.DisabledMetrics) *AdapterMet
 	for _, err := range AdapterErrors() {
 		newAdapter.ErrorMeters[err] = blankMeter
 	}
+	for _, state := range CircuitBreakerStates() {
+		newAdapter.CircuitBreakerMeters[state] = blankMeter
+	}
+	for _, blocklist := range CreativeBlocklists() {
+		newAdapter.BlockedCreativeMeters[blocklist] = blankMeter
+	}
+	for _, winner := range HedgeWinners() {
+		newAdapter.HedgeMeters[winner] = blankMeter
+	}
+	for _, direction := range PayloadDirections() {
+		newAdapter.PayloadRawBytes[direction] = metrics.NilCounter{}
+		newAdapter.PayloadWireBytes[direction] = metrics.NilCounter{}
+	}
 	return newAdapter
 }
 
@@ -350,6 +389,24 @@ func registerAdapterMetrics(registry metrics.Registry, adapterOrAccount string,
 	for err := range am.ErrorMeters {
 		am.ErrorMeters[err] = metrics.GetOrRegisterMeter(fmt.Sprintf("%s.%s.requests.%s", adapterOrAccount, exchange, err), registry)
 	}
+	if adapterOrAccount == "adapter" {
+		am.SKANIDListFetchSuccessTimer = metrics.GetOrRegisterTimer(fmt.Sprintf("%[1]s.%[2]s.skan_id_list.fetch_time.ok", adapterOrAccount, exchange), registry)
+		am.SKANIDListFetchErrorTimer = metrics.GetOrRegisterTimer(fmt.Sprintf("%[1]s.%[2]s.skan_id_list.fetch_time.err", adapterOrAccount, exchange), registry)
+		am.SKANIDListSize = metrics.GetOrRegisterGauge(fmt.Sprintf("%[1]s.%[2]s.skan_id_list.size", adapterOrAccount, exchange), registry)
+		for state := range am.CircuitBreakerMeters {
+			am.CircuitBreakerMeters[state] = metrics.GetOrRegisterMeter(fmt.Sprintf("%s.%s.circuit_breaker.%s", adapterOrAccount, exchange, state), registry)
+		}
+		for blocklist := range am.BlockedCreativeMeters {
+			am.BlockedCreativeMeters[blocklist] = metrics.GetOrRegisterMeter(fmt.Sprintf("%s.%s.blocked_creatives.%s", adapterOrAccount, exchange, blocklist), registry)
+		}
+		for winner := range am.HedgeMeters {
+			am.HedgeMeters[winner] = metrics.GetOrRegisterMeter(fmt.Sprintf("%s.%s.hedged_requests.%s", adapterOrAccount, exchange, winner), registry)
+		}
+		for direction := range am.PayloadRawBytes {
+			am.PayloadRawBytes[direction] = metrics.GetOrRegisterCounter(fmt.Sprintf("%s.%s.payload.%s.raw_bytes", adapterOrAccount, exchange, direction), registry)
+			am.PayloadWireBytes[direction] = metrics.GetOrRegisterCounter(fmt.Sprintf("%s.%s.payload.%s.wire_bytes", adapterOrAccount, exchange, direction), registry)
+		}
+	}
 	if adapterOrAccount != "adapter" {
 		am.BidsReceivedMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.bids_received", adapterOrAccount, exchange), registry)
 	}
@@ -723,6 +780,73 @@ func (me *Metrics) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.Bidde
 	am.GDPRRequestBlocked.Mark(1)
 }
 
+func (me *Metrics) RecordSKANIDListFetch(adapterName openrtb_ext.BidderName, success bool, length time.Duration, listSize int) {
+	am, ok := me.AdapterMetrics[adapterName]
+	if !ok {
+		glog.Errorf("Trying to log adapter SKAN ID List fetch metric for %s: adapter not found", string(adapterName))
+		return
+	}
+
+	if !success {
+		am.SKANIDListFetchErrorTimer.Update(length)
+		return
+	}
+
+	am.SKANIDListFetchSuccessTimer.Update(length)
+	am.SKANIDListSize.Update(int64(listSize))
+}
+
+func (me *Metrics) RecordAdapterCircuitBreaker(adapterName openrtb_ext.BidderName, state CircuitBreakerState) {
+	am, ok := me.AdapterMetrics[adapterName]
+	if !ok {
+		glog.Errorf("Trying to log adapter circuit breaker metric for %s: adapter not found", string(adapterName))
+		return
+	}
+
+	if meter, ok := am.CircuitBreakerMeters[state]; ok {
+		meter.Mark(1)
+	}
+}
+
+func (me *Metrics) RecordAdapterBlockedCreative(adapterName openrtb_ext.BidderName, blocklist CreativeBlocklist) {
+	am, ok := me.AdapterMetrics[adapterName]
+	if !ok {
+		glog.Errorf("Trying to log adapter blocked creative metric for %s: adapter not found", string(adapterName))
+		return
+	}
+
+	if meter, ok := am.BlockedCreativeMeters[blocklist]; ok {
+		meter.Mark(1)
+	}
+}
+
+func (me *Metrics) RecordAdapterHedge(adapterName openrtb_ext.BidderName, winner HedgeWinner) {
+	am, ok := me.AdapterMetrics[adapterName]
+	if !ok {
+		glog.Errorf("Trying to log adapter hedge metric for %s: adapter not found", string(adapterName))
+		return
+	}
+
+	if meter, ok := am.HedgeMeters[winner]; ok {
+		meter.Mark(1)
+	}
+}
+
+func (me *Metrics) RecordAdapterPayload(adapterName openrtb_ext.BidderName, direction PayloadDirection, rawBytes int, wireBytes int) {
+	am, ok := me.AdapterMetrics[adapterName]
+	if !ok {
+		glog.Errorf("Trying to log adapter payload metric for %s: adapter not found", string(adapterName))
+		return
+	}
+
+	if counter, ok := am.PayloadRawBytes[direction]; ok {
+		counter.Inc(int64(rawBytes))
+	}
+	if counter, ok := am.PayloadWireBytes[direction]; ok {
+		counter.Inc(int64(wireBytes))
+	}
+}
+
 func doMark(bidder openrtb_ext.BidderName, meters map[openrtb_ext.BidderName]metrics.Meter) {
 	met, ok := meters[bidder]
 	if ok {
@@ -599,6 +599,76 @@ func TestRecordAdapterGDPRRequestBlocked(t *testing.T) {
 	}
 }
 
+func TestRecordSKANIDListFetch(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderRubicon}, config.DisabledMetrics{})
+
+	m.RecordSKANIDListFetch(openrtb_ext.BidderRubicon, true, time.Second, 42)
+	m.RecordSKANIDListFetch(openrtb_ext.BidderRubicon, false, time.Second, 0)
+
+	am := m.AdapterMetrics[openrtb_ext.BidderRubicon]
+	assert.Equal(t, int64(1), am.SKANIDListFetchSuccessTimer.Count(), "success timer")
+	assert.Equal(t, int64(1), am.SKANIDListFetchErrorTimer.Count(), "error timer")
+	assert.Equal(t, int64(42), am.SKANIDListSize.Value(), "list size")
+}
+
+func TestRecordAdapterCircuitBreaker(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderRubicon}, config.DisabledMetrics{})
+
+	m.RecordAdapterCircuitBreaker(openrtb_ext.BidderRubicon, CircuitBreakerOpen)
+	m.RecordAdapterCircuitBreaker(openrtb_ext.BidderRubicon, CircuitBreakerOpen)
+	m.RecordAdapterCircuitBreaker(openrtb_ext.BidderRubicon, CircuitBreakerHalfOpen)
+
+	am := m.AdapterMetrics[openrtb_ext.BidderRubicon]
+	assert.Equal(t, int64(2), am.CircuitBreakerMeters[CircuitBreakerOpen].Count(), "open")
+	assert.Equal(t, int64(1), am.CircuitBreakerMeters[CircuitBreakerHalfOpen].Count(), "half open")
+	assert.Equal(t, int64(0), am.CircuitBreakerMeters[CircuitBreakerClosed].Count(), "closed")
+}
+
+func TestRecordAdapterBlockedCreative(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderRubicon}, config.DisabledMetrics{})
+
+	m.RecordAdapterBlockedCreative(openrtb_ext.BidderRubicon, CreativeBlocklistAdvertiser)
+	m.RecordAdapterBlockedCreative(openrtb_ext.BidderRubicon, CreativeBlocklistAdvertiser)
+	m.RecordAdapterBlockedCreative(openrtb_ext.BidderRubicon, CreativeBlocklistCategory)
+
+	am := m.AdapterMetrics[openrtb_ext.BidderRubicon]
+	assert.Equal(t, int64(2), am.BlockedCreativeMeters[CreativeBlocklistAdvertiser].Count(), "badv")
+	assert.Equal(t, int64(1), am.BlockedCreativeMeters[CreativeBlocklistCategory].Count(), "bcat")
+	assert.Equal(t, int64(0), am.BlockedCreativeMeters[CreativeBlocklistCreative].Count(), "crid")
+}
+
+func TestRecordAdapterHedge(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderLiftoff}, config.DisabledMetrics{})
+
+	m.RecordAdapterHedge(openrtb_ext.BidderLiftoff, HedgeWinnerSecondary)
+	m.RecordAdapterHedge(openrtb_ext.BidderLiftoff, HedgeWinnerSecondary)
+	m.RecordAdapterHedge(openrtb_ext.BidderLiftoff, HedgeWinnerPrimary)
+
+	am := m.AdapterMetrics[openrtb_ext.BidderLiftoff]
+	assert.Equal(t, int64(1), am.HedgeMeters[HedgeWinnerPrimary].Count(), "primary")
+	assert.Equal(t, int64(2), am.HedgeMeters[HedgeWinnerSecondary].Count(), "secondary")
+	assert.Equal(t, int64(0), am.HedgeMeters[HedgeWinnerNone].Count(), "none")
+}
+
+func TestRecordAdapterPayload(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderLiftoff}, config.DisabledMetrics{})
+
+	m.RecordAdapterPayload(openrtb_ext.BidderLiftoff, PayloadRequest, 1000, 200)
+	m.RecordAdapterPayload(openrtb_ext.BidderLiftoff, PayloadRequest, 500, 100)
+	m.RecordAdapterPayload(openrtb_ext.BidderLiftoff, PayloadResponse, 300, 300)
+
+	am := m.AdapterMetrics[openrtb_ext.BidderLiftoff]
+	assert.Equal(t, int64(1500), am.PayloadRawBytes[PayloadRequest].Count(), "request raw")
+	assert.Equal(t, int64(300), am.PayloadWireBytes[PayloadRequest].Count(), "request wire")
+	assert.Equal(t, int64(300), am.PayloadRawBytes[PayloadResponse].Count(), "response raw")
+	assert.Equal(t, int64(300), am.PayloadWireBytes[PayloadResponse].Count(), "response wire")
+}
+
 func ensureContainsBidTypeMetrics(t *testing.T, registry metrics.Registry, prefix string, mdm map[openrtb_ext.BidType]*MarkupDeliveryMetrics) {
 	ensureContains(t, registry, prefix+".banner.adm_bids_received", mdm[openrtb_ext.BidTypeBanner].AdmMeter)
 	ensureContains(t, registry, prefix+".banner.nurl_bids_received", mdm[openrtb_ext.BidTypeBanner].NurlMeter)
@@ -24,6 +24,11 @@ type AdapterLabels struct {
 	CookieFlag    CookieFlag
 	AdapterBids   AdapterBid
 	AdapterErrors map[AdapterError]struct{}
+	// The region, placement type and SKAN data of the first call made to the adapter, set once it is made.
+	// Only the OpenTelemetry engine exports them.
+	Region        string
+	PlacementType string
+	SKANSent      bool
 }
 
 // ImpLabels defines metric labels describing the impression type.
@@ -238,6 +243,7 @@ const (
 	AdapterErrorBadServerResponse   AdapterError = "badserverresponse"
 	AdapterErrorTimeout             AdapterError = "timeout"
 	AdapterErrorFailedToRequestBids AdapterError = "failedtorequestbid"
+	AdapterErrorBidBelowFloor       AdapterError = "bidbelowfloor"
 	AdapterErrorUnknown             AdapterError = "unknown_error"
 )
 
@@ -247,10 +253,96 @@ func AdapterErrors() []AdapterError {
 		AdapterErrorBadServerResponse,
 		AdapterErrorTimeout,
 		AdapterErrorFailedToRequestBids,
+		AdapterErrorBidBelowFloor,
 		AdapterErrorUnknown,
 	}
 }
 
+// CreativeBlocklist is the blocklist a bid was rejected by
+type CreativeBlocklist string
+
+const (
+	// CreativeBlocklistAdvertiser checks bid.adomain against the request badv
+	CreativeBlocklistAdvertiser CreativeBlocklist = "badv"
+	// CreativeBlocklistCategory checks bid.cat against the request bcat
+	CreativeBlocklistCategory CreativeBlocklist = "bcat"
+	// CreativeBlocklistAttribute checks bid.attr against the battr of the imp
+	CreativeBlocklistAttribute CreativeBlocklist = "battr"
+	// CreativeBlocklistApp checks bid.bundle against the request bapp
+	CreativeBlocklistApp CreativeBlocklist = "bapp"
+	// CreativeBlocklistCreative checks bid.crid against the creatives blocked by the account
+	CreativeBlocklistCreative CreativeBlocklist = "crid"
+)
+
+// CreativeBlocklists returns the blocklists bids are checked against
+func CreativeBlocklists() []CreativeBlocklist {
+	return []CreativeBlocklist{
+		CreativeBlocklistAdvertiser,
+		CreativeBlocklistCategory,
+		CreativeBlocklistAttribute,
+		CreativeBlocklistApp,
+		CreativeBlocklistCreative,
+	}
+}
+
+// CircuitBreakerState is the state of the circuit breaker guarding an adapter endpoint
+type CircuitBreakerState string
+
+const (
+	// CircuitBreakerClosed lets every request through
+	CircuitBreakerClosed CircuitBreakerState = "closed"
+	// CircuitBreakerOpen rejects every request
+	CircuitBreakerOpen CircuitBreakerState = "open"
+	// CircuitBreakerHalfOpen lets a few probe requests through to decide whether to close the circuit again
+	CircuitBreakerHalfOpen CircuitBreakerState = "halfopen"
+)
+
+// CircuitBreakerStates returns the possible states of a circuit breaker
+func CircuitBreakerStates() []CircuitBreakerState {
+	return []CircuitBreakerState{
+		CircuitBreakerClosed,
+		CircuitBreakerOpen,
+		CircuitBreakerHalfOpen,
+	}
+}
+
+// HedgeWinner is the endpoint whose response was kept when a request to an adapter was hedged
+type HedgeWinner string
+
+const (
+	// HedgeWinnerPrimary is the endpoint of the region of the request
+	HedgeWinnerPrimary HedgeWinner = "primary"
+	// HedgeWinnerSecondary is the fallback endpoint of the region, called once the hedge delay elapsed
+	HedgeWinnerSecondary HedgeWinner = "secondary"
+	// HedgeWinnerNone is recorded when neither endpoint returned a valid response
+	HedgeWinnerNone HedgeWinner = "none"
+)
+
+// HedgeWinners returns the possible winners of a hedged request
+func HedgeWinners() []HedgeWinner {
+	return []HedgeWinner{
+		HedgeWinnerPrimary,
+		HedgeWinnerSecondary,
+		HedgeWinnerNone,
+	}
+}
+
+// PayloadDirection is the direction of a body exchanged with an adapter
+type PayloadDirection string
+
+const (
+	PayloadRequest  PayloadDirection = "request"
+	PayloadResponse PayloadDirection = "response"
+)
+
+// PayloadDirections returns the possible directions of a body exchanged with an adapter
+func PayloadDirections() []PayloadDirection {
+	return []PayloadDirection{
+		PayloadRequest,
+		PayloadResponse,
+	}
+}
+
 const (
 	// CacheHit represents a cache hit i.e the key was found in cache
 	CacheHit CacheResult = "hit"
@@ -355,4 +447,17 @@ type MetricsEngine interface {
 	RecordTimeoutNotice(sucess bool)
 	RecordRequestPrivacy(privacy PrivacyLabels)
 	RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.BidderName)
+	// RecordSKANIDListFetch records the outcome of a bidder's SKAN ID List refresh. The list size is only
+	// recorded for successful fetches.
+	RecordSKANIDListFetch(adapterName openrtb_ext.BidderName, success bool, length time.Duration, listSize int)
+	// RecordAdapterCircuitBreaker records the transition of the circuit breaker of one of the adapter endpoints to state
+	RecordAdapterCircuitBreaker(adapterName openrtb_ext.BidderName, state CircuitBreakerState)
+	// RecordAdapterBlockedCreative records a bid of the adapter rejected because its creative is in blocklist
+	RecordAdapterBlockedCreative(adapterName openrtb_ext.BidderName, blocklist CreativeBlocklist)
+	// RecordAdapterHedge records a request to the adapter which was hedged to its secondary endpoint, along with
+	// the endpoint whose response was kept
+	RecordAdapterHedge(adapterName openrtb_ext.BidderName, winner HedgeWinner)
+	// RecordAdapterPayload records the size of a body exchanged with an adapter accepting gzip, before and after
+	// compression. Both sizes are the same for an uncompressed response.
+	RecordAdapterPayload(adapterName openrtb_ext.BidderName, direction PayloadDirection, rawBytes int, wireBytes int)
 }
@@ -145,3 +145,28 @@ func (me *MetricsEngineMock) RecordRequestPrivacy(privacy PrivacyLabels) {
 func (me *MetricsEngineMock) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.BidderName) {
 	me.Called(adapterName)
 }
+
+// RecordSKANIDListFetch mock
+func (me *MetricsEngineMock) RecordSKANIDListFetch(adapterName openrtb_ext.BidderName, success bool, length time.Duration, listSize int) {
+	me.Called(adapterName, success, length, listSize)
+}
+
+// RecordAdapterCircuitBreaker mock
+func (me *MetricsEngineMock) RecordAdapterCircuitBreaker(adapterName openrtb_ext.BidderName, state CircuitBreakerState) {
+	me.Called(adapterName, state)
+}
+
+// RecordAdapterBlockedCreative mock
+func (me *MetricsEngineMock) RecordAdapterBlockedCreative(adapterName openrtb_ext.BidderName, blocklist CreativeBlocklist) {
+	me.Called(adapterName, blocklist)
+}
+
+// RecordAdapterHedge mock
+func (me *MetricsEngineMock) RecordAdapterHedge(adapterName openrtb_ext.BidderName, winner HedgeWinner) {
+	me.Called(adapterName, winner)
+}
+
+// RecordAdapterPayload mock
+func (me *MetricsEngineMock) RecordAdapterPayload(adapterName openrtb_ext.BidderName, direction PayloadDirection, rawBytes int, wireBytes int) {
+	me.Called(adapterName, direction, rawBytes, wireBytes)
+}
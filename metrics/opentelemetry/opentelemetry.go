@@ -0,0 +1,653 @@
+package otelmetrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/metrics"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/metric"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/lastvalue"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	controller "go.opentelemetry.io/otel/sdk/metric/controller/basic"
+	processor "go.opentelemetry.io/otel/sdk/metric/processor/basic"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/semconv"
+)
+
+const instrumentationName = "github.com/prebid/prebid-server/metrics/opentelemetry"
+
+// Metrics defines the OpenTelemetry instruments backing the MetricsEngine implementation. They are
+// exported to an OTLP collector every collect period.
+type Metrics struct {
+	controller *controller.Controller
+	exporter   *otlp.Exporter
+
+	// General Metrics
+	connectionsClosed            metric.Int64Counter
+	connectionsError             metric.Int64Counter
+	connectionsOpened            metric.Int64Counter
+	cookieSync                   metric.Int64Counter
+	impressions                  metric.Int64Counter
+	impressionsLegacy            metric.Int64Counter
+	prebidCacheWriteTimer        metric.Float64ValueRecorder
+	requests                     metric.Int64Counter
+	requestsTimer                metric.Float64ValueRecorder
+	requestsQueueTimer           metric.Float64ValueRecorder
+	requestsWithoutCookie        metric.Int64Counter
+	storedImpressionsCacheResult metric.Int64Counter
+	storedRequestCacheResult     metric.Int64Counter
+	accountCacheResult           metric.Int64Counter
+	storedDataFetchTimer         metric.Float64ValueRecorder
+	storedDataErrors             metric.Int64Counter
+	timeoutNotifications         metric.Int64Counter
+	dnsLookupTimer               metric.Float64ValueRecorder
+	tlsHandhakeTimer             metric.Float64ValueRecorder
+	privacyCCPA                  metric.Int64Counter
+	privacyCOPPA                 metric.Int64Counter
+	privacyLMT                   metric.Int64Counter
+	privacyTCF                   metric.Int64Counter
+
+	// Adapter Metrics
+	adapterBids                 metric.Int64Counter
+	adapterCookieSync           metric.Int64Counter
+	adapterErrors               metric.Int64Counter
+	adapterPanics               metric.Int64Counter
+	adapterPrices               metric.Float64ValueRecorder
+	adapterRequests             metric.Int64Counter
+	adapterRequestsTimer        metric.Float64ValueRecorder
+	adapterUserSync             metric.Int64Counter
+	adapterReusedConnections    metric.Int64Counter
+	adapterCreatedConnections   metric.Int64Counter
+	adapterConnectionWaitTime   metric.Float64ValueRecorder
+	adapterGDPRBlockedRequests  metric.Int64Counter
+	adapterSKANIDListFetchTimer metric.Float64ValueRecorder
+	adapterCircuitBreaker       metric.Int64Counter
+	adapterBlockedCreatives     metric.Int64Counter
+	adapterHedgedRequests       metric.Int64Counter
+	adapterPayloadRawBytes      metric.Int64Counter
+	adapterPayloadWireBytes     metric.Int64Counter
+
+	// Account Metrics
+	accountRequests metric.Int64Counter
+
+	// skanIDListSizes is the size of the last successfully fetched SKAN ID List of the adapters, observed
+	// on every collection
+	skanIDListSizesMux sync.Mutex
+	skanIDListSizes    map[openrtb_ext.BidderName]int
+
+	metricsDisabled config.DisabledMetrics
+}
+
+var (
+	accountKey         = attribute.Key("account")
+	actionKey          = attribute.Key("action")
+	adapterErrorKey    = attribute.Key("adapter_error")
+	bidderKey          = attribute.Key("bidder")
+	blocklistKey       = attribute.Key("blocklist")
+	cacheResultKey     = attribute.Key("cache_result")
+	circuitStateKey    = attribute.Key("circuit_state")
+	connectionErrorKey = attribute.Key("connection_error")
+	cookieKey          = attribute.Key("cookie")
+	directionKey       = attribute.Key("direction")
+	dataTypeKey        = attribute.Key("stored_data_type")
+	fetchTypeKey       = attribute.Key("stored_data_fetch_type")
+	hasBidsKey         = attribute.Key("has_bids")
+	hedgeWinnerKey     = attribute.Key("winner")
+	isAudioKey         = attribute.Key("audio")
+	isBannerKey        = attribute.Key("banner")
+	isNativeKey        = attribute.Key("native")
+	isVideoKey         = attribute.Key("video")
+	markupDeliveryKey  = attribute.Key("delivery")
+	optOutKey          = attribute.Key("opt_out")
+	placementTypeKey   = attribute.Key("placement_type")
+	privacyBlockedKey  = attribute.Key("privacy_blocked")
+	regionKey          = attribute.Key("region")
+	requestStatusKey   = attribute.Key("request_status")
+	requestTypeKey     = attribute.Key("request_type")
+	skanSentKey        = attribute.Key("skan_sent")
+	sourceKey          = attribute.Key("source")
+	storedDataErrorKey = attribute.Key("stored_data_error")
+	successKey         = attribute.Key("success")
+	versionKey         = attribute.Key("version")
+)
+
+const (
+	connectionAcceptError = "accept"
+	connectionCloseError  = "close"
+)
+
+const (
+	markupDeliveryAdm  = "adm"
+	markupDeliveryNurl = "nurl"
+)
+
+const (
+	requestSuccessLabel = "requestAcceptedLabel"
+	requestRejectLabel  = "requestRejectedLabel"
+)
+
+const (
+	requestSuccessful = "ok"
+	requestFailed     = "failed"
+)
+
+const sourceRequest = "request"
+
+// NewMetrics creates the OpenTelemetry instruments and starts exporting them to the collector at the
+// endpoint of the config. The metrics carry the deployment region as a resource attribute.
+func NewMetrics(cfg config.OpenTelemetryMetrics, region string, disabledMetrics config.DisabledMetrics) (*Metrics, error) {
+	ctx := context.Background()
+
+	driver := otlpgrpc.NewDriver(
+		otlpgrpc.WithInsecure(),
+		otlpgrpc.WithEndpoint(cfg.Endpoint),
+	)
+	exporter, err := otlp.NewExporter(ctx, driver)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := []attribute.KeyValue{
+		semconv.ServiceNameKey.String("prebid"),
+		semconv.ServiceNamespaceKey.String("supply"),
+	}
+	if region != "" {
+		attributes = append(attributes, semconv.CloudRegionKey.String(region))
+	}
+
+	// The processor remembers the label sets not updated since the last collection, so that the cumulative
+	// values of every series are exported each time, as Prometheus would scrape them.
+	selector := &aggregatorSelector{buckets: make(map[string][]float64)}
+	cont := controller.New(
+		processor.New(selector, exporter, processor.WithMemory(true)),
+		controller.WithExporter(exporter),
+		resourceOption{resource.NewWithAttributes(attributes...)},
+		controller.WithCollectPeriod(cfg.CollectPeriod()),
+	)
+
+	m := newMetrics(cont.MeterProvider().Meter(instrumentationName), selector, disabledMetrics)
+	m.controller = cont
+	m.exporter = exporter
+
+	if err := cont.Start(ctx); err != nil {
+		exporter.Shutdown(ctx)
+		return nil, err
+	}
+	return m, nil
+}
+
+// newMetrics creates the instruments, registering the buckets of the histograms in the selector
+func newMetrics(meter metric.Meter, selector *aggregatorSelector, disabledMetrics config.DisabledMetrics) *Metrics {
+	standardTimeBuckets := []float64{0.05, 0.1, 0.15, 0.20, 0.25, 0.3, 0.4, 0.5, 0.75, 1}
+	cacheWriteTimeBuckets := []float64{0.001, 0.002, 0.005, 0.01, 0.025, 0.05, 0.1, 0.2, 0.3, 0.4, 0.5, 1}
+	priceBuckets := []float64{250, 500, 750, 1000, 1500, 2000, 2500, 3000, 3500, 4000}
+	queuedRequestTimeBuckets := []float64{0, 1, 5, 30, 60, 120, 180, 240, 300}
+
+	must := metric.Must(meter)
+	newHistogram := func(name, description string, buckets []float64) metric.Float64ValueRecorder {
+		selector.buckets[name] = buckets
+		return must.NewFloat64ValueRecorder(name, metric.WithDescription(description))
+	}
+
+	m := &Metrics{
+		skanIDListSizes: make(map[openrtb_ext.BidderName]int),
+		metricsDisabled: disabledMetrics,
+	}
+
+	m.connectionsClosed = must.NewInt64Counter("connections_closed",
+		metric.WithDescription("Count of successful connections closed to Prebid Server."))
+
+	m.connectionsError = must.NewInt64Counter("connections_error",
+		metric.WithDescription("Count of errors for connection open and close attempts to Prebid Server labeled by type."))
+
+	m.connectionsOpened = must.NewInt64Counter("connections_opened",
+		metric.WithDescription("Count of successful connections opened to Prebid Server."))
+
+	m.cookieSync = must.NewInt64Counter("cookie_sync_requests",
+		metric.WithDescription("Count of cookie sync requests to Prebid Server."))
+
+	m.impressions = must.NewInt64Counter("impressions_requests",
+		metric.WithDescription("Count of requested impressions to Prebid Server labeled by type."))
+
+	m.impressionsLegacy = must.NewInt64Counter("impressions_requests_legacy",
+		metric.WithDescription("Count of requested impressions to Prebid Server using the legacy endpoint."))
+
+	m.prebidCacheWriteTimer = newHistogram("prebidcache_write_time_seconds",
+		"Seconds to write to Prebid Cache labeled by success or failure. Failure timing is limited by Prebid Server enforced timeouts.",
+		cacheWriteTimeBuckets)
+
+	m.requests = must.NewInt64Counter("requests",
+		metric.WithDescription("Count of total requests to Prebid Server labeled by type and status."))
+
+	m.requestsTimer = newHistogram("request_time_seconds",
+		"Seconds to resolve successful Prebid Server requests labeled by type.",
+		standardTimeBuckets)
+
+	m.requestsQueueTimer = newHistogram("request_queue_time",
+		"Seconds request was waiting in queue",
+		queuedRequestTimeBuckets)
+
+	m.requestsWithoutCookie = must.NewInt64Counter("requests_without_cookie",
+		metric.WithDescription("Count of total requests to Prebid Server without a cookie labeled by type."))
+
+	m.storedImpressionsCacheResult = must.NewInt64Counter("stored_impressions_cache_performance",
+		metric.WithDescription("Count of stored impression cache requests attempts by hits or miss."))
+
+	m.storedRequestCacheResult = must.NewInt64Counter("stored_request_cache_performance",
+		metric.WithDescription("Count of stored request cache requests attempts by hits or miss."))
+
+	m.accountCacheResult = must.NewInt64Counter("account_cache_performance",
+		metric.WithDescription("Count of account cache lookups by hits or miss."))
+
+	m.storedDataFetchTimer = newHistogram("stored_data_fetch_time_seconds",
+		"Seconds to fetch stored data labeled by data type and fetch type",
+		standardTimeBuckets)
+
+	m.storedDataErrors = must.NewInt64Counter("stored_data_errors",
+		metric.WithDescription("Count of stored data errors labeled by data type and error type"))
+
+	m.timeoutNotifications = must.NewInt64Counter("timeout_notification",
+		metric.WithDescription("Count of timeout notifications triggered, and if they were successfully sent."))
+
+	m.dnsLookupTimer = newHistogram("dns_lookup_time",
+		"Seconds to resolve DNS",
+		standardTimeBuckets)
+
+	m.tlsHandhakeTimer = newHistogram("tls_handshake_time",
+		"Seconds to perform TLS Handshake",
+		standardTimeBuckets)
+
+	m.privacyCCPA = must.NewInt64Counter("privacy_ccpa",
+		metric.WithDescription("Count of total requests to Prebid Server where CCPA was provided by source and opt-out ."))
+
+	m.privacyCOPPA = must.NewInt64Counter("privacy_coppa",
+		metric.WithDescription("Count of total requests to Prebid Server where the COPPA flag was set by source"))
+
+	m.privacyTCF = must.NewInt64Counter("privacy_tcf",
+		metric.WithDescription("Count of TCF versions for requests where GDPR was enforced by source and version."))
+
+	m.privacyLMT = must.NewInt64Counter("privacy_lmt",
+		metric.WithDescription("Count of total requests to Prebid Server where the LMT flag was set by source"))
+
+	if !m.metricsDisabled.AdapterGDPRRequestBlocked {
+		m.adapterGDPRBlockedRequests = must.NewInt64Counter("adapter_gdpr_requests_blocked",
+			metric.WithDescription("Count of total bidder requests blocked due to unsatisfied GDPR purpose 2 legal basis"))
+	}
+
+	m.adapterBids = must.NewInt64Counter("adapter_bids",
+		metric.WithDescription("Count of bids labeled by bidder, region, placement type, SKAN and markup delivery type (adm or nurl)."))
+
+	m.adapterCookieSync = must.NewInt64Counter("adapter_cookie_sync",
+		metric.WithDescription("Count of cookie sync requests received labeled by bidder and if the sync was blocked due to privacy regulation (GDPR, CCPA, etc...)."))
+
+	m.adapterErrors = must.NewInt64Counter("adapter_errors",
+		metric.WithDescription("Count of errors labeled by bidder, region, placement type, SKAN and error type."))
+
+	m.adapterPanics = must.NewInt64Counter("adapter_panics",
+		metric.WithDescription("Count of panics labeled by bidder."))
+
+	m.adapterPrices = newHistogram("adapter_prices",
+		"Monetary value of the bids labeled by bidder, region, placement type and SKAN.",
+		priceBuckets)
+
+	m.adapterRequests = must.NewInt64Counter("adapter_requests",
+		metric.WithDescription("Count of requests labeled by bidder, region, placement type, SKAN, if has a cookie, and if it resulted in bids."))
+
+	if !m.metricsDisabled.AdapterConnectionMetrics {
+		m.adapterCreatedConnections = must.NewInt64Counter("adapter_connection_created",
+			metric.WithDescription("Count that keeps track of new connections when contacting adapter bidder endpoints."))
+
+		m.adapterReusedConnections = must.NewInt64Counter("adapter_connection_reused",
+			metric.WithDescription("Count that keeps track of reused connections when contacting adapter bidder endpoints."))
+
+		m.adapterConnectionWaitTime = newHistogram("adapter_connection_wait",
+			"Seconds from when the connection was requested until it is either created or reused",
+			standardTimeBuckets)
+	}
+
+	m.adapterRequestsTimer = newHistogram("adapter_request_time_seconds",
+		"Seconds to resolve each successful request labeled by bidder, region, placement type and SKAN.",
+		standardTimeBuckets)
+
+	m.adapterSKANIDListFetchTimer = newHistogram("adapter_skan_id_list_fetch_time_seconds",
+		"Seconds to fetch the SKAN ID List hosted by the bidder labeled by success or failure.",
+		standardTimeBuckets)
+
+	must.NewInt64ValueObserver("adapter_skan_id_list_size", m.observeSKANIDListSizes,
+		metric.WithDescription("Number of SKAN IDs in the last successfully fetched SKAN ID List labeled by bidder."))
+
+	m.adapterCircuitBreaker = must.NewInt64Counter("adapter_circuit_breaker_transitions",
+		metric.WithDescription("Count of transitions of the circuit breakers of the adapter endpoints labeled by bidder and new state."))
+	m.adapterBlockedCreatives = must.NewInt64Counter("adapter_blocked_creatives",
+		metric.WithDescription("Count of bids rejected because their creative is blocked labeled by bidder and blocklist."))
+	m.adapterHedgedRequests = must.NewInt64Counter("adapter_hedged_requests",
+		metric.WithDescription("Count of requests hedged to the secondary endpoint of the bidder labeled by bidder and winning endpoint."))
+	m.adapterPayloadRawBytes = must.NewInt64Counter("adapter_payload_raw_bytes",
+		metric.WithDescription("Bytes of the bodies exchanged with the bidders accepting gzip before compression labeled by bidder and direction."))
+	m.adapterPayloadWireBytes = must.NewInt64Counter("adapter_payload_wire_bytes",
+		metric.WithDescription("Bytes of the bodies exchanged with the bidders accepting gzip as sent over the network labeled by bidder and direction."))
+
+	m.adapterUserSync = must.NewInt64Counter("adapter_user_sync",
+		metric.WithDescription("Count of user ID sync requests received labeled by bidder and action."))
+
+	m.accountRequests = must.NewInt64Counter("account_requests",
+		metric.WithDescription("Count of total requests to Prebid Server labeled by account."))
+
+	return m
+}
+
+// Stop exports the metrics one last time and closes the connection to the collector
+func (m *Metrics) Stop() error {
+	ctx := context.Background()
+	if err := m.controller.Stop(ctx); err != nil {
+		m.exporter.Shutdown(ctx)
+		return err
+	}
+	return m.exporter.Shutdown(ctx)
+}
+
+func (m *Metrics) observeSKANIDListSizes(_ context.Context, result metric.Int64ObserverResult) {
+	m.skanIDListSizesMux.Lock()
+	defer m.skanIDListSizesMux.Unlock()
+	for adapterName, size := range m.skanIDListSizes {
+		result.Observe(int64(size), bidderKey.String(string(adapterName)))
+	}
+}
+
+// adapterAttributes are the attributes of the calls made to an adapter
+func adapterAttributes(labels metrics.AdapterLabels) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		bidderKey.String(string(labels.Adapter)),
+		regionKey.String(labels.Region),
+		placementTypeKey.String(labels.PlacementType),
+		skanSentKey.Bool(labels.SKANSent),
+	}
+}
+
+func (m *Metrics) RecordConnectionAccept(success bool) {
+	if success {
+		m.connectionsOpened.Add(context.Background(), 1)
+	} else {
+		m.connectionsError.Add(context.Background(), 1, connectionErrorKey.String(connectionAcceptError))
+	}
+}
+
+func (m *Metrics) RecordConnectionClose(success bool) {
+	if success {
+		m.connectionsClosed.Add(context.Background(), 1)
+	} else {
+		m.connectionsError.Add(context.Background(), 1, connectionErrorKey.String(connectionCloseError))
+	}
+}
+
+func (m *Metrics) RecordRequest(labels metrics.Labels) {
+	ctx := context.Background()
+	m.requests.Add(ctx, 1,
+		requestTypeKey.String(string(labels.RType)),
+		requestStatusKey.String(string(labels.RequestStatus)))
+
+	if labels.CookieFlag == metrics.CookieFlagNo {
+		m.requestsWithoutCookie.Add(ctx, 1, requestTypeKey.String(string(labels.RType)))
+	}
+
+	if labels.PubID != metrics.PublisherUnknown {
+		m.accountRequests.Add(ctx, 1, accountKey.String(labels.PubID))
+	}
+}
+
+func (m *Metrics) RecordImps(labels metrics.ImpLabels) {
+	m.impressions.Add(context.Background(), 1,
+		isBannerKey.Bool(labels.BannerImps),
+		isVideoKey.Bool(labels.VideoImps),
+		isAudioKey.Bool(labels.AudioImps),
+		isNativeKey.Bool(labels.NativeImps))
+}
+
+func (m *Metrics) RecordLegacyImps(labels metrics.Labels, numImps int) {
+	m.impressionsLegacy.Add(context.Background(), int64(numImps))
+}
+
+func (m *Metrics) RecordRequestTime(labels metrics.Labels, length time.Duration) {
+	if labels.RequestStatus == metrics.RequestStatusOK {
+		m.requestsTimer.Record(context.Background(), length.Seconds(), requestTypeKey.String(string(labels.RType)))
+	}
+}
+
+func (m *Metrics) RecordStoredDataFetchTime(labels metrics.StoredDataLabels, length time.Duration) {
+	m.storedDataFetchTimer.Record(context.Background(), length.Seconds(),
+		dataTypeKey.String(string(labels.DataType)),
+		fetchTypeKey.String(string(labels.DataFetchType)))
+}
+
+func (m *Metrics) RecordStoredDataError(labels metrics.StoredDataLabels) {
+	m.storedDataErrors.Add(context.Background(), 1,
+		dataTypeKey.String(string(labels.DataType)),
+		storedDataErrorKey.String(string(labels.Error)))
+}
+
+func (m *Metrics) RecordAdapterRequest(labels metrics.AdapterLabels) {
+	ctx := context.Background()
+	m.adapterRequests.Add(ctx, 1, append(adapterAttributes(labels),
+		cookieKey.String(string(labels.CookieFlag)),
+		hasBidsKey.Bool(labels.AdapterBids == metrics.AdapterBidPresent))...)
+
+	for err := range labels.AdapterErrors {
+		m.adapterErrors.Add(ctx, 1, append(adapterAttributes(labels), adapterErrorKey.String(string(err)))...)
+	}
+}
+
+// Keeps track of created and reused connections to adapter bidders and the time from the
+// connection request, to the connection creation, or reuse from the pool across all engines
+func (m *Metrics) RecordAdapterConnections(adapterName openrtb_ext.BidderName, connWasReused bool, connWaitTime time.Duration) {
+	if m.metricsDisabled.AdapterConnectionMetrics {
+		return
+	}
+
+	ctx := context.Background()
+	if connWasReused {
+		m.adapterReusedConnections.Add(ctx, 1, bidderKey.String(string(adapterName)))
+	} else {
+		m.adapterCreatedConnections.Add(ctx, 1, bidderKey.String(string(adapterName)))
+	}
+
+	m.adapterConnectionWaitTime.Record(ctx, connWaitTime.Seconds(), bidderKey.String(string(adapterName)))
+}
+
+func (m *Metrics) RecordDNSTime(dnsLookupTime time.Duration) {
+	m.dnsLookupTimer.Record(context.Background(), dnsLookupTime.Seconds())
+}
+
+func (m *Metrics) RecordTLSHandshakeTime(tlsHandshakeTime time.Duration) {
+	m.tlsHandhakeTimer.Record(context.Background(), tlsHandshakeTime.Seconds())
+}
+
+func (m *Metrics) RecordAdapterPanic(labels metrics.AdapterLabels) {
+	m.adapterPanics.Add(context.Background(), 1, bidderKey.String(string(labels.Adapter)))
+}
+
+func (m *Metrics) RecordAdapterBidReceived(labels metrics.AdapterLabels, bidType openrtb_ext.BidType, hasAdm bool) {
+	markupDelivery := markupDeliveryNurl
+	if hasAdm {
+		markupDelivery = markupDeliveryAdm
+	}
+
+	m.adapterBids.Add(context.Background(), 1, append(adapterAttributes(labels), markupDeliveryKey.String(markupDelivery))...)
+}
+
+func (m *Metrics) RecordAdapterPrice(labels metrics.AdapterLabels, cpm float64) {
+	m.adapterPrices.Record(context.Background(), cpm, adapterAttributes(labels)...)
+}
+
+func (m *Metrics) RecordAdapterTime(labels metrics.AdapterLabels, length time.Duration) {
+	if len(labels.AdapterErrors) == 0 {
+		m.adapterRequestsTimer.Record(context.Background(), length.Seconds(), adapterAttributes(labels)...)
+	}
+}
+
+func (m *Metrics) RecordCookieSync() {
+	m.cookieSync.Add(context.Background(), 1)
+}
+
+func (m *Metrics) RecordAdapterCookieSync(adapter openrtb_ext.BidderName, privacyBlocked bool) {
+	m.adapterCookieSync.Add(context.Background(), 1,
+		bidderKey.String(string(adapter)),
+		privacyBlockedKey.Bool(privacyBlocked))
+}
+
+func (m *Metrics) RecordUserIDSet(labels metrics.UserLabels) {
+	adapter := string(labels.Bidder)
+	if adapter != "" {
+		m.adapterUserSync.Add(context.Background(), 1,
+			bidderKey.String(adapter),
+			actionKey.String(string(labels.Action)))
+	}
+}
+
+func (m *Metrics) RecordStoredReqCacheResult(cacheResult metrics.CacheResult, inc int) {
+	m.storedRequestCacheResult.Add(context.Background(), int64(inc), cacheResultKey.String(string(cacheResult)))
+}
+
+func (m *Metrics) RecordStoredImpCacheResult(cacheResult metrics.CacheResult, inc int) {
+	m.storedImpressionsCacheResult.Add(context.Background(), int64(inc), cacheResultKey.String(string(cacheResult)))
+}
+
+func (m *Metrics) RecordAccountCacheResult(cacheResult metrics.CacheResult, inc int) {
+	m.accountCacheResult.Add(context.Background(), int64(inc), cacheResultKey.String(string(cacheResult)))
+}
+
+func (m *Metrics) RecordPrebidCacheRequestTime(success bool, length time.Duration) {
+	m.prebidCacheWriteTimer.Record(context.Background(), length.Seconds(), successKey.Bool(success))
+}
+
+func (m *Metrics) RecordRequestQueueTime(success bool, requestType metrics.RequestType, length time.Duration) {
+	successLabelFormatted := requestRejectLabel
+	if success {
+		successLabelFormatted = requestSuccessLabel
+	}
+	m.requestsQueueTimer.Record(context.Background(), length.Seconds(),
+		requestTypeKey.String(string(requestType)),
+		requestStatusKey.String(successLabelFormatted))
+}
+
+func (m *Metrics) RecordTimeoutNotice(success bool) {
+	if success {
+		m.timeoutNotifications.Add(context.Background(), 1, successKey.String(requestSuccessful))
+	} else {
+		m.timeoutNotifications.Add(context.Background(), 1, successKey.String(requestFailed))
+	}
+}
+
+func (m *Metrics) RecordRequestPrivacy(privacy metrics.PrivacyLabels) {
+	ctx := context.Background()
+	if privacy.CCPAProvided {
+		m.privacyCCPA.Add(ctx, 1,
+			sourceKey.String(sourceRequest),
+			optOutKey.Bool(privacy.CCPAEnforced))
+	}
+
+	if privacy.COPPAEnforced {
+		m.privacyCOPPA.Add(ctx, 1, sourceKey.String(sourceRequest))
+	}
+
+	if privacy.GDPREnforced {
+		m.privacyTCF.Add(ctx, 1,
+			versionKey.String(string(privacy.GDPRTCFVersion)),
+			sourceKey.String(sourceRequest))
+	}
+
+	if privacy.LMTEnforced {
+		m.privacyLMT.Add(ctx, 1, sourceKey.String(sourceRequest))
+	}
+}
+
+func (m *Metrics) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.BidderName) {
+	if m.metricsDisabled.AdapterGDPRRequestBlocked {
+		return
+	}
+
+	m.adapterGDPRBlockedRequests.Add(context.Background(), 1, bidderKey.String(string(adapterName)))
+}
+
+func (m *Metrics) RecordSKANIDListFetch(adapterName openrtb_ext.BidderName, success bool, length time.Duration, listSize int) {
+	m.adapterSKANIDListFetchTimer.Record(context.Background(), length.Seconds(),
+		bidderKey.String(string(adapterName)),
+		successKey.Bool(success))
+
+	if success {
+		m.skanIDListSizesMux.Lock()
+		m.skanIDListSizes[adapterName] = listSize
+		m.skanIDListSizesMux.Unlock()
+	}
+}
+
+func (m *Metrics) RecordAdapterCircuitBreaker(adapterName openrtb_ext.BidderName, state metrics.CircuitBreakerState) {
+	m.adapterCircuitBreaker.Add(context.Background(), 1,
+		bidderKey.String(string(adapterName)),
+		circuitStateKey.String(string(state)))
+}
+
+func (m *Metrics) RecordAdapterBlockedCreative(adapterName openrtb_ext.BidderName, blocklist metrics.CreativeBlocklist) {
+	m.adapterBlockedCreatives.Add(context.Background(), 1,
+		bidderKey.String(string(adapterName)),
+		blocklistKey.String(string(blocklist)))
+}
+
+func (m *Metrics) RecordAdapterHedge(adapterName openrtb_ext.BidderName, winner metrics.HedgeWinner) {
+	m.adapterHedgedRequests.Add(context.Background(), 1,
+		bidderKey.String(string(adapterName)),
+		hedgeWinnerKey.String(string(winner)))
+}
+
+func (m *Metrics) RecordAdapterPayload(adapterName openrtb_ext.BidderName, direction metrics.PayloadDirection, rawBytes int, wireBytes int) {
+	attrs := []attribute.KeyValue{
+		bidderKey.String(string(adapterName)),
+		directionKey.String(string(direction)),
+	}
+	m.adapterPayloadRawBytes.Add(context.Background(), int64(rawBytes), attrs...)
+	m.adapterPayloadWireBytes.Add(context.Background(), int64(wireBytes), attrs...)
+}
+
+// resourceOption sets the resource of the controller as is. controller.WithResource merges it with the
+// resource of the environment, which logs a nil error in this version of the SDK.
+type resourceOption struct {
+	resource *resource.Resource
+}
+
+func (o resourceOption) Apply(cfg *controller.Config) {
+	cfg.Resource = o.resource
+}
+
+// aggregatorSelector aggregates the value recorders in histograms with the buckets of their instrument, as
+// the Prometheus engine does. The observers keep their last value and the counters their sum.
+type aggregatorSelector struct {
+	// buckets is written while the instruments are created, and only read afterwards
+	buckets map[string][]float64
+}
+
+func (s *aggregatorSelector) AggregatorFor(descriptor *metric.Descriptor, aggPtrs ...*export.Aggregator) {
+	switch descriptor.InstrumentKind() {
+	case metric.ValueObserverInstrumentKind:
+		aggs := lastvalue.New(len(aggPtrs))
+		for i := range aggPtrs {
+			*aggPtrs[i] = &aggs[i]
+		}
+	case metric.ValueRecorderInstrumentKind:
+		aggs := histogram.New(len(aggPtrs), descriptor, histogram.WithExplicitBoundaries(s.buckets[descriptor.Name()]))
+		for i := range aggPtrs {
+			*aggPtrs[i] = &aggs[i]
+		}
+	default:
+		aggs := sum.New(len(aggPtrs))
+		for i := range aggPtrs {
+			*aggPtrs[i] = &aggs[i]
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package otelmetrics
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/metrics"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	collectormetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
+)
+
+// collectorStandIn is an in-process OTLP collector keeping the metrics it receives
+type collectorStandIn struct {
+	collectormetricpb.UnimplementedMetricsServiceServer
+
+	mux      sync.Mutex
+	requests []*collectormetricpb.ExportMetricsServiceRequest
+}
+
+func (c *collectorStandIn) Export(_ context.Context, request *collectormetricpb.ExportMetricsServiceRequest) (*collectormetricpb.ExportMetricsServiceResponse, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.requests = append(c.requests, request)
+	return &collectormetricpb.ExportMetricsServiceResponse{}, nil
+}
+
+// lastExport returns the metrics of the last export by name, and the attributes of their resource
+func (c *collectorStandIn) lastExport() (map[string]*metricpb.Metric, map[string]string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	exported := make(map[string]*metricpb.Metric)
+	resource := make(map[string]string)
+	if len(c.requests) == 0 {
+		return exported, resource
+	}
+	for _, resourceMetrics := range c.requests[len(c.requests)-1].GetResourceMetrics() {
+		for _, attribute := range resourceMetrics.GetResource().GetAttributes() {
+			resource[attribute.GetKey()] = attribute.GetValue().GetStringValue()
+		}
+		for _, libraryMetrics := range resourceMetrics.GetInstrumentationLibraryMetrics() {
+			for _, metric := range libraryMetrics.GetMetrics() {
+				exported[metric.GetName()] = metric
+			}
+		}
+	}
+	return exported, resource
+}
+
+// newTestMetrics starts a collector stand-in and the metrics exporting to it. The collect period is long
+// enough that the metrics are only exported when stopped.
+func newTestMetrics(t *testing.T, disabledMetrics config.DisabledMetrics) (*Metrics, *collectorStandIn) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	collector := &collectorStandIn{}
+	server := grpc.NewServer()
+	collectormetricpb.RegisterMetricsServiceServer(server, collector)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	cfg := config.OpenTelemetryMetrics{Endpoint: listener.Addr().String(), CollectPeriodSeconds: 3600}
+	m, err := NewMetrics(cfg, "us-east", disabledMetrics)
+	if err != nil {
+		t.Fatalf("Failed to create the metrics: %v", err)
+	}
+	return m, collector
+}
+
+// intSums returns the values of the data points of a counter keyed by their sorted labels
+func intSums(metric *metricpb.Metric) map[string]int64 {
+	values := make(map[string]int64)
+	for _, point := range metric.GetIntSum().GetDataPoints() {
+		labels := make([]string, 0, len(point.GetLabels()))
+		for _, label := range point.GetLabels() {
+			labels = append(labels, label.GetKey()+"="+label.GetValue())
+		}
+		sort.Strings(labels)
+		values[strings.Join(labels, ",")] = point.GetValue()
+	}
+	return values
+}
+
+func TestExportedResource(t *testing.T) {
+	m, collector := newTestMetrics(t, config.DisabledMetrics{})
+	m.RecordCookieSync()
+	assert.NoError(t, m.Stop())
+
+	exported, resource := collector.lastExport()
+	assert.Equal(t, map[string]string{"service.name": "prebid", "service.namespace": "supply", "cloud.region": "us-east"}, resource)
+	assert.Equal(t, map[string]int64{"": 1}, intSums(exported["cookie_sync_requests"]))
+}
+
+func TestAdapterMetrics(t *testing.T) {
+	m, collector := newTestMetrics(t, config.DisabledMetrics{})
+	labels := metrics.AdapterLabels{
+		Adapter:       openrtb_ext.BidderAppnexus,
+		CookieFlag:    metrics.CookieFlagYes,
+		AdapterBids:   metrics.AdapterBidPresent,
+		AdapterErrors: map[metrics.AdapterError]struct{}{metrics.AdapterErrorBadServerResponse: {}},
+		Region:        "us-east",
+		PlacementType: "rewarded",
+		SKANSent:      true,
+	}
+
+	m.RecordAdapterRequest(labels)
+	m.RecordAdapterRequest(labels)
+	m.RecordAdapterBidReceived(labels, openrtb_ext.BidTypeVideo, true)
+	m.RecordAdapterPrice(labels, 1200)
+	m.RecordAdapterCircuitBreaker(openrtb_ext.BidderAppnexus, metrics.CircuitBreakerOpen)
+	m.RecordAdapterBlockedCreative(openrtb_ext.BidderAppnexus, metrics.CreativeBlocklistCategory)
+	m.RecordAdapterHedge(openrtb_ext.BidderAppnexus, metrics.HedgeWinnerPrimary)
+	m.RecordAdapterPayload(openrtb_ext.BidderAppnexus, metrics.PayloadRequest, 1000, 200)
+	assert.NoError(t, m.Stop())
+
+	exported, _ := collector.lastExport()
+	assert.Equal(t, map[string]int64{"bidder=appnexus,cookie=exists,has_bids=true,placement_type=rewarded,region=us-east,skan_sent=true": 2}, intSums(exported["adapter_requests"]), "adapter_requests")
+	assert.Equal(t, map[string]int64{"adapter_error=badserverresponse,bidder=appnexus,placement_type=rewarded,region=us-east,skan_sent=true": 2}, intSums(exported["adapter_errors"]), "adapter_errors")
+	assert.Equal(t, map[string]int64{"bidder=appnexus,delivery=adm,placement_type=rewarded,region=us-east,skan_sent=true": 1}, intSums(exported["adapter_bids"]), "adapter_bids")
+	assert.Equal(t, map[string]int64{"bidder=appnexus,circuit_state=open": 1}, intSums(exported["adapter_circuit_breaker_transitions"]), "adapter_circuit_breaker_transitions")
+	assert.Equal(t, map[string]int64{"bidder=appnexus,blocklist=bcat": 1}, intSums(exported["adapter_blocked_creatives"]), "adapter_blocked_creatives")
+	assert.Equal(t, map[string]int64{"bidder=appnexus,winner=primary": 1}, intSums(exported["adapter_hedged_requests"]), "adapter_hedged_requests")
+	assert.Equal(t, map[string]int64{"bidder=appnexus,direction=request": 1000}, intSums(exported["adapter_payload_raw_bytes"]), "adapter_payload_raw_bytes")
+	assert.Equal(t, map[string]int64{"bidder=appnexus,direction=request": 200}, intSums(exported["adapter_payload_wire_bytes"]), "adapter_payload_wire_bytes")
+
+	prices := exported["adapter_prices"].GetDoubleHistogram().GetDataPoints()
+	if assert.Len(t, prices, 1, "adapter_prices") {
+		assert.Equal(t, []float64{250, 500, 750, 1000, 1500, 2000, 2500, 3000, 3500, 4000}, prices[0].GetExplicitBounds(), "adapter_prices")
+		assert.Equal(t, uint64(1), prices[0].GetCount(), "adapter_prices")
+		assert.Equal(t, float64(1200), prices[0].GetSum(), "adapter_prices")
+	}
+}
+
+func TestRequestMetrics(t *testing.T) {
+	m, collector := newTestMetrics(t, config.DisabledMetrics{})
+
+	m.RecordRequest(metrics.Labels{RType: metrics.ReqTypeORTB2App, RequestStatus: metrics.RequestStatusOK, PubID: "1001", CookieFlag: metrics.CookieFlagNo})
+	m.RecordRequest(metrics.Labels{RType: metrics.ReqTypeORTB2App, RequestStatus: metrics.RequestStatusBadInput, PubID: metrics.PublisherUnknown})
+	m.RecordRequestTime(metrics.Labels{RType: metrics.ReqTypeORTB2App, RequestStatus: metrics.RequestStatusOK}, 120*time.Millisecond)
+	m.RecordRequestPrivacy(metrics.PrivacyLabels{CCPAProvided: true, CCPAEnforced: true, LMTEnforced: true})
+	m.RecordStoredReqCacheResult(metrics.CacheHit, 3)
+	m.RecordAccountCacheResult(metrics.CacheMiss, 1)
+	m.RecordStoredDataError(metrics.StoredDataLabels{DataType: metrics.AccountDataType, Error: metrics.StoredDataErrorNetwork})
+	assert.NoError(t, m.Stop())
+
+	exported, _ := collector.lastExport()
+	assert.Equal(t, map[string]int64{
+		"request_status=ok,request_type=openrtb2-app":       1,
+		"request_status=badinput,request_type=openrtb2-app": 1,
+	}, intSums(exported["requests"]), "requests")
+	assert.Equal(t, map[string]int64{"request_type=openrtb2-app": 1}, intSums(exported["requests_without_cookie"]), "requests_without_cookie")
+	assert.Equal(t, map[string]int64{"account=1001": 1}, intSums(exported["account_requests"]), "account_requests")
+	assert.Equal(t, map[string]int64{"opt_out=true,source=request": 1}, intSums(exported["privacy_ccpa"]), "privacy_ccpa")
+	assert.Equal(t, map[string]int64{"source=request": 1}, intSums(exported["privacy_lmt"]), "privacy_lmt")
+	assert.Equal(t, map[string]int64{"cache_result=hit": 3}, intSums(exported["stored_request_cache_performance"]), "stored_request_cache_performance")
+	assert.Equal(t, map[string]int64{"cache_result=miss": 1}, intSums(exported["account_cache_performance"]), "account_cache_performance")
+	assert.Equal(t, map[string]int64{"stored_data_error=network,stored_data_type=account": 1}, intSums(exported["stored_data_errors"]), "stored_data_errors")
+
+	timer := exported["request_time_seconds"].GetDoubleHistogram().GetDataPoints()
+	if assert.Len(t, timer, 1, "request_time_seconds") {
+		assert.Equal(t, uint64(1), timer[0].GetCount(), "request_time_seconds")
+		assert.InDelta(t, 0.12, timer[0].GetSum(), 0.0001, "request_time_seconds")
+	}
+}
+
+func TestSKANIDListMetrics(t *testing.T) {
+	m, collector := newTestMetrics(t, config.DisabledMetrics{})
+
+	m.RecordSKANIDListFetch(openrtb_ext.BidderAppnexus, true, 50*time.Millisecond, 30)
+	m.RecordSKANIDListFetch(openrtb_ext.BidderAppnexus, false, 50*time.Millisecond, 0)
+	assert.NoError(t, m.Stop())
+
+	exported, _ := collector.lastExport()
+	sizes := exported["adapter_skan_id_list_size"].GetIntGauge().GetDataPoints()
+	if assert.Len(t, sizes, 1, "adapter_skan_id_list_size") {
+		assert.Equal(t, int64(30), sizes[0].GetValue(), "the size of the last successful fetch is kept")
+	}
+	assert.Len(t, exported["adapter_skan_id_list_fetch_time_seconds"].GetDoubleHistogram().GetDataPoints(), 2, "adapter_skan_id_list_fetch_time_seconds")
+}
+
+func TestDisabledMetrics(t *testing.T) {
+	m, collector := newTestMetrics(t, config.DisabledMetrics{AdapterConnectionMetrics: true, AdapterGDPRRequestBlocked: true})
+
+	m.RecordAdapterConnections(openrtb_ext.BidderAppnexus, true, time.Millisecond)
+	m.RecordAdapterGDPRRequestBlocked(openrtb_ext.BidderAppnexus)
+	m.RecordCookieSync()
+	assert.NoError(t, m.Stop())
+
+	exported, _ := collector.lastExport()
+	assert.NotContains(t, exported, "adapter_connection_reused")
+	assert.NotContains(t, exported, "adapter_connection_wait")
+	assert.NotContains(t, exported, "adapter_gdpr_requests_blocked")
+}
@@ -48,18 +48,25 @@ type Metrics struct {
 	privacyTCF                   *prometheus.CounterVec
 
 	// Adapter Metrics
-	adapterBids                *prometheus.CounterVec
-	adapterCookieSync          *prometheus.CounterVec
-	adapterErrors              *prometheus.CounterVec
-	adapterPanics              *prometheus.CounterVec
-	adapterPrices              *prometheus.HistogramVec
-	adapterRequests            *prometheus.CounterVec
-	adapterRequestsTimer       *prometheus.HistogramVec
-	adapterUserSync            *prometheus.CounterVec
-	adapterReusedConnections   *prometheus.CounterVec
-	adapterCreatedConnections  *prometheus.CounterVec
-	adapterConnectionWaitTime  *prometheus.HistogramVec
-	adapterGDPRBlockedRequests *prometheus.CounterVec
+	adapterBids                 *prometheus.CounterVec
+	adapterCookieSync           *prometheus.CounterVec
+	adapterErrors               *prometheus.CounterVec
+	adapterPanics               *prometheus.CounterVec
+	adapterPrices               *prometheus.HistogramVec
+	adapterRequests             *prometheus.CounterVec
+	adapterRequestsTimer        *prometheus.HistogramVec
+	adapterUserSync             *prometheus.CounterVec
+	adapterReusedConnections    *prometheus.CounterVec
+	adapterCreatedConnections   *prometheus.CounterVec
+	adapterConnectionWaitTime   *prometheus.HistogramVec
+	adapterGDPRBlockedRequests  *prometheus.CounterVec
+	adapterSKANIDListFetchTimer *prometheus.HistogramVec
+	adapterSKANIDListSize       *prometheus.GaugeVec
+	adapterCircuitBreaker       *prometheus.CounterVec
+	adapterBlockedCreatives     *prometheus.CounterVec
+	adapterHedgedRequests       *prometheus.CounterVec
+	adapterPayloadRawBytes      *prometheus.CounterVec
+	adapterPayloadWireBytes     *prometheus.CounterVec
 
 	// Account Metrics
 	accountRequests *prometheus.CounterVec
@@ -73,10 +80,14 @@ const (
 	adapterErrorLabel    = "adapter_error"
 	adapterLabel         = "adapter"
 	bidTypeLabel         = "bid_type"
+	blocklistLabel       = "blocklist"
 	cacheResultLabel     = "cache_result"
+	circuitStateLabel    = "circuit_state"
 	connectionErrorLabel = "connection_error"
 	cookieLabel          = "cookie"
+	directionLabel       = "direction"
 	hasBidsLabel         = "has_bids"
+	hedgeWinnerLabel     = "winner"
 	isAudioLabel         = "audio"
 	isBannerLabel        = "banner"
 	isNativeLabel        = "native"
@@ -346,6 +357,42 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		[]string{adapterLabel},
 		standardTimeBuckets)
 
+	metrics.adapterSKANIDListFetchTimer = newHistogramVec(cfg, metrics.Registry,
+		"adapter_skan_id_list_fetch_time_seconds",
+		"Seconds to fetch the SKAN ID List hosted by the adapter labeled by success or failure.",
+		[]string{adapterLabel, successLabel},
+		standardTimeBuckets)
+
+	metrics.adapterSKANIDListSize = newGaugeVec(cfg, metrics.Registry,
+		"adapter_skan_id_list_size",
+		"Number of SKAN IDs in the last successfully fetched SKAN ID List labeled by adapter.",
+		[]string{adapterLabel})
+
+	metrics.adapterCircuitBreaker = newCounter(cfg, metrics.Registry,
+		"adapter_circuit_breaker_transitions",
+		"Count of transitions of the circuit breakers of the adapter endpoints labeled by adapter and new state.",
+		[]string{adapterLabel, circuitStateLabel})
+
+	metrics.adapterBlockedCreatives = newCounter(cfg, metrics.Registry,
+		"adapter_blocked_creatives",
+		"Count of bids rejected because their creative is blocked labeled by adapter and blocklist.",
+		[]string{adapterLabel, blocklistLabel})
+
+	metrics.adapterHedgedRequests = newCounter(cfg, metrics.Registry,
+		"adapter_hedged_requests",
+		"Count of requests hedged to the secondary endpoint of the adapter labeled by adapter and winning endpoint.",
+		[]string{adapterLabel, hedgeWinnerLabel})
+
+	metrics.adapterPayloadRawBytes = newCounter(cfg, metrics.Registry,
+		"adapter_payload_raw_bytes",
+		"Bytes of the bodies exchanged with the adapters accepting gzip before compression labeled by adapter and direction.",
+		[]string{adapterLabel, directionLabel})
+
+	metrics.adapterPayloadWireBytes = newCounter(cfg, metrics.Registry,
+		"adapter_payload_wire_bytes",
+		"Bytes of the bodies exchanged with the adapters accepting gzip as sent over the network labeled by adapter and direction.",
+		[]string{adapterLabel, directionLabel})
+
 	metrics.adapterUserSync = newCounter(cfg, metrics.Registry,
 		"adapter_user_sync",
 		"Count of user ID sync requests received labeled by adapter and action.",
@@ -391,6 +438,18 @@ func newCounterWithoutLabels(cfg config.PrometheusMetrics, registry *prometheus.
 	return counter
 }
 
+func newGaugeVec(cfg config.PrometheusMetrics, registry *prometheus.Registry, name, help string, labels []string) *prometheus.GaugeVec {
+	opts := prometheus.GaugeOpts{
+		Namespace: cfg.Namespace,
+		Subsystem: cfg.Subsystem,
+		Name:      name,
+		Help:      help,
+	}
+	gauge := prometheus.NewGaugeVec(opts, labels)
+	registry.MustRegister(gauge)
+	return gauge
+}
+
 func newHistogramVec(cfg config.PrometheusMetrics, registry *prometheus.Registry, name, help string, labels []string, buckets []float64) *prometheus.HistogramVec {
 	opts := prometheus.HistogramOpts{
 		Namespace: cfg.Namespace,
@@ -709,3 +768,46 @@ func (m *Metrics) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.Bidder
 		adapterLabel: string(adapterName),
 	}).Inc()
 }
+
+func (m *Metrics) RecordSKANIDListFetch(adapterName openrtb_ext.BidderName, success bool, length time.Duration, listSize int) {
+	m.adapterSKANIDListFetchTimer.With(prometheus.Labels{
+		adapterLabel: string(adapterName),
+		successLabel: strconv.FormatBool(success),
+	}).Observe(length.Seconds())
+
+	if success {
+		m.adapterSKANIDListSize.With(prometheus.Labels{
+			adapterLabel: string(adapterName),
+		}).Set(float64(listSize))
+	}
+}
+
+func (m *Metrics) RecordAdapterCircuitBreaker(adapterName openrtb_ext.BidderName, state metrics.CircuitBreakerState) {
+	m.adapterCircuitBreaker.With(prometheus.Labels{
+		adapterLabel:      string(adapterName),
+		circuitStateLabel: string(state),
+	}).Inc()
+}
+
+func (m *Metrics) RecordAdapterBlockedCreative(adapterName openrtb_ext.BidderName, blocklist metrics.CreativeBlocklist) {
+	m.adapterBlockedCreatives.With(prometheus.Labels{
+		adapterLabel:   string(adapterName),
+		blocklistLabel: string(blocklist),
+	}).Inc()
+}
+
+func (m *Metrics) RecordAdapterHedge(adapterName openrtb_ext.BidderName, winner metrics.HedgeWinner) {
+	m.adapterHedgedRequests.With(prometheus.Labels{
+		adapterLabel:     string(adapterName),
+		hedgeWinnerLabel: string(winner),
+	}).Inc()
+}
+
+func (m *Metrics) RecordAdapterPayload(adapterName openrtb_ext.BidderName, direction metrics.PayloadDirection, rawBytes int, wireBytes int) {
+	labels := prometheus.Labels{
+		adapterLabel:   string(adapterName),
+		directionLabel: string(direction),
+	}
+	m.adapterPayloadRawBytes.With(labels).Add(float64(rawBytes))
+	m.adapterPayloadWireBytes.With(labels).Add(float64(wireBytes))
+}
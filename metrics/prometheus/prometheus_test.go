@@ -1514,3 +1514,74 @@ func TestRecordAdapterGDPRRequestBlocked(t *testing.T) {
 			adapterLabel: string(openrtb_ext.BidderAppnexus),
 		})
 }
+
+func TestRecordSKANIDListFetch(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordSKANIDListFetch(openrtb_ext.BidderRubicon, true, time.Duration(500)*time.Millisecond, 42)
+	m.RecordSKANIDListFetch(openrtb_ext.BidderRubicon, false, time.Duration(250)*time.Millisecond, 0)
+
+	successResult := getHistogramFromHistogramVecByTwoKeys(m.adapterSKANIDListFetchTimer, adapterLabel, string(openrtb_ext.BidderRubicon), successLabel, "true")
+	assertHistogram(t, "adapter_skan_id_list_fetch_time_seconds:ok", successResult, 1, 0.5)
+
+	errorResult := getHistogramFromHistogramVecByTwoKeys(m.adapterSKANIDListFetchTimer, adapterLabel, string(openrtb_ext.BidderRubicon), successLabel, "false")
+	assertHistogram(t, "adapter_skan_id_list_fetch_time_seconds:err", errorResult, 1, 0.25)
+
+	var size float64
+	processMetrics(m.adapterSKANIDListSize, func(m dto.Metric) {
+		size = m.GetGauge().GetValue()
+	})
+	assert.Equal(t, float64(42), size, "adapter_skan_id_list_size")
+}
+
+func TestRecordAdapterCircuitBreaker(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordAdapterCircuitBreaker(openrtb_ext.BidderRubicon, metrics.CircuitBreakerOpen)
+
+	assertCounterVecValue(t, "", "adapter_circuit_breaker_transitions:open", m.adapterCircuitBreaker,
+		1,
+		prometheus.Labels{
+			adapterLabel:      string(openrtb_ext.BidderRubicon),
+			circuitStateLabel: string(metrics.CircuitBreakerOpen),
+		})
+}
+
+func TestRecordAdapterBlockedCreative(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordAdapterBlockedCreative(openrtb_ext.BidderRubicon, metrics.CreativeBlocklistAdvertiser)
+
+	assertCounterVecValue(t, "", "adapter_blocked_creatives:badv", m.adapterBlockedCreatives,
+		1,
+		prometheus.Labels{
+			adapterLabel:   string(openrtb_ext.BidderRubicon),
+			blocklistLabel: string(metrics.CreativeBlocklistAdvertiser),
+		})
+}
+
+func TestRecordAdapterHedge(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordAdapterHedge(openrtb_ext.BidderLiftoff, metrics.HedgeWinnerSecondary)
+
+	assertCounterVecValue(t, "", "adapter_hedged_requests:secondary", m.adapterHedgedRequests,
+		1,
+		prometheus.Labels{
+			adapterLabel:     string(openrtb_ext.BidderLiftoff),
+			hedgeWinnerLabel: string(metrics.HedgeWinnerSecondary),
+		})
+}
+
+func TestRecordAdapterPayload(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordAdapterPayload(openrtb_ext.BidderLiftoff, metrics.PayloadRequest, 1000, 200)
+
+	labels := prometheus.Labels{
+		adapterLabel:   string(openrtb_ext.BidderLiftoff),
+		directionLabel: string(metrics.PayloadRequest),
+	}
+	assertCounterVecValue(t, "", "adapter_payload_raw_bytes:request", m.adapterPayloadRawBytes, 1000, labels)
+	assertCounterVecValue(t, "", "adapter_payload_wire_bytes:request", m.adapterPayloadWireBytes, 200, labels)
+}
@@ -9,6 +9,35 @@ type ExtBid struct {
 	Prebid *ExtBidPrebid `json:"prebid,omitempty"`
 }
 
+// ExtBidSKADN defines the contract for bidresponse.seatbid.bid[i].ext.skadn, the IAB SKAdNetwork extension
+type ExtBidSKADN struct {
+	Version    string `json:"version"`
+	Network    string `json:"network"`
+	ITunesItem string `json:"itunesitem"`
+	SourceApp  string `json:"sourceapp,omitempty"`
+	// Campaign is used up to SKAdNetwork 3.0
+	Campaign string `json:"campaign,omitempty"`
+	// SourceIdentifier replaces Campaign as of SKAdNetwork 4.0
+	SourceIdentifier string `json:"sourceidentifier,omitempty"`
+	// ProductPageID is the custom product page the ad leads to (SKAdNetwork 4.0)
+	ProductPageID string `json:"productpageid,omitempty"`
+	// Fidelities are required as of SKAdNetwork 2.2
+	Fidelities []SKADNFidelity `json:"fidelities,omitempty"`
+	// Nonce, Timestamp and Signature are used before SKAdNetwork 2.2
+	Nonce     string `json:"nonce,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Signature string `json:"signature,omitempty"`
+}
+
+// SKADNFidelity defines the contract for bidresponse.seatbid.bid[i].ext.skadn.fidelities[i]
+type SKADNFidelity struct {
+	// Fidelity is 0 for view-through and 1 for StoreKit-rendered ads
+	Fidelity  int    `json:"fidelity"`
+	Nonce     string `json:"nonce"`
+	Timestamp string `json:"timestamp"`
+	Signature string `json:"signature"`
+}
+
 // ExtBidPrebid defines the contract for bidresponse.seatbid.bid[i].ext.prebid
 // DealPriority represents priority of deal bid. If its non deal bid then value will be 0
 // DealTierSatisfied true represents corresponding bid has satisfied the deal tier
@@ -22,6 +51,8 @@ type ExtBidPrebid struct {
 	Video             *ExtBidPrebidVideo  `json:"video,omitempty"`
 	Events            *ExtBidPrebidEvents `json:"events,omitempty"`
 	BidId             string              `json:"bidid,omitempty"`
+	// TargetBidderCode is the bidder code of the targeting keys of a bid from a multibid bidder
+	TargetBidderCode string `json:"targetbiddercode,omitempty"`
 }
 
 // ExtBidPrebidCache defines the contract for  bidresponse.seatbid.bid[i].ext.prebid.cache
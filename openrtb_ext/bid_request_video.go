@@ -1,6 +1,6 @@
 package openrtb_ext
 
-import "github.com/mxmCherry/openrtb/v15/openrtb2"
+import "github.com/prebid/openrtb/v20/openrtb2"
 
 type BidRequestVideo struct {
 	// Attribute:
@@ -173,6 +173,37 @@ type PodConfig struct {
 	//   object; required
 	//  Container object for describing the adPod(s) to be requested.
 	Pods []Pod `json:"pods"`
+
+	// Attribute:
+	//   exclusion
+	// Type:
+	//   object; optional
+	//  Competitive separation of the bids within each pod. Bids sharing an enabled field
+	//  with a higher priced bid of the same pod are removed from the response.
+	Exclusion *PodExclusion `json:"exclusion,omitempty"`
+}
+
+type PodExclusion struct {
+	// Attribute:
+	//   adomain
+	// Type:
+	//   boolean; optional
+	//  Flag indicating that a pod cannot hold two bids of the same advertiser domain
+	AdvertiserDomain bool `json:"adomain,omitempty"`
+
+	// Attribute:
+	//   crid
+	// Type:
+	//   boolean; optional
+	//  Flag indicating that a pod cannot hold the same creative twice
+	CreativeID bool `json:"crid,omitempty"`
+
+	// Attribute:
+	//   primarycategory
+	// Type:
+	//   boolean; optional
+	//  Flag indicating that a pod cannot hold two bids of the same primary category
+	PrimaryCategory bool `json:"primarycategory,omitempty"`
 }
 
 type Pod struct {
@@ -18,3 +18,20 @@ type VideoTargeting struct {
 	HbPbCatDur string `json:"hb_pb_cat_dur,omitempty"`
 	HbCacheID  string `json:"hb_cache_id,omitempty"`
 }
+
+// Reasons of the bids excluded from a pod, named after the field shared with the bid kept
+const (
+	PodExclusionAdvertiserDomain = "adomain"
+	PodExclusionCreativeID       = "crid"
+	PodExclusionPrimaryCategory  = "primarycategory"
+)
+
+// ExcludedPodBid defines the contract for the video endpoint bidresponse.ext.prebid.excludedbids[i], a bid
+// removed from a pod because it shares a field with a higher priced bid of the pod
+type ExcludedPodBid struct {
+	PodId        int64  `json:"podid"`
+	BidId        string `json:"bidid"`
+	Seat         string `json:"seat"`
+	Reason       string `json:"reason"`
+	WinningBidId string `json:"winningbidid"`
+}
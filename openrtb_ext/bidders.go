@@ -445,3 +445,35 @@ func (validator *bidderParamValidator) Validate(name BidderName, ext json.RawMes
 func (validator *bidderParamValidator) Schema(name BidderName) string {
 	return validator.schemaContents[name]
 }
+
+// NewSeatParamsValidator makes a BidderParamValidator which validates the params of the seats set in the adapter
+// config, which have no bidder name nor schema of their own, with the schema of the core bidder serving them.
+func NewSeatParamsValidator(validator BidderParamValidator, seats map[BidderName]BidderName) BidderParamValidator {
+	if len(seats) == 0 {
+		return validator
+	}
+	return &seatParamValidator{
+		BidderParamValidator: validator,
+		seats:                seats,
+	}
+}
+
+type seatParamValidator struct {
+	BidderParamValidator
+	seats map[BidderName]BidderName
+}
+
+func (validator *seatParamValidator) Validate(name BidderName, ext json.RawMessage) error {
+	return validator.BidderParamValidator.Validate(validator.core(name), ext)
+}
+
+func (validator *seatParamValidator) Schema(name BidderName) string {
+	return validator.BidderParamValidator.Schema(validator.core(name))
+}
+
+func (validator *seatParamValidator) core(name BidderName) BidderName {
+	if core, ok := validator.seats[name]; ok {
+		return core
+	}
+	return name
+}
@@ -118,3 +118,21 @@ func TestIsBidderNameReserved(t *testing.T) {
 		assert.Equal(t, test.expected, result, test.bidder)
 	}
 }
+
+func TestSeatParamsValidator(t *testing.T) {
+	validator, err := NewBidderParamsValidator("../static/bidder-params")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, validator, NewSeatParamsValidator(validator, nil), "no seats")
+
+	seat := BidderName("newseat")
+	seatValidator := NewSeatParamsValidator(validator, map[BidderName]BidderName{seat: BidderRubicon})
+	assert.Equal(t, validator.Schema(BidderRubicon), seatValidator.Schema(seat))
+	assert.NoError(t, seatValidator.Validate(seat, json.RawMessage(`{"accountId":1,"siteId":2,"zoneId":3}`)))
+	assert.Error(t, seatValidator.Validate(seat, json.RawMessage(`{}`)))
+	assert.Equal(t, validator.Schema(BidderAppnexus), seatValidator.Schema(BidderAppnexus), "core bidder")
+
+	_, ok := NormalizeBidderName(string(seat))
+	assert.False(t, ok, "the seat is not registered as a bidder name")
+}
@@ -0,0 +1,58 @@
+package openrtb_ext
+
+// Fields a price floors schema can be keyed by
+const (
+	FloorFieldMediaType     = "mediaType"
+	FloorFieldSize          = "size"
+	FloorFieldBundle        = "bundle"
+	FloorFieldCountry       = "country"
+	FloorFieldPlacementType = "placementType"
+)
+
+// FloorWildcard matches any value of a schema field
+const FloorWildcard = "*"
+
+// PriceFloorRules defines the contract for bidrequest.ext.prebid.floors
+type PriceFloorRules struct {
+	// Enabled turns floors off for the request when explicitly set to false
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Currency of the rule values and of the default floor, USD if empty
+	Currency string `json:"currency,omitempty"`
+
+	Schema PriceFloorSchema `json:"schema"`
+
+	// Values maps a rule key, the schema field values joined by the schema delimiter, to a floor.
+	// Any field of a key may be "*".
+	Values map[string]float64 `json:"values,omitempty"`
+
+	// Default is the floor used when no rule matches an imp
+	Default float64 `json:"default,omitempty"`
+
+	Enforcement *PriceFloorEnforcement `json:"enforcement,omitempty"`
+}
+
+// PriceFloorSchema defines the contract for bidrequest.ext.prebid.floors.schema
+type PriceFloorSchema struct {
+	Fields    []string `json:"fields"`
+	Delimiter string   `json:"delimiter,omitempty"`
+}
+
+// PriceFloorEnforcement defines the contract for bidrequest.ext.prebid.floors.enforcement
+type PriceFloorEnforcement struct {
+	// EnforcePBS makes Prebid Server reject the bids under floor, true if not set
+	EnforcePBS *bool `json:"enforcepbs,omitempty"`
+}
+
+// GetEnabled returns true unless floors were explicitly disabled
+func (floors *PriceFloorRules) GetEnabled() bool {
+	return floors != nil && (floors.Enabled == nil || *floors.Enabled)
+}
+
+// GetEnforcePBS returns true if bids under floor have to be rejected by Prebid Server
+func (floors *PriceFloorRules) GetEnforcePBS() bool {
+	if !floors.GetEnabled() {
+		return false
+	}
+	return floors.Enforcement == nil || floors.Enforcement.EnforcePBS == nil || *floors.Enforcement.EnforcePBS
+}
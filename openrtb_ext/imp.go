@@ -23,10 +23,26 @@ type ExtStoredRequest struct {
 	ID string `json:"id"`
 }
 
-// SKADN ..
+// SKADN defines the contract for bidrequest.imp[i].ext.prebid.skadn, the IAB SKAdNetwork extension
 type SKADN struct {
-	Version    string   `json:"version,omitempty"`
-	Versions   []string `json:"versions,omitempty"`
-	SourceApp  string   `json:"sourceapp,omitempty"`
-	SKADNetIDs []string `json:"skadnetids,omitempty"`
+	Version     string       `json:"version,omitempty"`
+	Versions    []string     `json:"versions,omitempty"`
+	SourceApp   string       `json:"sourceapp,omitempty"`
+	SKADNetIDs  []string     `json:"skadnetids,omitempty"`
+	SKADNetList *SKADNetList `json:"skadnetlist,omitempty"`
+	// ProductPage is 1 when the app supports custom product pages (SKAdNetwork 4.0)
+	ProductPage int8 `json:"productpage,omitempty"`
+	// SKOverlay is 1 when the app supports SKOverlay (SKAdNetwork 4.0)
+	SKOverlay int8 `json:"skoverlay,omitempty"`
+}
+
+// SKADNetList defines the contract for bidrequest.imp[i].ext.prebid.skadn.skadnetlist, the network IDs
+// declared in the app Info.plist as a version of the IAB SKAdNetwork ID list plus exclusions and additions
+type SKADNetList struct {
+	// Max is the version of the IAB SKAdNetwork ID list the app was built with
+	Max int `json:"max"`
+	// Excl lists the IAB list entries excluded by the app
+	Excl []int `json:"excl,omitempty"`
+	// Addl lists the network IDs declared by the app that are not in the IAB list
+	Addl []string `json:"addl,omitempty"`
 }
@@ -0,0 +1,107 @@
+package openrtb_ext
+
+import (
+	"fmt"
+
+	"github.com/prebid/prebid-server/errortypes"
+)
+
+// DefaultBidLimit is the number of bids per imp a bidder gets targeting for without multibid
+const DefaultBidLimit = 1
+
+// MaxBidLimit caps bidrequest.ext.prebid.multibid[i].maxbids
+const MaxBidLimit = 9
+
+// ExtMultiBid defines the contract for bidrequest.ext.prebid.multibid
+type ExtMultiBid struct {
+	Bidder  string   `json:"bidder,omitempty"`
+	Bidders []string `json:"bidders,omitempty"`
+	// MaxBids is the number of bids per imp of the bidders kept in the response
+	MaxBids *int `json:"maxbids,omitempty"`
+	// TargetBidderCodePrefix gives targeting keys to the extra bids of Bidder, the Nth bid being
+	// targeted as bidder <prefix><N>. It is ignored along with Bidders.
+	TargetBidderCodePrefix string `json:"targetbiddercodeprefix,omitempty"`
+}
+
+func (mb ExtMultiBid) String() string {
+	maxBids := "<nil>"
+	if mb.MaxBids != nil {
+		maxBids = fmt.Sprint(*mb.MaxBids)
+	}
+	return fmt.Sprintf("{Bidder:%s, Bidders:%v, MaxBids:%s, TargetBidderCodePrefix:%s}", mb.Bidder, mb.Bidders, maxBids, mb.TargetBidderCodePrefix)
+}
+
+// ValidateAndBuildExtMultiBid validates bidrequest.ext.prebid.multibid. The invalid entries are
+// dropped or fixed with a warning, so the returned entries are all valid and define each bidder once.
+func ValidateAndBuildExtMultiBid(prebid *ExtRequestPrebid) ([]*ExtMultiBid, []error) {
+	if prebid == nil || prebid.MultiBid == nil {
+		return nil, nil
+	}
+
+	var validated []*ExtMultiBid
+	var warnings []error
+	seen := make(map[string]struct{}, len(prebid.MultiBid))
+	for _, multiBid := range prebid.MultiBid {
+		if multiBid == nil {
+			continue
+		}
+		if multiBid.MaxBids == nil {
+			warnings = append(warnings, multiBidWarning("maxbids not defined for %v", *multiBid))
+			continue
+		}
+
+		maxBids := *multiBid.MaxBids
+		if maxBids < DefaultBidLimit {
+			warnings = append(warnings, multiBidWarning("invalid maxbids value, using minimum %d limit", DefaultBidLimit))
+			maxBids = DefaultBidLimit
+		} else if maxBids > MaxBidLimit {
+			warnings = append(warnings, multiBidWarning("invalid maxbids value, using maximum %d limit", MaxBidLimit))
+			maxBids = MaxBidLimit
+		}
+
+		if multiBid.Bidder != "" {
+			if _, ok := seen[multiBid.Bidder]; ok {
+				warnings = append(warnings, multiBidWarning("multibid already defined for %s, ignoring this instance %v", multiBid.Bidder, *multiBid))
+				continue
+			}
+			if multiBid.Bidders != nil {
+				warnings = append(warnings, multiBidWarning("ignoring bidders from %v", *multiBid))
+			}
+			seen[multiBid.Bidder] = struct{}{}
+			validated = append(validated, &ExtMultiBid{
+				Bidder:                 multiBid.Bidder,
+				MaxBids:                &maxBids,
+				TargetBidderCodePrefix: multiBid.TargetBidderCodePrefix,
+			})
+		} else if len(multiBid.Bidders) > 0 {
+			if multiBid.TargetBidderCodePrefix != "" {
+				warnings = append(warnings, multiBidWarning("ignoring targetbiddercodeprefix for %v", *multiBid))
+			}
+			var bidders []string
+			for _, bidder := range multiBid.Bidders {
+				if _, ok := seen[bidder]; ok {
+					warnings = append(warnings, multiBidWarning("multibid already defined for %s, ignoring this instance %v", bidder, *multiBid))
+					continue
+				}
+				seen[bidder] = struct{}{}
+				bidders = append(bidders, bidder)
+			}
+			if len(bidders) > 0 {
+				validated = append(validated, &ExtMultiBid{
+					Bidders: bidders,
+					MaxBids: &maxBids,
+				})
+			}
+		} else {
+			warnings = append(warnings, multiBidWarning("bidder(s) not specified for %v", *multiBid))
+		}
+	}
+	return validated, warnings
+}
+
+func multiBidWarning(format string, a ...interface{}) error {
+	return &errortypes.Warning{
+		Message:     "request.ext.prebid.multibid: " + fmt.Sprintf(format, a...),
+		WarningCode: errortypes.MultiBidWarningCode,
+	}
+}
@@ -0,0 +1,108 @@
+package openrtb_ext
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAndBuildExtMultiBid(t *testing.T) {
+	zero, one, two, ten := 0, 1, 2, 10
+	max := MaxBidLimit
+
+	testCases := []struct {
+		description      string
+		multiBid         []*ExtMultiBid
+		expected         []*ExtMultiBid
+		expectedWarnings []string
+	}{
+		{
+			description: "valid entries",
+			multiBid: []*ExtMultiBid{
+				{Bidder: "appnexus", MaxBids: &two, TargetBidderCodePrefix: "appN"},
+				{Bidders: []string{"rubicon", "pubmatic"}, MaxBids: &two},
+			},
+			expected: []*ExtMultiBid{
+				{Bidder: "appnexus", MaxBids: &two, TargetBidderCodePrefix: "appN"},
+				{Bidders: []string{"rubicon", "pubmatic"}, MaxBids: &two},
+			},
+		},
+		{
+			description: "maxbids missing",
+			multiBid:    []*ExtMultiBid{{Bidder: "appnexus"}},
+			expectedWarnings: []string{
+				"request.ext.prebid.multibid: maxbids not defined for {Bidder:appnexus, Bidders:[], MaxBids:<nil>, TargetBidderCodePrefix:}",
+			},
+		},
+		{
+			description: "maxbids out of range",
+			multiBid: []*ExtMultiBid{
+				{Bidder: "appnexus", MaxBids: &zero},
+				{Bidder: "rubicon", MaxBids: &ten},
+			},
+			expected: []*ExtMultiBid{
+				{Bidder: "appnexus", MaxBids: &one},
+				{Bidder: "rubicon", MaxBids: &max},
+			},
+			expectedWarnings: []string{
+				"request.ext.prebid.multibid: invalid maxbids value, using minimum 1 limit",
+				"request.ext.prebid.multibid: invalid maxbids value, using maximum 9 limit",
+			},
+		},
+		{
+			description: "bidder defined twice",
+			multiBid: []*ExtMultiBid{
+				{Bidder: "appnexus", MaxBids: &two},
+				{Bidders: []string{"appnexus", "rubicon"}, MaxBids: &one},
+				{Bidder: "rubicon", MaxBids: &two},
+			},
+			expected: []*ExtMultiBid{
+				{Bidder: "appnexus", MaxBids: &two},
+				{Bidders: []string{"rubicon"}, MaxBids: &one},
+			},
+			expectedWarnings: []string{
+				"request.ext.prebid.multibid: multibid already defined for appnexus, ignoring this instance {Bidder:, Bidders:[appnexus rubicon], MaxBids:1, TargetBidderCodePrefix:}",
+				"request.ext.prebid.multibid: multibid already defined for rubicon, ignoring this instance {Bidder:rubicon, Bidders:[], MaxBids:2, TargetBidderCodePrefix:}",
+			},
+		},
+		{
+			description: "bidders ignored with bidder, prefix ignored with bidders",
+			multiBid: []*ExtMultiBid{
+				{Bidder: "appnexus", Bidders: []string{"rubicon"}, MaxBids: &two},
+				{Bidders: []string{"pubmatic"}, MaxBids: &two, TargetBidderCodePrefix: "pm"},
+			},
+			expected: []*ExtMultiBid{
+				{Bidder: "appnexus", MaxBids: &two},
+				{Bidders: []string{"pubmatic"}, MaxBids: &two},
+			},
+			expectedWarnings: []string{
+				"request.ext.prebid.multibid: ignoring bidders from {Bidder:appnexus, Bidders:[rubicon], MaxBids:2, TargetBidderCodePrefix:}",
+				"request.ext.prebid.multibid: ignoring targetbiddercodeprefix for {Bidder:, Bidders:[pubmatic], MaxBids:2, TargetBidderCodePrefix:pm}",
+			},
+		},
+		{
+			description: "no bidder",
+			multiBid:    []*ExtMultiBid{{MaxBids: &two}},
+			expectedWarnings: []string{
+				"request.ext.prebid.multibid: bidder(s) not specified for {Bidder:, Bidders:[], MaxBids:2, TargetBidderCodePrefix:}",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		validated, warnings := ValidateAndBuildExtMultiBid(&ExtRequestPrebid{MultiBid: test.multiBid})
+
+		assert.Equal(t, test.expected, validated, test.description)
+		var messages []string
+		for _, warning := range warnings {
+			assert.Equal(t, errortypes.MultiBidWarningCode, errortypes.ReadCode(warning), test.description)
+			messages = append(messages, warning.Error())
+		}
+		assert.Equal(t, test.expectedWarnings, messages, test.description)
+	}
+
+	validated, warnings := ValidateAndBuildExtMultiBid(&ExtRequestPrebid{})
+	assert.Nil(t, validated)
+	assert.Nil(t, warnings)
+}
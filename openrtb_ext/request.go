@@ -32,6 +32,8 @@ type ExtRequestPrebid struct {
 	Data                 *ExtRequestPrebidData     `json:"data,omitempty"`
 	Debug                bool                      `json:"debug,omitempty"`
 	Events               json.RawMessage           `json:"events,omitempty"`
+	Floors               *PriceFloorRules          `json:"floors,omitempty"`
+	MultiBid             []*ExtMultiBid            `json:"multibid,omitempty"`
 	SChains              []*ExtRequestPrebidSChain `json:"schains,omitempty"`
 	StoredRequest        *ExtStoredRequest         `json:"storedrequest,omitempty"`
 	SupportDeals         bool                      `json:"supportdeals,omitempty"`
@@ -1,6 +1,6 @@
 package openrtb_ext
 
-import "github.com/mxmCherry/openrtb/v15/openrtb2"
+import "github.com/prebid/openrtb/v20/openrtb2"
 
 // ExtBidResponse defines the contract for bidresponse.ext
 type ExtBidResponse struct {
@@ -26,6 +26,19 @@ type ExtResponseDebug struct {
 	HttpCalls map[BidderName][]*ExtHttpCall `json:"httpcalls,omitempty"`
 	// Request after resolution of stored requests and debug overrides
 	ResolvedRequest *openrtb2.BidRequest `json:"resolvedrequest,omitempty"`
+	// TimeoutBudgets defines the contract for bidresponse.ext.debug.timeoutbudgets, only set with adaptive timeouts
+	TimeoutBudgets map[BidderName]*ExtTimeoutBudget `json:"timeoutbudgets,omitempty"`
+}
+
+// ExtTimeoutBudget defines the contract for bidresponse.ext.debug.timeoutbudgets.{bidder}, the time the bidder was
+// given out of the auction timeout
+type ExtTimeoutBudget struct {
+	// BudgetMillis is 0 when the bidder has too few recent latencies, it is then given the whole auction timeout
+	BudgetMillis     int64 `json:"budgetmillis"`
+	PercentileMillis int64 `json:"percentilemillis"`
+	Samples          int   `json:"samples"`
+	// TMax is the tmax sent to the bidder
+	TMax int64 `json:"tmax"`
 }
 
 // ExtResponseSyncData defines the contract for bidresponse.ext.usersync.{bidder}
@@ -38,6 +51,21 @@ type ExtResponseSyncData struct {
 // ExtResponsePrebid defines the contract for bidresponse.ext.prebid
 type ExtResponsePrebid struct {
 	AuctionTimestamp int64 `json:"auctiontimestamp,omitempty"`
+	// SKANRouting is only emitted for debugging
+	SKANRouting *ExtResponseSKANRouting `json:"skanrouting,omitempty"`
+}
+
+// ExtResponseSKANRouting defines the contract for bidresponse.ext.prebid.skanrouting, the bidders kept from
+// the imps they cannot attribute through SKAdNetwork when App Tracking Transparency is denied
+type ExtResponseSKANRouting struct {
+	Mode    string                                  `json:"mode"`
+	Bidders map[BidderName][]ExtSKANRoutingDecision `json:"bidders,omitempty"`
+}
+
+// ExtSKANRoutingDecision defines the contract for bidresponse.ext.prebid.skanrouting.bidders.{bidder}[i]
+type ExtSKANRoutingDecision struct {
+	ImpID  string `json:"impid"`
+	Reason string `json:"reason"`
 }
 
 // ExtUserSync defines the contract for bidresponse.ext.usersync.{bidder}.syncs[i]
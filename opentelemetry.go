@@ -3,17 +3,12 @@ package main
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/prebid/prebid-server/config"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
-	"go.opentelemetry.io/otel/metric/global"
 	"go.opentelemetry.io/otel/propagation"
-	controller "go.opentelemetry.io/otel/sdk/metric/controller/basic"
-	processor "go.opentelemetry.io/otel/sdk/metric/processor/basic"
-	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/semconv"
@@ -21,8 +16,9 @@ import (
 
 type DoneCallback func()
 
-// Initializes an OTLP exporter, and configures the corresponding trace and
-// metric providers.
+// Initializes an OTLP exporter, and configures the corresponding trace
+// provider. The metrics are exported by the OpenTelemetry metrics engine,
+// configured under metrics.opentelemetry.
 func initProvider(cfg config.OpenTelemetry) (DoneCallback, error) {
 	if !cfg.Enabled {
 		return func() {}, fmt.Errorf("error getting opentelemetry configs")
@@ -61,29 +57,10 @@ func initProvider(cfg config.OpenTelemetry) (DoneCallback, error) {
 		sdktrace.WithSpanProcessor(bsp),
 	)
 
-	cont := controller.New(
-		processor.New(
-			simple.NewWithExactDistribution(),
-			exp,
-		),
-		controller.WithExporter(exp),
-		controller.WithCollectPeriod(2*time.Second),
-	)
-
-	// LATER: currently do not report metrics
-	// err = cont.Start(ctx)
-	// if err != nil {
-	// 	log.Fatalf("failed to initialize metric controller: %v", err)
-	// }
-
 	// set global propagator to tracecontext (the default is no-op).
 	otel.SetTextMapPropagator(propagation.TraceContext{})
 	otel.SetTracerProvider(tracerProvider)
-	global.SetMeterProvider(cont.MeterProvider())
 	return func() {
-		// Push any last metric events to the exporter.
-		cont.Stop(context.Background())
-
 		// Shutdown will flush any remaining spans and shut down the exporter.
 		tracerProvider.Shutdown(ctx)
 	}, nil
@@ -0,0 +1,236 @@
+package ortb
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/buger/jsonparser"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// ConvertDownTo25 converts a bid request down to OpenRTB 2.5 for the bidders not speaking OpenRTB 2.6. Its OpenRTB
+// 2.6 fields are moved to their OpenRTB 2.5 ext equivalents, replacing the values already there:
+//
+//	regs.gdpr, regs.us_privacy, regs.gpp, regs.gpp_sid  to  regs.ext
+//	user.consent, user.eids                             to  user.ext
+//	source.schain                                       to  source.ext
+//	device.sua                                          to  device.ext
+//	imp.rwdd                                            to  imp.ext.prebid.is_rewarded_inventory
+//	imp.video.plcmt, imp.video.maxseq                   to  imp.video.ext
+//	the pod fields of imp.video and imp.audio           to  their ext: poddur, rqddurs, podid, podseq,
+//	                                                        slotinpod and mincpmpersec
+//
+// The request may share its objects with the requests of the other bidders, the objects it changes are copied.
+func ConvertDownTo25(request *openrtb2.BidRequest) error {
+	var err error
+	if request.Regs, err = convertRegs(request.Regs); err != nil {
+		return err
+	}
+	if request.User, err = convertUser(request.User); err != nil {
+		return err
+	}
+	if request.Source, err = convertSource(request.Source); err != nil {
+		return err
+	}
+	if request.Device, err = convertDevice(request.Device); err != nil {
+		return err
+	}
+
+	if len(request.Imp) == 0 {
+		return nil
+	}
+	imps := make([]openrtb2.Imp, len(request.Imp))
+	for i, imp := range request.Imp {
+		if imps[i], err = convertImp(imp); err != nil {
+			return fmt.Errorf("request.imp[%d] is invalid: %v", i, err)
+		}
+	}
+	request.Imp = imps
+	return nil
+}
+
+func convertRegs(regs *openrtb2.Regs) (*openrtb2.Regs, error) {
+	if regs == nil || (regs.GDPR == nil && regs.USPrivacy == "" && regs.GPP == "" && len(regs.GPPSID) == 0) {
+		return regs, nil
+	}
+
+	ext := newExtWriter(regs.Ext)
+	ext.set(regs.GDPR != nil, regs.GDPR, "gdpr")
+	ext.set(regs.USPrivacy != "", regs.USPrivacy, "us_privacy")
+	ext.set(regs.GPP != "", regs.GPP, "gpp")
+	ext.set(len(regs.GPPSID) > 0, regs.GPPSID, "gpp_sid")
+	if ext.err != nil {
+		return nil, fmt.Errorf("request.regs.ext is invalid: %v", ext.err)
+	}
+
+	converted := *regs
+	converted.GDPR, converted.USPrivacy, converted.GPP, converted.GPPSID = nil, "", "", nil
+	converted.Ext = ext.data
+	return &converted, nil
+}
+
+func convertUser(user *openrtb2.User) (*openrtb2.User, error) {
+	if user == nil || (user.Consent == "" && len(user.EIDs) == 0) {
+		return user, nil
+	}
+
+	ext := newExtWriter(user.Ext)
+	ext.set(user.Consent != "", user.Consent, "consent")
+	ext.set(len(user.EIDs) > 0, user.EIDs, "eids")
+	if ext.err != nil {
+		return nil, fmt.Errorf("request.user.ext is invalid: %v", ext.err)
+	}
+
+	converted := *user
+	converted.Consent, converted.EIDs = "", nil
+	converted.Ext = ext.data
+	return &converted, nil
+}
+
+func convertSource(source *openrtb2.Source) (*openrtb2.Source, error) {
+	if source == nil || source.SChain == nil {
+		return source, nil
+	}
+
+	ext := newExtWriter(source.Ext)
+	ext.set(true, source.SChain, "schain")
+	if ext.err != nil {
+		return nil, fmt.Errorf("request.source.ext is invalid: %v", ext.err)
+	}
+
+	converted := *source
+	converted.SChain = nil
+	converted.Ext = ext.data
+	return &converted, nil
+}
+
+func convertDevice(device *openrtb2.Device) (*openrtb2.Device, error) {
+	if device == nil || device.SUA == nil {
+		return device, nil
+	}
+
+	ext := newExtWriter(device.Ext)
+	ext.set(true, device.SUA, "sua")
+	if ext.err != nil {
+		return nil, fmt.Errorf("request.device.ext is invalid: %v", ext.err)
+	}
+
+	converted := *device
+	converted.SUA = nil
+	converted.Ext = ext.data
+	return &converted, nil
+}
+
+func convertImp(imp openrtb2.Imp) (openrtb2.Imp, error) {
+	var err error
+	if imp.Rwdd != 0 {
+		ext := newExtWriter(imp.Ext)
+		ext.set(true, imp.Rwdd, "prebid", "is_rewarded_inventory")
+		if ext.err != nil {
+			return imp, fmt.Errorf("ext is invalid: %v", ext.err)
+		}
+		imp.Rwdd = 0
+		imp.Ext = ext.data
+	}
+	if imp.Video, err = convertVideo(imp.Video); err != nil {
+		return imp, err
+	}
+	if imp.Audio, err = convertAudio(imp.Audio); err != nil {
+		return imp, err
+	}
+	return imp, nil
+}
+
+func convertVideo(video *openrtb2.Video) (*openrtb2.Video, error) {
+	if video == nil {
+		return video, nil
+	}
+	videoPod := pod{video.PodDur, video.RqdDurs, video.PodID, video.PodSeq, video.SlotInPod, video.MinCPMPerSec}
+	if video.Plcmt == 0 && video.MaxSeq == 0 && !videoPod.isSet() {
+		return video, nil
+	}
+
+	ext := newExtWriter(video.Ext)
+	ext.set(video.Plcmt != 0, video.Plcmt, "plcmt")
+	ext.set(video.MaxSeq != 0, video.MaxSeq, "maxseq")
+	ext.setPod(videoPod)
+	if ext.err != nil {
+		return nil, fmt.Errorf("video.ext is invalid: %v", ext.err)
+	}
+
+	converted := *video
+	converted.Plcmt, converted.MaxSeq = 0, 0
+	converted.PodDur, converted.RqdDurs, converted.PodID, converted.PodSeq, converted.SlotInPod, converted.MinCPMPerSec = 0, nil, "", 0, 0, 0
+	converted.Ext = ext.data
+	return &converted, nil
+}
+
+// audio.maxseq is already an OpenRTB 2.5 field, only video.maxseq is new in OpenRTB 2.6
+func convertAudio(audio *openrtb2.Audio) (*openrtb2.Audio, error) {
+	if audio == nil {
+		return audio, nil
+	}
+	audioPod := pod{audio.PodDur, audio.RqdDurs, audio.PodID, audio.PodSeq, audio.SlotInPod, audio.MinCPMPerSec}
+	if !audioPod.isSet() {
+		return audio, nil
+	}
+
+	ext := newExtWriter(audio.Ext)
+	ext.setPod(audioPod)
+	if ext.err != nil {
+		return nil, fmt.Errorf("audio.ext is invalid: %v", ext.err)
+	}
+
+	converted := *audio
+	converted.PodDur, converted.RqdDurs, converted.PodID, converted.PodSeq, converted.SlotInPod, converted.MinCPMPerSec = 0, nil, "", 0, 0, 0
+	converted.Ext = ext.data
+	return &converted, nil
+}
+
+// pod is the pod fields OpenRTB 2.6 added to both video and audio
+type pod struct {
+	podDur       int64
+	rqdDurs      []int64
+	podID        string
+	podSeq       adcom1.PodSequence
+	slotInPod    adcom1.SlotPositionInPod
+	minCPMPerSec float64
+}
+
+func (p pod) isSet() bool {
+	return p.podDur != 0 || len(p.rqdDurs) > 0 || p.podID != "" || p.podSeq != 0 || p.slotInPod != 0 || p.minCPMPerSec != 0
+}
+
+// extWriter sets values in a copy of an ext, it stops at the first error
+type extWriter struct {
+	data json.RawMessage
+	err  error
+}
+
+func newExtWriter(ext json.RawMessage) *extWriter {
+	if len(ext) == 0 {
+		return &extWriter{data: json.RawMessage(`{}`)}
+	}
+	return &extWriter{data: append(json.RawMessage(nil), ext...)}
+}
+
+// set sets the value at path if ok
+func (w *extWriter) set(ok bool, value interface{}, path ...string) {
+	if !ok || w.err != nil {
+		return
+	}
+	var data []byte
+	if data, w.err = json.Marshal(value); w.err == nil {
+		w.data, w.err = jsonparser.Set(w.data, data, path...)
+	}
+}
+
+func (w *extWriter) setPod(p pod) {
+	w.set(p.podDur != 0, p.podDur, "poddur")
+	w.set(len(p.rqdDurs) > 0, p.rqdDurs, "rqddurs")
+	w.set(p.podID != "", p.podID, "podid")
+	w.set(p.podSeq != 0, p.podSeq, "podseq")
+	w.set(p.slotInPod != 0, p.slotInPod, "slotinpod")
+	w.set(p.minCPMPerSec != 0, p.minCPMPerSec, "mincpmpersec")
+}
@@ -0,0 +1,97 @@
+package ortb
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertDownTo25(t *testing.T) {
+	testCases := []struct {
+		description   string
+		request       string
+		expected      string
+		expectedError bool
+	}{
+		{
+			description: "No Regs",
+			request:     `{"id":"1","imp":[]}`,
+			expected:    `{"id":"1","imp":[]}`,
+		},
+		{
+			description: "Moved",
+			request:     `{"id":"1","imp":[],"regs":{"coppa":1,"gpp":"DBABL~BVVqAAAAAg","gpp_sid":[7]}}`,
+			expected:    `{"id":"1","imp":[],"regs":{"coppa":1,"ext":{"gpp":"DBABL~BVVqAAAAAg","gpp_sid":[7]}}}`,
+		},
+		{
+			description: "Moved Into Existing Ext",
+			request:     `{"id":"1","imp":[],"regs":{"gpp":"DBABL~BVVqAAAAAg","ext":{"us_privacy":"1YNN"}}}`,
+			expected:    `{"id":"1","imp":[],"regs":{"ext":{"us_privacy":"1YNN","gpp":"DBABL~BVVqAAAAAg"}}}`,
+		},
+		{
+			description: "OpenRTB 2.6 Preferred",
+			request:     `{"id":"1","imp":[],"regs":{"gpp":"DBABL~BVVqAAAAAg","gpp_sid":[7],"ext":{"gpp":"DBABTA~1YNN","gpp_sid":[6]}}}`,
+			expected:    `{"id":"1","imp":[],"regs":{"ext":{"gpp":"DBABL~BVVqAAAAAg","gpp_sid":[7]}}}`,
+		},
+		{
+			description: "Privacy, Identity And Supply Chain",
+			request:     `{"id":"1","imp":[],"regs":{"gdpr":1,"us_privacy":"1YNN"},"user":{"id":"u","consent":"CO","eids":[{"source":"s.com","uids":[{"id":"x"}]}]},"source":{"schain":{"complete":1,"nodes":[],"ver":"1.0"}}}`,
+			expected:    `{"id":"1","imp":[],"regs":{"ext":{"gdpr":1,"us_privacy":"1YNN"}},"user":{"id":"u","ext":{"consent":"CO","eids":[{"source":"s.com","uids":[{"id":"x"}]}]}},"source":{"ext":{"schain":{"complete":1,"nodes":[],"ver":"1.0"}}}}`,
+		},
+		{
+			description: "Structured User Agent",
+			request:     `{"id":"1","imp":[],"device":{"ua":"ua","sua":{"mobile":1}}}`,
+			expected:    `{"id":"1","imp":[],"device":{"ua":"ua","ext":{"sua":{"mobile":1}}}}`,
+		},
+		{
+			description: "Imps",
+			request:     `{"id":"1","imp":[{"id":"1","rwdd":1,"video":{"mimes":["video/mp4"],"plcmt":1,"podid":"pod","slotinpod":1},"ext":{"bidder":{}}},{"id":"2","audio":{"mimes":["audio/mp4"],"poddur":60,"rqddurs":[15,30]}}]}`,
+			expected:    `{"id":"1","imp":[{"id":"1","video":{"mimes":["video/mp4"],"ext":{"plcmt":1,"podid":"pod","slotinpod":1}},"ext":{"bidder":{},"prebid":{"is_rewarded_inventory":1}}},{"id":"2","audio":{"mimes":["audio/mp4"],"ext":{"poddur":60,"rqddurs":[15,30]}}}]}`,
+		},
+		{
+			description: "Audio Max Sequence Kept",
+			request:     `{"id":"1","imp":[{"id":"1","video":{"mimes":["video/mp4"],"maxseq":3},"audio":{"mimes":["audio/mp4"],"maxseq":-1}}]}`,
+			expected:    `{"id":"1","imp":[{"id":"1","video":{"mimes":["video/mp4"],"ext":{"maxseq":3}},"audio":{"mimes":["audio/mp4"],"maxseq":-1}}]}`,
+		},
+		{
+			description:   "Invalid Imp Ext",
+			request:       `{"id":"1","imp":[{"id":"1","rwdd":1,"ext":["bidder"]}]}`,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		var request openrtb2.BidRequest
+		if err := json.Unmarshal([]byte(test.request), &request); err != nil {
+			t.Fatalf("%s: %v", test.description, err)
+		}
+
+		err := ConvertDownTo25(&request)
+
+		if test.expectedError {
+			assert.Error(t, err, test.description)
+			continue
+		}
+		if assert.NoError(t, err, test.description) {
+			result, _ := json.Marshal(request)
+			assert.JSONEq(t, test.expected, string(result), test.description)
+		}
+	}
+}
+
+func TestConvertDownTo25KeepsSharedObjects(t *testing.T) {
+	regs := &openrtb2.Regs{GPP: "DBABL~BVVqAAAAAg"}
+	video := &openrtb2.Video{Plcmt: adcom1.VideoPlcmtInterstitial}
+	imps := []openrtb2.Imp{{ID: "1", Rwdd: 1, Video: video}}
+	request := openrtb2.BidRequest{Regs: regs, Imp: imps}
+
+	assert.NoError(t, ConvertDownTo25(&request))
+
+	assert.Equal(t, &openrtb2.Regs{GPP: "DBABL~BVVqAAAAAg"}, regs)
+	assert.Equal(t, []openrtb2.Imp{{ID: "1", Rwdd: 1, Video: &openrtb2.Video{Plcmt: adcom1.VideoPlcmtInterstitial}}}, imps)
+	assert.JSONEq(t, `{"gpp":"DBABL~BVVqAAAAAg"}`, string(request.Regs.Ext))
+	assert.JSONEq(t, `{"prebid":{"is_rewarded_inventory":1}}`, string(request.Imp[0].Ext))
+}
@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -28,6 +28,15 @@ func TestConsentWriter(t *testing.T) {
 				Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"us_privacy":"anyConsent"}`)},
 			},
 		},
+		{
+			description: "Replaces OpenRTB 2.6",
+			request: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{USPrivacy: "otherConsent"},
+			},
+			expected: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"us_privacy":"anyConsent"}`)},
+			},
+		},
 		{
 			description: "Error With Regs.Ext - Does Not Mutate",
 			request: &openrtb2.BidRequest{
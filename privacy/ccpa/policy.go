@@ -5,7 +5,7 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/openrtb_ext"
 )
 
@@ -24,8 +24,10 @@ func ReadFromRequest(req *openrtb2.BidRequest) (Policy, error) {
 		return Policy{}, nil
 	}
 
-	// Read consent from request.regs.ext
-	if req.Regs != nil && len(req.Regs.Ext) > 0 {
+	// Read consent from the OpenRTB 2.6 request.regs.us_privacy, or request.regs.ext without it
+	if req.Regs != nil && req.Regs.USPrivacy != "" {
+		consent = req.Regs.USPrivacy
+	} else if req.Regs != nil && len(req.Regs.Ext) > 0 {
 		var ext openrtb_ext.ExtRegs
 		if err := json.Unmarshal(req.Regs.Ext, &ext); err != nil {
 			return Policy{}, fmt.Errorf("error reading request.regs.ext: %s", err)
@@ -66,6 +68,13 @@ func (p Policy) Write(req *openrtb2.BidRequest) error {
 }
 
 func buildRegs(consent string, regs *openrtb2.Regs) (*openrtb2.Regs, error) {
+	// the consent is written to request.regs.ext, it replaces the OpenRTB 2.6 request.regs.us_privacy
+	if regs != nil && regs.USPrivacy != "" {
+		regsCopy := *regs
+		regsCopy.USPrivacy = ""
+		regs = &regsCopy
+	}
+
 	if consent == "" {
 		return buildRegsClear(regs)
 	}
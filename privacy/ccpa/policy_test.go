@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -26,6 +26,15 @@ func TestReadFromRequest(t *testing.T) {
 				NoSaleBidders: []string{"a", "b"},
 			},
 		},
+		{
+			description: "OpenRTB 2.6 Preferred",
+			request: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{USPrivacy: "ABC", Ext: json.RawMessage(`{"us_privacy":"DEF"}`)},
+			},
+			expectedPolicy: Policy{
+				Consent: "ABC",
+			},
+		},
 		{
 			description: "Nil Request",
 			request:     nil,
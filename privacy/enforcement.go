@@ -1,6 +1,6 @@
 package privacy
 
-import "github.com/mxmCherry/openrtb/v15/openrtb2"
+import "github.com/prebid/openrtb/v20/openrtb2"
 
 // Enforcement represents the privacy policies to enforce for an OpenRTB bid request.
 type Enforcement struct {
@@ -8,12 +8,13 @@ type Enforcement struct {
 	COPPA   bool
 	GDPRGeo bool
 	GDPRID  bool
+	GPP     bool
 	LMT     bool
 }
 
 // Any returns true if at least one privacy policy requires enforcement.
 func (e Enforcement) Any() bool {
-	return e.CCPA || e.COPPA || e.GDPRGeo || e.GDPRID || e.LMT
+	return e.CCPA || e.COPPA || e.GDPRGeo || e.GDPRID || e.GPP || e.LMT
 }
 
 // Apply cleans personally identifiable information from an OpenRTB bid request.
@@ -29,7 +30,7 @@ func (e Enforcement) apply(bidRequest *openrtb2.BidRequest, scrubber Scrubber) {
 }
 
 func (e Enforcement) getDeviceIDScrubStrategy() ScrubStrategyDeviceID {
-	if e.COPPA || e.GDPRID || e.CCPA || e.LMT {
+	if e.COPPA || e.GDPRID || e.CCPA || e.GPP || e.LMT {
 		return ScrubStrategyDeviceIDAll
 	}
 
@@ -37,7 +38,7 @@ func (e Enforcement) getDeviceIDScrubStrategy() ScrubStrategyDeviceID {
 }
 
 func (e Enforcement) getIPv4ScrubStrategy() ScrubStrategyIPV4 {
-	if e.COPPA || e.GDPRGeo || e.CCPA || e.LMT {
+	if e.COPPA || e.GDPRGeo || e.CCPA || e.GPP || e.LMT {
 		return ScrubStrategyIPV4Lowest8
 	}
 
@@ -49,7 +50,7 @@ func (e Enforcement) getIPv6ScrubStrategy() ScrubStrategyIPV6 {
 		return ScrubStrategyIPV6Lowest32
 	}
 
-	if e.GDPRGeo || e.CCPA || e.LMT {
+	if e.GDPRGeo || e.CCPA || e.GPP || e.LMT {
 		return ScrubStrategyIPV6Lowest16
 	}
 
@@ -61,7 +62,7 @@ func (e Enforcement) getGeoScrubStrategy() ScrubStrategyGeo {
 		return ScrubStrategyGeoFull
 	}
 
-	if e.GDPRGeo || e.CCPA || e.LMT {
+	if e.GDPRGeo || e.CCPA || e.GPP || e.LMT {
 		return ScrubStrategyGeoReducedPrecision
 	}
 
@@ -73,7 +74,7 @@ func (e Enforcement) getUserScrubStrategy() ScrubStrategyUser {
 		return ScrubStrategyUserIDAndDemographic
 	}
 
-	if e.CCPA || e.LMT {
+	if e.CCPA || e.GPP || e.LMT {
 		return ScrubStrategyUserID
 	}
 
@@ -3,7 +3,7 @@ package gdpr
 import (
 	"encoding/json"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/openrtb_ext"
 )
 
@@ -12,7 +12,8 @@ type ConsentWriter struct {
 	Consent string
 }
 
-// Write mutates an OpenRTB bid request with the GDPR TCF consent.
+// Write mutates an OpenRTB bid request with the GDPR TCF consent. The consent is written to user.ext.consent and
+// replaces the OpenRTB 2.6 user.consent.
 func (c ConsentWriter) Write(req *openrtb2.BidRequest) error {
 	if c.Consent == "" {
 		return nil
@@ -20,6 +21,10 @@ func (c ConsentWriter) Write(req *openrtb2.BidRequest) error {
 
 	if req.User == nil {
 		req.User = &openrtb2.User{}
+	} else if req.User.Consent != "" {
+		user := *req.User
+		user.Consent = ""
+		req.User = &user
 	}
 
 	if req.User.Ext == nil {
@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -36,6 +36,13 @@ func TestConsentWriter(t *testing.T) {
 			expected: &openrtb2.BidRequest{User: &openrtb2.User{
 				Ext: json.RawMessage(`{"consent":"anyConsent"}`)}},
 		},
+		{
+			description: "Enabled With Request User Consent - Overwrites",
+			consent:     "anyConsent",
+			request:     &openrtb2.BidRequest{User: &openrtb2.User{Consent: "toBeOverwritten"}},
+			expected: &openrtb2.BidRequest{User: &openrtb2.User{
+				Ext: json.RawMessage(`{"consent":"anyConsent"}`)}},
+		},
 		{
 			description: "Enabled With Existing Request User Ext Object - Doesn't Overwrite",
 			consent:     "anyConsent",
@@ -0,0 +1,61 @@
+package gpp
+
+import (
+	"encoding/json"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// ConsentWriter implements the PolicyWriter interface for GPP.
+type ConsentWriter struct {
+	Consent    string
+	SectionIDs []int8
+}
+
+// Write mutates an OpenRTB bid request with the GPP string and its section IDs. An empty consent removes
+// both from the request. They are written to request.regs.ext and replace the OpenRTB 2.6 request.regs.gpp and
+// request.regs.gpp_sid.
+func (c ConsentWriter) Write(req *openrtb2.BidRequest) error {
+	if req == nil {
+		return nil
+	}
+
+	if c.Consent == "" && (req.Regs == nil || (len(req.Regs.Ext) == 0 && req.Regs.GPP == "" && len(req.Regs.GPPSID) == 0)) {
+		return nil
+	}
+
+	extMap := make(map[string]interface{})
+	if req.Regs != nil && len(req.Regs.Ext) > 0 {
+		if err := json.Unmarshal(req.Regs.Ext, &extMap); err != nil {
+			return err
+		}
+	}
+
+	if c.Consent == "" {
+		delete(extMap, "gpp")
+		delete(extMap, "gpp_sid")
+	} else {
+		extMap["gpp"] = c.Consent
+		if len(c.SectionIDs) > 0 {
+			extMap["gpp_sid"] = c.SectionIDs
+		} else {
+			delete(extMap, "gpp_sid")
+		}
+	}
+
+	var regs openrtb2.Regs
+	if req.Regs != nil {
+		regs = *req.Regs
+	}
+	regs.GPP, regs.GPPSID = "", nil
+	regs.Ext = nil
+	if len(extMap) > 0 {
+		ext, err := json.Marshal(extMap)
+		if err != nil {
+			return err
+		}
+		regs.Ext = ext
+	}
+	req.Regs = &regs
+	return nil
+}
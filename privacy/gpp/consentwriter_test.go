@@ -0,0 +1,102 @@
+package gpp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsentWriter(t *testing.T) {
+	testCases := []struct {
+		description   string
+		writer        ConsentWriter
+		request       *openrtb2.BidRequest
+		expected      *openrtb2.BidRequest
+		expectedError bool
+	}{
+		{
+			description: "Nil Request",
+			writer:      ConsentWriter{Consent: "DBABL~BVVqAAAAAg"},
+			request:     nil,
+			expected:    nil,
+		},
+		{
+			description: "Write",
+			writer:      ConsentWriter{Consent: "DBABL~BVVqAAAAAg", SectionIDs: []int8{7}},
+			request:     &openrtb2.BidRequest{},
+			expected: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"gpp":"DBABL~BVVqAAAAAg","gpp_sid":[7]}`)},
+			},
+		},
+		{
+			description: "Write Keeps Other Fields",
+			writer:      ConsentWriter{Consent: "DBABL~BVVqAAAAAg"},
+			request: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{COPPA: 1, Ext: json.RawMessage(`{"us_privacy":"1YNN","gpp_sid":[6]}`)},
+			},
+			expected: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{COPPA: 1, Ext: json.RawMessage(`{"gpp":"DBABL~BVVqAAAAAg","us_privacy":"1YNN"}`)},
+			},
+		},
+		{
+			description: "Clear",
+			writer:      ConsentWriter{},
+			request: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"us_privacy":"1YNN","gpp":"DBABL~BVVqAAAAAg","gpp_sid":[7]}`)},
+			},
+			expected: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"us_privacy":"1YNN"}`)},
+			},
+		},
+		{
+			description: "Clear Removes Empty Ext",
+			writer:      ConsentWriter{},
+			request: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{COPPA: 1, Ext: json.RawMessage(`{"gpp":"DBABL~BVVqAAAAAg"}`)},
+			},
+			expected: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{COPPA: 1},
+			},
+		},
+		{
+			description: "Clear OpenRTB 2.6",
+			writer:      ConsentWriter{},
+			request: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{COPPA: 1, GPP: "DBABL~BVVqAAAAAg", GPPSID: []int8{7}},
+			},
+			expected: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{COPPA: 1},
+			},
+		},
+		{
+			description: "Clear Without Regs",
+			writer:      ConsentWriter{},
+			request:     &openrtb2.BidRequest{},
+			expected:    &openrtb2.BidRequest{},
+		},
+		{
+			description: "Error With Regs.Ext - Does Not Mutate",
+			writer:      ConsentWriter{Consent: "DBABL~BVVqAAAAAg"},
+			request: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{Ext: json.RawMessage(`malformed}`)},
+			},
+			expectedError: true,
+			expected: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{Ext: json.RawMessage(`malformed}`)},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		err := test.writer.Write(test.request)
+
+		if test.expectedError {
+			assert.Error(t, err, test.description)
+		} else {
+			assert.NoError(t, err, test.description)
+		}
+		assert.Equal(t, test.expected, test.request, test.description)
+	}
+}
@@ -0,0 +1,332 @@
+package gpp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	gppType    = 3
+	gppVersion = 1
+)
+
+// Section IDs of the GPP sections, see: https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/blob/main/Sections/Section%20Information.md
+const (
+	SectionTCFEUV2 = 2
+	SectionUSPV1   = 6
+	SectionUSNat   = 7
+	SectionUSCA    = 8
+	SectionUSVA    = 9
+	SectionUSCO    = 10
+	SectionUSUT    = 11
+	SectionUSCT    = 12
+)
+
+// The values of the notice, opt-out and consent fields of the US sections
+const (
+	notApplicable = 0
+	yes           = 1
+	no            = 2
+)
+
+// The fields of the US sections the policy is derived from
+const (
+	fieldVersion                   = "Version"
+	fieldSaleOptOutNotice          = "SaleOptOutNotice"
+	fieldSaleOptOut                = "SaleOptOut"
+	fieldSharingOptOut             = "SharingOptOut"
+	fieldTargetedAdvertisingOptOut = "TargetedAdvertisingOptOut"
+	fieldKnownChildConsents        = "KnownChildSensitiveDataConsents"
+	fieldMSPACoveredTransaction    = "MspaCoveredTransaction"
+)
+
+// field is a field of the core segment of a section, count is its number of entries for an N-Bitfield
+type field struct {
+	name  string
+	bits  int
+	count int
+}
+
+func integer(name string, bits int) field {
+	return field{name, bits, 1}
+}
+
+func bitfield(name string, count int) field {
+	return field{name, 2, count}
+}
+
+var mspaFields = []field{
+	integer(fieldMSPACoveredTransaction, 2),
+	integer("MspaOptOutOptionMode", 2),
+	integer("MspaServiceProviderMode", 2),
+}
+
+// usSections are the layouts of the core segment of the version 1 of the US sections
+var usSections = map[int][]field{
+	SectionUSNat: append([]field{
+		integer(fieldVersion, 6),
+		integer("SharingNotice", 2),
+		integer(fieldSaleOptOutNotice, 2),
+		integer("SharingOptOutNotice", 2),
+		integer("TargetedAdvertisingOptOutNotice", 2),
+		integer("SensitiveDataProcessingOptOutNotice", 2),
+		integer("SensitiveDataLimitUseNotice", 2),
+		integer(fieldSaleOptOut, 2),
+		integer(fieldSharingOptOut, 2),
+		integer(fieldTargetedAdvertisingOptOut, 2),
+		bitfield("SensitiveDataProcessing", 12),
+		bitfield(fieldKnownChildConsents, 2),
+		integer("PersonalDataConsents", 2),
+	}, mspaFields...),
+	SectionUSCA: append([]field{
+		integer(fieldVersion, 6),
+		integer(fieldSaleOptOutNotice, 2),
+		integer("SharingOptOutNotice", 2),
+		integer("SensitiveDataLimitUseNotice", 2),
+		integer(fieldSaleOptOut, 2),
+		integer(fieldSharingOptOut, 2),
+		bitfield("SensitiveDataProcessing", 9),
+		bitfield(fieldKnownChildConsents, 2),
+		integer("PersonalDataConsents", 2),
+	}, mspaFields...),
+	SectionUSVA: append([]field{
+		integer(fieldVersion, 6),
+		integer("SharingNotice", 2),
+		integer(fieldSaleOptOutNotice, 2),
+		integer("TargetedAdvertisingOptOutNotice", 2),
+		integer(fieldSaleOptOut, 2),
+		integer(fieldTargetedAdvertisingOptOut, 2),
+		bitfield("SensitiveDataProcessing", 8),
+		integer(fieldKnownChildConsents, 2),
+	}, mspaFields...),
+	SectionUSCO: append([]field{
+		integer(fieldVersion, 6),
+		integer("SharingNotice", 2),
+		integer(fieldSaleOptOutNotice, 2),
+		integer("TargetedAdvertisingOptOutNotice", 2),
+		integer(fieldSaleOptOut, 2),
+		integer(fieldTargetedAdvertisingOptOut, 2),
+		bitfield("SensitiveDataProcessing", 7),
+		integer(fieldKnownChildConsents, 2),
+	}, mspaFields...),
+	SectionUSUT: append([]field{
+		integer(fieldVersion, 6),
+		integer("SharingNotice", 2),
+		integer(fieldSaleOptOutNotice, 2),
+		integer("TargetedAdvertisingOptOutNotice", 2),
+		integer("SensitiveDataProcessingOptOutNotice", 2),
+		integer(fieldSaleOptOut, 2),
+		integer(fieldTargetedAdvertisingOptOut, 2),
+		bitfield("SensitiveDataProcessing", 8),
+		integer(fieldKnownChildConsents, 2),
+	}, mspaFields...),
+	SectionUSCT: append([]field{
+		integer(fieldVersion, 6),
+		integer("SharingNotice", 2),
+		integer(fieldSaleOptOutNotice, 2),
+		integer("TargetedAdvertisingOptOutNotice", 2),
+		integer(fieldSaleOptOut, 2),
+		integer(fieldTargetedAdvertisingOptOut, 2),
+		bitfield("SensitiveDataProcessing", 8),
+		bitfield(fieldKnownChildConsents, 3),
+	}, mspaFields...),
+}
+
+var sectionNames = map[int]string{
+	SectionTCFEUV2: "tcfeuv2",
+	SectionUSPV1:   "uspv1",
+	SectionUSNat:   "usnat",
+	SectionUSCA:    "usca",
+	SectionUSVA:    "usva",
+	SectionUSCO:    "usco",
+	SectionUSUT:    "usut",
+	SectionUSCT:    "usct",
+}
+
+func sectionName(id int) string {
+	if name, ok := sectionNames[id]; ok {
+		return name
+	}
+	return fmt.Sprintf("section %d", id)
+}
+
+// usSection holds the values of the fields of a decoded US section
+type usSection map[string][]int
+
+func (s usSection) value(name string) int {
+	if values := s[name]; len(values) > 0 {
+		return values[0]
+	}
+	return notApplicable
+}
+
+// optedOut returns true when the user opted out of the sale or sharing of their data or of targeted
+// advertising, or when a known child did not consent to the processing of their data
+func (s usSection) optedOut() bool {
+	if s.value(fieldSaleOptOut) == yes || s.value(fieldSharingOptOut) == yes || s.value(fieldTargetedAdvertisingOptOut) == yes {
+		return true
+	}
+	for _, consent := range s[fieldKnownChildConsents] {
+		if consent == yes {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeString splits a GPP string into its sections, keyed by section ID. The sections are the encoded
+// strings following the header, in the order of the section IDs of the header.
+func decodeString(consent string) (map[int]string, error) {
+	segments := strings.Split(consent, "~")
+	ids, err := decodeHeader(segments[0], len(segments)-1)
+	if err != nil {
+		return nil, fmt.Errorf("header %v", err)
+	}
+	if len(ids) != len(segments)-1 {
+		return nil, fmt.Errorf("header declares %d sections, found %d", len(ids), len(segments)-1)
+	}
+
+	sections := make(map[int]string, len(ids))
+	for i, id := range ids {
+		sections[id] = segments[i+1]
+	}
+	return sections, nil
+}
+
+// decodeHeader returns the section IDs of the header. The ranges are expanded up to maxIDs IDs, the number of
+// sections found, as a few characters can encode ranges of millions of IDs.
+func decodeHeader(header string, maxIDs int) ([]int, error) {
+	r, err := newBitReader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	if t, err := r.readInt(6); err != nil {
+		return nil, err
+	} else if t != gppType {
+		return nil, fmt.Errorf("type must be %d", gppType)
+	}
+	if version, err := r.readInt(6); err != nil {
+		return nil, err
+	} else if version != gppVersion {
+		return nil, fmt.Errorf("version must be %d", gppVersion)
+	}
+
+	count, err := r.readInt(12)
+	if err != nil {
+		return nil, err
+	}
+	// the IDs of a range are offsets from the previous ID
+	var ids []int
+	last := 0
+	for i := 0; i < count; i++ {
+		isRange, err := r.readInt(1)
+		if err != nil {
+			return nil, err
+		}
+		offset, err := r.readFibonacci()
+		if err != nil {
+			return nil, err
+		}
+		start := last + offset
+		end := start
+		if isRange == 1 {
+			if offset, err = r.readFibonacci(); err != nil {
+				return nil, err
+			}
+			end = start + offset
+			if end < start || end-start >= maxIDs-len(ids) {
+				return nil, fmt.Errorf("declares more than %d sections", maxIDs)
+			}
+		}
+		for id := start; id <= end; id++ {
+			ids = append(ids, id)
+		}
+		last = end
+	}
+	return ids, nil
+}
+
+// decodeUSSection decodes the core segment of a US section, the optional segments following it are ignored
+func decodeUSSection(id int, section string) (usSection, error) {
+	core := strings.SplitN(section, ".", 2)[0]
+	r, err := newBitReader(core)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := make(usSection, len(usSections[id]))
+	for _, f := range usSections[id] {
+		values := make([]int, f.count)
+		for i := range values {
+			if values[i], err = r.readInt(f.bits); err != nil {
+				return nil, err
+			}
+		}
+		decoded[f.name] = values
+	}
+	if version := decoded.value(fieldVersion); version != 1 {
+		return nil, fmt.Errorf("version %d is not supported", version)
+	}
+	return decoded, nil
+}
+
+const base64URLAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+
+var errTruncated = errors.New("is truncated")
+
+// bitReader reads the fields of a segment, a base64url string of 6 bits characters without padding
+type bitReader struct {
+	bits []byte
+	pos  int
+}
+
+func newBitReader(segment string) (*bitReader, error) {
+	if segment == "" {
+		return nil, errors.New("is empty")
+	}
+	bits := make([]byte, 0, len(segment)*6)
+	for i := 0; i < len(segment); i++ {
+		value := strings.IndexByte(base64URLAlphabet, segment[i])
+		if value < 0 {
+			return nil, fmt.Errorf("has an invalid character '%c'", segment[i])
+		}
+		for shift := 5; shift >= 0; shift-- {
+			bits = append(bits, byte(value>>shift)&1)
+		}
+	}
+	return &bitReader{bits: bits}, nil
+}
+
+func (r *bitReader) readInt(bits int) (int, error) {
+	if r.pos+bits > len(r.bits) {
+		return 0, errTruncated
+	}
+	value := 0
+	for _, bit := range r.bits[r.pos : r.pos+bits] {
+		value = value<<1 | int(bit)
+	}
+	r.pos += bits
+	return value, nil
+}
+
+// readFibonacci reads a Fibonacci coded integer, terminated by two consecutive 1 bits
+func (r *bitReader) readFibonacci() (int, error) {
+	value := 0
+	term, next := 1, 2
+	var previous byte
+	for r.pos < len(r.bits) {
+		bit := r.bits[r.pos]
+		r.pos++
+		if bit == 1 && previous == 1 {
+			return value, nil
+		}
+		if bit == 1 {
+			value += term
+		}
+		term, next = next, term+next
+		previous = bit
+	}
+	return 0, errTruncated
+}
@@ -0,0 +1,128 @@
+package gpp
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/privacy/ccpa"
+)
+
+// ParsedPolicy represents parsed and validated GPP regulatory information. Use this struct
+// to make enforcement decisions.
+type ParsedPolicy struct {
+	consentSpecified bool
+	optedOut         bool
+	// usPrivacy is the uspv1 section, or the CCPA string equivalent to the US sections
+	usPrivacy string
+}
+
+// Parse returns a parsed and validated ParsedPolicy intended for use in enforcement decisions. Only the
+// sections listed by the section IDs apply when the request lists them, the US sections are enforced.
+func (p Policy) Parse() (ParsedPolicy, error) {
+	if p.Consent == "" {
+		return ParsedPolicy{}, nil
+	}
+
+	sections, err := decodeString(p.Consent)
+	if err != nil {
+		return ParsedPolicy{}, invalidConsent(err.Error())
+	}
+
+	applicable := make([]int, 0, len(sections))
+	if len(p.SectionIDs) > 0 {
+		for _, id := range p.SectionIDs {
+			if _, ok := sections[int(id)]; !ok {
+				return ParsedPolicy{}, invalidConsent(fmt.Sprintf("has no %s section listed by request.regs.ext.gpp_sid", sectionName(int(id))))
+			}
+			applicable = append(applicable, int(id))
+		}
+	} else {
+		for id := range sections {
+			applicable = append(applicable, id)
+		}
+		sort.Ints(applicable)
+	}
+
+	parsed := ParsedPolicy{consentSpecified: true}
+	// the uspv1 section is preferred to the national section, preferred to the first state section
+	fromUSPV1 := false
+	for _, id := range applicable {
+		if id == SectionUSPV1 {
+			ccpaPolicy, err := ccpa.Policy{Consent: sections[id]}.Parse(nil)
+			if err != nil {
+				return ParsedPolicy{}, invalidConsent(fmt.Sprintf("uspv1 section %s", ccpaSectionError(err)))
+			}
+			parsed.optedOut = parsed.optedOut || ccpaPolicy.ShouldEnforce("")
+			parsed.usPrivacy = sections[id]
+			fromUSPV1 = true
+			continue
+		}
+
+		if _, ok := usSections[id]; !ok {
+			continue
+		}
+		section, err := decodeUSSection(id, sections[id])
+		if err != nil {
+			return ParsedPolicy{}, invalidConsent(fmt.Sprintf("%s section %v", sectionName(id), err))
+		}
+		parsed.optedOut = parsed.optedOut || section.optedOut()
+		if !fromUSPV1 && (parsed.usPrivacy == "" || id == SectionUSNat) {
+			parsed.usPrivacy = toUSPrivacy(section)
+		}
+	}
+	return parsed, nil
+}
+
+func invalidConsent(reason string) error {
+	return &errortypes.Warning{
+		Message:     fmt.Sprintf("request.regs.ext.gpp %s", reason),
+		WarningCode: errortypes.InvalidPrivacyConsentWarningCode,
+	}
+}
+
+// ccpaSectionError removes the request.regs.ext.us_privacy prefix of the CCPA errors
+func ccpaSectionError(err error) string {
+	const prefix = "request.regs.ext.us_privacy "
+	message := err.Error()
+	if len(message) > len(prefix) && message[:len(prefix)] == prefix {
+		return message[len(prefix):]
+	}
+	return message
+}
+
+// toUSPrivacy returns the CCPA string equivalent to a US section, for the bidders reading only regs.ext.us_privacy
+func toUSPrivacy(section usSection) string {
+	optOut := byte('N')
+	if section.optedOut() {
+		optOut = 'Y'
+	}
+	return string([]byte{'1', usPrivacyFlag(section.value(fieldSaleOptOutNotice)), optOut, usPrivacyFlag(section.value(fieldMSPACoveredTransaction))})
+}
+
+func usPrivacyFlag(value int) byte {
+	switch value {
+	case yes:
+		return 'Y'
+	case no:
+		return 'N'
+	default:
+		return '-'
+	}
+}
+
+// CanEnforce returns true when a GPP string is specifically provided by the publisher.
+func (p ParsedPolicy) CanEnforce() bool {
+	return p.consentSpecified
+}
+
+// ShouldEnforce returns true when an applicable section carries an opt-out signal. The opt-out applies to
+// every bidder.
+func (p ParsedPolicy) ShouldEnforce(bidder string) bool {
+	return p.optedOut
+}
+
+// USPrivacy returns the CCPA string equivalent to the applicable US sections, empty if there is none.
+func (p ParsedPolicy) USPrivacy() string {
+	return p.usPrivacy
+}
@@ -0,0 +1,144 @@
+package gpp
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		description    string
+		policy         Policy
+		expectedPolicy ParsedPolicy
+		expectedError  string
+	}{
+		{
+			description:    "No Consent",
+			policy:         Policy{},
+			expectedPolicy: ParsedPolicy{},
+		},
+		{
+			description:    "USPV1 - No Opt Out",
+			policy:         Policy{Consent: "DBABTA~1YNN"},
+			expectedPolicy: ParsedPolicy{consentSpecified: true, usPrivacy: "1YNN"},
+		},
+		{
+			description:    "USPV1 - Opt Out",
+			policy:         Policy{Consent: "DBABTA~1YYN"},
+			expectedPolicy: ParsedPolicy{consentSpecified: true, optedOut: true, usPrivacy: "1YYN"},
+		},
+		{
+			description:    "TCF EU And USPV1",
+			policy:         Policy{Consent: "DBACNY~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~1YNN", SectionIDs: []int8{2, 6}},
+			expectedPolicy: ParsedPolicy{consentSpecified: true, usPrivacy: "1YNN"},
+		},
+		{
+			description:    "TCF EU Only",
+			policy:         Policy{Consent: "DBABM~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA"},
+			expectedPolicy: ParsedPolicy{consentSpecified: true},
+		},
+		{
+			description:    "US National - No Opt Out",
+			policy:         Policy{Consent: "DBABL~BVVqAAEABgA.QA", SectionIDs: []int8{7}},
+			expectedPolicy: ParsedPolicy{consentSpecified: true, usPrivacy: "1YNN"},
+		},
+		{
+			description:    "US National - Sale Opt Out",
+			policy:         Policy{Consent: "DBABL~BVVaAAAAAg"},
+			expectedPolicy: ParsedPolicy{consentSpecified: true, optedOut: true, usPrivacy: "1YYN"},
+		},
+		{
+			description:    "US National - Targeted Advertising Opt Out",
+			policy:         Policy{Consent: "DBABL~BVVpAAAAAg"},
+			expectedPolicy: ParsedPolicy{consentSpecified: true, optedOut: true, usPrivacy: "1YYN"},
+		},
+		{
+			description:    "US National - Known Child Without Consent",
+			policy:         Policy{Consent: "DBABL~BVVqAAAAQg"},
+			expectedPolicy: ParsedPolicy{consentSpecified: true, optedOut: true, usPrivacy: "1YYN"},
+		},
+		{
+			description:    "US Virginia - Targeted Advertising Opt Out",
+			policy:         Policy{Consent: "DBABRg~BVkAACA"},
+			expectedPolicy: ParsedPolicy{consentSpecified: true, optedOut: true, usPrivacy: "1YYN"},
+		},
+		{
+			description:    "US National And California Range - California Sharing Opt Out",
+			policy:         Policy{Consent: "DBABrw~BVVqAAAAAg~BVkAAACA"},
+			expectedPolicy: ParsedPolicy{consentSpecified: true, optedOut: true, usPrivacy: "1YNN"},
+		},
+		{
+			description:    "US National And California - Only National Applies",
+			policy:         Policy{Consent: "DBACLY~BVVqAAAAAg~BVkAAACA", SectionIDs: []int8{7}},
+			expectedPolicy: ParsedPolicy{consentSpecified: true, usPrivacy: "1YNN"},
+		},
+		{
+			description:    "USPV1 Preferred To US National",
+			policy:         Policy{Consent: "DBACTY~1YNY~BVVaAAAAAg", SectionIDs: []int8{7, 6}},
+			expectedPolicy: ParsedPolicy{consentSpecified: true, optedOut: true, usPrivacy: "1YNY"},
+		},
+		{
+			description:   "Section ID Not In String",
+			policy:        Policy{Consent: "DBABL~BVVqAAAAAg", SectionIDs: []int8{8}},
+			expectedError: "request.regs.ext.gpp has no usca section listed by request.regs.ext.gpp_sid",
+		},
+		{
+			description:   "Malformed Header",
+			policy:        Policy{Consent: "malformed"},
+			expectedError: "request.regs.ext.gpp header type must be 3",
+		},
+		{
+			description:   "Missing Section",
+			policy:        Policy{Consent: "DBABL"},
+			expectedError: "request.regs.ext.gpp header declares 1 sections, found 0",
+		},
+		{
+			description:   "Oversized Range",
+			policy:        Policy{Consent: "DBAB4AAAAG~BVVqAAAAAg"},
+			expectedError: "request.regs.ext.gpp header declares more than 1 sections",
+		},
+		{
+			description:   "Invalid Character",
+			policy:        Policy{Consent: "DBABL~BV!"},
+			expectedError: "request.regs.ext.gpp usnat section has an invalid character '!'",
+		},
+		{
+			description:   "Truncated Section",
+			policy:        Policy{Consent: "DBABL~BVV"},
+			expectedError: "request.regs.ext.gpp usnat section is truncated",
+		},
+		{
+			description:   "Unsupported Section Version",
+			policy:        Policy{Consent: "DBABL~CAAAAAAAAAA"},
+			expectedError: "request.regs.ext.gpp usnat section version 2 is not supported",
+		},
+		{
+			description:   "Invalid USPV1",
+			policy:        Policy{Consent: "DBABTA~1X"},
+			expectedError: "request.regs.ext.gpp uspv1 section must contain 4 characters",
+		},
+	}
+
+	for _, test := range testCases {
+		result, err := test.policy.Parse()
+
+		if test.expectedError == "" {
+			assert.NoError(t, err, test.description)
+		} else if assert.EqualError(t, err, test.expectedError, test.description) {
+			assert.Equal(t, errortypes.InvalidPrivacyConsentWarningCode, errortypes.ReadCode(err), test.description)
+		}
+		assert.Equal(t, test.expectedPolicy, result, test.description)
+	}
+}
+
+func TestShouldEnforce(t *testing.T) {
+	assert.False(t, ParsedPolicy{}.CanEnforce())
+	assert.False(t, ParsedPolicy{}.ShouldEnforce("anyBidder"))
+
+	policy := ParsedPolicy{consentSpecified: true, optedOut: true, usPrivacy: "1YYN"}
+	assert.True(t, policy.CanEnforce())
+	assert.True(t, policy.ShouldEnforce("anyBidder"))
+	assert.Equal(t, "1YYN", policy.USPrivacy())
+}
@@ -0,0 +1,33 @@
+package gpp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+// Policy represents the GPP (Global Privacy Platform) regulatory information from an OpenRTB bid request.
+type Policy struct {
+	Consent    string
+	SectionIDs []int8
+}
+
+// ReadFromRequest extracts the GPP regulatory information from an OpenRTB bid request, the OpenRTB 2.6
+// request.regs.gpp and request.regs.gpp_sid take precedence over their request.regs.ext equivalents.
+func ReadFromRequest(req *openrtb2.BidRequest) (Policy, error) {
+	if req != nil && req.Regs != nil && req.Regs.GPP != "" {
+		return Policy{req.Regs.GPP, req.Regs.GPPSID}, nil
+	}
+
+	if req == nil || req.Regs == nil || len(req.Regs.Ext) == 0 {
+		return Policy{}, nil
+	}
+
+	var ext openrtb_ext.ExtRegs
+	if err := json.Unmarshal(req.Regs.Ext, &ext); err != nil {
+		return Policy{}, fmt.Errorf("error reading request.regs.ext: %s", err)
+	}
+	return Policy{ext.GPP, ext.GPPSID}, nil
+}
@@ -0,0 +1,75 @@
+package gpp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadFromRequest(t *testing.T) {
+	testCases := []struct {
+		description    string
+		request        *openrtb2.BidRequest
+		expectedPolicy Policy
+		expectedError  bool
+	}{
+		{
+			description:    "Nil Request",
+			request:        nil,
+			expectedPolicy: Policy{},
+		},
+		{
+			description:    "Nil Regs",
+			request:        &openrtb2.BidRequest{},
+			expectedPolicy: Policy{},
+		},
+		{
+			description: "Success",
+			request: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"us_privacy":"1YNN","gpp":"DBABL~BVVqAAAAAg","gpp_sid":[7]}`)},
+			},
+			expectedPolicy: Policy{Consent: "DBABL~BVVqAAAAAg", SectionIDs: []int8{7}},
+		},
+		{
+			description: "OpenRTB 2.6",
+			request: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{GPP: "DBABL~BVVqAAAAAg", GPPSID: []int8{7}},
+			},
+			expectedPolicy: Policy{Consent: "DBABL~BVVqAAAAAg", SectionIDs: []int8{7}},
+		},
+		{
+			description: "OpenRTB 2.6 Preferred",
+			request: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{GPP: "DBABL~BVVqAAAAAg", GPPSID: []int8{7}, Ext: json.RawMessage(`{"gpp":"DBABTA~1YNN","gpp_sid":[6]}`)},
+			},
+			expectedPolicy: Policy{Consent: "DBABL~BVVqAAAAAg", SectionIDs: []int8{7}},
+		},
+		{
+			description: "No GPP",
+			request: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"us_privacy":"1YNN"}`)},
+			},
+			expectedPolicy: Policy{},
+		},
+		{
+			description: "Malformed Section IDs",
+			request: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"gpp":"DBABL~BVVqAAAAAg","gpp_sid":"7"}`)},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		result, err := ReadFromRequest(test.request)
+
+		if test.expectedError {
+			assert.Error(t, err, test.description)
+		} else {
+			assert.NoError(t, err, test.description)
+		}
+		assert.Equal(t, test.expectedPolicy, result, test.description)
+	}
+}
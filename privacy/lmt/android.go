@@ -0,0 +1,80 @@
+package lmt
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/buger/jsonparser"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/util/androidutil"
+)
+
+// Keys of the App Set ID in device.ext, the SDKs sending it as app_set_id
+const (
+	appSetIDKey      = "appsetid"
+	appSetIDAliasKey = "app_set_id"
+)
+
+// ModifyForAndroid normalizes the request's LMT flag, advertising ID and App Set ID based on Android version and
+// identity.
+func ModifyForAndroid(req *openrtb2.BidRequest) {
+	if !isRequestForAndroid(req) {
+		return
+	}
+
+	modifyAndroidIFA(req.Device, androidutil.DetectVersionClassification(req.Device.OSV))
+	modifyAndroidAppSetID(req.Device)
+}
+
+func isRequestForAndroid(req *openrtb2.BidRequest) bool {
+	return req != nil && req.App != nil && req.Device != nil && strings.EqualFold(req.Device.OS, "android")
+}
+
+// modifyAndroidIFA strips a zeroed advertising ID, which is what the device hands out when the user opted out
+// of personalized ads. As of Android 12 the advertising ID is also zeroed when the app lacks the AD_ID
+// permission, so a missing one limits ad tracking as well.
+func modifyAndroidIFA(device *openrtb2.Device, versionClassification androidutil.VersionClassification) {
+	if device.IFA != "" && isZeroID(device.IFA) {
+		device.IFA = ""
+		device.Lmt = &int8One
+		return
+	}
+
+	if device.IFA == "" && versionClassification == androidutil.Version12OrGreater {
+		device.Lmt = &int8One
+	}
+}
+
+// modifyAndroidAppSetID moves the App Set ID sent as device.ext.app_set_id to device.ext.appsetid and strips
+// a zeroed one.
+func modifyAndroidAppSetID(device *openrtb2.Device) {
+	if len(device.Ext) == 0 {
+		return
+	}
+
+	if appSetID, err := jsonparser.GetString(device.Ext, appSetIDKey); err == nil {
+		if isZeroID(appSetID) {
+			device.Ext = jsonparser.Delete(device.Ext, appSetIDKey)
+		}
+		return
+	}
+
+	appSetID, err := jsonparser.GetString(device.Ext, appSetIDAliasKey)
+	if err != nil {
+		return
+	}
+	device.Ext = jsonparser.Delete(device.Ext, appSetIDAliasKey)
+	if isZeroID(appSetID) {
+		return
+	}
+	if appSetIDJSON, err := json.Marshal(appSetID); err == nil {
+		if ext, err := jsonparser.Set(device.Ext, appSetIDJSON, appSetIDKey); err == nil {
+			device.Ext = ext
+		}
+	}
+}
+
+// isZeroID returns true for an empty or zeroed UUID
+func isZeroID(id string) bool {
+	return strings.Trim(id, "0-") == ""
+}
@@ -0,0 +1,218 @@
+package lmt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/util/androidutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestModifyForAndroid is a simple spot check end-to-end test for the integration of all functional components.
+func TestModifyForAndroid(t *testing.T) {
+	testCases := []struct {
+		description    string
+		givenRequest   *openrtb2.BidRequest
+		expectedDevice *openrtb2.Device
+	}{
+		{
+			description: "11 Without IFA",
+			givenRequest: &openrtb2.BidRequest{
+				App:    &openrtb2.App{},
+				Device: &openrtb2.Device{OS: "Android", OSV: "11", IFA: ""},
+			},
+			expectedDevice: &openrtb2.Device{OS: "Android", OSV: "11", IFA: ""},
+		},
+		{
+			description: "12 Without IFA",
+			givenRequest: &openrtb2.BidRequest{
+				App:    &openrtb2.App{},
+				Device: &openrtb2.Device{OS: "android", OSV: "12", IFA: ""},
+			},
+			expectedDevice: &openrtb2.Device{OS: "android", OSV: "12", IFA: "", Lmt: openrtb2.Int8Ptr(1)},
+		},
+		{
+			description: "Zeroed IFA And App Set ID",
+			givenRequest: &openrtb2.BidRequest{
+				App:    &openrtb2.App{},
+				Device: &openrtb2.Device{OS: "Android", OSV: "10", IFA: "00000000-0000-0000-0000-000000000000", Ext: json.RawMessage(`{"app_set_id":"abc-123","atts":0}`)},
+			},
+			expectedDevice: &openrtb2.Device{OS: "Android", OSV: "10", IFA: "", Lmt: openrtb2.Int8Ptr(1), Ext: json.RawMessage(`{"atts":0,"appsetid":"abc-123"}`)},
+		},
+		{
+			description: "Not Android",
+			givenRequest: &openrtb2.BidRequest{
+				App:    &openrtb2.App{},
+				Device: &openrtb2.Device{OS: "iOS", OSV: "12", IFA: "00000000-0000-0000-0000-000000000000"},
+			},
+			expectedDevice: &openrtb2.Device{OS: "iOS", OSV: "12", IFA: "00000000-0000-0000-0000-000000000000"},
+		},
+	}
+
+	for _, test := range testCases {
+		ModifyForAndroid(test.givenRequest)
+		assert.Equal(t, test.expectedDevice, test.givenRequest.Device, test.description)
+	}
+}
+
+func TestIsRequestForAndroid(t *testing.T) {
+	testCases := []struct {
+		description  string
+		givenRequest *openrtb2.BidRequest
+		expected     bool
+	}{
+		{
+			description: "Valid",
+			givenRequest: &openrtb2.BidRequest{
+				App:    &openrtb2.App{},
+				Device: &openrtb2.Device{OS: "Android"},
+			},
+			expected: true,
+		},
+		{
+			description: "Valid - OS Case Insensitive",
+			givenRequest: &openrtb2.BidRequest{
+				App:    &openrtb2.App{},
+				Device: &openrtb2.Device{OS: "ANDROID"},
+			},
+			expected: true,
+		},
+		{
+			description:  "Invalid - Nil Request",
+			givenRequest: nil,
+			expected:     false,
+		},
+		{
+			description: "Invalid - Nil App",
+			givenRequest: &openrtb2.BidRequest{
+				Site:   &openrtb2.Site{},
+				Device: &openrtb2.Device{OS: "Android"},
+			},
+			expected: false,
+		},
+		{
+			description: "Invalid - Nil Device",
+			givenRequest: &openrtb2.BidRequest{
+				App: &openrtb2.App{},
+			},
+			expected: false,
+		},
+		{
+			description: "Invalid - Wrong OS",
+			givenRequest: &openrtb2.BidRequest{
+				App:    &openrtb2.App{},
+				Device: &openrtb2.Device{OS: "iOS"},
+			},
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		result := isRequestForAndroid(test.givenRequest)
+		assert.Equal(t, test.expected, result, test.description)
+	}
+}
+
+func TestModifyAndroidIFA(t *testing.T) {
+	testCases := []struct {
+		description         string
+		givenDevice         openrtb2.Device
+		givenClassification androidutil.VersionClassification
+		expectedIFA         string
+		expectedLMT         *int8
+	}{
+		{
+			description:         "IFA Populated",
+			givenDevice:         openrtb2.Device{IFA: "any-real-value"},
+			givenClassification: androidutil.Version12OrGreater,
+			expectedIFA:         "any-real-value",
+		},
+		{
+			description:         "IFA Populated - LMT Kept",
+			givenDevice:         openrtb2.Device{IFA: "any-real-value", Lmt: openrtb2.Int8Ptr(1)},
+			givenClassification: androidutil.VersionBelow12,
+			expectedIFA:         "any-real-value",
+			expectedLMT:         openrtb2.Int8Ptr(1),
+		},
+		{
+			description:         "IFA Zero UUID",
+			givenDevice:         openrtb2.Device{IFA: "00000000-0000-0000-0000-000000000000", Lmt: openrtb2.Int8Ptr(0)},
+			givenClassification: androidutil.VersionBelow12,
+			expectedIFA:         "",
+			expectedLMT:         openrtb2.Int8Ptr(1),
+		},
+		{
+			description:         "IFA Empty - Below 12",
+			givenDevice:         openrtb2.Device{IFA: ""},
+			givenClassification: androidutil.VersionBelow12,
+			expectedIFA:         "",
+		},
+		{
+			description:         "IFA Empty - Unknown Version",
+			givenDevice:         openrtb2.Device{IFA: ""},
+			givenClassification: androidutil.VersionUnknown,
+			expectedIFA:         "",
+		},
+		{
+			description:         "IFA Empty - 12 Or Greater",
+			givenDevice:         openrtb2.Device{IFA: "", Lmt: openrtb2.Int8Ptr(0)},
+			givenClassification: androidutil.Version12OrGreater,
+			expectedIFA:         "",
+			expectedLMT:         openrtb2.Int8Ptr(1),
+		},
+	}
+
+	for _, test := range testCases {
+		modifyAndroidIFA(&test.givenDevice, test.givenClassification)
+		assert.Equal(t, test.expectedIFA, test.givenDevice.IFA, test.description)
+		assert.Equal(t, test.expectedLMT, test.givenDevice.Lmt, test.description)
+	}
+}
+
+func TestModifyAndroidAppSetID(t *testing.T) {
+	testCases := []struct {
+		description string
+		givenExt    json.RawMessage
+		expectedExt json.RawMessage
+	}{
+		{
+			description: "No Ext",
+		},
+		{
+			description: "No App Set ID",
+			givenExt:    json.RawMessage(`{"atts":0}`),
+			expectedExt: json.RawMessage(`{"atts":0}`),
+		},
+		{
+			description: "App Set ID",
+			givenExt:    json.RawMessage(`{"appsetid":"abc-123"}`),
+			expectedExt: json.RawMessage(`{"appsetid":"abc-123"}`),
+		},
+		{
+			description: "Zeroed App Set ID",
+			givenExt:    json.RawMessage(`{"appsetid":"00000000-0000-0000-0000-000000000000","atts":0}`),
+			expectedExt: json.RawMessage(`{"atts":0}`),
+		},
+		{
+			description: "Alias Moved",
+			givenExt:    json.RawMessage(`{"app_set_id":"abc-123"}`),
+			expectedExt: json.RawMessage(`{"appsetid":"abc-123"}`),
+		},
+		{
+			description: "Zeroed Alias Stripped",
+			givenExt:    json.RawMessage(`{"app_set_id":"00000000-0000-0000-0000-000000000000","atts":0}`),
+			expectedExt: json.RawMessage(`{"atts":0}`),
+		},
+	}
+
+	for _, test := range testCases {
+		device := openrtb2.Device{Ext: test.givenExt}
+		modifyAndroidAppSetID(&device)
+		if test.expectedExt == nil {
+			assert.Nil(t, device.Ext, test.description)
+		} else {
+			assert.JSONEq(t, string(test.expectedExt), string(device.Ext), test.description)
+		}
+	}
+}
@@ -3,7 +3,7 @@ package lmt
 import (
 	"strings"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/openrtb_ext"
 	"github.com/prebid/prebid-server/util/iosutil"
 )
@@ -34,6 +34,26 @@ func modifyForIOS(req *openrtb2.BidRequest, modifiers map[iosutil.VersionClassif
 	}
 }
 
+// IsTrackingDeniedForIOS returns true for the iOS app requests which cannot be attributed through the IFA, the
+// user having denied or restricted App Tracking Transparency or the IFA being zeroed.
+func IsTrackingDeniedForIOS(req *openrtb2.BidRequest) bool {
+	if !isRequestForIOS(req) {
+		return false
+	}
+	if isZeroIFA(req.Device.IFA) {
+		return true
+	}
+	atts, err := openrtb_ext.ParseDeviceExtATTS(req.Device.Ext)
+	if err != nil || atts == nil {
+		return false
+	}
+	return *atts == openrtb_ext.IOSAppTrackingStatusDenied || *atts == openrtb_ext.IOSAppTrackingStatusRestricted
+}
+
+func isZeroIFA(ifa string) bool {
+	return ifa == "" || ifa == "00000000-0000-0000-0000-000000000000"
+}
+
 func isRequestForIOS(req *openrtb2.BidRequest) bool {
 	return req != nil && req.App != nil && req.Device != nil && strings.EqualFold(req.Device.OS, "ios")
 }
@@ -41,7 +61,7 @@ func isRequestForIOS(req *openrtb2.BidRequest) bool {
 type modifier func(req *openrtb2.BidRequest)
 
 func modifyForIOS14X(req *openrtb2.BidRequest) {
-	if req.Device.IFA == "" || req.Device.IFA == "00000000-0000-0000-0000-000000000000" {
+	if isZeroIFA(req.Device.IFA) {
 		req.Device.Lmt = &int8One
 	} else {
 		req.Device.Lmt = &int8Zero
@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/util/iosutil"
 	"github.com/stretchr/testify/assert"
 )
@@ -113,6 +113,76 @@ func TestModifyForIOSHelper(t *testing.T) {
 	}
 }
 
+func TestIsTrackingDeniedForIOS(t *testing.T) {
+	testCases := []struct {
+		description  string
+		givenRequest *openrtb2.BidRequest
+		expected     bool
+	}{
+		{
+			description: "Authorized",
+			givenRequest: &openrtb2.BidRequest{
+				App:    &openrtb2.App{},
+				Device: &openrtb2.Device{OS: "iOS", IFA: "abc", Ext: json.RawMessage(`{"atts":3}`)},
+			},
+			expected: false,
+		},
+		{
+			description: "Not Determined",
+			givenRequest: &openrtb2.BidRequest{
+				App:    &openrtb2.App{},
+				Device: &openrtb2.Device{OS: "iOS", IFA: "abc", Ext: json.RawMessage(`{"atts":0}`)},
+			},
+			expected: false,
+		},
+		{
+			description: "No ATTS",
+			givenRequest: &openrtb2.BidRequest{
+				App:    &openrtb2.App{},
+				Device: &openrtb2.Device{OS: "iOS", IFA: "abc"},
+			},
+			expected: false,
+		},
+		{
+			description: "Denied",
+			givenRequest: &openrtb2.BidRequest{
+				App:    &openrtb2.App{},
+				Device: &openrtb2.Device{OS: "iOS", IFA: "abc", Ext: json.RawMessage(`{"atts":2}`)},
+			},
+			expected: true,
+		},
+		{
+			description: "Restricted",
+			givenRequest: &openrtb2.BidRequest{
+				App:    &openrtb2.App{},
+				Device: &openrtb2.Device{OS: "iOS", IFA: "abc", Ext: json.RawMessage(`{"atts":1}`)},
+			},
+			expected: true,
+		},
+		{
+			description: "Zero IFA",
+			givenRequest: &openrtb2.BidRequest{
+				App:    &openrtb2.App{},
+				Device: &openrtb2.Device{OS: "iOS", IFA: "00000000-0000-0000-0000-000000000000", Ext: json.RawMessage(`{"atts":3}`)},
+			},
+			expected: true,
+		},
+		{
+			description: "Not iOS",
+			givenRequest: &openrtb2.BidRequest{
+				App:    &openrtb2.App{},
+				Device: &openrtb2.Device{OS: "Android", Ext: json.RawMessage(`{"atts":2}`)},
+			},
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		result := IsTrackingDeniedForIOS(test.givenRequest)
+		assert.Equal(t, test.expected, result, test.description)
+	}
+}
+
 func TestIsRequestForIOS(t *testing.T) {
 	testCases := []struct {
 		description  string
@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"strings"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 )
 
 // ScrubStrategyIPV4 defines the approach to scrub PII from an IPV4 address.
@@ -135,12 +135,14 @@ func (scrubber) ScrubUser(user *openrtb2.User, strategy ScrubStrategyUser, geo S
 	case ScrubStrategyUserIDAndDemographic:
 		userCopy.BuyerUID = ""
 		userCopy.ID = ""
+		userCopy.EIDs = nil
 		userCopy.Ext = scrubUserExtIDs(userCopy.Ext)
 		userCopy.Yob = 0
 		userCopy.Gender = ""
 	case ScrubStrategyUserID:
 		userCopy.BuyerUID = ""
 		userCopy.ID = ""
+		userCopy.EIDs = nil
 		userCopy.Ext = scrubUserExtIDs(userCopy.Ext)
 	}
 
@@ -208,8 +210,14 @@ func scrubGeoPrecision(geo *openrtb2.Geo) *openrtb2.Geo {
 	}
 
 	geoCopy := *geo
-	geoCopy.Lat = float64(int(geo.Lat*100.0+0.5)) / 100.0 // Round Latitude
-	geoCopy.Lon = float64(int(geo.Lon*100.0+0.5)) / 100.0 // Round Longitude
+	if geo.Lat != nil {
+		lat := float64(int(*geo.Lat*100.0+0.5)) / 100.0 // Round Latitude
+		geoCopy.Lat = &lat
+	}
+	if geo.Lon != nil {
+		lon := float64(int(*geo.Lon*100.0+0.5)) / 100.0 // Round Longitude
+		geoCopy.Lon = &lon
+	}
 	return &geoCopy
 }
 
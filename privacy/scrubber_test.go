@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
-	"github.com/mxmCherry/openrtb/v15/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -20,8 +20,8 @@ func TestScrubDevice(t *testing.T) {
 		IP:       "1.2.3.4",
 		IPv6:     "2001:0db8:0000:0000:0000:ff00:0042:8329",
 		Geo: &openrtb2.Geo{
-			Lat:   123.456,
-			Lon:   678.89,
+			Lat:   float64Ptr(123.456),
+			Lon:   float64Ptr(678.89),
 			Metro: "some metro",
 			City:  "some city",
 			ZIP:   "some zip",
@@ -152,8 +152,8 @@ func TestScrubDevice(t *testing.T) {
 				IP:       "1.2.3.4",
 				IPv6:     "2001:0db8:0000:0000:0000:ff00:0042:8329",
 				Geo: &openrtb2.Geo{
-					Lat:   123.46,
-					Lon:   678.89,
+					Lat:   float64Ptr(123.46),
+					Lon:   float64Ptr(678.89),
 					Metro: "some metro",
 					City:  "some city",
 					ZIP:   "some zip",
@@ -202,10 +202,11 @@ func TestScrubUser(t *testing.T) {
 		BuyerUID: "anyBuyerUID",
 		Yob:      42,
 		Gender:   "anyGender",
+		EIDs:     []openrtb2.EID{{Source: "anySource"}},
 		Ext:      json.RawMessage(`{"digitrust":{"id":"anyId","keyv":4,"pref":8}}`),
 		Geo: &openrtb2.Geo{
-			Lat:   123.456,
-			Lon:   678.89,
+			Lat:   float64Ptr(123.456),
+			Lon:   float64Ptr(678.89),
 			Metro: "some metro",
 			City:  "some city",
 			ZIP:   "some zip",
@@ -240,8 +241,8 @@ func TestScrubUser(t *testing.T) {
 				Gender:   "",
 				Ext:      json.RawMessage(`{}`),
 				Geo: &openrtb2.Geo{
-					Lat:   123.46,
-					Lon:   678.89,
+					Lat:   float64Ptr(123.46),
+					Lon:   float64Ptr(678.89),
 					Metro: "some metro",
 					City:  "some city",
 					ZIP:   "some zip",
@@ -259,8 +260,8 @@ func TestScrubUser(t *testing.T) {
 				Gender:   "",
 				Ext:      json.RawMessage(`{}`),
 				Geo: &openrtb2.Geo{
-					Lat:   123.456,
-					Lon:   678.89,
+					Lat:   float64Ptr(123.456),
+					Lon:   float64Ptr(678.89),
 					Metro: "some metro",
 					City:  "some city",
 					ZIP:   "some zip",
@@ -291,8 +292,8 @@ func TestScrubUser(t *testing.T) {
 				Gender:   "anyGender",
 				Ext:      json.RawMessage(`{}`),
 				Geo: &openrtb2.Geo{
-					Lat:   123.46,
-					Lon:   678.89,
+					Lat:   float64Ptr(123.46),
+					Lon:   float64Ptr(678.89),
 					Metro: "some metro",
 					City:  "some city",
 					ZIP:   "some zip",
@@ -310,8 +311,8 @@ func TestScrubUser(t *testing.T) {
 				Gender:   "anyGender",
 				Ext:      json.RawMessage(`{}`),
 				Geo: &openrtb2.Geo{
-					Lat:   123.456,
-					Lon:   678.89,
+					Lat:   float64Ptr(123.456),
+					Lon:   float64Ptr(678.89),
 					Metro: "some metro",
 					City:  "some city",
 					ZIP:   "some zip",
@@ -327,6 +328,7 @@ func TestScrubUser(t *testing.T) {
 				BuyerUID: "anyBuyerUID",
 				Yob:      42,
 				Gender:   "anyGender",
+				EIDs:     []openrtb2.EID{{Source: "anySource"}},
 				Ext:      json.RawMessage(`{"digitrust":{"id":"anyId","keyv":4,"pref":8}}`),
 				Geo:      &openrtb2.Geo{},
 			},
@@ -340,10 +342,11 @@ func TestScrubUser(t *testing.T) {
 				BuyerUID: "anyBuyerUID",
 				Yob:      42,
 				Gender:   "anyGender",
+				EIDs:     []openrtb2.EID{{Source: "anySource"}},
 				Ext:      json.RawMessage(`{"digitrust":{"id":"anyId","keyv":4,"pref":8}}`),
 				Geo: &openrtb2.Geo{
-					Lat:   123.46,
-					Lon:   678.89,
+					Lat:   float64Ptr(123.46),
+					Lon:   float64Ptr(678.89),
 					Metro: "some metro",
 					City:  "some city",
 					ZIP:   "some zip",
@@ -359,10 +362,11 @@ func TestScrubUser(t *testing.T) {
 				BuyerUID: "anyBuyerUID",
 				Yob:      42,
 				Gender:   "anyGender",
+				EIDs:     []openrtb2.EID{{Source: "anySource"}},
 				Ext:      json.RawMessage(`{"digitrust":{"id":"anyId","keyv":4,"pref":8}}`),
 				Geo: &openrtb2.Geo{
-					Lat:   123.456,
-					Lon:   678.89,
+					Lat:   float64Ptr(123.456),
+					Lon:   float64Ptr(678.89),
 					Metro: "some metro",
 					City:  "some city",
 					ZIP:   "some zip",
@@ -504,15 +508,13 @@ func TestScrubIPV6Lowest32Bits(t *testing.T) {
 
 func TestScrubGeoFull(t *testing.T) {
 	geo := &openrtb2.Geo{
-		Lat:   123.456,
-		Lon:   678.89,
+		Lat:   float64Ptr(123.456),
+		Lon:   float64Ptr(678.89),
 		Metro: "some metro",
 		City:  "some city",
 		ZIP:   "some zip",
 	}
 	geoExpected := &openrtb2.Geo{
-		Lat:   0,
-		Lon:   0,
 		Metro: "",
 		City:  "",
 		ZIP:   "",
@@ -530,15 +532,15 @@ func TestScrubGeoFullWhenNil(t *testing.T) {
 
 func TestScrubGeoPrecision(t *testing.T) {
 	geo := &openrtb2.Geo{
-		Lat:   123.456,
-		Lon:   678.89,
+		Lat:   float64Ptr(123.456),
+		Lon:   float64Ptr(678.89),
 		Metro: "some metro",
 		City:  "some city",
 		ZIP:   "some zip",
 	}
 	geoExpected := &openrtb2.Geo{
-		Lat:   123.46,
-		Lon:   678.89,
+		Lat:   float64Ptr(123.46),
+		Lon:   float64Ptr(678.89),
 		Metro: "some metro",
 		City:  "some city",
 		ZIP:   "some zip",
@@ -642,3 +644,7 @@ func TestScrubUserExtIDs(t *testing.T) {
 		assert.Equal(t, test.expected, result, test.description)
 	}
 }
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,132 @@
+package replay
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/spf13/viper"
+)
+
+// exit codes of Run, like diff(1)
+const (
+	exitSame = iota
+	exitDifferent
+	exitError
+)
+
+const usage = `Usage: prebid-server replay -requests requests.jsonl -config pbs.yaml [flags]
+
+Replays the bid requests through the auction, the bidders answering with the recorded responses, and
+diffs the auction responses and the outbound bidder requests:
+  - with the run of another config, given by -against
+  - with the results of a previous run written by -out, given by -baseline, e.g. from another build
+
+The recorded responses are JSON lines of {"bidder": "...", "imp": "...", "status": 200, "body": {...}}.
+Bidders without a response recorded for one of the imps of the request, or for any imp, answer 204.
+
+Flags:
+`
+
+// Run is the replay subcommand. It returns 0 when the runs are the same, 1 when they differ and 2 on errors.
+func Run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+	requestsFile := flags.String("requests", "", "JSONL file of the bid requests to replay")
+	responsesFile := flags.String("responses", "", "JSONL file of the recorded bidder responses")
+	configFile := flags.String("config", "", "config file of the run")
+	againstFile := flags.String("against", "", "config file of the run to diff with")
+	baselineFile := flags.String("baseline", "", "results of a previous run to diff with")
+	outFile := flags.String("out", "", "file the results of the run are written to")
+	dirs := Directories{}
+	flags.StringVar(&dirs.BidderParams, "bidder-params", "./static/bidder-params", "directory of the bidder params schemas")
+	flags.StringVar(&dirs.BidderInfo, "bidder-info", "./static/bidder-info", "directory of the bidder infos")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if *requestsFile == "" || *configFile == "" || (*againstFile != "" && *baselineFile != "") {
+		flags.Usage()
+		return exitError
+	}
+
+	requests, err := ReadRequests(*requestsFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to read the requests: %v\n", err)
+		return exitError
+	}
+	var responses []RecordedResponse
+	if *responsesFile != "" {
+		if responses, err = ReadRecordedResponses(*responsesFile); err != nil {
+			fmt.Fprintf(stderr, "Failed to read the recorded responses: %v\n", err)
+			return exitError
+		}
+	}
+
+	standIn := NewStandIn(responses)
+	defer standIn.Close()
+
+	results, err := replayConfig(*configFile, standIn, dirs, requests)
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to replay %s: %v\n", *configFile, err)
+		return exitError
+	}
+	if *outFile != "" {
+		if err := WriteResults(*outFile, results); err != nil {
+			fmt.Fprintf(stderr, "Failed to write the results: %v\n", err)
+			return exitError
+		}
+	}
+
+	var base []Result
+	switch {
+	case *againstFile != "":
+		base = results
+		if results, err = replayConfig(*againstFile, standIn, dirs, requests); err != nil {
+			fmt.Fprintf(stderr, "Failed to replay %s: %v\n", *againstFile, err)
+			return exitError
+		}
+	case *baselineFile != "":
+		if base, err = ReadResults(*baselineFile); err != nil {
+			fmt.Fprintf(stderr, "Failed to read the baseline: %v\n", err)
+			return exitError
+		}
+	default:
+		fmt.Fprintf(stdout, "%d requests replayed\n", len(results))
+		return exitSame
+	}
+
+	differences := Diff(base, results)
+	WriteDifferences(stdout, differences, len(requests))
+	if len(differences) > 0 {
+		return exitDifferent
+	}
+	return exitSame
+}
+
+func replayConfig(filename string, standIn *StandIn, dirs Directories, requests []json.RawMessage) ([]Result, error) {
+	cfg, err := loadConfig(filename)
+	if err != nil {
+		return nil, err
+	}
+	replayer, err := NewReplayer(cfg, standIn, dirs)
+	if err != nil {
+		return nil, err
+	}
+	return replayer.Replay(requests), nil
+}
+
+func loadConfig(filename string) (*config.Configuration, error) {
+	v := viper.New()
+	config.SetupViper(v, "")
+	v.SetConfigFile(filename)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	return config.New(v)
+}
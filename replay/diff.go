@@ -0,0 +1,107 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/yudai/gojsondiff"
+)
+
+// Difference is a replayed request whose auction response or bidder calls changed between two runs
+type Difference struct {
+	// Index is the line of the request in the requests file, starting at 0
+	Index     int
+	RequestID string
+	// Report lists the changed values, or why the results could not be compared
+	Report string
+}
+
+// Diff compares the results of two runs of the same requests file, line by line
+func Diff(base, other []Result) []Difference {
+	var differences []Difference
+	for i := 0; i < len(base) || i < len(other); i++ {
+		switch {
+		case i >= len(other):
+			differences = append(differences, Difference{i, base[i].RequestID, "missing from the other run"})
+		case i >= len(base):
+			differences = append(differences, Difference{i, other[i].RequestID, "missing from the base run"})
+		case base[i].RequestID != other[i].RequestID:
+			differences = append(differences, Difference{i, base[i].RequestID, fmt.Sprintf("request %q in the other run, the runs did not replay the same requests", other[i].RequestID)})
+		default:
+			if report, modified := diffResults(base[i], other[i]); modified {
+				differences = append(differences, Difference{i, base[i].RequestID, report})
+			}
+		}
+	}
+	return differences
+}
+
+func diffResults(base, other Result) (string, bool) {
+	baseJSON, err := json.Marshal(base)
+	if err != nil {
+		return fmt.Sprintf("cannot serialize the base result: %v", err), true
+	}
+	otherJSON, err := json.Marshal(other)
+	if err != nil {
+		return fmt.Sprintf("cannot serialize the other result: %v", err), true
+	}
+
+	diff, err := gojsondiff.New().Compare(baseJSON, otherJSON)
+	if err != nil {
+		return fmt.Sprintf("json diff failed: %v", err), true
+	}
+	if !diff.Modified() {
+		return "", false
+	}
+
+	var report strings.Builder
+	writeDeltas(&report, "", diff.Deltas())
+	return report.String(), true
+}
+
+// writeDeltas prints a line per changed value: "~ path: old -> new", "+ path: new" or "- path: old"
+func writeDeltas(w io.Writer, path string, deltas []gojsondiff.Delta) {
+	for _, delta := range deltas {
+		switch d := delta.(type) {
+		case *gojsondiff.Object:
+			writeDeltas(w, joinPath(path, d.PostPosition()), d.Deltas)
+		case *gojsondiff.Array:
+			writeDeltas(w, joinPath(path, d.PostPosition()), d.Deltas)
+		case *gojsondiff.Added:
+			fmt.Fprintf(w, "+ %s: %s\n", joinPath(path, d.PostPosition()), toJSON(d.Value))
+		case *gojsondiff.Deleted:
+			fmt.Fprintf(w, "- %s: %s\n", joinPath(path, d.PrePosition()), toJSON(d.Value))
+		case *gojsondiff.Modified:
+			fmt.Fprintf(w, "~ %s: %s -> %s\n", joinPath(path, d.PostPosition()), toJSON(d.OldValue), toJSON(d.NewValue))
+		case *gojsondiff.TextDiff:
+			fmt.Fprintf(w, "~ %s: %s -> %s\n", joinPath(path, d.PostPosition()), toJSON(d.OldValue), toJSON(d.NewValue))
+		case *gojsondiff.Moved:
+			fmt.Fprintf(w, "~ %s: moved to %s\n", joinPath(path, d.PrePosition()), joinPath(path, d.PostPosition()))
+		}
+	}
+}
+
+func joinPath(path string, position gojsondiff.Position) string {
+	if path == "" {
+		return position.String()
+	}
+	return path + "." + position.String()
+}
+
+func toJSON(value interface{}) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
+
+// WriteDifferences prints a report of the differences
+func WriteDifferences(w io.Writer, differences []Difference, compared int) {
+	for _, d := range differences {
+		fmt.Fprintf(w, "=== request #%d %q\n%s", d.Index, d.RequestID, d.Report)
+	}
+	fmt.Fprintf(w, "%d of %d requests differ\n", len(differences), compared)
+}
@@ -0,0 +1,226 @@
+// Package replay runs captured bid requests through the auction endpoint against bidders answered
+// with recorded responses, and diffs the auction responses and outbound bidder requests of two runs.
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/buger/jsonparser"
+	analyticsConf "github.com/prebid/prebid-server/analytics/config"
+	"github.com/prebid/prebid-server/cache/skanidlist"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/currency"
+	"github.com/prebid/prebid-server/endpoints/openrtb2"
+	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/exchange"
+	"github.com/prebid/prebid-server/gdpr"
+	metricsConf "github.com/prebid/prebid-server/metrics/config"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	pbc "github.com/prebid/prebid-server/prebid_cache_client"
+	"github.com/prebid/prebid-server/stored_requests/backends/empty_fetcher"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// maxLineSize is the size of the longest line of the JSONL files
+const maxLineSize = 10 * 1024 * 1024
+
+// standInHost replaces the host of the StandIn in the auction responses, its port changing on every run
+const standInHost = "standin"
+
+// Result is the outcome of one replayed bid request
+type Result struct {
+	RequestID   string          `json:"request_id"`
+	Status      int             `json:"status"`
+	Response    json.RawMessage `json:"response,omitempty"`
+	BidderCalls []BidderCall    `json:"bidder_calls"`
+}
+
+// Replayer runs bid requests through the /openrtb2/auction endpoint of a config. The bidders, the
+// prebid cache and the GDPR vendor lists are all served by the StandIn: a replay makes no network
+// call. Stored requests are not supported, the requests must be complete.
+type Replayer struct {
+	standIn *StandIn
+	handler httprouter.Handle
+}
+
+// Directories holds the static files of the server, relative to the working directory
+type Directories struct {
+	BidderParams string
+	BidderInfo   string
+}
+
+func NewReplayer(cfg *config.Configuration, standIn *StandIn, dirs Directories) (*Replayer, error) {
+	standIn.RewriteEndpoints(cfg.Adapters)
+	standInURL, _ := url.Parse(standIn.URL())
+	cfg.CacheURL.Scheme = standInURL.Scheme
+	cfg.CacheURL.Host = standInURL.Host
+
+	client := standIn.Client()
+	metricsEngine := &metricsConf.DummyMetricsEngine{}
+
+	paramsValidator, err := openrtb_ext.NewBidderParamsValidator(dirs.BidderParams)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create the bidder params validator. %v", err)
+	}
+	paramsValidator = openrtb_ext.NewSeatParamsValidator(paramsValidator, config.AdapterSeats(cfg.Adapters))
+
+	infoDirectory, _ := filepath.Abs(dirs.BidderInfo)
+	bidderInfos, err := config.LoadBidderInfoFromDisk(infoDirectory, cfg.Adapters, openrtb_ext.BuildBidderStringSlice())
+	if err != nil {
+		return nil, err
+	}
+	skanidlist.Init(bidderInfos)
+
+	adapters, adaptersErrs := exchange.BuildAdapters(client, cfg, bidderInfos, metricsEngine)
+	if len(adaptersErrs) > 0 {
+		return nil, errortypes.NewAggregateError("Failed to initialize adapters", adaptersErrs)
+	}
+
+	gdprPerms := gdpr.NewPermissions(context.Background(), cfg.GDPR, bidderInfos.ToGVLVendorIDMap(), client)
+	currencyConverter := currency.NewRateConverter(client, "", time.Duration(cfg.CurrencyConverter.StaleRatesSeconds)*time.Second)
+	cacheClient := pbc.NewClient(client, &cfg.CacheURL, &cfg.ExtCacheURL, metricsEngine)
+	theExchange := exchange.NewExchange(adapters, cacheClient, cfg, metricsEngine, bidderInfos, gdprPerms, currencyConverter, empty_fetcher.EmptyFetcher{}, nil)
+
+	handler, err := openrtb2.NewEndpoint(
+		theExchange,
+		paramsValidator,
+		empty_fetcher.EmptyFetcher{},
+		empty_fetcher.EmptyFetcher{},
+		cfg,
+		metricsEngine,
+		analyticsConf.NewPBSAnalytics(&config.Analytics{}),
+		exchange.GetDisabledBiddersErrorMessages(bidderInfos),
+		nil,
+		exchange.GetActiveBidders(bidderInfos))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Replayer{
+		standIn: standIn,
+		handler: handler,
+	}, nil
+}
+
+// Replay runs the bid requests one at a time
+func (r *Replayer) Replay(requests []json.RawMessage) []Result {
+	results := make([]Result, 0, len(requests))
+	for _, request := range requests {
+		results = append(results, r.replay(request))
+	}
+	return results
+}
+
+func (r *Replayer) replay(request json.RawMessage) Result {
+	requestID, _ := jsonparser.GetString(request, "id")
+
+	httpRequest := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", bytes.NewReader(request))
+	recorder := httptest.NewRecorder()
+	r.handler(recorder, httpRequest, nil)
+
+	calls := r.standIn.TakeCalls()
+	// the exchange generates a source.tid for the requests without one
+	if _, _, _, err := jsonparser.Get(request, "source", "tid"); err != nil {
+		for i := range calls {
+			calls[i].Body = jsonparser.Delete(calls[i].Body, "source", "tid")
+		}
+	}
+
+	return Result{
+		RequestID:   requestID,
+		Status:      recorder.Code,
+		Response:    r.normalize(recorder.Body.Bytes()),
+		BidderCalls: calls,
+	}
+}
+
+// normalize removes what changes from a run to the next from the auction response
+func (r *Replayer) normalize(response []byte) json.RawMessage {
+	standInURL, _ := url.Parse(r.standIn.URL())
+	response = bytes.ReplaceAll(response, []byte(standInURL.Host), []byte(standInHost))
+	response = jsonparser.Delete(response, "ext", "responsetimemillis")
+	response = jsonparser.Delete(response, "ext", "prebid", "auctiontimestamp")
+	return rawJSON(bytes.TrimSpace(response))
+}
+
+// ReadRequests reads a JSONL file of bid requests
+func ReadRequests(filename string) ([]json.RawMessage, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var requests []json.RawMessage
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		request := bytes.TrimSpace(scanner.Bytes())
+		if len(request) == 0 {
+			continue
+		}
+		if !json.Valid(request) {
+			return nil, fmt.Errorf("%s:%d: invalid JSON", filename, line)
+		}
+		requests = append(requests, append(json.RawMessage(nil), request...))
+	}
+	return requests, scanner.Err()
+}
+
+// ReadResults reads the results of a previous replay, as written by WriteResults
+func ReadResults(filename string) ([]Result, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var results []Result
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var result Result
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", filename, line, err)
+		}
+		results = append(results, result)
+	}
+	return results, scanner.Err()
+}
+
+// WriteResults writes the results as JSONL
+func WriteResults(filename string, results []Result) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(file)
+	encoder := json.NewEncoder(w)
+	for _, result := range results {
+		if err := encoder.Encode(result); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
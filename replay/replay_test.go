@@ -0,0 +1,217 @@
+package replay
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/stretchr/testify/assert"
+)
+
+const liftoffRequest = `{"id":"req-1","imp":[{"id":"imp-1","video":{"mimes":["video/mp4"],"w":480,"h":320},"ext":{"liftoff":{}}}],` +
+	`"app":{"bundle":"com.example.app"},"device":{"ifa":"f29ec2bf-48e2-40e1-8d04-b3ebdf99b2cc","os":"android"},"tmax":500}`
+
+const liftoffResponse = `{"bidder":"liftoff","imp":"imp-1","body":{"id":"req-1","cur":"USD","seatbid":[{"seat":"liftoff",` +
+	`"bid":[{"id":"bid-1","impid":"imp-1","price":1.5,"adm":"<VAST version=\"3.0\"></VAST>","crid":"crid-1","w":480,"h":320}]}]}}`
+
+func TestRunAgainstConfig(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"requests.jsonl":  liftoffRequest + "\n",
+		"responses.jsonl": liftoffResponse + "\n",
+		"a.yaml":          "adapters:\n  liftoff:\n    disabled: false\n",
+		"b.yaml":          "adapters:\n  liftoff:\n    disabled: false\n    endpoint: https://other.liftoff.io/v2/bid\n",
+	})
+	defer os.RemoveAll(dir)
+
+	var stdout, stderr bytes.Buffer
+	code := Run(append(testArgs(dir), "-config", filepath.Join(dir, "a.yaml"), "-against", filepath.Join(dir, "b.yaml"), "-out", filepath.Join(dir, "a.jsonl")), &stdout, &stderr)
+	assert.Equal(t, exitDifferent, code, stderr.String())
+	assert.Equal(t, "=== request #0 \"req-1\"\n~ bidder_calls.0.uri: \"/tapjoy/bid\" -> \"/v2/bid\"\n1 of 1 requests differ\n", stdout.String())
+
+	results, err := ReadResults(filepath.Join(dir, "a.jsonl"))
+	if assert.NoError(t, err) && assert.Len(t, results, 1) {
+		assert.Equal(t, "req-1", results[0].RequestID)
+		assert.Equal(t, 200, results[0].Status)
+		assert.Contains(t, string(results[0].Response), `"price":1.5`)
+		if assert.Len(t, results[0].BidderCalls, 1) {
+			assert.Equal(t, "liftoff", results[0].BidderCalls[0].Bidder)
+			assert.Equal(t, "POST", results[0].BidderCalls[0].Method)
+			assert.Contains(t, string(results[0].BidderCalls[0].Body), `"bundle":"com.example.app"`)
+		}
+	}
+
+	stdout.Reset()
+	code = Run(append(testArgs(dir), "-config", filepath.Join(dir, "a.yaml"), "-baseline", filepath.Join(dir, "a.jsonl")), &stdout, &stderr)
+	assert.Equal(t, exitSame, code, stderr.String())
+	assert.Equal(t, "0 of 1 requests differ\n", stdout.String(), "replays are deterministic")
+}
+
+func TestRunErrors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"requests.jsonl": "{\n",
+		"a.yaml":         "adapters: {}\n",
+	})
+	defer os.RemoveAll(dir)
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitError, Run([]string{"-config", filepath.Join(dir, "a.yaml")}, &stdout, &stderr), "requests are required")
+	assert.Equal(t, exitError, Run([]string{"-requests", filepath.Join(dir, "requests.jsonl"), "-config", filepath.Join(dir, "a.yaml")}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "requests.jsonl:1: invalid JSON")
+}
+
+func TestStandInRewrite(t *testing.T) {
+	standIn := NewStandIn(nil)
+	defer standIn.Close()
+
+	adapters := map[string]config.Adapter{
+		"rubicon": {
+			Endpoint: "http://exapi-us-east.rubiconproject.com/a/api/exchange.json?tk_sdc=us-east",
+			XAPI:     config.AdapterXAPI{EndpointEU: "https://exapi-eu.rubiconproject.com/a/api/exchange.json"},
+			Regions:  map[string]config.AdapterRegion{"APAC": {Endpoint: "https://{{.Host}}.apac.com", Countries: []string{"JPN"}}},
+		},
+	}
+	standIn.RewriteEndpoints(adapters)
+
+	assert.Equal(t, standIn.URL()+"/rubicon/a/api/exchange.json?tk_sdc=us-east", adapters["rubicon"].Endpoint)
+	assert.Equal(t, standIn.URL()+"/rubicon@eu/a/api/exchange.json", adapters["rubicon"].XAPI.EndpointEU)
+	assert.Equal(t, config.AdapterRegion{Endpoint: standIn.URL() + "/rubicon@apac", Countries: []string{"JPN"}}, adapters["rubicon"].Regions["APAC"])
+}
+
+func TestStandInResponses(t *testing.T) {
+	standIn := NewStandIn([]RecordedResponse{
+		{Bidder: "appnexus", Imp: "imp-2", Body: json.RawMessage(`{"id":"imp-2"}`)},
+		{Bidder: "appnexus", Body: json.RawMessage(`{"id":"any"}`)},
+		{Bidder: "rubicon", Status: 500},
+	})
+	defer standIn.Close()
+	client := standIn.Client()
+
+	testCases := []struct {
+		description    string
+		path           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			description:    "response recorded for the imp",
+			path:           "/appnexus/bid",
+			body:           `{"imp":[{"id":"imp-1"},{"id":"imp-2"}]}`,
+			expectedStatus: 200,
+			expectedBody:   `{"id":"imp-2"}`,
+		},
+		{
+			description:    "response recorded for any imp",
+			path:           "/appnexus@eu/bid",
+			body:           `{"imp":[{"id":"imp-3"}]}`,
+			expectedStatus: 200,
+			expectedBody:   `{"id":"any"}`,
+		},
+		{
+			description:    "recorded status",
+			path:           "/rubicon",
+			body:           `not json`,
+			expectedStatus: 500,
+		},
+		{
+			description:    "no response recorded",
+			path:           "/pubmatic/bid?source=pbs",
+			expectedStatus: 204,
+		},
+	}
+
+	for _, test := range testCases {
+		resp, err := client.Post(standIn.URL()+test.path, "application/json", strings.NewReader(test.body))
+		if !assert.NoError(t, err, test.description) {
+			continue
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, test.expectedStatus, resp.StatusCode, test.description)
+		assert.Equal(t, test.expectedBody, string(body), test.description)
+	}
+
+	assert.Equal(t, []BidderCall{
+		{Bidder: "appnexus", Method: "POST", Uri: "/bid", Headers: map[string][]string{"Content-Type": {"application/json"}}, Body: json.RawMessage(`{"imp":[{"id":"imp-1"},{"id":"imp-2"}]}`)},
+		{Bidder: "appnexus", Region: "eu", Method: "POST", Uri: "/bid", Headers: map[string][]string{"Content-Type": {"application/json"}}, Body: json.RawMessage(`{"imp":[{"id":"imp-3"}]}`)},
+		{Bidder: "pubmatic", Method: "POST", Uri: "/bid?source=pbs", Headers: map[string][]string{"Content-Type": {"application/json"}}},
+		{Bidder: "rubicon", Method: "POST", Uri: "/", Headers: map[string][]string{"Content-Type": {"application/json"}}, Body: json.RawMessage(`"not json"`)},
+	}, standIn.TakeCalls())
+	assert.Empty(t, standIn.TakeCalls())
+
+	_, err := client.Get("https://example.com/vendor-list.json")
+	assert.Error(t, err, "no network call")
+}
+
+func TestStandInGzipRequest(t *testing.T) {
+	standIn := NewStandIn([]RecordedResponse{{Bidder: "liftoff", Imp: "imp-1", Body: json.RawMessage(`{"id":"imp-1"}`)}})
+	defer standIn.Close()
+
+	var body bytes.Buffer
+	writer := gzip.NewWriter(&body)
+	writer.Write([]byte(`{"imp":[{"id":"imp-1"}]}`))
+	writer.Close()
+	req, _ := http.NewRequest("POST", standIn.URL()+"/liftoff/bid", &body)
+	req.Header.Set("Content-Encoding", "gzip")
+
+	resp, err := standIn.Client().Do(req)
+	if assert.NoError(t, err) {
+		responseBody, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, `{"id":"imp-1"}`, string(responseBody), "the response is found from the decoded body")
+	}
+	assert.Equal(t, []BidderCall{
+		{Bidder: "liftoff", Method: "POST", Uri: "/bid", Headers: map[string][]string{"Content-Encoding": {"gzip"}}, Body: json.RawMessage(`{"imp":[{"id":"imp-1"}]}`)},
+	}, standIn.TakeCalls())
+}
+
+func TestDiff(t *testing.T) {
+	base := []Result{
+		{RequestID: "1", Status: 200, Response: json.RawMessage(`{"id":"1","cur":"USD"}`)},
+		{RequestID: "2", Status: 200, BidderCalls: []BidderCall{{Bidder: "appnexus", Uri: "/bid"}}},
+		{RequestID: "3", Status: 204},
+	}
+	other := []Result{
+		{RequestID: "1", Status: 200, Response: json.RawMessage(`{"id":"1","cur":"EUR","ext":{}}`)},
+		{RequestID: "2", Status: 200, BidderCalls: []BidderCall{{Bidder: "appnexus", Uri: "/bid"}}},
+		{RequestID: "4", Status: 204},
+		{RequestID: "5", Status: 204},
+	}
+
+	differences := Diff(base, other)
+	assert.Equal(t, []Difference{
+		{0, "1", "~ response.cur: \"USD\" -> \"EUR\"\n+ response.ext: {}\n"},
+		{2, "3", "request \"4\" in the other run, the runs did not replay the same requests"},
+		{3, "5", "missing from the base run"},
+	}, differences)
+}
+
+func testArgs(dir string) []string {
+	return []string{
+		"-requests", filepath.Join(dir, "requests.jsonl"),
+		"-responses", filepath.Join(dir, "responses.jsonl"),
+		"-bidder-params", "../static/bidder-params",
+		"-bidder-info", "../static/bidder-info",
+	}
+}
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
@@ -0,0 +1,276 @@
+package replay
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/prebid/prebid-server/config"
+)
+
+// RecordedResponse is a bidder response answered by the StandIn, read from one line of the responses file
+type RecordedResponse struct {
+	Bidder string `json:"bidder"`
+	// Imp is the id of the imp the response was recorded for. An empty Imp answers any request of the bidder.
+	Imp string `json:"imp,omitempty"`
+	// Status defaults to 200, or 204 when there is no body
+	Status int             `json:"status,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// BidderCall is an outbound bidder request received by the StandIn
+type BidderCall struct {
+	Bidder  string              `json:"bidder"`
+	Region  string              `json:"region,omitempty"`
+	Method  string              `json:"method"`
+	Uri     string              `json:"uri"`
+	Headers map[string][]string `json:"headers,omitempty"`
+	Body    json.RawMessage     `json:"body,omitempty"`
+}
+
+type responseKey struct {
+	bidder string
+	imp    string
+}
+
+// headers which depend on the transport rather than on the adapter
+var ignoredHeaders = map[string]bool{
+	"Accept-Encoding": true,
+	"Content-Length":  true,
+	"User-Agent":      true,
+}
+
+// StandIn is an in-process HTTP server standing in for the bidders of a replay. Every adapter endpoint
+// of the replayed configs is rewritten to http://<standin>/<bidder>[@<region>]/<original path>.
+type StandIn struct {
+	server    *httptest.Server
+	responses map[responseKey]RecordedResponse
+
+	mux   sync.Mutex
+	calls []BidderCall
+}
+
+func NewStandIn(responses []RecordedResponse) *StandIn {
+	s := &StandIn{
+		responses: make(map[responseKey]RecordedResponse, len(responses)),
+	}
+	for _, response := range responses {
+		s.responses[responseKey{strings.ToLower(response.Bidder), response.Imp}] = response
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// ReadRecordedResponses reads a JSONL file of RecordedResponse
+func ReadRecordedResponses(filename string) ([]RecordedResponse, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var responses []RecordedResponse
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var response RecordedResponse
+		if err := json.Unmarshal(scanner.Bytes(), &response); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", filename, line, err)
+		}
+		if response.Bidder == "" {
+			return nil, fmt.Errorf("%s:%d: the bidder is required", filename, line)
+		}
+		responses = append(responses, response)
+	}
+	return responses, scanner.Err()
+}
+
+func (s *StandIn) URL() string {
+	return s.server.URL
+}
+
+func (s *StandIn) Close() {
+	s.server.Close()
+}
+
+// RewriteEndpoints points every endpoint of the adapters to the StandIn
+func (s *StandIn) RewriteEndpoints(adapters map[string]config.Adapter) {
+	for name, adapter := range adapters {
+		adapter.Endpoint = s.rewrite(adapter.Endpoint, name, "")
+		adapter.XAPI.EndpointUSEast = s.rewrite(adapter.XAPI.EndpointUSEast, name, config.RegionUSEast)
+		adapter.XAPI.EndpointUSWest = s.rewrite(adapter.XAPI.EndpointUSWest, name, config.RegionUSWest)
+		adapter.XAPI.EndpointEU = s.rewrite(adapter.XAPI.EndpointEU, name, config.RegionEU)
+		adapter.XAPI.EndpointAPAC = s.rewrite(adapter.XAPI.EndpointAPAC, name, config.RegionAPAC)
+		adapter.XAPI.EndpointJP = s.rewrite(adapter.XAPI.EndpointJP, name, config.RegionJP)
+		adapter.XAPI.EndpointSG = s.rewrite(adapter.XAPI.EndpointSG, name, config.RegionSG)
+
+		regions := make(map[string]config.AdapterRegion, len(adapter.Regions))
+		for region, regionCfg := range adapter.Regions {
+			regionCfg.Endpoint = s.rewrite(regionCfg.Endpoint, name, strings.ToLower(region))
+			regions[region] = regionCfg
+		}
+		adapter.Regions = regions
+
+		adapters[name] = adapter
+	}
+}
+
+// rewrite keeps the path and query of the endpoint, which may hold template macros
+func (s *StandIn) rewrite(endpoint, bidder, region string) string {
+	if endpoint == "" {
+		return ""
+	}
+	prefix := s.server.URL + "/" + bidder
+	if region != "" {
+		prefix += "@" + region
+	}
+
+	rest := endpoint
+	if i := strings.Index(rest, "://"); i >= 0 {
+		rest = rest[i+len("://"):]
+	}
+	if i := strings.IndexAny(rest, "/?"); i >= 0 {
+		return prefix + rest[i:]
+	}
+	return prefix
+}
+
+// Client returns an http client failing the requests to any other host than the StandIn
+func (s *StandIn) Client() *http.Client {
+	return &http.Client{Transport: standInOnly{s.server.Listener.Addr().String()}}
+}
+
+type standInOnly struct {
+	host string
+}
+
+func (t standInOnly) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != t.host {
+		return nil, fmt.Errorf("a replay makes no network call, %s is not served by the stand-in", req.URL.Host)
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// TakeCalls returns the bidder calls received since the last call, in a stable order
+func (s *StandIn) TakeCalls() []BidderCall {
+	s.mux.Lock()
+	calls := s.calls
+	s.calls = nil
+	s.mux.Unlock()
+
+	sort.SliceStable(calls, func(i, j int) bool {
+		if calls[i].Bidder != calls[j].Bidder {
+			return calls[i].Bidder < calls[j].Bidder
+		}
+		if calls[i].Uri != calls[j].Uri {
+			return calls[i].Uri < calls[j].Uri
+		}
+		return string(calls[i].Body) < string(calls[j].Body)
+	})
+	return calls
+}
+
+func (s *StandIn) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := readBody(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	segments := strings.SplitN(strings.TrimPrefix(r.URL.RequestURI(), "/"), "/", 2)
+	call := BidderCall{
+		Method:  r.Method,
+		Uri:     "/",
+		Headers: make(map[string][]string, len(r.Header)),
+		Body:    rawJSON(body),
+	}
+	call.Bidder = segments[0]
+	if i := strings.Index(call.Bidder, "@"); i >= 0 {
+		call.Bidder, call.Region = call.Bidder[:i], call.Bidder[i+1:]
+	}
+	if len(segments) > 1 {
+		call.Uri += segments[1]
+	}
+	for name, values := range r.Header {
+		if !ignoredHeaders[name] {
+			call.Headers[name] = values
+		}
+	}
+
+	s.mux.Lock()
+	s.calls = append(s.calls, call)
+	s.mux.Unlock()
+
+	response, ok := s.findResponse(strings.ToLower(call.Bidder), body)
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	status := response.Status
+	if status == 0 {
+		status = http.StatusOK
+		if len(response.Body) == 0 {
+			status = http.StatusNoContent
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(response.Body)
+}
+
+// readBody reads the body of a request, decoding the gzip bodies of the bidders accepting them so that the
+// calls compare across configs
+func readBody(r *http.Request) ([]byte, error) {
+	var reader io.Reader = r.Body
+	if strings.EqualFold(r.Header.Get("Content-Encoding"), "gzip") {
+		gzipReader, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+	return ioutil.ReadAll(reader)
+}
+
+// findResponse looks for a response recorded for one of the imps of the request, then for any imp
+func (s *StandIn) findResponse(bidder string, body []byte) (RecordedResponse, bool) {
+	var request struct {
+		Imp []struct {
+			ID string `json:"id"`
+		} `json:"imp"`
+	}
+	if err := json.Unmarshal(body, &request); err == nil {
+		for _, imp := range request.Imp {
+			if response, ok := s.responses[responseKey{bidder, imp.ID}]; ok {
+				return response, true
+			}
+		}
+	}
+	response, ok := s.responses[responseKey{bidder, ""}]
+	return response, ok
+}
+
+// rawJSON keeps a JSON body as is, and quotes anything else
+func rawJSON(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	if json.Valid(body) {
+		return body
+	}
+	quoted, _ := json.Marshal(string(body))
+	return quoted
+}
@@ -5,11 +5,12 @@ import (
 	"net/http/pprof"
 	"time"
 
+	"github.com/prebid/prebid-server/biddercontrol"
 	"github.com/prebid/prebid-server/currency"
 	"github.com/prebid/prebid-server/endpoints"
 )
 
-func Admin(revision string, rateConverter *currency.RateConverter, rateConverterFetchingInterval time.Duration) *http.ServeMux {
+func Admin(revision string, rateConverter *currency.RateConverter, rateConverterFetchingInterval time.Duration, bidderControls *biddercontrol.Controls) *http.ServeMux {
 	// Add endpoints to the admin server
 	// Making sure to add pprof routes
 	mux := http.NewServeMux()
@@ -22,5 +23,7 @@ func Admin(revision string, rateConverter *currency.RateConverter, rateConverter
 	// Register prebid-server defined admin handlers
 	mux.HandleFunc("/currency/rates", endpoints.NewCurrencyRatesEndpoint(rateConverter, rateConverterFetchingInterval))
 	mux.HandleFunc("/version", endpoints.NewVersionEndpoint(revision))
+	mux.HandleFunc("/bidders/status", endpoints.NewBidderStatusEndpoint(bidderControls))
+	mux.HandleFunc("/bidders/rules", endpoints.NewBidderRulesEndpoint(bidderControls))
 	return mux
 }
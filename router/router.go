@@ -36,14 +36,15 @@ import (
 	"github.com/prebid/prebid-server/adapters/pubmatic"
 	"github.com/prebid/prebid-server/adapters/pulsepoint"
 	"github.com/prebid/prebid-server/adapters/rubicon"
-	"github.com/prebid/prebid-server/adapters/rubiconmraid"
 	"github.com/prebid/prebid-server/adapters/sovrn"
 	"github.com/prebid/prebid-server/adapters/taurusx"
 	analyticsConf "github.com/prebid/prebid-server/analytics/config"
+	"github.com/prebid/prebid-server/biddercontrol"
 	"github.com/prebid/prebid-server/cache"
 	"github.com/prebid/prebid-server/cache/dummycache"
 	"github.com/prebid/prebid-server/cache/filecache"
 	"github.com/prebid/prebid-server/cache/postgrescache"
+	"github.com/prebid/prebid-server/cache/skanidlist"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/endpoints"
 	infoEndpoints "github.com/prebid/prebid-server/endpoints/info"
@@ -58,6 +59,7 @@ import (
 	"github.com/prebid/prebid-server/server/ssl"
 	storedRequestsConf "github.com/prebid/prebid-server/stored_requests/config"
 	"github.com/prebid/prebid-server/usersync/usersyncers"
+	"github.com/prebid/prebid-server/util/task"
 
 	"github.com/golang/glog"
 	"github.com/julienschmidt/httprouter"
@@ -203,7 +205,8 @@ func newExchangeMap(cfg *config.Configuration) map[string]adapters.Adapter {
 			cfg.Adapters[string(openrtb_ext.BidderRubicon)].XAPI.EndpointUSWest,
 			cfg.Adapters[string(openrtb_ext.BidderRubicon)].XAPI.EndpointEU,
 			cfg.Adapters[string(openrtb_ext.BidderRubicon)].XAPI.EndpointAPAC),
-		"rubiconmraid": rubiconmraid.NewRubiconLegacyAdapter(
+		"rubiconmraid": rubicon.NewRubiconLegacySeatAdapter(
+			string(openrtb_ext.BidderRubiconMRAID),
 			adapters.DefaultHTTPAdapterConfig,
 			cfg.Adapters[string(openrtb_ext.BidderRubiconMRAID)].Endpoint,
 			cfg.Adapters[string(openrtb_ext.BidderRubicon)].XAPI.Username,
@@ -229,7 +232,9 @@ type Router struct {
 	*nrhttprouter.Router
 	MetricsEngine   *metricsConf.DetailedMetricsEngine
 	ParamsValidator openrtb_ext.BidderParamValidator
-	Shutdown        func()
+	// BidderControls holds the bidder rules set through the admin endpoints
+	BidderControls *biddercontrol.Controls
+	Shutdown       func()
 }
 
 func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *Router, err error) {
@@ -279,6 +284,11 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 	// Hack because of how legacy handles districtm
 	legacyBidderList := openrtb_ext.CoreBidderNames()
 	legacyBidderList = append(legacyBidderList, openrtb_ext.BidderName("districtm"))
+	// the seats set in the adapter config have their metrics under their own bidder code
+	adapterSeats := config.AdapterSeats(cfg.Adapters)
+	for seat := range adapterSeats {
+		legacyBidderList = append(legacyBidderList, seat)
+	}
 
 	// Metrics engine
 	r.MetricsEngine = metricsConf.NewMetricsEngine(cfg, legacyBidderList)
@@ -295,6 +305,7 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 	if err != nil {
 		glog.Fatalf("Failed to create the bidder params validator. %v", err)
 	}
+	paramsValidator = openrtb_ext.NewSeatParamsValidator(paramsValidator, adapterSeats)
 
 	p, _ := filepath.Abs(infoDirectory)
 	bidderInfos, err := config.LoadBidderInfoFromDisk(p, cfg.Adapters, openrtb_ext.BuildBidderStringSlice())
@@ -302,6 +313,18 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 		glog.Fatal(err)
 	}
 
+	skanidlist.Init(bidderInfos)
+	skanIDListRefresher := skanidlist.NewRefresher(generalHttpClient, cfg.SKANIDList, r.MetricsEngine)
+	skanIDListRefresher.Restore()
+	skanIDListTickerTask := task.NewTickerTask(time.Duration(cfg.SKANIDList.FetchIntervalSeconds)*time.Second, skanIDListRefresher)
+	// the first fetch doesn't hold the startup, the static and restored lists are served until it completes
+	go skanIDListTickerTask.Start()
+	r.Shutdown = func() {
+		skanIDListTickerTask.Stop()
+		shutdown()
+		r.MetricsEngine.Shutdown()
+	}
+
 	activeBidders := exchange.GetActiveBidders(bidderInfos)
 	disabledBidders := exchange.GetDisabledBiddersErrorMessages(bidderInfos)
 
@@ -323,7 +346,9 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 		glog.Fatalf("%v", errs)
 	}
 
-	theExchange := exchange.NewExchange(adapters, cacheClient, cfg, r.MetricsEngine, bidderInfos, gdprPerms, rateConvertor, categoriesFetcher)
+	r.BidderControls = biddercontrol.NewControls(bidderInfos)
+
+	theExchange := exchange.NewExchange(adapters, cacheClient, cfg, r.MetricsEngine, bidderInfos, gdprPerms, rateConvertor, categoriesFetcher, r.BidderControls)
 
 	openrtbEndpoint, err := openrtb2.NewEndpoint(theExchange, paramsValidator, fetcher, accounts, cfg, r.MetricsEngine, pbsAnalytics, disabledBidders, defReqJSON, activeBidders)
 	if err != nil {
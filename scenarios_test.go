@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/currency"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/prebid/prebid-server/replay"
+	"github.com/prebid/prebid-server/router"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+const scenariosDirectory = "testdata/scenarios"
+
+// scenario is the scenario.json file of a directory of testdata/scenarios. The file paths are
+// relative to the scenario directory.
+type scenario struct {
+	Description string `json:"description"`
+	Request     string `json:"request"`
+	// Bidders maps the bidders enabled for the scenario to their canned response
+	Bidders  map[string]scenarioBidder `json:"bidders"`
+	Expected scenarioExpectation       `json:"expected"`
+}
+
+type scenarioBidder struct {
+	Response string `json:"response"`
+	// Status defaults to 200
+	Status int `json:"status,omitempty"`
+}
+
+type scenarioExpectation struct {
+	// Status defaults to 200
+	Status int `json:"status,omitempty"`
+	// Winner is the seat of the highest bid of the auction response, empty when no bidder fills
+	Winner string  `json:"winner,omitempty"`
+	Price  float64 `json:"price,omitempty"`
+	// Targeting holds keys expected in ext.prebid.targeting of the winning bid
+	Targeting map[string]string `json:"targeting,omitempty"`
+	// SKADN is the expected ext.skadn of the winning bid
+	SKADN json.RawMessage `json:"skadn,omitempty"`
+}
+
+// TestScenarios runs every scenario of testdata/scenarios through the /openrtb2/auction endpoint
+// of the router, the bidders answering with the canned responses of the scenario.
+func TestScenarios(t *testing.T) {
+	dirs, err := filepath.Glob(filepath.Join(scenariosDirectory, "*", "scenario.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) == 0 {
+		t.Fatalf("no scenario found in %s", scenariosDirectory)
+	}
+
+	for _, file := range dirs {
+		dir := filepath.Dir(file)
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			runScenario(t, dir)
+		})
+	}
+}
+
+func runScenario(t *testing.T, dir string) {
+	var s scenario
+	if err := readScenarioJSON(dir, "scenario.json", &s); err != nil {
+		t.Fatal(err)
+	}
+	request, err := ioutil.ReadFile(filepath.Join(dir, s.Request))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	responses := make([]replay.RecordedResponse, 0, len(s.Bidders))
+	for bidder, b := range s.Bidders {
+		body, err := ioutil.ReadFile(filepath.Join(dir, b.Response))
+		if err != nil {
+			t.Fatal(err)
+		}
+		responses = append(responses, replay.RecordedResponse{Bidder: bidder, Status: b.Status, Body: body})
+	}
+	standIn := replay.NewStandIn(responses)
+	defer standIn.Close()
+
+	handler := newScenarioRouter(t, standIn, s.Bidders)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/openrtb2/auction", bytes.NewReader(request)))
+
+	expectedStatus := s.Expected.Status
+	if expectedStatus == 0 {
+		expectedStatus = http.StatusOK
+	}
+	if !assert.Equal(t, expectedStatus, recorder.Code, recorder.Body.String()) || expectedStatus != http.StatusOK {
+		return
+	}
+
+	var response scenarioResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("invalid auction response: %v", err)
+	}
+	winner, seat := response.winningBid()
+	if s.Expected.Winner == "" {
+		assert.Nil(t, winner, "no bidder fills")
+		return
+	}
+	if !assert.NotNil(t, winner, "a bidder fills") {
+		return
+	}
+	assert.Equal(t, s.Expected.Winner, seat, "winner")
+	if s.Expected.Price != 0 {
+		assert.Equal(t, s.Expected.Price, winner.Price, "price")
+	}
+	for key, value := range s.Expected.Targeting {
+		assert.Equal(t, value, winner.Ext.Prebid.Targeting[key], "targeting key %s", key)
+	}
+	if len(s.Expected.SKADN) > 0 {
+		assert.JSONEq(t, string(s.Expected.SKADN), string(winner.Ext.SKADN), "skadn")
+	}
+}
+
+// newScenarioRouter builds the router of a config enabling the bidders of the scenario, their
+// endpoints pointing to the stand-in
+func newScenarioRouter(t *testing.T, standIn *replay.StandIn, bidders map[string]scenarioBidder) http.Handler {
+	v := viper.New()
+	config.SetupViper(v, "")
+	v.Set("gdpr.enabled", false)
+	v.Set("monitoring.newrelic.log_level", "error")
+	// the SKAN ID Lists of the bidders are fetched once from a server without them, the scenarios rely on
+	// their static ids
+	v.Set("skan_id_list.fetch_interval_seconds", 0)
+	for bidder := range bidders {
+		v.Set("adapters."+bidder+".disabled", false)
+	}
+	cfg, err := config.New(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	standIn.RewriteEndpoints(cfg.Adapters)
+	infos, err := config.LoadBidderInfoFromDisk("static/bidder-info", cfg.Adapters, openrtb_ext.BuildBidderStringSlice())
+	if err != nil {
+		t.Fatal(err)
+	}
+	noSKANIDList := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(noSKANIDList.Close)
+	for name, adapter := range cfg.Adapters {
+		// the seats use the SKAN ID List of the bidder serving them
+		if info := infos[name]; info.Seat != nil && info.Seat.Of != "" {
+			continue
+		}
+		adapter.SKAdNetwork.ListURL = noSKANIDList.URL
+		cfg.Adapters[name] = adapter
+	}
+
+	r, err := router.New(cfg, currency.NewRateConverter(&http.Client{}, "", 24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(r.Shutdown)
+	return r
+}
+
+func readScenarioJSON(dir, name string, v interface{}) error {
+	data, err := ioutil.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// scenarioResponse holds the fields of the auction response the scenarios assert on
+type scenarioResponse struct {
+	SeatBid []struct {
+		Seat string        `json:"seat"`
+		Bid  []scenarioBid `json:"bid"`
+	} `json:"seatbid"`
+}
+
+type scenarioBid struct {
+	Price float64 `json:"price"`
+	Ext   struct {
+		Prebid struct {
+			Targeting map[string]string `json:"targeting"`
+		} `json:"prebid"`
+		SKADN json.RawMessage `json:"skadn"`
+	} `json:"ext"`
+}
+
+// winningBid returns the highest bid of the response and its seat
+func (r scenarioResponse) winningBid() (*scenarioBid, string) {
+	var winner *scenarioBid
+	var seat string
+	for i := range r.SeatBid {
+		for j := range r.SeatBid[i].Bid {
+			if bid := &r.SeatBid[i].Bid[j]; winner == nil || bid.Price > winner.Price {
+				winner, seat = bid, r.SeatBid[i].Seat
+			}
+		}
+	}
+	return winner, seat
+}
@@ -2,4 +2,4 @@
 
 TPE_PREBID_SERVER_HOST=${TPE_PREBID_SERVER_HOST:=localhost:8000}
 
-curl --data @../../requests/rubicon_liftoff.json "http://${TPE_PREBID_SERVER_HOST}/openrtb2/auction" -v
+curl --data @../../requests/all_bidders_skadn.json "http://${TPE_PREBID_SERVER_HOST}/openrtb2/auction" -v
@@ -0,0 +1,44 @@
+package androidutil
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ParseMajorVersion parses the major version of an Android device, "12", "12.1" and "12.1.0" are all 12.
+func ParseMajorVersion(v string) (int, error) {
+	major := v
+	if i := strings.IndexByte(v, '.'); i >= 0 {
+		major = v[:i]
+	}
+
+	version, err := strconv.Atoi(major)
+	if err != nil || version < 0 {
+		return 0, errors.New("major version is not an integer")
+	}
+	return version, nil
+}
+
+// VersionClassification describes Android version classifications which are important to Prebid Server.
+type VersionClassification int
+
+// Values of VersionClassification.
+const (
+	VersionUnknown VersionClassification = iota
+	VersionBelow12
+	Version12OrGreater
+)
+
+// DetectVersionClassification detects the Android version classification. As of Android 12, the advertising
+// ID is zeroed when the user opts out of personalized ads or the app lacks the AD_ID permission.
+func DetectVersionClassification(v string) VersionClassification {
+	major, err := ParseMajorVersion(v)
+	if err != nil {
+		return VersionUnknown
+	}
+	if major >= 12 {
+		return Version12OrGreater
+	}
+	return VersionBelow12
+}
@@ -0,0 +1,79 @@
+package androidutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMajorVersion(t *testing.T) {
+	tests := []struct {
+		description     string
+		given           string
+		expectedVersion int
+		expectedError   string
+	}{
+		{
+			description:     "Major Only",
+			given:           "12",
+			expectedVersion: 12,
+		},
+		{
+			description:     "Major And Minor",
+			given:           "11.1",
+			expectedVersion: 11,
+		},
+		{
+			description:     "Major, Minor And Patch",
+			given:           "13.0.2",
+			expectedVersion: 13,
+		},
+		{
+			description:   "Invalid - Empty",
+			given:         "",
+			expectedError: "major version is not an integer",
+		},
+		{
+			description:   "Invalid Major",
+			given:         "xxx.1",
+			expectedError: "major version is not an integer",
+		},
+		{
+			description:   "Invalid - Negative",
+			given:         "-1",
+			expectedError: "major version is not an integer",
+		},
+	}
+
+	for _, test := range tests {
+		version, err := ParseMajorVersion(test.given)
+
+		if test.expectedError == "" {
+			assert.NoError(t, err, test.description)
+		} else {
+			assert.EqualError(t, err, test.expectedError, test.description)
+		}
+
+		assert.Equal(t, test.expectedVersion, version, test.description)
+	}
+}
+
+func TestDetectVersionClassification(t *testing.T) {
+	tests := []struct {
+		given    string
+		expected VersionClassification
+	}{
+		{given: "11", expected: VersionBelow12},
+		{given: "9.0", expected: VersionBelow12},
+		{given: "12", expected: Version12OrGreater},
+		{given: "12.1", expected: Version12OrGreater},
+		{given: "14.0.1", expected: Version12OrGreater},
+		{given: "", expected: VersionUnknown},
+		{given: "S", expected: VersionUnknown},
+	}
+
+	for _, test := range tests {
+		result := DetectVersionClassification(test.given)
+		assert.Equal(t, test.expected, result, test.given)
+	}
+}